@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/sloanahrens/devbot-go/internal/advisory"
 	"github.com/sloanahrens/devbot-go/internal/branch"
 	"github.com/sloanahrens/devbot-go/internal/check"
 	"github.com/sloanahrens/devbot-go/internal/config"
@@ -17,17 +23,26 @@ import (
 	"github.com/sloanahrens/devbot-go/internal/detect"
 	"github.com/sloanahrens/devbot-go/internal/diff"
 	execPkg "github.com/sloanahrens/devbot-go/internal/exec"
+	"github.com/sloanahrens/devbot-go/internal/git"
+	"github.com/sloanahrens/devbot-go/internal/graph"
 	"github.com/sloanahrens/devbot-go/internal/lastcommit"
+	"github.com/sloanahrens/devbot-go/internal/license"
 	"github.com/sloanahrens/devbot-go/internal/makefile"
 	"github.com/sloanahrens/devbot-go/internal/output"
 	portPkg "github.com/sloanahrens/devbot-go/internal/port"
 	"github.com/sloanahrens/devbot-go/internal/prereq"
+	"github.com/sloanahrens/devbot-go/internal/pull"
 	pulumiPkg "github.com/sloanahrens/devbot-go/internal/pulumi"
 	"github.com/sloanahrens/devbot-go/internal/remote"
+	"github.com/sloanahrens/devbot-go/internal/report"
+	"github.com/sloanahrens/devbot-go/internal/reposync"
 	"github.com/sloanahrens/devbot-go/internal/runner"
+	"github.com/sloanahrens/devbot-go/internal/sbom"
+	"github.com/sloanahrens/devbot-go/internal/stack"
 	"github.com/sloanahrens/devbot-go/internal/stats"
 	"github.com/sloanahrens/devbot-go/internal/todos"
 	"github.com/sloanahrens/devbot-go/internal/tree"
+	"github.com/sloanahrens/devbot-go/internal/watch"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 	"github.com/sloanahrens/devbot-go/internal/worktrees"
 	"github.com/spf13/cobra"
@@ -37,8 +52,45 @@ var rootCmd = &cobra.Command{
 	Use:   "devbot",
 	Short: "Fast parallel development workspace tools",
 	Long:  `devbot accelerates common development operations through parallelization.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if outputJSON {
+			outputFormat = string(output.FormatJSON)
+		}
+		if err := output.SetFormat(outputFormat); err != nil {
+			return err
+		}
+		if err := configureStacks(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		return nil
+	},
 }
 
+// configureStacks installs the stacks and stack_dirs from config.yaml into
+// the registry used by detect, check, prereq and config
+func configureStacks() error {
+	cfg, err := workspace.LoadConfig()
+	if err != nil || cfg == nil || (len(cfg.Stacks) == 0 && len(cfg.StackDirs) == 0) {
+		return nil
+	}
+	reg, err := stack.New(cfg.Stacks, cfg.StackDirs)
+	if err != nil {
+		return fmt.Errorf("config.yaml: %w", err)
+	}
+	stack.SetDefault(reg)
+	return nil
+}
+
+// Global output flags
+var (
+	outputJSON   bool
+	outputFormat string
+)
+
+// reposSelector is the shared --repos flag (see workspace.ParseSelector)
+var reposSelector string
+
 // Status command
 var statusCmd = &cobra.Command{
 	Use:   "status [repo]",
@@ -57,28 +109,200 @@ var (
 var runCmd = &cobra.Command{
 	Use:   "run <command> [args...]",
 	Short: "Run a command in all repositories in parallel",
-	Long:  `Executes the given command in all repositories simultaneously.`,
-	Args:  cobra.MinimumNArgs(1),
-	Run:   runRun,
+	Long: `Executes the given command in all repositories in parallel.
+
+At most --jobs repos run at once. Each repo's command runs in its own
+process group, which is killed on --timeout or Ctrl-C. Exits non-zero
+if the command failed, timed out or was cancelled in any repo.
+
+Output is buffered per repo by default. With --stream, lines are printed
+as they arrive (stdout to stdout, stderr to stderr), prefixed with the
+repo name, followed by a summary of exit codes and durations.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runRun,
 }
 
 var (
-	runFilter string
-	runQuiet  bool
+	runFilter  string
+	runQuiet   bool
+	runJobs    int
+	runTimeout time.Duration
+	runStream  bool
 )
 
 // Deps command
 var depsCmd = &cobra.Command{
 	Use:   "deps [repo]",
 	Short: "Show dependencies across repositories",
-	Long:  `Analyzes package.json and go.mod files to show dependencies.`,
-	Args:  cobra.MaximumNArgs(1),
-	Run:   runDeps,
+	Long: `Analyzes package.json, go.mod, pyproject.toml, requirements*.txt and
+Cargo.toml files to show dependencies. Optional and dev groups count as dev;
+Go modules marked "// indirect" are treated like transitive dependencies.
+
+Installed versions are read from lockfiles (package-lock.json, pnpm-lock.yaml,
+yarn.lock, go.sum, Cargo.lock, uv.lock, poetry.lock). Workspace members (npm,
+yarn and pnpm workspaces, go.work, Cargo workspaces) are analyzed too, and a
+lockfile at the repo root resolves their manifests. Packages that appear only
+in a lockfile are transitive and are hidden unless --transitive is given.
+
+Examples:
+  devbot deps                     # Shared dependencies (2+ repos)
+  devbot deps --all               # All direct deps with installed versions
+  devbot deps --all --transitive  # Include transitive deps
+  devbot deps --count             # Prod/dev/transitive counts per repo
+  devbot deps --drift             # Version drift; exits 1 if policy is exceeded
+  devbot deps --drift --fail-on minor
+  devbot deps --audit             # Known vulnerabilities (offline; exits 1 if any)
+  devbot deps --audit --severity high
+
+Drift policy lives in config.yaml:
+  deps:
+    drift:
+      fail_on: major              # major (default), minor, patch or never
+      packages:
+        react: minor              # Stricter for specific packages
+        typescript: minor
+      ignore: ["@types/*"]`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runDeps,
+}
+
+var (
+	depsShowAll    bool
+	depsCount      bool
+	depsTransitive bool
+	depsDrift      bool
+	depsFailOn     string
+	depsAudit      bool
+	depsSeverity   string
+	depsAdvisoryDB string
+)
+
+// Advisories command
+var advisoriesCmd = &cobra.Command{
+	Use:   "advisories",
+	Short: "Manage the offline vulnerability advisory database",
+	Long: `Shows the local OSV advisory database used by 'devbot deps --audit'.
+
+The database lives in <workspace>/.devbot/advisories and is only updated by
+'devbot advisories import', so audits never need the network. Download
+ecosystem exports from https://osv-vulnerabilities.storage.googleapis.com
+(e.g. npm/all.zip, Go/all.zip, PyPI/all.zip, crates.io/all.zip) or point
+import at any directory of OSV JSON files.`,
+	Args: cobra.NoArgs,
+	Run:  runAdvisories,
+}
+
+var advisoriesImportCmd = &cobra.Command{
+	Use:   "import <dir|zip>...",
+	Short: "Import OSV advisories from directories or zip archives",
+	Long: `Copies OSV-format advisories for npm, Go, PyPI and crates.io into the local
+database. Advisories already present are replaced only if the import has a
+newer modified date; other ecosystems are skipped.
+
+Examples:
+  devbot advisories import ~/Downloads/npm-all.zip ~/Downloads/go-all.zip
+  devbot advisories import ./osv-advisories/`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runAdvisoriesImport,
+}
+
+// SBOM command
+var sbomCmd = &cobra.Command{
+	Use:   "sbom [repo]",
+	Short: "Generate CycloneDX or SPDX software bills of materials",
+	Long: `Builds an SBOM from the same analysis as 'devbot deps': every direct and
+transitive package with its purl and installed version, the dependency
+relationships recorded in lockfiles, and the repo's git commit.
+
+Output is deterministic - sorted, with identifiers derived from the content
+and the commit time as the timestamp - so SBOMs from two commits can be
+diffed. Development-only packages are marked (CycloneDX scope "excluded",
+SPDX DEV_DEPENDENCY_OF).
+
+One repo in one format prints to stdout. --all, or several formats, writes
+<repo>.cdx.json and <repo>.spdx.json files to --output.
+
+Examples:
+  devbot sbom my-app > my-app.cdx.json
+  devbot sbom my-app --spec spdx
+  devbot sbom --all                        # Every repo into .devbot/sbom/
+  devbot sbom --all --spec cyclonedx,spdx -o ./sboms`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runSbom,
 }
 
 var (
-	depsShowAll bool
-	depsCount   bool
+	sbomAll    bool
+	sbomSpecs  []string
+	sbomOutput string
+)
+
+// Graph command
+var graphCmd = &cobra.Command{
+	Use:   "graph [repo]",
+	Short: "Show how workspace repos depend on each other",
+	Long: `Matches the modules and packages each repo declares (go.mod module,
+package.json name, pyproject.toml and Cargo.toml names, including workspace
+members) against the dependencies of every other repo to find internal edges:
+Go modules consumed via replace ../x or a version, npm packages published from
+one repo and installed in another, and so on.
+
+With a repo, shows what it depends on and what depends on it. --dependents
+lists every repo affected by a change to it, directly or through other repos -
+the ones to re-check.
+
+Examples:
+  devbot graph                            # All internal edges
+  devbot graph api                        # What api uses and what uses api
+  devbot graph api --dependents           # Everything to re-check after changing api
+  devbot graph --dot | dot -Tsvg > deps.svg
+  devbot graph --json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runGraph,
+}
+
+var (
+	graphDependents bool
+	graphDot        bool
+)
+
+// Licenses command
+var licensesCmd = &cobra.Command{
+	Use:   "licenses [repo]",
+	Short: "Check dependency licenses against an allow/deny list",
+	Long: `Reads the license of every resolved dependency from what is installed
+locally - node_modules/*/package.json (including pnpm's store), vendor/ and
+the Go module cache, and .venv site-packages metadata, plus the cargo registry
+cache - normalizes it to an SPDX expression and checks it against the
+licenses section of config.yaml. Nothing is downloaded.
+
+  licenses:
+    allow: [MIT, Apache-2.0, "BSD-*", ISC]
+    deny: ["GPL-*", "AGPL-*"]
+    packages:                 # Overrides for missing or wrong metadata
+      some-pkg: MIT
+    ignore: ["@myorg/*"]
+    fail_on_unknown: false
+
+"MIT OR GPL-3.0-only" passes if MIT is acceptable; an AND needs every part.
+With no allow list, anything not denied passes. Dev dependencies are skipped
+unless --dev is given, as they don't ship.
+
+Exits 1 if any package violates the policy.
+
+Examples:
+  devbot licenses                         # Violations across the workspace
+  devbot licenses my-app --all            # Every package and its license
+  devbot licenses --dev --fail-unknown
+  devbot licenses --json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runLicenses,
+}
+
+var (
+	licensesAll         bool
+	licensesDev         bool
+	licensesFailUnknown bool
 )
 
 // Tree command
@@ -97,13 +321,28 @@ var (
 
 // Detect command
 var detectCmd = &cobra.Command{
-	Use:   "detect [path]",
+	Use:   "detect [path|repo]",
 	Short: "Detect project stack for a directory",
-	Long:  `Identifies the technology stack (Go, TypeScript, Python, etc.) for a project.`,
-	Args:  cobra.MaximumNArgs(1),
-	Run:   runDetect,
+	Long: `Identifies the technology stack (Go, TypeScript, Python, etc.) for a project.
+
+Stacks are recognized by marker files in the directory and in sub-app
+directories such as api/, web/ and packages/*. Add stacks or sub-app
+directories with stacks: and stack_dirs: in config.yaml.
+
+--explain shows the evidence: every directory examined and which marker
+files matched, the sub-apps 'devbot check' would find and the commands it
+would run for each, and anywhere detect and check disagree.
+
+Examples:
+  devbot detect
+  devbot detect ~/code/my-app
+  devbot detect --explain my-app`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runDetect,
 }
 
+var detectExplain bool
+
 // Todos command
 var todosCmd = &cobra.Command{
 	Use:   "todos [repo]",
@@ -182,15 +421,36 @@ var (
 var checkCmd = &cobra.Command{
 	Use:   "check <repo>",
 	Short: "Run lint, typecheck, build, and test for a repository",
-	Long:  `Auto-detects project stack and runs appropriate quality checks.`,
-	Args:  cobra.ExactArgs(1),
-	Run:   runCheckCmd,
+	Long: `Auto-detects project stack and runs appropriate quality checks.
+
+Passing results are cached by a fingerprint of the tracked file contents,
+dirty files, shared root lockfiles and configs, resolved command and tool
+versions, so re-running on an unchanged tree is instant. Untracked binary
+files are treated as build output and don't affect the fingerprint.
+
+Examples:
+  devbot check my-app
+  devbot check my-app --no-cache
+  devbot check my-app --report junit=out/checks.xml --report sarif=out/lint.sarif
+  devbot check --cache-stats
+  devbot check --cache-clear`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if checkCacheStats || checkCacheClear {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: runCheckCmd,
 }
 
 var (
-	checkOnly   string
-	checkFix    bool
-	checkPrereq bool
+	checkOnly       string
+	checkFix        bool
+	checkPrereq     bool
+	checkNoCache    bool
+	checkCacheStats bool
+	checkCacheClear bool
+	checkReports    []string
 )
 
 // Branch command
@@ -313,16 +573,75 @@ Examples:
 
 // Fetch command
 var fetchCmd = &cobra.Command{
-	Use:   "fetch <repo>",
-	Short: "Fetch from remote for a repository",
-	Long: `Fetches all remotes and prunes stale references.
+	Use:   "fetch [repo]",
+	Short: "Fetch from remotes for one or more repositories",
+	Long: `Fetches all remotes and prunes stale references, in parallel across repos.
+Shows how many commits arrived on each repo's upstream branch.
 
 Examples:
-  devbot fetch slash-commands`,
-	Args: cobra.ExactArgs(1),
+  devbot fetch slash-commands
+  devbot fetch --all
+  devbot fetch --repos group:apps`,
+	Args: cobra.MaximumNArgs(1),
 	Run:  runFetch,
 }
 
+var (
+	fetchAll  bool
+	fetchJobs int
+)
+
+// Pull command
+var pullCmd = &cobra.Command{
+	Use:   "pull [repo]",
+	Short: "Fast-forward repositories to their upstream",
+	Long: `Fetches and fast-forwards the current branch of every selected repo.
+
+Never merges or rebases. Repos that are detached, have no upstream, have
+uncommitted changes or have diverged from upstream are skipped with the
+reason shown. Exits non-zero only if a fetch or fast-forward fails.
+
+Examples:
+  devbot pull                     # All repos
+  devbot pull --repos lang:go
+  devbot pull my-app`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPull,
+}
+
+var pullJobs int
+
+// Watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch [repo]",
+	Short: "Live-updating workspace status dashboard",
+	Long: `Shows a full-screen status table that redraws whenever a repo's git
+metadata (HEAD, index, refs) changes. Uses inotify on Linux; macOS and
+other platforms poll .git instead. All repos are also re-read every
+--interval to pick up working-tree edits.
+
+Keys:
+  ↑/↓ j/k       Select repo
+  enter, d      Diff for the selected repo
+  b / l         Branch info / log
+  a             Toggle all repos / only those needing attention
+  r             Refresh now
+  esc           Back to the list
+  q             Quit
+
+Examples:
+  devbot watch
+  devbot watch --repos lang:go
+  devbot watch --poll --interval 30s`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runWatch,
+}
+
+var (
+	watchInterval time.Duration
+	watchPoll     bool
+)
+
 // Switch command
 var switchCmd = &cobra.Command{
 	Use:   "switch <repo> <branch>",
@@ -392,29 +711,108 @@ Examples:
 	Run:  runPrereq,
 }
 
+// Sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Clone missing repos and reconcile the workspace with config.yaml",
+	Long: `Compares config.yaml with the repos on disk.
+
+Configured repos with a remote (or url) that aren't on disk are cloned,
+at most --jobs at a time, to their path (default: workspace/<name>).
+Repos whose origin doesn't match the configured remote are flagged, and
+on-disk repos missing from config.yaml are listed (or added with --add).
+
+Examples:
+  devbot sync --dry-run     # Show what would be cloned
+  devbot sync               # Clone missing repos
+  devbot sync --add         # Also add untracked repos to config.yaml`,
+	Args: cobra.NoArgs,
+	Run:  runSync,
+}
+
+var (
+	syncJobs   int
+	syncAdd    bool
+	syncDryRun bool
+)
+
 func init() {
+	// Global flags
+	rootCmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "Output results as JSON (same as --format=json)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "Output format: text or json")
+
+	// Repo selector for multi-repo commands
+	for _, c := range []*cobra.Command{statusCmd, runCmd, depsCmd, todosCmd, configCmd, makeCmd, worktreesCmd, fetchCmd, pullCmd, watchCmd} {
+		c.Flags().StringVar(&reposSelector, "repos", "", "Select repos: names, globs, group:X, tag:X, lang:X, !negation")
+	}
+
 	// Status flags
 	statusCmd.Flags().BoolVar(&showDirtyOnly, "dirty", false, "Only show repos with uncommitted changes")
 	statusCmd.Flags().BoolVarP(&showAll, "all", "a", false, "Show all repos including clean ones")
 
+	// Fetch/pull flags
+	fetchCmd.Flags().BoolVarP(&fetchAll, "all", "a", false, "Fetch all repos")
+	fetchCmd.Flags().IntVarP(&fetchJobs, "jobs", "j", pull.DefaultJobs, "Max repos to fetch concurrently")
+	pullCmd.Flags().IntVarP(&pullJobs, "jobs", "j", pull.DefaultJobs, "Max repos to pull concurrently")
+
+	// Watch flags
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 10*time.Second, "Re-read every repo's status this often")
+	watchCmd.Flags().BoolVar(&watchPoll, "poll", false, "Poll git metadata instead of using filesystem notifications")
+
+	// Sync flags
+	syncCmd.Flags().IntVarP(&syncJobs, "jobs", "j", 4, "Max concurrent clones")
+	syncCmd.Flags().BoolVar(&syncAdd, "add", false, "Add on-disk repos missing from config.yaml")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show what would change without cloning or editing config")
+
 	// Run flags
 	runCmd.Flags().StringVarP(&runFilter, "filter", "f", "", "Only run in repos matching this name")
+	_ = runCmd.Flags().MarkDeprecated("filter", "use --repos '*name*' instead")
 	runCmd.Flags().BoolVarP(&runQuiet, "quiet", "q", false, "Only show output from repos with non-empty results")
+	runCmd.Flags().IntVarP(&runJobs, "jobs", "j", runtime.NumCPU(), "Max repos to run concurrently (0 = unlimited)")
+	runCmd.Flags().DurationVar(&runTimeout, "timeout", 0, "Per-repo time limit, e.g. 30s or 5m (0 = none)")
+	runCmd.Flags().BoolVar(&runStream, "stream", false, "Print output live, prefixed with the repo name")
 
 	// Deps flags
 	depsCmd.Flags().BoolVarP(&depsShowAll, "all", "a", false, "Show all dependencies (not just summary)")
 	depsCmd.Flags().BoolVarP(&depsCount, "count", "c", false, "Show dependency counts only")
+	depsCmd.Flags().BoolVar(&depsTransitive, "transitive", false, "Include transitive dependencies from lockfiles")
+	depsCmd.Flags().BoolVar(&depsDrift, "drift", false, "Report shared dependencies used at different versions")
+	depsCmd.Flags().BoolVar(&depsAudit, "audit", false, "Check installed versions against the local advisory database")
+	depsCmd.Flags().StringVar(&depsSeverity, "severity", "unknown", "With --audit, lowest severity to report: critical, high, medium, low or unknown")
+	depsCmd.Flags().StringVar(&depsAdvisoryDB, "advisory-db", "", "Advisory database directory (default: <workspace>/.devbot/advisories)")
+	depsCmd.Flags().StringVar(&depsFailOn, "fail-on", "", "With --drift, exit non-zero on this much drift: major, minor, patch or never (default from config.yaml, else major)")
+
+	// Advisories flags
+	advisoriesCmd.PersistentFlags().StringVar(&depsAdvisoryDB, "db", "", "Advisory database directory (default: <workspace>/.devbot/advisories)")
+	advisoriesCmd.AddCommand(advisoriesImportCmd)
+
+	// SBOM flags
+	sbomCmd.Flags().BoolVarP(&sbomAll, "all", "a", false, "Generate an SBOM for every repo")
+	sbomCmd.Flags().StringSliceVar(&sbomSpecs, "spec", []string{"cyclonedx"}, "SBOM formats: cyclonedx, spdx or both (comma-separated)")
+	sbomCmd.Flags().StringVarP(&sbomOutput, "output", "o", "", "Write files to this directory (default: <workspace>/.devbot/sbom when writing files)")
+
+	// Graph flags
+	graphCmd.Flags().BoolVar(&graphDependents, "dependents", false, "List every repo that depends on [repo], directly or transitively")
+	graphCmd.Flags().BoolVar(&graphDot, "dot", false, "Output Graphviz DOT")
+
+	// Licenses flags
+	licensesCmd.Flags().BoolVarP(&licensesAll, "all", "a", false, "List every package, not just violations and unknowns")
+	licensesCmd.Flags().BoolVar(&licensesDev, "dev", false, "Include dev dependencies")
+	licensesCmd.Flags().BoolVar(&licensesFailUnknown, "fail-unknown", false, "Treat unknown licenses as violations")
 
 	// Tree flags
 	treeCmd.Flags().IntVarP(&treeDepth, "depth", "d", 3, "Maximum depth to display")
 	treeCmd.Flags().BoolVar(&treeHidden, "hidden", false, "Show hidden files")
 
+	// Detect flags
+	detectCmd.Flags().BoolVarP(&detectExplain, "explain", "e", false, "Show the evidence behind the detected stacks and check's sub-apps")
+
 	// Todos flags
 	todosCmd.Flags().BoolVarP(&todosCount, "count", "c", false, "Show counts only")
 	todosCmd.Flags().StringVarP(&todosType, "type", "t", "", "Filter by type (TODO, FIXME, HACK, XXX, BUG)")
 
 	// Config flags
-	configCmd.Flags().StringVarP(&configType, "type", "t", "", "Filter by type (node, go, python, infra, iac, ci, config)")
+	configCmd.Flags().StringVarP(&configType, "type", "t", "", "Filter by type (node, go, python, rust, infra, iac, ci, config, or a configured stack)")
 	configCmd.Flags().StringVar(&configHas, "has", "", "Show only repos with this config type")
 
 	// Make flags
@@ -427,9 +825,13 @@ func init() {
 	diffCmd.Flags().BoolVar(&diffFull, "full", false, "Show full diff content")
 
 	// Check flags
-	checkCmd.Flags().StringVar(&checkOnly, "only", "", "Only run specific checks (comma-separated: lint,typecheck,build,test)")
+	checkCmd.Flags().StringVar(&checkOnly, "only", "", "Only run specific checks (comma-separated: lint,typecheck,build,test, or custom checks from .devbot.yaml)")
 	checkCmd.Flags().BoolVar(&checkFix, "fix", false, "Auto-fix issues where possible")
 	checkCmd.Flags().BoolVar(&checkPrereq, "prereq", false, "Validate tools, deps, and env vars before running checks")
+	checkCmd.Flags().BoolVar(&checkNoCache, "no-cache", false, "Always run checks, ignoring cached results")
+	checkCmd.Flags().BoolVar(&checkCacheStats, "cache-stats", false, "Show check cache statistics")
+	checkCmd.Flags().BoolVar(&checkCacheClear, "cache-clear", false, "Delete all cached check results")
+	checkCmd.Flags().StringArrayVar(&checkReports, "report", nil, "Write a report file: junit=<path> (test results) or sarif=<path> (lint findings); repeatable")
 
 	// Deploy flags
 	deployCmd.Flags().BoolVar(&deployQuick, "quick", false, "Skip build step")
@@ -441,6 +843,10 @@ func init() {
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(depsCmd)
+	rootCmd.AddCommand(advisoriesCmd)
+	rootCmd.AddCommand(sbomCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(licensesCmd)
 	rootCmd.AddCommand(treeCmd)
 	rootCmd.AddCommand(detectCmd)
 	rootCmd.AddCommand(todosCmd)
@@ -460,10 +866,56 @@ func init() {
 	rootCmd.AddCommand(logCmd)
 	rootCmd.AddCommand(showCmd)
 	rootCmd.AddCommand(fetchCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(watchCmd)
 	rootCmd.AddCommand(switchCmd)
 	rootCmd.AddCommand(execCmd)
 	rootCmd.AddCommand(portCmd)
 	rootCmd.AddCommand(prereqCmd)
+	rootCmd.AddCommand(syncCmd)
+}
+
+// selectRepos narrows repos by --repos and an optional positional [repo]
+// argument, which is itself a selector. A plain positional name that isn't
+// an exact match falls back to a substring match. Exits if nothing matches.
+func selectRepos(repos []workspace.RepoInfo, args []string) []workspace.RepoInfo {
+	// Selectors read group, tag and language from config.yaml; report a
+	// broken file as such rather than as a bad --repos value
+	if _, err := workspace.LoadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: config.yaml: %v\n", err)
+		os.Exit(1)
+	}
+
+	selected, err := workspace.Select(repos, reposSelector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --repos: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(args) > 0 {
+		target := args[0]
+		byArg, err := workspace.Select(selected, target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		plain := !strings.ContainsAny(target, ":*?[, ") && !strings.HasPrefix(target, "!") && !strings.HasPrefix(target, "-")
+		if len(byArg) == 0 && plain {
+			byArg, _ = workspace.Select(selected, "*"+target+"*")
+		}
+		if len(byArg) == 0 {
+			fmt.Fprintf(os.Stderr, "Repository '%s' not found\n", target)
+			os.Exit(1)
+		}
+		selected = byArg
+	}
+
+	if len(selected) == 0 && reposSelector != "" {
+		fmt.Fprintf(os.Stderr, "No repositories match --repos %q\n", reposSelector)
+		os.Exit(1)
+	}
+
+	return selected
 }
 
 func runStatus(cmd *cobra.Command, args []string) {
@@ -486,31 +938,21 @@ func runStatus(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	if len(args) == 1 {
-		target := args[0]
-		var filtered []workspace.RepoInfo
-		for _, r := range repos {
-			if r.Name == target {
-				filtered = append(filtered, r)
-				break
-			}
-		}
-		if len(filtered) == 0 {
-			fmt.Fprintf(os.Stderr, "Repository '%s' not found\n", target)
-			os.Exit(1)
-		}
-		repos = filtered
-	}
+	// Configured repos that aren't on disk are reported, not dropped
+	repos = append(repos, workspace.MissingRepos(repos)...)
+	repos = selectRepos(repos, args)
 
 	statuses := workspace.GetStatus(repos)
 	elapsed := time.Since(start)
 
-	showAllRepos := showAll || len(args) == 1
+	showAllRepos := showAll || len(args) == 1 || reposSelector != ""
 	if showDirtyOnly {
 		showAllRepos = false
 	}
 
-	output.RenderStatus(statuses, elapsed, showAllRepos, workspacePath)
+	view := output.NewStatusView(statuses, workspacePath)
+	view.ShowAll = showAllRepos
+	output.Render("status", elapsed, view)
 }
 
 func runRun(cmd *cobra.Command, args []string) {
@@ -528,26 +970,31 @@ func runRun(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Filter repos if requested
+	// --filter is the old substring match, kept as an alias
+	var filter []string
 	if runFilter != "" {
-		var filtered []workspace.RepoInfo
-		for _, r := range repos {
-			if strings.Contains(r.Name, runFilter) {
-				filtered = append(filtered, r)
-			}
-		}
-		repos = filtered
-	}
-
-	if len(repos) == 0 {
-		fmt.Println("No repositories matched")
-		return
+		filter = []string{"*" + runFilter + "*"}
 	}
+	repos = selectRepos(repos, filter)
 
 	command := args[0]
 	cmdArgs := args[1:]
 
-	results := runner.RunParallel(repos, command, cmdArgs)
+	// Ctrl-C cancels the run; children live in their own process groups
+	// so they don't see the terminal's SIGINT and must be killed by us
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := runner.Options{
+		Jobs:    runJobs,
+		Timeout: runTimeout,
+	}
+	if runStream && !output.IsJSON() {
+		printer := output.NewLinePrinter(repos, output.IsTerminal(os.Stdout))
+		opts.OnLine = printer.Print
+	}
+
+	results := runner.RunContext(ctx, repos, command, cmdArgs, opts)
 	elapsed := time.Since(start)
 
 	// Sort by repo name
@@ -555,34 +1002,28 @@ func runRun(cmd *cobra.Command, args []string) {
 		return results[i].Repo.Name < results[j].Repo.Name
 	})
 
-	// Display results
-	for _, r := range results {
-		if runQuiet && r.Output == "" && r.Error == nil {
-			continue
-		}
-
-		fmt.Printf("── %s ", r.Repo.Name)
-		if r.Error != nil {
-			fmt.Printf("(error: %v)\n", r.Error)
-		} else {
-			fmt.Println()
-		}
+	view := output.NewRunView(args, results)
+	view.Stream, view.Quiet = runStream, runQuiet
+	output.Render("run", elapsed, view)
 
-		if r.Output != "" {
-			// Indent output
-			lines := strings.Split(strings.TrimSpace(r.Output), "\n")
-			for _, line := range lines {
-				fmt.Printf("   %s\n", line)
-			}
+	if ctx.Err() != nil {
+		os.Exit(130)
+	}
+	for _, r := range results {
+		if r.Failed() {
+			os.Exit(1)
 		}
 	}
-
-	fmt.Printf("\n(%d repos, %.2fs)\n", len(repos), elapsed.Seconds())
 }
 
 func runDeps(cmd *cobra.Command, args []string) {
 	start := time.Now()
 
+	if depsDrift && depsAudit {
+		fmt.Fprintln(os.Stderr, "Error: --drift and --audit are separate reports; run them one at a time")
+		os.Exit(1)
+	}
+
 	workspacePath := workspace.DefaultWorkspace()
 	if workspacePath == "" {
 		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
@@ -595,17 +1036,7 @@ func runDeps(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	if len(args) == 1 {
-		target := args[0]
-		var filtered []workspace.RepoInfo
-		for _, r := range repos {
-			if r.Name == target {
-				filtered = append(filtered, r)
-				break
-			}
-		}
-		repos = filtered
-	}
+	repos = selectRepos(repos, args)
 
 	results := deps.AnalyzeParallel(repos)
 	elapsed := time.Since(start)
@@ -615,88 +1046,346 @@ func runDeps(cmd *cobra.Command, args []string) {
 		return results[i].Repo.Name < results[j].Repo.Name
 	})
 
-	if depsCount {
-		// Just show counts
-		fmt.Println("\nDependency counts:")
-		fmt.Println(strings.Repeat("─", 40))
-		for _, r := range results {
-			if len(r.Dependencies) > 0 {
-				prod := 0
-				dev := 0
-				for _, d := range r.Dependencies {
-					if d.Dev {
-						dev++
-					} else {
-						prod++
-					}
-				}
-				fmt.Printf("  %-25s %3d prod, %3d dev\n", r.Repo.Name, prod, dev)
-			}
+	if depsDrift {
+		printDrift(results, elapsed)
+		return
+	}
+	if depsAudit {
+		printAudit(workspacePath, results, start)
+		return
+	}
+
+	view := output.NewDepsView(results)
+	view.Count, view.All, view.Transitive = depsCount, depsShowAll, depsTransitive
+	output.Render("deps", elapsed, view)
+}
+
+// printDrift reports version drift and exits non-zero if the policy is exceeded
+func printDrift(results []deps.RepoDeps, elapsed time.Duration) {
+	var cfg workspace.DriftConfig
+	if wsCfg, err := workspace.LoadConfig(); err == nil && wsCfg != nil {
+		cfg = wsCfg.Deps.Drift
+	}
+	if depsFailOn != "" {
+		if _, err := deps.ParseDriftLevel(depsFailOn); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --fail-on: %v\n", err)
+			os.Exit(1)
 		}
-	} else {
-		// Aggregate all dependencies
-		depCount := make(map[string][]string) // dep name -> repos using it
+		cfg.FailOn = depsFailOn
+	}
+	policy, err := deps.NewDriftPolicy(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-		for _, r := range results {
-			for _, d := range r.Dependencies {
-				depCount[d.Name] = append(depCount[d.Name], r.Repo.Name)
-			}
+	drifts := deps.FindDrift(deps.Aggregate(results, depsTransitive), policy)
+	view := output.NewDriftView(drifts, policy)
+	output.Render("deps", elapsed, view)
+
+	if view.Exceeded > 0 {
+		os.Exit(1)
+	}
+}
+
+// advisoryDir returns the --advisory-db/--db directory or the default
+func advisoryDir(workspacePath string) string {
+	if depsAdvisoryDB != "" {
+		return depsAdvisoryDB
+	}
+	return advisory.DefaultDir(workspacePath)
+}
+
+// printAudit reports known vulnerabilities and exits non-zero if any are found
+func printAudit(workspacePath string, results []deps.RepoDeps, start time.Time) {
+	minLevel, err := advisory.ParseLevel(depsSeverity)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --severity: %v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := advisory.Open(advisoryDir(workspacePath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading advisory database: %v\n", err)
+		os.Exit(1)
+	}
+	if db.Advisories == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no advisories in %s (run 'devbot advisories import <dir|zip>' first)\n", db.Dir)
+		os.Exit(1)
+	}
+	meta, _ := advisory.ReadMeta(db.Dir)
+
+	report := advisory.Audit(db, results, minLevel)
+	elapsed := time.Since(start)
+
+	output.Render("deps", elapsed, output.NewAuditView(db, meta, report, minLevel))
+
+	if len(report.Findings) > 0 {
+		os.Exit(1)
+	}
+}
+
+func runAdvisories(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
+	workspacePath := workspace.DefaultWorkspace()
+	db, err := advisory.Open(advisoryDir(workspacePath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading advisory database: %v\n", err)
+		os.Exit(1)
+	}
+	meta, _ := advisory.ReadMeta(db.Dir)
+
+	output.Render("advisories", time.Since(start), output.NewAdvisoryDBView(db, meta))
+}
+
+func runAdvisoriesImport(cmd *cobra.Command, args []string) {
+	start := time.Now()
+	dir := advisoryDir(workspace.DefaultWorkspace())
+
+	view := output.AdvisoryImportsView{Database: dir}
+	failed := false
+	for _, src := range args {
+		stats, err := advisory.Import(src, dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing %s: %v\n", src, err)
+			failed = true
+			continue
 		}
+		view.Imports = append(view.Imports, output.AdvisoryImportView{
+			Database:    dir,
+			Source:      src,
+			Imported:    stats.Imported,
+			Unchanged:   stats.Unchanged,
+			Unsupported: stats.Unsupported,
+			Invalid:     stats.Invalid,
+		})
+	}
 
-		if depsShowAll {
-			// Show all deps sorted by usage
-			type depUsage struct {
-				name  string
-				repos []string
-			}
-			var usages []depUsage
-			for name, repos := range depCount {
-				usages = append(usages, depUsage{name, repos})
-			}
-			sort.Slice(usages, func(i, j int) bool {
-				if len(usages[i].repos) != len(usages[j].repos) {
-					return len(usages[i].repos) > len(usages[j].repos)
-				}
-				return usages[i].name < usages[j].name
-			})
+	output.Render("advisories-import", time.Since(start), view)
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func runSbom(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
+	var formats []sbom.Format
+	for _, spec := range sbomSpecs {
+		f, err := sbom.ParseFormat(strings.TrimSpace(spec))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --spec: %v\n", err)
+			os.Exit(1)
+		}
+		formats = append(formats, f)
+	}
+	if len(args) == 0 && !sbomAll && reposSelector == "" {
+		fmt.Fprintln(os.Stderr, "Error: specify a repo, --repos or --all")
+		os.Exit(1)
+	}
 
-			fmt.Println("\nAll dependencies (by usage):")
-			fmt.Println(strings.Repeat("─", 60))
-			for _, u := range usages {
-				fmt.Printf("  %-40s (%d repos)\n", u.name, len(u.repos))
+	workspacePath := workspace.DefaultWorkspace()
+	if workspacePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
+		os.Exit(1)
+	}
+
+	repos, err := workspace.Discover(workspacePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
+		os.Exit(1)
+	}
+	repos = selectRepos(repos, args)
+
+	results := deps.AnalyzeParallel(repos)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Repo.Name < results[j].Repo.Name
+	})
+
+	// A single document goes to stdout so it can be piped or redirected
+	toStdout := sbomOutput == "" && !sbomAll && len(results) == 1 && len(formats) == 1
+	dir := sbomOutput
+	if dir == "" {
+		dir = filepath.Join(workspacePath, ".devbot", "sbom")
+	}
+	if !toStdout {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	view := output.SBOMFilesView{Dir: dir}
+	failed := false
+	for _, r := range results {
+		if r.Error != nil {
+			fmt.Fprintf(os.Stderr, "Error analyzing %s: %v\n", r.Repo.Name, r.Error)
+			failed = true
+			continue
+		}
+		doc := sbom.Build(sbom.GitSource(r.Repo), r)
+		for _, f := range formats {
+			data, err := doc.Encode(f)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding %s for %s: %v\n", f, r.Repo.Name, err)
+				failed = true
+				continue
 			}
-		} else {
-			// Show shared dependencies (used by 2+ repos)
-			fmt.Println("\nShared dependencies (2+ repos):")
-			fmt.Println(strings.Repeat("─", 60))
-
-			type depUsage struct {
-				name  string
-				repos []string
+			if toStdout {
+				os.Stdout.Write(data)
+				continue
 			}
-			var shared []depUsage
-			for name, repos := range depCount {
-				if len(repos) >= 2 {
-					shared = append(shared, depUsage{name, repos})
-				}
+
+			path := filepath.Join(dir, strings.ReplaceAll(r.Repo.Name, string(filepath.Separator), "-")+f.Extension())
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+				failed = true
+				continue
 			}
-			sort.Slice(shared, func(i, j int) bool {
-				if len(shared[i].repos) != len(shared[j].repos) {
-					return len(shared[i].repos) > len(shared[j].repos)
-				}
-				return shared[i].name < shared[j].name
+			view.Files = append(view.Files, output.SBOMFileView{
+				Repo:       r.Repo.Name,
+				Format:     string(f),
+				Path:       path,
+				Commit:     doc.Source.Commit,
+				Components: len(doc.Components),
 			})
+		}
+	}
 
-			for _, s := range shared {
-				fmt.Printf("  %-40s %v\n", s.name, s.repos)
+	if !toStdout {
+		output.Render("sbom", time.Since(start), view)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func runGraph(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
+	if graphDependents && len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: --dependents needs a repo")
+		os.Exit(1)
+	}
+
+	workspacePath := workspace.DefaultWorkspace()
+	if workspacePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
+		os.Exit(1)
+	}
+
+	repos, err := workspace.Discover(workspacePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
+		os.Exit(1)
+	}
+
+	// --repos narrows the graph; the positional repo picks its focus
+	repos = selectRepos(repos, nil)
+	target := ""
+	if len(args) > 0 {
+		matched := selectRepos(repos, args)
+		if len(matched) != 1 {
+			fmt.Fprintf(os.Stderr, "Error: '%s' matches %d repos; name one\n", args[0], len(matched))
+			os.Exit(1)
+		}
+		target = matched[0].Name
+	}
+
+	g := graph.Build(deps.AnalyzeParallel(repos))
+	elapsed := time.Since(start)
+
+	if graphDependents {
+		dependents := g.Dependents(target)
+		switch {
+		case graphDot:
+			names := []string{target}
+			for _, d := range dependents {
+				names = append(names, d.Repo)
 			}
+			fmt.Print(g.Subgraph(names).DOT())
+		default:
+			output.Render("graph", elapsed, output.NewDependentsView(target, dependents))
+		}
+		return
+	}
+
+	if target != "" {
+		names := []string{target}
+		for _, e := range g.DependenciesOf(target) {
+			names = append(names, e.To)
+		}
+		for _, e := range g.DependentsOf(target) {
+			names = append(names, e.From)
+		}
+		g = g.Subgraph(names)
+	}
+
+	if graphDot {
+		fmt.Print(g.DOT())
+		return
+	}
+	view := output.NewGraphView(g)
+	view.Focus = target
+	output.Render("graph", elapsed, view)
+}
+
+func runLicenses(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
+	var cfg workspace.LicensesConfig
+	if wsCfg, err := workspace.LoadConfig(); err == nil && wsCfg != nil {
+		cfg = wsCfg.Licenses
+	}
+	policy, err := license.NewPolicy(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if licensesFailUnknown {
+		policy.FailOnUnknown = true
+	}
+
+	workspacePath := workspace.DefaultWorkspace()
+	if workspacePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
+		os.Exit(1)
+	}
+
+	repos, err := workspace.Discover(workspacePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
+		os.Exit(1)
+	}
+	repos = selectRepos(repos, args)
+
+	results := deps.AnalyzeParallel(repos)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Repo.Name < results[j].Repo.Name
+	})
+	for _, r := range results {
+		if r.Error != nil {
+			fmt.Fprintf(os.Stderr, "Error analyzing %s: %v\n", r.Repo.Name, r.Error)
 		}
 	}
 
-	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+	report := license.Inventory(results, policy, licensesDev)
+	elapsed := time.Since(start)
+	view := output.NewLicensesView(report, licensesAll)
+
+	output.Render("licenses", elapsed, view)
+
+	if view.Violations > 0 {
+		os.Exit(1)
+	}
 }
 
 func runTree(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
 	path := "."
 	if len(args) == 1 {
 		path = args[0]
@@ -719,10 +1408,12 @@ func runTree(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	fmt.Print(tree.Render(entry, "", true, true))
+	output.Render("tree", time.Since(start), output.NewTreeView(entry))
 }
 
 func runDetect(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
 	path := "."
 	if len(args) == 1 {
 		path = args[0]
@@ -741,14 +1432,31 @@ func runDetect(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	stack := detect.ProjectStack(absPath)
+	// Not a directory: treat the argument as a repo name
+	if info, err := os.Stat(absPath); len(args) == 1 && (err != nil || !info.IsDir()) {
+		repos, err := workspace.Discover(workspace.DefaultWorkspace())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
+			os.Exit(1)
+		}
+		matched := selectRepos(repos, args)
+		if len(matched) != 1 {
+			fmt.Fprintf(os.Stderr, "Error: '%s' matches %d repos; name one\n", args[0], len(matched))
+			os.Exit(1)
+		}
+		absPath = matched[0].Path
+	}
 
-	if len(stack) == 0 {
-		fmt.Println("No project stack detected")
+	if detectExplain {
+		output.Render("detect", time.Since(start), output.NewDetectExplainView(check.Explain(absPath)))
 		return
 	}
 
-	fmt.Printf("Detected: %s\n", strings.Join(stack, ", "))
+	stack := detect.ProjectStack(absPath)
+	if stack == nil {
+		stack = []string{}
+	}
+	output.Render("detect", time.Since(start), output.DetectView{Path: absPath, Stack: stack})
 }
 
 func runWorktrees(cmd *cobra.Command, args []string) {
@@ -766,21 +1474,7 @@ func runWorktrees(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Filter to single repo if specified
-	if len(args) == 1 {
-		target := args[0]
-		var filtered []workspace.RepoInfo
-		for _, r := range repos {
-			if r.Name == target || strings.Contains(r.Name, target) {
-				filtered = append(filtered, r)
-			}
-		}
-		if len(filtered) == 0 {
-			fmt.Fprintf(os.Stderr, "Repository '%s' not found\n", target)
-			os.Exit(1)
-		}
-		repos = filtered
-	}
+	repos = selectRepos(repos, args)
 
 	results := worktrees.ScanParallel(repos)
 	elapsed := time.Since(start)
@@ -790,32 +1484,7 @@ func runWorktrees(cmd *cobra.Command, args []string) {
 		return results[i].Repo.Name < results[j].Repo.Name
 	})
 
-	reposWithWorktrees := 0
-	totalWorktrees := 0
-
-	for _, r := range results {
-		if len(r.Worktrees) == 0 {
-			continue
-		}
-		reposWithWorktrees++
-		totalWorktrees += len(r.Worktrees)
-
-		fmt.Printf("\n%s/\n", r.Repo.Name)
-		for _, wt := range r.Worktrees {
-			status := "clean"
-			if wt.DirtyFiles > 0 {
-				status = fmt.Sprintf("%d modified", wt.DirtyFiles)
-			}
-			fmt.Printf("  .trees/%-25s → %s (%s)\n", wt.Name, wt.Branch, status)
-		}
-	}
-
-	if totalWorktrees == 0 {
-		fmt.Println("\nNo worktrees found")
-	}
-
-	fmt.Printf("\n(%d repos, %d with worktrees, %d total, %.2fs)\n",
-		len(repos), reposWithWorktrees, totalWorktrees, elapsed.Seconds())
+	output.Render("worktrees", elapsed, output.NewWorktreesView(results))
 }
 
 func runMake(cmd *cobra.Command, args []string) {
@@ -833,22 +1502,8 @@ func runMake(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Filter to single repo if specified
 	singleRepo := len(args) == 1
-	if singleRepo {
-		target := args[0]
-		var filtered []workspace.RepoInfo
-		for _, r := range repos {
-			if r.Name == target || strings.Contains(r.Name, target) {
-				filtered = append(filtered, r)
-			}
-		}
-		if len(filtered) == 0 {
-			fmt.Fprintf(os.Stderr, "Repository '%s' not found\n", target)
-			os.Exit(1)
-		}
-		repos = filtered
-	}
+	repos = selectRepos(repos, args)
 
 	results := makefile.ScanParallel(repos)
 	elapsed := time.Since(start)
@@ -858,56 +1513,9 @@ func runMake(cmd *cobra.Command, args []string) {
 		return results[i].Repo.Name < results[j].Repo.Name
 	})
 
-	reposWithMakefiles := 0
-	totalTargets := 0
-
-	for _, r := range results {
-		if len(r.Targets) == 0 {
-			continue
-		}
-		reposWithMakefiles++
-		totalTargets += len(r.Targets)
-
-		if singleRepo {
-			// Detailed view for single repo
-			fmt.Printf("\n%s/%s - %d targets\n\n", r.Repo.Name, r.Path, len(r.Targets))
-
-			groups := makefile.GroupByCategory(r.Targets)
-			for _, cat := range makefile.CategoryOrder() {
-				targets := groups[cat]
-				if len(targets) == 0 {
-					continue
-				}
-
-				var names []string
-				for _, t := range targets {
-					names = append(names, t.Name)
-				}
-				fmt.Printf("  %-10s %s\n", cat+":", strings.Join(names, ", "))
-			}
-		} else if makeTargets {
-			// Show all targets
-			var names []string
-			for _, t := range r.Targets {
-				names = append(names, t.Name)
-			}
-			fmt.Printf("  %-25s %d targets  (%s)\n", r.Repo.Name, len(r.Targets), strings.Join(names, ", "))
-		} else {
-			// Summary view
-			var names []string
-			for i, t := range r.Targets {
-				if i >= 3 {
-					names = append(names, "...")
-					break
-				}
-				names = append(names, t.Name)
-			}
-			fmt.Printf("  %-25s %2d targets  (%s)\n", r.Repo.Name, len(r.Targets), strings.Join(names, ", "))
-		}
-	}
-
-	fmt.Printf("\n(%d repos, %d with Makefiles, %d targets, %.2fs)\n",
-		len(repos), reposWithMakefiles, totalTargets, elapsed.Seconds())
+	view := output.NewMakeView(results)
+	view.Detailed, view.AllTargets = singleRepo, makeTargets
+	output.Render("make", elapsed, view)
 }
 
 func runConfig(cmd *cobra.Command, args []string) {
@@ -925,21 +1533,7 @@ func runConfig(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Filter to single repo if specified
-	if len(args) == 1 {
-		target := args[0]
-		var filtered []workspace.RepoInfo
-		for _, r := range repos {
-			if r.Name == target || strings.Contains(r.Name, target) {
-				filtered = append(filtered, r)
-			}
-		}
-		if len(filtered) == 0 {
-			fmt.Fprintf(os.Stderr, "Repository '%s' not found\n", target)
-			os.Exit(1)
-		}
-		repos = filtered
-	}
+	repos = selectRepos(repos, args)
 
 	results := config.ScanParallel(repos, configType)
 	elapsed := time.Since(start)
@@ -949,32 +1543,17 @@ func runConfig(cmd *cobra.Command, args []string) {
 		return results[i].Repo.Name < results[j].Repo.Name
 	})
 
-	totalFiles := 0
-	reposWithConfigs := 0
-
-	for _, r := range results {
-		// Apply --has filter
-		if configHas != "" && !config.HasConfigType(r.Files, configHas) {
-			continue
-		}
-
-		if len(r.Files) == 0 {
-			continue
-		}
-		reposWithConfigs++
-		totalFiles += len(r.Files)
-
-		fmt.Printf("\n%s/\n", r.Repo.Name)
-
-		// Group by relative path prefix for cleaner output
-		var fileNames []string
-		for _, f := range r.Files {
-			fileNames = append(fileNames, f.RelPath)
+	if configHas != "" {
+		var filtered []config.RepoConfig
+		for _, r := range results {
+			if config.HasConfigType(r.Files, configHas) {
+				filtered = append(filtered, r)
+			}
 		}
-		fmt.Printf("  %s\n", strings.Join(fileNames, ", "))
+		results = filtered
 	}
 
-	fmt.Printf("\n(%d repos, %d config files, %.2fs)\n", reposWithConfigs, totalFiles, elapsed.Seconds())
+	output.Render("config", elapsed, output.NewConfigView(results))
 }
 
 func runTodos(cmd *cobra.Command, args []string) {
@@ -992,21 +1571,7 @@ func runTodos(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Filter to single repo if specified
-	if len(args) == 1 {
-		target := args[0]
-		var filtered []workspace.RepoInfo
-		for _, r := range repos {
-			if r.Name == target || strings.Contains(r.Name, target) {
-				filtered = append(filtered, r)
-			}
-		}
-		if len(filtered) == 0 {
-			fmt.Fprintf(os.Stderr, "Repository '%s' not found\n", target)
-			os.Exit(1)
-		}
-		repos = filtered
-	}
+	repos = selectRepos(repos, args)
 
 	// Normalize type filter to uppercase
 	typeFilter := strings.ToUpper(todosType)
@@ -1019,44 +1584,9 @@ func runTodos(cmd *cobra.Command, args []string) {
 		return results[i].Repo.Name < results[j].Repo.Name
 	})
 
-	totalItems := 0
-	reposWithTodos := 0
-
-	for _, r := range results {
-		if len(r.Items) == 0 {
-			continue
-		}
-		reposWithTodos++
-		totalItems += len(r.Items)
-
-		if todosCount {
-			// Just show counts
-			counts := todos.CountByType(r.Items)
-			parts := []string{}
-			for t, c := range counts {
-				parts = append(parts, fmt.Sprintf("%s:%d", t, c))
-			}
-			fmt.Printf("  %-25s %d items (%s)\n", r.Repo.Name, len(r.Items), strings.Join(parts, ", "))
-		} else {
-			// Show full details
-			fmt.Printf("\n%s/\n", r.Repo.Name)
-			for _, item := range r.Items {
-				text := item.Text
-				if len(text) > 60 {
-					text = text[:57] + "..."
-				}
-				fmt.Printf("  %-40s %s: %s\n",
-					fmt.Sprintf("%s:%d", item.RelPath, item.Line),
-					item.Type,
-					text)
-			}
-		}
-	}
-
-	if todosCount {
-		fmt.Println()
-	}
-	fmt.Printf("\n(%d repos, %d items, %.2fs)\n", reposWithTodos, totalItems, elapsed.Seconds())
+	view := output.NewTodosView(results)
+	view.Count = todosCount
+	output.Render("todos", elapsed, view)
 }
 
 func runStats(cmd *cobra.Command, args []string) {
@@ -1096,59 +1626,7 @@ func runStats(cmd *cobra.Command, args []string) {
 		}
 		elapsed := time.Since(start)
 
-		if dirStats.TotalFiles == 0 {
-			fmt.Println("No source files found")
-			return
-		}
-
-		// Summary
-		fmt.Printf("\n%s/\n", absPath)
-		fmt.Println(strings.Repeat("─", 60))
-		fmt.Printf("  Files:     %d\n", dirStats.TotalFiles)
-		fmt.Printf("  Total:     %d lines\n", dirStats.TotalLines)
-		fmt.Printf("  Code:      %d lines (%.1f%%)\n", dirStats.CodeLines, float64(dirStats.CodeLines)/float64(dirStats.TotalLines)*100)
-		fmt.Printf("  Comments:  %d lines (%.1f%%)\n", dirStats.CommentLines, float64(dirStats.CommentLines)/float64(dirStats.TotalLines)*100)
-		fmt.Printf("  Blank:     %d lines\n", dirStats.BlankLines)
-		fmt.Printf("  Functions: %d (avg %d lines)\n", dirStats.TotalFunctions, dirStats.AvgFuncLength)
-
-		// Complexity flags
-		if len(dirStats.LargeFiles) > 0 {
-			fmt.Printf("\n  ⚠ Large files (>%d lines):\n", stats.LargeFileThreshold)
-			for i, f := range dirStats.LargeFiles {
-				if i >= 5 {
-					fmt.Printf("    ... and %d more\n", len(dirStats.LargeFiles)-5)
-					break
-				}
-				relPath, _ := filepath.Rel(absPath, f.Path)
-				fmt.Printf("    %s (%d lines)\n", relPath, f.TotalLines)
-			}
-		}
-
-		if len(dirStats.LongFunctions) > 0 {
-			fmt.Printf("\n  ⚠ Long functions (>%d lines):\n", stats.LongFunctionThreshold)
-			for i, lf := range dirStats.LongFunctions {
-				if i >= 5 {
-					fmt.Printf("    ... and %d more\n", len(dirStats.LongFunctions)-5)
-					break
-				}
-				relPath, _ := filepath.Rel(absPath, lf.File)
-				fmt.Printf("    %s:%d %s (%d lines)\n", relPath, lf.Function.Line, lf.Function.Name, lf.Function.Lines)
-			}
-		}
-
-		if len(dirStats.DeepNesting) > 0 {
-			fmt.Printf("\n  ⚠ Deep nesting (>%d levels):\n", stats.DeepNestingThreshold)
-			for i, f := range dirStats.DeepNesting {
-				if i >= 5 {
-					fmt.Printf("    ... and %d more\n", len(dirStats.DeepNesting)-5)
-					break
-				}
-				relPath, _ := filepath.Rel(absPath, f.Path)
-				fmt.Printf("    %s (max %d levels)\n", relPath, f.MaxNesting)
-			}
-		}
-
-		fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+		output.Render("stats", elapsed, output.NewDirStatsView(dirStats))
 	} else {
 		// Single file analysis
 		fileStats, err := stats.AnalyzeFile(absPath)
@@ -1158,33 +1636,7 @@ func runStats(cmd *cobra.Command, args []string) {
 		}
 		elapsed := time.Since(start)
 
-		if fileStats.Language == "" {
-			fmt.Println("Unsupported file type")
-			return
-		}
-
-		fmt.Printf("\n%s (%s)\n", absPath, fileStats.Language)
-		fmt.Println(strings.Repeat("─", 60))
-		fmt.Printf("  Total:     %d lines\n", fileStats.TotalLines)
-		fmt.Printf("  Code:      %d lines\n", fileStats.CodeLines)
-		fmt.Printf("  Comments:  %d lines\n", fileStats.CommentLines)
-		fmt.Printf("  Blank:     %d lines\n", fileStats.BlankLines)
-		fmt.Printf("  Imports:   %d\n", fileStats.Imports)
-		fmt.Printf("  Functions: %d\n", len(fileStats.Functions))
-		fmt.Printf("  Max nest:  %d levels\n", fileStats.MaxNesting)
-
-		if len(fileStats.Functions) > 0 {
-			fmt.Printf("\n  Functions:\n")
-			for _, fn := range fileStats.Functions {
-				flag := ""
-				if fn.Lines > stats.LongFunctionThreshold {
-					flag = " ⚠"
-				}
-				fmt.Printf("    L%-4d %-30s %3d lines%s\n", fn.Line, fn.Name, fn.Lines, flag)
-			}
-		}
-
-		fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+		output.Render("stats", elapsed, output.NewFileStatsView(fileStats))
 	}
 }
 
@@ -1228,87 +1680,26 @@ func runDiff(cmd *cobra.Command, args []string) {
 	}
 	elapsed := time.Since(start)
 
-	// Check if there are any changes
-	if len(result.Staged) == 0 && len(result.Unstaged) == 0 {
-		fmt.Printf("\n%s/ (clean)\n", result.Repo.Name)
-		fmt.Printf("  Branch: %s\n", result.Branch)
-		fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
-		return
-	}
-
-	// Header
-	fmt.Printf("\n%s/\n", result.Repo.Name)
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("  Branch:   %s\n", result.Branch)
-
-	// Summary
-	stagedAdd, stagedDel := 0, 0
-	for _, c := range result.Staged {
-		stagedAdd += c.Additions
-		stagedDel += c.Deletions
-	}
-	unstagedAdd, unstagedDel := 0, 0
-	for _, c := range result.Unstaged {
-		unstagedAdd += c.Additions
-		unstagedDel += c.Deletions
-	}
-
-	if len(result.Staged) > 0 {
-		fmt.Printf("  Staged:   %d files (+%d, -%d)\n", len(result.Staged), stagedAdd, stagedDel)
-	}
-	if len(result.Unstaged) > 0 {
-		fmt.Printf("  Unstaged: %d files (+%d, -%d)\n", len(result.Unstaged), unstagedAdd, unstagedDel)
-	}
-
-	// Staged files
-	if len(result.Staged) > 0 {
-		fmt.Printf("\n  Staged:\n")
-		for _, c := range result.Staged {
-			stats := ""
-			if c.Additions > 0 || c.Deletions > 0 {
-				stats = fmt.Sprintf(" (+%d, -%d)", c.Additions, c.Deletions)
-			}
-			fmt.Printf("    %s  %s%s\n", c.Status, c.Path, stats)
-			if diffFull && c.Content != "" {
-				printDiffContent(c.Content)
-			}
-		}
-	}
-
-	// Unstaged files
-	if len(result.Unstaged) > 0 {
-		fmt.Printf("\n  Unstaged:\n")
-		for _, c := range result.Unstaged {
-			stats := ""
-			if c.Additions > 0 || c.Deletions > 0 {
-				stats = fmt.Sprintf(" (+%d, -%d)", c.Additions, c.Deletions)
-			}
-			fmt.Printf("    %s  %s%s\n", c.Status, c.Path, stats)
-			if diffFull && c.Content != "" {
-				printDiffContent(c.Content)
-			}
-		}
-	}
-
-	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
-}
-
-// printDiffContent prints diff content with proper indentation
-func printDiffContent(content string) {
-	fmt.Println()
-	lines := strings.Split(content, "\n")
-	for _, line := range lines {
-		fmt.Printf("      %s\n", line)
-	}
+	view := output.NewDiffView(result)
+	view.Full = diffFull
+	output.Render("diff", elapsed, view)
 }
 
 func runCheckCmd(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
 	workspacePath := workspace.DefaultWorkspace()
 	if workspacePath == "" {
 		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
 		os.Exit(1)
 	}
 
+	cache := check.NewCache(check.DefaultCacheDir(workspacePath))
+	if checkCacheStats || checkCacheClear {
+		runCheckCache(cache, start)
+		return
+	}
+
 	repos, err := workspace.Discover(workspacePath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
@@ -1332,6 +1723,7 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 	}
 
 	// Run prereq checks if requested
+	var prereqResult *prereq.Result
 	if checkPrereq {
 		dir, err := execPkg.ResolveTarget(target)
 		if err != nil {
@@ -1339,112 +1731,86 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 			os.Exit(1)
 		}
 
-		prereqResult := prereq.Run(dir)
-		prereq.Render(prereqResult)
-
+		prereqResult = prereq.Run(dir)
 		if !prereqResult.Passed() {
-			fmt.Fprintln(os.Stderr, "Prereq checks failed. Fix issues before running checks.")
+			// A check result with the prereq failure and no checks run
+			output.Render("check", time.Since(start), output.NewPrereqFailedCheckView(*targetRepo, prereqResult))
+			os.Exit(1)
+		}
+	}
+
+	var reports []report.Spec
+	for _, r := range checkReports {
+		spec, err := report.ParseSpec(r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println() // Blank line between prereq and check output
+		reports = append(reports, spec)
 	}
 
 	// Parse --only flag
 	var only []check.CheckType
 	if checkOnly != "" {
 		for _, c := range strings.Split(checkOnly, ",") {
-			switch strings.TrimSpace(c) {
-			case "lint":
-				only = append(only, check.CheckLint)
-			case "typecheck":
-				only = append(only, check.CheckTypecheck)
-			case "build":
-				only = append(only, check.CheckBuild)
-			case "test":
-				only = append(only, check.CheckTest)
+			// Built-in names map directly; anything else is a custom check from .devbot.yaml
+			if name := strings.TrimSpace(c); name != "" {
+				only = append(only, check.CheckType(name))
 			}
 		}
 	}
 
 	// Run checks
-	result := check.Run(*targetRepo, only, checkFix)
-
-	// Display results
-	fmt.Printf("\n%s/ (%s)\n", result.Repo.Name, result.StackSummary())
-	fmt.Println(strings.Repeat("─", 60))
-
-	if len(result.Checks) == 0 {
-		fmt.Println("  No checks available for this stack")
-		return
+	opts := check.Options{Only: only, Fix: checkFix}
+	if !checkNoCache {
+		opts.Cache = cache
+	}
+	result := check.RunWithOptions(*targetRepo, opts)
+
+	// Write reports before printing so a failed run still produces them
+	var written []string
+	reportFailed := false
+	for _, spec := range reports {
+		if err := report.Write(spec, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s report: %v\n", spec.Format, err)
+			reportFailed = true
+			continue
+		}
+		written = append(written, spec.Path)
 	}
 
-	// Group checks by sub-app
-	type subAppChecks struct {
-		path   string
-		checks []check.CheckResult
+	view := output.NewCheckView(result)
+	view.Reports = written
+	if prereqResult != nil {
+		prereqView := output.NewPrereqView(prereqResult)
+		view.Prereq = &prereqView
 	}
-	var grouped []subAppChecks
-	seen := make(map[string]int)
+	output.Render("check", time.Since(start), view)
 
-	for _, c := range result.Checks {
-		idx, ok := seen[c.SubDir]
-		if !ok {
-			idx = len(grouped)
-			seen[c.SubDir] = idx
-			grouped = append(grouped, subAppChecks{path: c.SubDir})
-		}
-		grouped[idx].checks = append(grouped[idx].checks, c)
+	if !result.Passed() || reportFailed {
+		os.Exit(1)
 	}
+}
 
-	// Display each sub-app's results
-	for _, sg := range grouped {
-		if sg.path != "" {
-			fmt.Printf("\n  %s/\n", sg.path)
-		}
-
-		for _, c := range sg.checks {
-			status := c.Status
-			switch status {
-			case "pass":
-				status = "✓ PASS"
-			case "fail":
-				status = "✗ FAIL"
-			case "skip":
-				status = "- SKIP"
-			}
-
-			duration := ""
-			if c.Duration > 0 {
-				duration = fmt.Sprintf("%.1fs", c.Duration.Seconds())
-			}
-
-			prefix := "  "
-			if sg.path != "" {
-				prefix = "    "
-			}
-			fmt.Printf("%s%-12s %-8s %s\n", prefix, c.Type, status, duration)
-
-			// Show error output for failed checks
-			if c.Status == "fail" && c.Output != "" {
-				lines := strings.Split(c.Output, "\n")
-				maxLines := 10
-				if len(lines) > maxLines {
-					lines = lines[:maxLines]
-					lines = append(lines, fmt.Sprintf("... (%d more lines)", len(strings.Split(c.Output, "\n"))-maxLines))
-				}
-				for _, line := range lines {
-					fmt.Printf("%s  %s\n", prefix, line)
-				}
-			}
+// runCheckCache handles --cache-stats and --cache-clear
+func runCheckCache(cache *check.Cache, start time.Time) {
+	if checkCacheClear {
+		removed, err := cache.Clear()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing cache: %v\n", err)
+			os.Exit(1)
 		}
+		output.Render("check-cache-clear", time.Since(start), output.CacheClearView{Dir: cache.Dir, Removed: removed})
+		return
 	}
 
-	fmt.Printf("\n  %s\n", strings.Repeat("─", 40))
-	fmt.Printf("  Total: %s (%.1fs)\n", result.Summary(), result.Duration.Seconds())
-
-	if !result.Passed() {
+	stats, err := cache.Stats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading cache: %v\n", err)
 		os.Exit(1)
 	}
+
+	output.Render("check-cache-stats", time.Since(start), output.NewCacheStatsView(stats))
 }
 
 func runBranch(cmd *cobra.Command, args []string) {
@@ -1481,42 +1847,7 @@ func runBranch(cmd *cobra.Command, args []string) {
 	result := branch.GetBranch(*targetRepo)
 	elapsed := time.Since(start)
 
-	// Header
-	fmt.Printf("\n%s/\n", result.Repo.Name)
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("  Branch:   %s\n", result.Branch)
-
-	if result.HasUpstream {
-		fmt.Printf("  Tracking: %s\n", result.Tracking)
-	} else if result.Tracking != "" {
-		fmt.Printf("  Remote:   %s\n", result.Tracking)
-	} else {
-		fmt.Printf("  Tracking: (none - new branch)\n")
-	}
-
-	// Ahead/behind
-	if result.Ahead > 0 || result.Behind > 0 {
-		fmt.Printf("  Ahead:    %d commits\n", result.Ahead)
-		fmt.Printf("  Behind:   %d commits\n", result.Behind)
-	}
-
-	// Commits to push
-	if len(result.Commits) > 0 {
-		fmt.Printf("\n  Commits to push:\n")
-		for i, c := range result.Commits {
-			if i >= 10 {
-				fmt.Printf("    ... and %d more\n", len(result.Commits)-10)
-				break
-			}
-			subject := c.Subject
-			if len(subject) > 50 {
-				subject = subject[:47] + "..."
-			}
-			fmt.Printf("    %s %s\n", c.Hash, subject)
-		}
-	}
-
-	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+	output.Render("branch", elapsed, output.NewBranchView(result))
 }
 
 func runRemote(cmd *cobra.Command, args []string) {
@@ -1553,22 +1884,7 @@ func runRemote(cmd *cobra.Command, args []string) {
 	result := remote.GetRemotes(*targetRepo)
 	elapsed := time.Since(start)
 
-	// Header
-	fmt.Printf("\n%s/\n", result.Repo.Name)
-	fmt.Println(strings.Repeat("─", 60))
-
-	if len(result.Remotes) == 0 {
-		fmt.Println("  No remotes configured")
-	} else {
-		for _, r := range result.Remotes {
-			fmt.Printf("  %-10s %s\n", r.Name+":", r.URL)
-			if r.GitHub != "" {
-				fmt.Printf("             GitHub: %s\n", r.GitHub)
-			}
-		}
-	}
-
-	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+	output.Render("remote", elapsed, output.NewRemoteView(result))
 }
 
 func runFindRepo(cmd *cobra.Command, args []string) {
@@ -1590,22 +1906,15 @@ func runFindRepo(cmd *cobra.Command, args []string) {
 	result := remote.FindRepoByGitHub(repos, identifier)
 	elapsed := time.Since(start)
 
+	output.Render("find-repo", elapsed, output.NewFindRepoView(identifier, result))
 	if !result.Found {
-		fmt.Printf("No local repo found for '%s'\n", identifier)
-		fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
 		os.Exit(1)
 	}
-
-	fmt.Printf("\n%s\n", result.Repo.Name)
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("  Path:   %s\n", result.Repo.Path)
-	fmt.Printf("  GitHub: %s\n", result.Remote.GitHub)
-	fmt.Printf("  Remote: %s (%s)\n", result.Remote.Name, result.Remote.URL)
-
-	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
 }
 
 func runPath(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
 	name := args[0]
 
 	workspacePath := workspace.DefaultWorkspace()
@@ -1622,14 +1931,14 @@ func runPath(cmd *cobra.Command, args []string) {
 		if repo.WorkDir != "" {
 			fullPath = filepath.Join(fullPath, repo.WorkDir)
 		}
-		fmt.Println(fullPath)
+		output.Render("path", time.Since(start), output.PathView{Name: name, Path: fullPath})
 		return
 	}
 
 	// Not found - check if directory exists anyway (for repos not in config)
 	directPath := filepath.Join(workspacePath, name)
 	if info, err := os.Stat(directPath); err == nil && info.IsDir() {
-		fmt.Println(directPath)
+		output.Render("path", time.Since(start), output.PathView{Name: name, Path: directPath})
 		return
 	}
 
@@ -1648,6 +1957,8 @@ func runPath(cmd *cobra.Command, args []string) {
 }
 
 func runLastCommit(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
 	workspacePath := workspace.DefaultWorkspace()
 	if workspacePath == "" {
 		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
@@ -1684,8 +1995,7 @@ func runLastCommit(cmd *cobra.Command, args []string) {
 
 	result := lastcommit.GetLastCommit(*targetRepo, file)
 
-	// Output just the relative time (simple output for scripting)
-	fmt.Println(result.RelativeAge)
+	output.Render("last-commit", time.Since(start), output.NewLastCommitView(result))
 }
 
 func runDeploy(cmd *cobra.Command, args []string) {
@@ -1781,84 +2091,21 @@ func runPulumi(cmd *cobra.Command, args []string) {
 	results := pulumiPkg.Check(*targetRepo)
 	elapsed := time.Since(start)
 
-	// Header
-	fmt.Printf("\n%s/ - Pulumi Infrastructure State\n", targetRepo.Name)
-	fmt.Println(strings.Repeat("═", 60))
+	output.Render("pulumi", elapsed, output.NewPulumiView(*targetRepo, results))
+}
 
-	if len(results) == 0 || !results[0].HasPulumiYaml {
-		fmt.Println("  No Pulumi.yaml found in this repository")
-		fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+// streamGit runs git in dir with its output going straight to the terminal,
+// exiting if it fails. git explains its own failures on stderr.
+func streamGit(dir string, args ...string) {
+	err := git.Stream(context.Background(), dir, os.Stdout, os.Stderr, args...)
+	if err == nil {
 		return
 	}
-
-	hasExistingInfra := false
-
-	for _, r := range results {
-		dirLabel := r.PulumiDir
-		if dirLabel == "" {
-			dirLabel = "(root)"
-		}
-
-		fmt.Printf("\n  📁 %s/\n", dirLabel)
-		fmt.Println(strings.Repeat("─", 50))
-
-		if r.Error != nil {
-			fmt.Printf("    ⚠️  Error: %v\n", r.Error)
-			continue
-		}
-
-		// Stacks
-		if len(r.Stacks) == 0 {
-			fmt.Println("    Stacks:    (none)")
-		} else {
-			fmt.Printf("    Stacks:    %s\n", strings.Join(r.Stacks, ", "))
-		}
-
-		// Current stack
-		if r.CurrentStack != "" {
-			fmt.Printf("    Current:   %s\n", r.CurrentStack)
-			fmt.Printf("    Resources: %d\n", r.ResourceCount)
-
-			if r.HasInfra {
-				hasExistingInfra = true
-				fmt.Println()
-				fmt.Println("    ⚠️  INFRASTRUCTURE EXISTS - DO NOT run 'pulumi stack init'")
-			}
-		} else {
-			fmt.Println("    Current:   (no stack selected)")
-			if len(r.Stacks) > 0 {
-				fmt.Printf("\n    ℹ️  Run: pulumi stack select %s\n", r.Stacks[0])
-			}
-		}
-	}
-
-	// Safety guidance
-	fmt.Println()
-	fmt.Println(strings.Repeat("═", 60))
-	if hasExistingInfra {
-		fmt.Println("🛑 EXISTING INFRASTRUCTURE DETECTED")
-		fmt.Println()
-		fmt.Println("   Safe commands:")
-		fmt.Println("     pulumi preview       # See what would change")
-		fmt.Println("     pulumi up            # Apply changes")
-		fmt.Println("     pulumi stack         # Show current state")
-		fmt.Println()
-		fmt.Println("   DANGEROUS - DO NOT RUN:")
-		fmt.Println("     pulumi stack init    # Would orphan existing infra!")
-		fmt.Println("     pulumi destroy       # Would delete everything!")
-		fmt.Println("     pulumi stack rm      # Would lose state!")
-	} else if len(results) > 0 && len(results[0].Stacks) > 0 {
-		fmt.Println("📋 STACKS EXIST - Select one before running commands")
-		fmt.Println()
-		fmt.Printf("   Run: cd <pulumi-dir> && pulumi stack select %s\n", results[0].Stacks[0])
-	} else {
-		fmt.Println("✅ NO EXISTING INFRASTRUCTURE")
-		fmt.Println()
-		fmt.Println("   Safe to initialize:")
-		fmt.Println("     pulumi stack init dev")
+	var gitErr *git.Error
+	if !errors.As(err, &gitErr) || gitErr.Stderr == "" {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 	}
-
-	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+	os.Exit(1)
 }
 
 func runLog(cmd *cobra.Command, args []string) {
@@ -1900,14 +2147,7 @@ func runLog(cmd *cobra.Command, args []string) {
 		gitArgs = append(gitArgs, "--oneline", "-20")
 	}
 
-	gitCmd := exec.Command("git", gitArgs...)
-	gitCmd.Dir = targetRepo.Path
-	gitCmd.Stdout = os.Stdout
-	gitCmd.Stderr = os.Stderr
-
-	if err := gitCmd.Run(); err != nil {
-		os.Exit(1)
-	}
+	streamGit(targetRepo.Path, gitArgs...)
 }
 
 func runShow(cmd *cobra.Command, args []string) {
@@ -1946,17 +2186,49 @@ func runShow(cmd *cobra.Command, args []string) {
 	}
 	// else defaults to HEAD
 
-	gitCmd := exec.Command("git", gitArgs...)
-	gitCmd.Dir = targetRepo.Path
-	gitCmd.Stdout = os.Stdout
-	gitCmd.Stderr = os.Stderr
+	streamGit(targetRepo.Path, gitArgs...)
+}
+
+func runFetch(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
+	workspacePath := workspace.DefaultWorkspace()
+	if workspacePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
+		os.Exit(1)
+	}
+
+	repos, err := workspace.Discover(workspacePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(args) == 0 && reposSelector == "" && !fetchAll {
+		fmt.Fprintln(os.Stderr, "Error: specify a repo, --repos or --all")
+		os.Exit(1)
+	}
+	repos = selectRepos(repos, args)
+
+	results := pull.FetchParallel(repos, fetchJobs)
+	elapsed := time.Since(start)
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Repo.Name < results[j].Repo.Name
+	})
 
-	if err := gitCmd.Run(); err != nil {
+	view := output.NewPullView(results)
+	view.Title = "fetch"
+	output.Render("fetch", elapsed, view)
+
+	if pull.Failed(results) {
 		os.Exit(1)
 	}
 }
 
-func runFetch(cmd *cobra.Command, args []string) {
+func runPull(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
 	workspacePath := workspace.DefaultWorkspace()
 	if workspacePath == "" {
 		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
@@ -1968,35 +2240,56 @@ func runFetch(cmd *cobra.Command, args []string) {
 		fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
 		os.Exit(1)
 	}
+	repos = selectRepos(repos, args)
 
-	// Find the target repo
-	target := args[0]
-	var targetRepo *workspace.RepoInfo
-	for _, r := range repos {
-		if r.Name == target || strings.Contains(strings.ToLower(r.Name), strings.ToLower(target)) {
-			repo := r
-			targetRepo = &repo
-			break
-		}
+	if len(repos) == 0 {
+		fmt.Println("No repositories found in", workspacePath)
+		return
 	}
 
-	if targetRepo == nil {
-		fmt.Fprintf(os.Stderr, "Repository '%s' not found\n", target)
+	results := pull.PullParallel(repos, pullJobs)
+	elapsed := time.Since(start)
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Repo.Name < results[j].Repo.Name
+	})
+
+	view := output.NewPullView(results)
+	view.Title = "pull"
+	output.Render("pull", elapsed, view)
+
+	if pull.Failed(results) {
 		os.Exit(1)
 	}
+}
 
-	fmt.Printf("Fetching %s...\n", targetRepo.Name)
-
-	gitCmd := exec.Command("git", "fetch", "--all", "--prune")
-	gitCmd.Dir = targetRepo.Path
-	gitCmd.Stdout = os.Stdout
-	gitCmd.Stderr = os.Stderr
+func runWatch(cmd *cobra.Command, args []string) {
+	workspacePath := workspace.DefaultWorkspace()
+	if workspacePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
+		os.Exit(1)
+	}
 
-	if err := gitCmd.Run(); err != nil {
+	repos, err := workspace.Discover(workspacePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
 		os.Exit(1)
 	}
+	repos = selectRepos(repos, args)
+
+	if len(repos) == 0 {
+		fmt.Println("No repositories found in", workspacePath)
+		return
+	}
 
-	fmt.Println("Done.")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := watch.Options{Poll: watchPoll, Refresh: watchInterval}
+	if err := watch.Run(ctx, workspacePath, repos, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 }
 
 func runSwitch(cmd *cobra.Command, args []string) {
@@ -2031,14 +2324,7 @@ func runSwitch(cmd *cobra.Command, args []string) {
 	branchName := args[1]
 	fmt.Printf("Switching %s to branch '%s'...\n", targetRepo.Name, branchName)
 
-	gitCmd := exec.Command("git", "switch", branchName)
-	gitCmd.Dir = targetRepo.Path
-	gitCmd.Stdout = os.Stdout
-	gitCmd.Stderr = os.Stderr
-
-	if err := gitCmd.Run(); err != nil {
-		os.Exit(1)
-	}
+	streamGit(targetRepo.Path, "switch", branchName)
 }
 
 func runExec(cmd *cobra.Command, args []string) {
@@ -2069,6 +2355,8 @@ func runExec(cmd *cobra.Command, args []string) {
 }
 
 func runPort(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
 	portNum, err := strconv.Atoi(args[0])
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: invalid port number '%s'\n", args[0])
@@ -2081,32 +2369,26 @@ func runPort(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	if len(processes) == 0 {
-		fmt.Printf("Port %d: nothing running\n", portNum)
-		return
-	}
-
 	// Get unique processes (lsof can return duplicates for same process)
 	unique := portPkg.GetUniqueProcesses(processes)
 
+	view := output.NewPortView(portNum, unique)
 	if portKill {
-		for _, p := range unique {
-			fmt.Printf("Killing %s (PID %d) on port %d...\n", p.Command, p.PID, portNum)
+		view.Kill = true
+		for i, p := range unique {
 			if err := portPkg.Kill(p.PID); err != nil {
-				fmt.Fprintf(os.Stderr, "  Failed to kill PID %d: %v\n", p.PID, err)
+				view.Processes[i].Error = err.Error()
 			} else {
-				fmt.Printf("  Killed PID %d\n", p.PID)
+				view.Processes[i].Killed = true
 			}
 		}
-	} else {
-		fmt.Printf("Port %d:\n", portNum)
-		for _, p := range unique {
-			fmt.Printf("  %s (PID %d) - %s\n", p.Command, p.PID, p.User)
-		}
 	}
+	output.Render("port", time.Since(start), view)
 }
 
 func runPrereq(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
 	target := args[0]
 
 	// Resolve target to directory (reuse exec's resolution)
@@ -2117,7 +2399,7 @@ func runPrereq(cmd *cobra.Command, args []string) {
 	}
 
 	result := prereq.Run(dir)
-	prereq.Render(result)
+	output.Render("prereq", time.Since(start), output.NewPrereqView(result))
 
 	if !result.Passed() {
 		os.Exit(1)
@@ -2129,3 +2411,47 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func runSync(cmd *cobra.Command, args []string) {
+	start := time.Now()
+
+	workspacePath := workspace.DefaultWorkspace()
+	if workspacePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: could not determine home directory")
+		os.Exit(1)
+	}
+
+	cfg, err := workspace.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(workspacePath, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating workspace: %v\n", err)
+		os.Exit(1)
+	}
+
+	repos, err := workspace.Discover(workspacePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering repos: %v\n", err)
+		os.Exit(1)
+	}
+
+	results := reposync.Sync(workspacePath, cfg, repos, reposync.Options{
+		Jobs:   syncJobs,
+		Add:    syncAdd,
+		DryRun: syncDryRun,
+	})
+	elapsed := time.Since(start)
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	output.Render("sync", elapsed, output.NewSyncView(results))
+
+	if reposync.Failed(results) {
+		os.Exit(1)
+	}
+}
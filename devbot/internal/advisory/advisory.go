@@ -0,0 +1,274 @@
+// Package advisory matches dependencies against a local copy of OSV-format
+// vulnerability advisories (https://ossf.github.io/osv-schema/). The
+// database is a directory of advisory JSON files filled by Import, so audits
+// never touch the network.
+package advisory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+)
+
+// Advisory is the subset of an OSV record used for matching and reporting
+type Advisory struct {
+	ID               string         `json:"id"`
+	Summary          string         `json:"summary"`
+	Details          string         `json:"details"`
+	Aliases          []string       `json:"aliases"`
+	Modified         time.Time      `json:"modified"`
+	Published        time.Time      `json:"published"`
+	Withdrawn        *time.Time     `json:"withdrawn,omitempty"`
+	Severity         []Severity     `json:"severity"`
+	Affected         []Affected     `json:"affected"`
+	DatabaseSpecific map[string]any `json:"database_specific"`
+}
+
+// Severity is a scored severity, e.g. {"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/..."}
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected lists the affected versions of one package
+type Affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges            []Range        `json:"ranges"`
+	Versions          []string       `json:"versions"`
+	DatabaseSpecific  map[string]any `json:"database_specific"`
+	EcosystemSpecific map[string]any `json:"ecosystem_specific"`
+}
+
+// Range is a sequence of introduced/fixed/last_affected events
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event is one point in a Range; exactly one field is set
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// osvEcosystems maps OSV ecosystem names to deps ecosystems
+var osvEcosystems = map[string]string{
+	"npm":       deps.EcosystemNPM,
+	"Go":        deps.EcosystemGo,
+	"PyPI":      deps.EcosystemPyPI,
+	"crates.io": deps.EcosystemCrates,
+}
+
+// DB is an in-memory index of advisories by ecosystem and package
+type DB struct {
+	Dir        string
+	Advisories int
+	byPackage  map[string][]*Advisory
+}
+
+// DefaultDir returns the advisory database location under the workspace
+func DefaultDir(workspacePath string) string {
+	return filepath.Join(workspacePath, ".devbot", "advisories")
+}
+
+// Open loads every advisory under dir. A missing directory is an empty DB.
+func Open(dir string) (*DB, error) {
+	db := &DB{Dir: dir, byPackage: make(map[string][]*Advisory)}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") || d.Name() == metaFile {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var adv Advisory
+		if err := json.Unmarshal(data, &adv); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		db.add(&adv)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *DB) add(adv *Advisory) {
+	if adv.Withdrawn != nil {
+		return
+	}
+	added := false
+	for _, a := range adv.Affected {
+		eco, ok := osvEcosystems[a.Package.Ecosystem]
+		if !ok {
+			continue
+		}
+		key := packageKey(eco, a.Package.Name)
+		if list := db.byPackage[key]; len(list) == 0 || list[len(list)-1] != adv {
+			db.byPackage[key] = append(list, adv)
+		}
+		added = true
+	}
+	if added {
+		db.Advisories++
+	}
+}
+
+// packageKey normalizes names the way each registry compares them
+func packageKey(ecosystem, name string) string {
+	if ecosystem == deps.EcosystemPyPI {
+		name = strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+	}
+	return ecosystem + " " + name
+}
+
+// Match is an advisory that affects a specific package version
+type Match struct {
+	Advisory *Advisory
+	Severity Level
+	Fixed    []string // Versions that fix it, ascending
+}
+
+// Query returns the advisories affecting name at version, sorted by
+// severity (worst first), then ID
+func (db *DB) Query(ecosystem, name, version string) []Match {
+	var matches []Match
+	for _, adv := range db.byPackage[packageKey(ecosystem, name)] {
+		for _, a := range adv.Affected {
+			if osvEcosystems[a.Package.Ecosystem] != ecosystem || packageKey(ecosystem, a.Package.Name) != packageKey(ecosystem, name) {
+				continue
+			}
+			if a.affects(version) {
+				matches = append(matches, Match{Advisory: adv, Severity: adv.level(a), Fixed: a.fixed()})
+				break
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Severity != matches[j].Severity {
+			return matches[i].Severity > matches[j].Severity
+		}
+		return matches[i].Advisory.ID < matches[j].Advisory.ID
+	})
+	return matches
+}
+
+// affects reports whether version is listed or falls in a SEMVER or
+// ECOSYSTEM range. GIT ranges (commit hashes) can't be matched to versions.
+func (a Affected) affects(version string) bool {
+	compare := deps.CompareFor(osvEcosystems[a.Package.Ecosystem])
+	v := strings.TrimPrefix(version, "v")
+	for _, listed := range a.Versions {
+		if strings.TrimPrefix(listed, "v") == v {
+			return true
+		}
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+		if r.contains(version, compare) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains evaluates the range's events in version order: an introduced
+// at or below version turns it on, a fixed at or below version (or a
+// last_affected below it) turns it off. Versions are ordered by compare.
+func (r Range) contains(version string, compare func(a, b string) int) bool {
+	events := append([]Event(nil), r.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return compareEvent(events[i].version(), events[j].version(), compare) < 0
+	})
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if compareEvent(e.Introduced, version, compare) <= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if compareEvent(e.Fixed, version, compare) <= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if compareEvent(e.LastAffected, version, compare) < 0 {
+				affected = false
+			}
+		case e.Limit != "":
+			if compareEvent(e.Limit, version, compare) <= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+func (e Event) version() string {
+	for _, v := range []string{e.Introduced, e.Fixed, e.LastAffected, e.Limit} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// compareEvent compares versions, treating "0" (introduced at the very
+// first version) as below everything
+func compareEvent(a, b string, compare func(a, b string) int) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "0":
+		return -1
+	case b == "0":
+		return 1
+	}
+	return compare(a, b)
+}
+
+// fixed returns the fix versions across all ranges, ascending
+func (a Affected) fixed() []string {
+	var fixed []string
+	seen := make(map[string]bool)
+	for _, r := range a.Ranges {
+		for _, e := range r.Events {
+			if e.Fixed != "" && !seen[e.Fixed] {
+				seen[e.Fixed] = true
+				fixed = append(fixed, e.Fixed)
+			}
+		}
+	}
+	compare := deps.CompareFor(osvEcosystems[a.Package.Ecosystem])
+	sort.Slice(fixed, func(i, j int) bool {
+		return compare(fixed[i], fixed[j]) < 0
+	})
+	return fixed
+}
@@ -0,0 +1,275 @@
+package advisory
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+const fixtures = "testdata/osv"
+
+// openFixtures imports the fixture advisories into a fresh database
+func openFixtures(t *testing.T) *DB {
+	t.Helper()
+	dir := t.TempDir()
+	if _, err := Import(fixtures, dir); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return db
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+
+	stats, err := Import(fixtures, dir)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if want := (ImportStats{Imported: 6, Unsupported: 1, Invalid: 1}); stats != want {
+		t.Errorf("first import = %+v, want %+v", stats, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "crates", "RUSTSEC-2021-0003.json")); err != nil {
+		t.Errorf("advisory not stored by ecosystem: %v", err)
+	}
+	meta, ok := ReadMeta(dir)
+	if !ok || meta.ImportedAt.IsZero() || len(meta.Sources) != 1 {
+		t.Errorf("meta = %+v, %v", meta, ok)
+	}
+
+	// Importing again changes nothing
+	stats, err = Import(fixtures, dir)
+	if err != nil {
+		t.Fatalf("second Import failed: %v", err)
+	}
+	if stats.Imported != 0 || stats.Unchanged != 6 {
+		t.Errorf("second import = %+v, want 6 unchanged", stats)
+	}
+
+	// Withdrawn advisories are stored but never match
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if db.Advisories != 5 {
+		t.Errorf("Advisories = %d, want 5", db.Advisories)
+	}
+}
+
+func TestImportZip(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for _, name := range []string{"npm/GHSA-35jh-r3h4-6jhm.json", "go/GO-2022-1059.json"} {
+		data, err := os.ReadFile(filepath.Join(fixtures, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := w.Create(filepath.Base(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := entry.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	stats, err := Import(archive, dir)
+	if err != nil {
+		t.Fatalf("Import zip failed: %v", err)
+	}
+	if stats.Imported != 2 {
+		t.Errorf("Imported = %d, want 2", stats.Imported)
+	}
+
+	if _, err := Import(filepath.Join(t.TempDir(), "missing.zip"), dir); err == nil {
+		t.Error("Import of a missing source should fail")
+	}
+}
+
+func TestOpenMissing(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "none"))
+	if err != nil {
+		t.Fatalf("Open of a missing dir should succeed: %v", err)
+	}
+	if db.Advisories != 0 {
+		t.Errorf("Advisories = %d, want 0", db.Advisories)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	db := openFixtures(t)
+
+	tests := []struct {
+		ecosystem, name, version string
+		want                     string // Advisory ID or "" for none
+		severity                 Level
+		fixed                    string
+	}{
+		{deps.EcosystemNPM, "lodash", "4.17.20", "GHSA-35jh-r3h4-6jhm", LevelHigh, "4.17.21"},
+		{deps.EcosystemNPM, "lodash", "4.17.21", "", 0, ""},
+		{deps.EcosystemNPM, "react", "18.3.1", "", 0, ""}, // Withdrawn
+		{deps.EcosystemGo, "golang.org/x/text", "v0.3.7", "GO-2022-1059", LevelUnknown, "0.3.8"},
+		{deps.EcosystemGo, "golang.org/x/text", "v0.14.0", "", 0, ""},
+		{deps.EcosystemPyPI, "requests", "2.30.0", "PYSEC-2023-74", LevelMedium, "2.31.0"},
+		{deps.EcosystemPyPI, "requests", "2.2.1", "", 0, ""}, // Before introduced
+		{deps.EcosystemPyPI, "typing-extensions", "4.5.0", "GHSA-last-affected", LevelLow, ""},
+		{deps.EcosystemPyPI, "typing-extensions", "4.5.1", "", 0, ""},
+		{deps.EcosystemCrates, "smallvec", "0.6.13", "RUSTSEC-2021-0003", LevelCritical, "0.6.14"},
+		{deps.EcosystemCrates, "smallvec", "0.6.14", "", 0, ""}, // Between ranges
+		{deps.EcosystemCrates, "smallvec", "1.6.0", "RUSTSEC-2021-0003", LevelCritical, "0.6.14"},
+		{deps.EcosystemCrates, "smallvec", "1.6.1", "", 0, ""},
+		{deps.EcosystemNPM, "smallvec", "0.6.13", "", 0, ""}, // Wrong ecosystem
+	}
+
+	for _, tt := range tests {
+		matches := db.Query(tt.ecosystem, tt.name, tt.version)
+		if tt.want == "" {
+			if len(matches) != 0 {
+				t.Errorf("Query(%s %s@%s) = %s, want none", tt.ecosystem, tt.name, tt.version, matches[0].Advisory.ID)
+			}
+			continue
+		}
+		if len(matches) != 1 || matches[0].Advisory.ID != tt.want {
+			t.Errorf("Query(%s %s@%s) = %v, want %s", tt.ecosystem, tt.name, tt.version, matches, tt.want)
+			continue
+		}
+		m := matches[0]
+		if m.Severity != tt.severity {
+			t.Errorf("%s severity = %s, want %s", tt.want, m.Severity, tt.severity)
+		}
+		if fixed := strings.Join(m.Fixed, ","); tt.fixed != "" && !strings.HasPrefix(fixed, tt.fixed) {
+			t.Errorf("%s fixed = %s, want %s first", tt.want, fixed, tt.fixed)
+		}
+	}
+}
+
+func TestAffectsPyPIPostRelease(t *testing.T) {
+	var a Affected
+	a.Package.Ecosystem = "PyPI"
+	a.Package.Name = "example"
+	a.Ranges = []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "1.0.post1"}}}}
+
+	// Under semver "1.0.post1" would be a prerelease of 1.0 and 1.0 fixed
+	for version, want := range map[string]bool{
+		"0.9":       true,
+		"1.0rc1":    true,
+		"1.0":       true,
+		"1.0.post1": false,
+		"1.0.1":     false,
+	} {
+		if got := a.affects(version); got != want {
+			t.Errorf("affects(%s) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestCVSS3Score(t *testing.T) {
+	tests := map[string]float64{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": 9.8,
+		"CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:C/C:H/I:N/A:N": 6.1,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N": 6.1,
+		"CVSS:3.0/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H": 7.8,
+		"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H": 9.9,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N": 0,
+	}
+	for vector, want := range tests {
+		got, err := cvss3Score(vector)
+		if err != nil {
+			t.Errorf("cvss3Score(%s) failed: %v", vector, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("cvss3Score(%s) = %.1f, want %.1f", vector, got, want)
+		}
+	}
+
+	for _, bad := range []string{"CVSS:2.0/AV:N", "CVSS:3.1/AV:N/AC:L", "garbage"} {
+		if _, err := cvss3Score(bad); err == nil {
+			t.Errorf("cvss3Score(%q) should fail", bad)
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	db := openFixtures(t)
+
+	results := []deps.RepoDeps{
+		{Repo: workspace.RepoInfo{Name: "web"}, Dependencies: []deps.Dependency{
+			{Name: "lodash", Version: "^4.17.0", Resolved: "4.17.20", Ecosystem: deps.EcosystemNPM},
+			{Name: "react", Version: "^18.2.0", Ecosystem: deps.EcosystemNPM}, // No lockfile
+		}},
+		{Repo: workspace.RepoInfo{Name: "api"}, Dependencies: []deps.Dependency{
+			{Name: "golang.org/x/text", Version: "v0.3.7", Indirect: true, Ecosystem: deps.EcosystemGo},
+			{Name: "requests", Version: "==2.30.0", Ecosystem: deps.EcosystemPyPI},
+			{Name: "smallvec", Resolved: "1.6.0", Transitive: true, Ecosystem: deps.EcosystemCrates},
+		}},
+	}
+
+	report := Audit(db, results, LevelUnknown)
+	if report.Checked != 4 || report.Unresolved != 1 {
+		t.Errorf("Checked/Unresolved = %d/%d, want 4/1", report.Checked, report.Unresolved)
+	}
+
+	var got []string
+	for _, f := range report.Findings {
+		got = append(got, f.Repo+" "+f.Dependency.Name+"@"+f.Version+" "+f.Severity.String()+" fixed "+f.FixedIn())
+	}
+	want := []string{
+		"api smallvec@1.6.0 critical fixed 1.6.1",
+		"web lodash@4.17.20 high fixed 4.17.21",
+		"api requests@2.30.0 medium fixed 2.31.0",
+		"api golang.org/x/text@v0.3.7 unknown fixed 0.3.8",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if report := Audit(db, results, LevelHigh); len(report.Findings) != 2 {
+		t.Errorf("high and above = %d findings, want 2", len(report.Findings))
+	}
+}
+
+func TestAuditGoReplace(t *testing.T) {
+	db := openFixtures(t)
+
+	results := []deps.RepoDeps{
+		{Repo: workspace.RepoInfo{Name: "api"}, Dependencies: []deps.Dependency{
+			// A fork at a vulnerable version is what gets built
+			{Name: "example.com/text", Version: "v0.14.0", Replace: "golang.org/x/text v0.3.7", Resolved: "v0.3.7", Ecosystem: deps.EcosystemGo},
+			// The original path at a fixed version would hide it
+			{Name: "golang.org/x/text", Version: "v0.14.0", Replace: "example.com/fork v0.3.7", Resolved: "v0.3.7", Ecosystem: deps.EcosystemGo},
+			// A local directory has no version to match
+			{Name: "golang.org/x/text", Version: "v0.3.7", Replace: "../text", Ecosystem: deps.EcosystemGo},
+		}},
+	}
+
+	report := Audit(db, results, LevelUnknown)
+	if report.Checked != 2 || report.Unresolved != 1 {
+		t.Errorf("Checked/Unresolved = %d/%d, want 2/1", report.Checked, report.Unresolved)
+	}
+	if len(report.Findings) != 1 {
+		t.Fatalf("findings = %+v, want only the replacement module", report.Findings)
+	}
+	if f := report.Findings[0]; f.Package != "golang.org/x/text" || f.Dependency.Name != "example.com/text" || f.Version != "v0.3.7" {
+		t.Errorf("finding = %s@%s for %s, want golang.org/x/text@v0.3.7 for example.com/text", f.Package, f.Version, f.Dependency.Name)
+	}
+}
@@ -0,0 +1,95 @@
+package advisory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+)
+
+// Finding is one advisory affecting one dependency of one repo
+type Finding struct {
+	Repo       string
+	Dependency deps.Dependency
+	Package    string // The package that was matched: a Go replace's module, else Dependency.Name
+	Version    string // The version that was matched
+	Match
+}
+
+// Report is the outcome of auditing a set of repos
+type Report struct {
+	Findings   []Finding
+	Checked    int // Dependencies matched against the database
+	Unresolved int // Dependencies skipped for lack of an exact version (ranges, local replaces)
+}
+
+// Audit matches every dependency (direct and transitive) with a known
+// version against db, keeping findings at or above minLevel. A Go module
+// with a replace directive is audited as its replacement; one replaced by a
+// local directory has no version and is skipped. Findings are sorted worst
+// first, then by repo and package.
+func Audit(db *DB, results []deps.RepoDeps, minLevel Level) Report {
+	var report Report
+	for _, r := range results {
+		seen := make(map[string]bool)
+		for _, d := range r.Dependencies {
+			name, version := auditTarget(d)
+			if version == "" { // Ranges without a lockfile can't be audited
+				report.Unresolved++
+				continue
+			}
+			key := d.Ecosystem + " " + name + "@" + version
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			report.Checked++
+
+			for _, m := range db.Query(d.Ecosystem, name, version) {
+				if m.Severity < minLevel {
+					continue
+				}
+				report.Findings = append(report.Findings, Finding{Repo: r.Repo.Name, Dependency: d, Package: name, Version: version, Match: m})
+			}
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Advisory.ID < b.Advisory.ID
+	})
+	return report
+}
+
+// auditTarget returns the package and exact version that d puts in the
+// build. The version is "" when there isn't one to match.
+func auditTarget(d deps.Dependency) (name, version string) {
+	if d.Ecosystem == deps.EcosystemGo && d.Replace != "" {
+		// "example.com/fork v1.2.0", or a bare directory
+		if fields := strings.Fields(d.Replace); len(fields) == 2 {
+			return fields[0], fields[1]
+		}
+		return d.Name, ""
+	}
+	return d.Name, d.Exact()
+}
+
+// FixedIn returns the lowest fix version above version, or "" if none
+func (f Finding) FixedIn() string {
+	compare := deps.CompareFor(f.Dependency.Ecosystem)
+	for _, fixed := range f.Fixed {
+		if compare(fixed, f.Version) > 0 {
+			return fixed
+		}
+	}
+	return ""
+}
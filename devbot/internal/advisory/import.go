@@ -0,0 +1,188 @@
+package advisory
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// metaFile records when the database was last imported
+const metaFile = "meta.json"
+
+// ImportStats summarizes an import
+type ImportStats struct {
+	Imported    int // Written (new or updated)
+	Unchanged   int // Already present and not modified since
+	Unsupported int // No affected package in a supported ecosystem
+	Invalid     int // Not OSV JSON
+}
+
+// Meta is the database's import record
+type Meta struct {
+	ImportedAt time.Time `json:"imported_at"`
+	Sources    []string  `json:"sources"`
+}
+
+// ReadMeta returns the import record for the database in dir, if any
+func ReadMeta(dir string) (Meta, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, metaFile))
+	if err != nil {
+		return Meta{}, false
+	}
+	var m Meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Meta{}, false
+	}
+	return m, true
+}
+
+// Import copies OSV advisories from src into the database at dir. src is a
+// directory of JSON files (searched recursively) or a zip archive such as
+// the per-ecosystem all.zip exports from osv.dev.
+func Import(src, dir string) (ImportStats, error) {
+	var stats ImportStats
+	info, err := os.Stat(src)
+	if err != nil {
+		return stats, err
+	}
+
+	write := func(name string, data []byte) error {
+		return importOne(dir, name, data, &stats)
+	}
+
+	if info.IsDir() {
+		err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".json") {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return write(path, data)
+		})
+	} else {
+		err = importZip(src, write)
+	}
+	if err != nil {
+		return stats, err
+	}
+
+	meta, _ := ReadMeta(dir)
+	meta.ImportedAt = time.Now().UTC()
+	if abs, err := filepath.Abs(src); err == nil {
+		src = abs
+	}
+	meta.Sources = appendUnique(meta.Sources, src)
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return stats, err
+	}
+	return stats, writeAtomic(filepath.Join(dir, metaFile), data)
+}
+
+func importZip(path string, write func(name string, data []byte) error) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+		if err := write(f.Name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importOne validates an advisory and stores it as <ecosystem>/<id>.json,
+// keeping whichever copy was modified most recently
+func importOne(dir, name string, data []byte, stats *ImportStats) error {
+	var adv Advisory
+	if err := json.Unmarshal(data, &adv); err != nil || adv.ID == "" || strings.ContainsAny(adv.ID, `/\`) {
+		stats.Invalid++
+		return nil
+	}
+
+	ecosystem := ""
+	for _, a := range adv.Affected {
+		if _, ok := osvEcosystems[a.Package.Ecosystem]; ok {
+			ecosystem = osvEcosystems[a.Package.Ecosystem]
+			break
+		}
+	}
+	if ecosystem == "" {
+		stats.Unsupported++
+		return nil
+	}
+
+	dest := filepath.Join(dir, ecosystem, adv.ID+".json")
+	if existing, err := os.ReadFile(dest); err == nil {
+		var old Advisory
+		if json.Unmarshal(existing, &old) == nil && !adv.Modified.After(old.Modified) {
+			stats.Unchanged++
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	if err := writeAtomic(dest, data); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	stats.Imported++
+	return nil
+}
+
+// writeAtomic writes via a temp file so readers never see partial files
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,149 @@
+package advisory
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Level is a normalized severity
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelLow
+	LevelMedium
+	LevelHigh
+	LevelCritical
+)
+
+var levelNames = []string{"unknown", "low", "medium", "high", "critical"}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel reads a severity name. GitHub's "moderate" is medium.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "critical":
+		return LevelCritical, nil
+	case "high":
+		return LevelHigh, nil
+	case "medium", "moderate":
+		return LevelMedium, nil
+	case "low":
+		return LevelLow, nil
+	case "unknown", "":
+		return LevelUnknown, nil
+	}
+	return LevelUnknown, fmt.Errorf("invalid severity %q (want critical, high, medium, low or unknown)", s)
+}
+
+// level picks the best severity available: a label from the database
+// (GitHub's "HIGH", "MODERATE"), else a CVSS v3 base score
+func (adv *Advisory) level(a Affected) Level {
+	for _, m := range []map[string]any{a.DatabaseSpecific, a.EcosystemSpecific, adv.DatabaseSpecific} {
+		if s, ok := m["severity"].(string); ok {
+			if l, err := ParseLevel(s); err == nil && l != LevelUnknown {
+				return l
+			}
+		}
+	}
+	for _, s := range adv.Severity {
+		if s.Type == "CVSS_V3" {
+			if score, err := cvss3Score(s.Score); err == nil {
+				return scoreLevel(score)
+			}
+		}
+	}
+	return LevelUnknown
+}
+
+// scoreLevel maps a CVSS score to its qualitative rating
+func scoreLevel(score float64) Level {
+	switch {
+	case score >= 9:
+		return LevelCritical
+	case score >= 7:
+		return LevelHigh
+	case score >= 4:
+		return LevelMedium
+	case score > 0:
+		return LevelLow
+	}
+	return LevelUnknown
+}
+
+// cvss3Weights are the CVSS v3.x base metric values
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvss3Score computes the base score of a CVSS v3.0/v3.1 vector such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+func cvss3Score(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, fmt.Errorf("not a CVSS v3 vector: %q", vector)
+	}
+
+	metrics := make(map[string]string)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, ":"); ok {
+			metrics[k] = v
+		}
+	}
+
+	scope := metrics["S"]
+	if scope != "U" && scope != "C" {
+		return 0, fmt.Errorf("missing scope in %q", vector)
+	}
+	w := make(map[string]float64)
+	for metric, values := range cvss3Weights {
+		v, ok := values[metrics[metric]]
+		if !ok {
+			return 0, fmt.Errorf("missing %s in %q", metric, vector)
+		}
+		w[metric] = v
+	}
+	// Privileges weigh more when the scope changes
+	if scope == "C" {
+		switch metrics["PR"] {
+		case "L":
+			w["PR"] = 0.68
+		case "H":
+			w["PR"] = 0.5
+		}
+	}
+
+	iss := 1 - (1-w["C"])*(1-w["I"])*(1-w["A"])
+	impact := 6.42 * iss
+	if scope == "C" {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+
+	exploitability := 8.22 * w["AV"] * w["AC"] * w["PR"] * w["UI"]
+	if scope == "C" {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUp rounds to one decimal upwards, as defined in CVSS v3.1 appendix A
+func roundUp(x float64) float64 {
+	n := int64(math.Round(x * 100000))
+	if n%10000 == 0 {
+		return float64(n) / 100000
+	}
+	return float64(n/10000+1) / 10
+}
@@ -1,11 +1,11 @@
 package branch
 
 import (
-	"bytes"
-	"os/exec"
+	"errors"
 	"strconv"
 	"strings"
 
+	"github.com/sloanahrens/devbot-go/internal/git"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
@@ -31,19 +31,24 @@ type BranchResult struct {
 func GetBranch(repo workspace.RepoInfo) BranchResult {
 	result := BranchResult{Repo: repo}
 
-	// Get current branch
-	result.Branch = gitCommand(repo.Path, "rev-parse", "--abbrev-ref", "HEAD")
+	// Get current branch; an empty repo has no HEAD yet but is otherwise fine
+	branch, err := git.Output(repo.Path, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil && !errors.Is(err, git.ErrNoCommits) {
+		result.Error = err
+		return result
+	}
+	result.Branch = branch
 	if result.Branch == "" {
 		result.Branch = "(detached)"
 	}
 
 	// Get upstream tracking branch
-	result.Tracking = gitCommand(repo.Path, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	result.Tracking, _ = git.Output(repo.Path, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
 	result.HasUpstream = result.Tracking != ""
 
 	if result.HasUpstream {
 		// Get ahead/behind counts
-		revList := gitCommand(repo.Path, "rev-list", "--left-right", "--count", result.Tracking+"...HEAD")
+		revList, _ := git.Output(repo.Path, "rev-list", "--left-right", "--count", result.Tracking+"...HEAD")
 		if revList != "" {
 			parts := strings.Fields(revList)
 			if len(parts) == 2 {
@@ -54,7 +59,7 @@ func GetBranch(repo workspace.RepoInfo) BranchResult {
 
 		// Get commits ahead (to push)
 		if result.Ahead > 0 {
-			logOutput := gitCommand(repo.Path, "log", "--oneline", result.Tracking+"..HEAD")
+			logOutput, _ := git.Output(repo.Path, "log", "--oneline", result.Tracking+"..HEAD")
 			if logOutput != "" {
 				for _, line := range strings.Split(logOutput, "\n") {
 					if line == "" {
@@ -72,7 +77,7 @@ func GetBranch(repo workspace.RepoInfo) BranchResult {
 	} else {
 		// No upstream - check if remote exists with same branch name
 		remoteBranch := "origin/" + result.Branch
-		exists := gitCommand(repo.Path, "rev-parse", "--verify", "--quiet", remoteBranch)
+		exists, _ := git.Output(repo.Path, "rev-parse", "--verify", "--quiet", remoteBranch)
 		if exists != "" {
 			// Remote branch exists but not tracking
 			result.Tracking = remoteBranch + " (not tracking)"
@@ -81,14 +86,14 @@ func GetBranch(repo workspace.RepoInfo) BranchResult {
 		// Count all commits on branch (for new branches)
 		mainBranch := getMainBranch(repo.Path)
 		if mainBranch != "" && mainBranch != result.Branch {
-			revList := gitCommand(repo.Path, "rev-list", "--count", mainBranch+"..HEAD")
+			revList, _ := git.Output(repo.Path, "rev-list", "--count", mainBranch+"..HEAD")
 			if revList != "" {
 				result.Ahead, _ = strconv.Atoi(revList)
 			}
 
 			// Get commits
 			if result.Ahead > 0 {
-				logOutput := gitCommand(repo.Path, "log", "--oneline", mainBranch+"..HEAD")
+				logOutput, _ := git.Output(repo.Path, "log", "--oneline", mainBranch+"..HEAD")
 				if logOutput != "" {
 					for _, line := range strings.Split(logOutput, "\n") {
 						if line == "" {
@@ -112,18 +117,18 @@ func GetBranch(repo workspace.RepoInfo) BranchResult {
 // getMainBranch determines the main branch (main or master)
 func getMainBranch(repoPath string) string {
 	// Check for origin/main first
-	if gitCommand(repoPath, "rev-parse", "--verify", "--quiet", "origin/main") != "" {
+	if _, err := git.Output(repoPath, "rev-parse", "--verify", "--quiet", "origin/main"); err == nil {
 		return "origin/main"
 	}
 	// Fall back to origin/master
-	if gitCommand(repoPath, "rev-parse", "--verify", "--quiet", "origin/master") != "" {
+	if _, err := git.Output(repoPath, "rev-parse", "--verify", "--quiet", "origin/master"); err == nil {
 		return "origin/master"
 	}
 	// Check local branches
-	if gitCommand(repoPath, "rev-parse", "--verify", "--quiet", "main") != "" {
+	if _, err := git.Output(repoPath, "rev-parse", "--verify", "--quiet", "main"); err == nil {
 		return "main"
 	}
-	if gitCommand(repoPath, "rev-parse", "--verify", "--quiet", "master") != "" {
+	if _, err := git.Output(repoPath, "rev-parse", "--verify", "--quiet", "master"); err == nil {
 		return "master"
 	}
 	return ""
@@ -143,18 +148,3 @@ func (b *BranchResult) NeedsPull() bool {
 func (b *BranchResult) IsNewBranch() bool {
 	return !b.HasUpstream
 }
-
-func gitCommand(dir string, args ...string) string {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = nil
-
-	if err := cmd.Run(); err != nil {
-		return ""
-	}
-
-	return strings.TrimSpace(out.String())
-}
@@ -0,0 +1,168 @@
+package check
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Cache stores passing check results keyed by a content fingerprint.
+// Each entry is a small JSON file named after its key.
+type Cache struct {
+	Dir string
+}
+
+// CacheStats summarizes the contents of a Cache
+type CacheStats struct {
+	Dir     string
+	Entries int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// cacheEntry is the on-disk format of a cached check result
+type cacheEntry struct {
+	Key        string      `json:"key"`
+	Type       CheckType   `json:"type"`
+	SubDir     string      `json:"sub_dir"`
+	Stack      string      `json:"stack"`
+	Command    string      `json:"command"`
+	Source     string      `json:"source"`
+	DurationMS int64       `json:"duration_ms"`
+	Output     string      `json:"output"`
+	Tests      *TestReport `json:"tests,omitempty"`
+	Findings   []Finding   `json:"findings,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// DefaultCacheDir returns the check cache location under the workspace
+func DefaultCacheDir(workspacePath string) string {
+	return filepath.Join(workspacePath, ".devbot", "cache", "check")
+}
+
+// NewCache returns a cache rooted at dir (created lazily on first write)
+func NewCache(dir string) *Cache {
+	return &Cache{Dir: dir}
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// Get returns the cached result for key, marked as Cached
+func (c *Cache) Get(key string) (CheckResult, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return CheckResult{}, false
+	}
+
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return CheckResult{}, false
+	}
+
+	return CheckResult{
+		Type:     e.Type,
+		SubDir:   e.SubDir,
+		Stack:    e.Stack,
+		Command:  e.Command,
+		Source:   e.Source,
+		Status:   "pass",
+		Duration: time.Duration(e.DurationMS) * time.Millisecond,
+		Output:   e.Output,
+		Tests:    e.Tests,
+		Findings: e.Findings,
+		Cached:   true,
+	}, true
+}
+
+// Put stores a passing result under key. Other statuses are ignored.
+func (c *Cache) Put(key string, r CheckResult) error {
+	if r.Status != "pass" {
+		return nil
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cacheEntry{
+		Key:        key,
+		Type:       r.Type,
+		SubDir:     r.SubDir,
+		Stack:      r.Stack,
+		Command:    r.Command,
+		Source:     r.Source,
+		DurationMS: r.Duration.Milliseconds(),
+		Output:     r.Output,
+		Tests:      r.Tests,
+		Findings:   r.Findings,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so concurrent checks never see partial entries
+	tmp, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Stats returns entry count, total size and age range
+func (c *Cache) Stats() (CacheStats, error) {
+	stats := CacheStats{Dir: c.Dir}
+
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return stats, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if stats.Oldest.IsZero() || info.ModTime().Before(stats.Oldest) {
+			stats.Oldest = info.ModTime()
+		}
+		if info.ModTime().After(stats.Newest) {
+			stats.Newest = info.ModTime()
+		}
+	}
+
+	return stats, nil
+}
+
+// Clear removes all entries and returns how many were deleted
+func (c *Cache) Clear() (int, error) {
+	stats, err := c.Stats()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return 0, err
+	}
+	return stats.Entries, nil
+}
@@ -0,0 +1,238 @@
+package check
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestCacheGetPut(t *testing.T) {
+	cache := NewCache(filepath.Join(t.TempDir(), "cache"))
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Get on empty cache should miss")
+	}
+
+	pass := CheckResult{Type: CheckTest, Stack: "go", Command: "go test ./...", Status: "pass", Duration: 1200 * time.Millisecond, Output: "ok"}
+	if err := cache.Put("abc", pass); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	got, ok := cache.Get("abc")
+	if !ok {
+		t.Fatal("Get should hit after Put")
+	}
+	if !got.Cached || got.Status != "pass" || got.Command != "go test ./..." {
+		t.Errorf("Get = %+v, want cached pass with same command", got)
+	}
+	if got.Duration != 1200*time.Millisecond {
+		t.Errorf("Duration = %v, want 1.2s", got.Duration)
+	}
+
+	// Failures are never cached
+	if err := cache.Put("def", CheckResult{Type: CheckLint, Status: "fail"}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, ok := cache.Get("def"); ok {
+		t.Error("failed results should not be cached")
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.Entries != 1 || stats.Bytes == 0 {
+		t.Errorf("Stats = %+v, want 1 entry", stats)
+	}
+
+	removed, err := cache.Clear()
+	if err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Clear removed %d, want 1", removed)
+	}
+	if stats, _ := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("Stats after Clear = %d entries, want 0", stats.Entries)
+	}
+}
+
+func TestRunWithCache(t *testing.T) {
+	repo := t.TempDir()
+	counter := filepath.Join(t.TempDir(), "runs")
+
+	writeFile(t, filepath.Join(repo, RepoConfigFile), `
+checks:
+  count:
+    command: [sh, -c, 'echo run >> "$COUNTER"']
+`)
+	writeFile(t, filepath.Join(repo, "main.txt"), "v1\n")
+	runGit(t, repo, "init")
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "init")
+
+	t.Setenv("COUNTER", counter)
+	cache := NewCache(filepath.Join(t.TempDir(), "cache"))
+	info := workspace.RepoInfo{Name: "cached", Path: repo}
+
+	runs := func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "run")
+	}
+	check := func(wantRuns int, wantCached bool) {
+		t.Helper()
+		result := RunWithOptions(info, Options{Cache: cache})
+		if len(result.Checks) != 1 {
+			t.Fatalf("Checks = %+v, want 1", result.Checks)
+		}
+		if result.Checks[0].Cached != wantCached {
+			t.Errorf("Cached = %v, want %v", result.Checks[0].Cached, wantCached)
+		}
+		if runs() != wantRuns {
+			t.Errorf("command ran %d times, want %d", runs(), wantRuns)
+		}
+	}
+
+	check(1, false) // first run executes
+	check(1, true)  // unchanged tree is served from cache
+
+	// Dirty tracked file invalidates
+	writeFile(t, filepath.Join(repo, "main.txt"), "v2\n")
+	check(2, false)
+	check(2, true)
+
+	// New untracked file invalidates
+	writeFile(t, filepath.Join(repo, "new.txt"), "new\n")
+	check(3, false)
+
+	// Ignored files don't
+	writeFile(t, filepath.Join(repo, ".gitignore"), "*.log\n")
+	check(4, false)
+	writeFile(t, filepath.Join(repo, "debug.log"), "noise\n")
+	check(4, true)
+
+	// --no-cache (nil cache) always runs
+	RunWithOptions(info, Options{})
+	if runs() != 5 {
+		t.Errorf("uncached run count = %d, want 5", runs())
+	}
+}
+
+func TestRunWithCacheAfterBuild(t *testing.T) {
+	repo := t.TempDir()
+	counter := filepath.Join(t.TempDir(), "runs")
+
+	// The script logs every invocation, so a version probe would show up
+	writeFile(t, filepath.Join(repo, RepoConfigFile), `
+checks:
+  build:
+    command: [./build.sh]
+`)
+	writeFile(t, filepath.Join(repo, "build.sh"), "#!/bin/sh\necho \"run $*\" >> \"$COUNTER\"\nprintf 'ELF\\000bin' > app\n")
+	if err := os.Chmod(filepath.Join(repo, "build.sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "init")
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "init")
+
+	t.Setenv("COUNTER", counter)
+	cache := NewCache(filepath.Join(t.TempDir(), "cache"))
+	info := workspace.RepoInfo{Name: "built", Path: repo}
+
+	for i, wantCached := range []bool{false, true} {
+		result := RunWithOptions(info, Options{Cache: cache})
+		if len(result.Checks) != 1 || result.Checks[0].Status != "pass" {
+			t.Fatalf("run %d: Checks = %+v, want 1 pass", i+1, result.Checks)
+		}
+		if result.Checks[0].Cached != wantCached {
+			t.Errorf("run %d: Cached = %v, want %v (untracked binary output changed the key)", i+1, result.Checks[0].Cached, wantCached)
+		}
+	}
+
+	data, _ := os.ReadFile(counter)
+	if string(data) != "run \n" {
+		t.Errorf("script invocations = %q, want a single run with no arguments", data)
+	}
+
+	// Editing the script itself invalidates
+	writeFile(t, filepath.Join(repo, "build.sh"), "#!/bin/sh\necho \"run $*\" >> \"$COUNTER\"\n")
+	if result := RunWithOptions(info, Options{Cache: cache}); result.Checks[0].Cached {
+		t.Error("changed script should not be served from cache")
+	}
+}
+
+func TestFingerprintSharedFiles(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "go.work"), "go 1.22\n\nuse ./api\n")
+	writeFile(t, filepath.Join(repo, "api", "go.mod"), "module example.com/api\n")
+	runGit(t, repo, "init")
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "init")
+
+	api := filepath.Join(repo, "api")
+	rc := resolvedCheck{args: []string{"true"}}
+	before := newFingerprinter(repo).key(api, CheckTest, rc)
+	if before == "" {
+		t.Fatal("key inside git should not be empty")
+	}
+
+	// A root go.work or lint config affects the sub-app's checks
+	writeFile(t, filepath.Join(repo, "go.work"), "go 1.22\n\nuse (\n\t./api\n\t./web\n)\n")
+	afterWork := newFingerprinter(repo).key(api, CheckTest, rc)
+	if afterWork == before {
+		t.Error("changing the root go.work should change the sub-app key")
+	}
+	writeFile(t, filepath.Join(repo, ".golangci.yml"), "linters:\n  enable: [errcheck]\n")
+	if newFingerprinter(repo).key(api, CheckTest, rc) == afterWork {
+		t.Error("adding a root lint config should change the sub-app key")
+	}
+}
+
+func TestFingerprintOutsideGit(t *testing.T) {
+	dir := t.TempDir()
+	fp := newFingerprinter(dir)
+	key := fp.key(dir, CheckTest, resolvedCheck{args: []string{"true"}})
+	if key != "" {
+		t.Errorf("key outside git = %q, want empty", key)
+	}
+}
+
+func TestToolsFor(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"go", "test", "./..."}, "go"},
+		{[]string{"pnpm", "run", "lint"}, "pnpm,node"},
+		{[]string{"npx", "tsc"}, "node"},
+		{[]string{"uv", "run", "pytest"}, "uv,python3"},
+		{[]string{"./t.sh"}, ""},
+		{[]string{"make", "test"}, ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(toolsFor(tt.args), ","); got != tt.want {
+			t.Errorf("toolsFor(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/sloanahrens/devbot-go/internal/node"
+	"github.com/sloanahrens/devbot-go/internal/stack"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
@@ -28,10 +31,22 @@ type CheckResult struct {
 	Type     CheckType
 	SubDir   string // subdirectory where check ran (empty = root)
 	Stack    string // which stack this check is for
+	Command  string // command line that ran (or would have run)
+	Source   string // where the command came from: "builtin:<stack>" or a .devbot.yaml path
 	Status   string // pass, fail, skip
 	Duration time.Duration
 	Output   string
 	Error    error
+	Cached   bool        // result was served from the check cache
+	Tests    *TestReport // per-test results, for test runners devbot can parse
+	Findings []Finding   // problems reported by lint, for linters devbot can parse
+}
+
+// Options controls how checks are run
+type Options struct {
+	Only  []CheckType // only run these checks (default: all available)
+	Fix   bool        // auto-fix where possible (never cached)
+	Cache *Cache      // reuse passing results with an unchanged fingerprint (nil disables)
 }
 
 // Result contains all check results for a repository
@@ -48,66 +63,36 @@ type SubApp struct {
 	Stack []string // detected stacks
 }
 
-// stackCommands maps stack types to their check commands
-var stackCommands = map[string]map[CheckType][]string{
-	"nextjs": {
-		CheckLint:      {"npm", "run", "lint"},
-		CheckTypecheck: {"npm", "run", "typecheck"},
-		CheckBuild:     {"npm", "run", "build"},
-		CheckTest:      {"npm", "test", "--", "--passWithNoTests"},
-	},
-	"ts": {
-		CheckLint:      {"npm", "run", "lint"},
-		CheckTypecheck: {"npx", "tsc", "--noEmit"},
-		CheckBuild:     {"npm", "run", "build"},
-		CheckTest:      {"npm", "test"},
-	},
-	"js": {
-		CheckLint:  {"npm", "run", "lint"},
-		CheckBuild: {"npm", "run", "build"},
-		CheckTest:  {"npm", "test"},
-	},
-	"go": {
-		CheckLint:  {"golangci-lint", "run"},
-		CheckBuild: {"go", "build", "./..."},
-		CheckTest:  {"go", "test", "./..."},
-	},
-	"python": {
-		CheckLint:      {"uv", "run", "ruff", "check", "."},
-		CheckTypecheck: {"uv", "run", "mypy", "."},
-		CheckTest:      {"uv", "run", "pytest"},
-	},
-	"rust": {
-		CheckLint:  {"cargo", "clippy"},
-		CheckBuild: {"cargo", "build"},
-		CheckTest:  {"cargo", "test"},
-	},
-}
-
-// stackMarkers maps marker files to their stack type
-var stackMarkers = map[string]string{
-	"go.mod":           "go",
-	"Cargo.toml":       "rust",
-	"pyproject.toml":   "python",
-	"requirements.txt": "python",
-}
-
 // sstProject tracks if a project uses SST and whether types are generated
 type sstProject struct {
 	hasSST     bool // sst.config.ts exists
 	typesReady bool // .sst/platform/config.d.ts exists
 }
 
-// Run executes checks for a repository
+// Run executes checks for a repository without caching
 func Run(repo workspace.RepoInfo, only []CheckType, fix bool) Result {
+	return RunWithOptions(repo, Options{Only: only, Fix: fix})
+}
+
+// RunWithOptions executes checks for a repository
+func RunWithOptions(repo workspace.RepoInfo, opts Options) Result {
 	start := time.Now()
+	only := opts.Only
+	fp := newFingerprinter(repo.Path)
 
 	result := Result{
 		Repo: repo,
 	}
 
-	// Discover sub-applications
-	result.SubApps = discoverSubApps(repo.Path)
+	// Discover sub-applications (plus any only defined in .devbot.yaml)
+	subApps, err := configuredSubApps(repo.Path, discoverSubApps(repo.Path))
+	result.SubApps = subApps
+	if err != nil {
+		// Without the config there's no telling which checks should run
+		result.Checks = append(result.Checks, configFailure("", err))
+		result.Duration = time.Since(start)
+		return result
+	}
 
 	if len(result.SubApps) == 0 {
 		result.Duration = time.Since(start)
@@ -121,12 +106,19 @@ func Run(repo workspace.RepoInfo, only []CheckType, fix bool) Result {
 			appPath = filepath.Join(repo.Path, subApp.Path)
 		}
 
+		// Load .devbot.yaml overrides for this sub-app
+		cfg, err := loadAppConfig(repo.Path, subApp.Path)
+		if err != nil {
+			result.Checks = append(result.Checks, configFailure(subApp.Path, err))
+			continue
+		}
+
 		// Determine which checks to run
-		checksToRun := determineChecks(subApp.Stack, only)
+		checksToRun := cfg.checkTypes(subApp.Stack, only)
 
 		// Run phase 1 (lint, typecheck) in parallel
 		var wg sync.WaitGroup
-		parallelResults := make(chan CheckResult, 2)
+		parallelResults := make(chan CheckResult, len(checksToRun))
 
 		phase1Checks := []CheckType{}
 		phase2Checks := []CheckType{}
@@ -144,7 +136,7 @@ func Run(repo workspace.RepoInfo, only []CheckType, fix bool) Result {
 			wg.Add(1)
 			go func(ct CheckType) {
 				defer wg.Done()
-				cr := runCheck(appPath, subApp.Stack, ct, fix)
+				cr := runCheckCached(appPath, subApp.Stack, ct, cfg, opts, fp)
 				cr.SubDir = subApp.Path
 				parallelResults <- cr
 			}(checkType)
@@ -171,7 +163,7 @@ func Run(repo workspace.RepoInfo, only []CheckType, fix bool) Result {
 		// Run phase 2 sequentially if phase 1 passed
 		if !phase1Failed {
 			for _, checkType := range phase2Checks {
-				cr := runCheck(appPath, subApp.Stack, checkType, fix)
+				cr := runCheckCached(appPath, subApp.Stack, checkType, cfg, opts, fp)
 				cr.SubDir = subApp.Path
 				result.Checks = append(result.Checks, cr)
 				if cr.Status == "fail" {
@@ -195,7 +187,15 @@ func Run(repo workspace.RepoInfo, only []CheckType, fix bool) Result {
 
 // discoverSubApps finds all sub-applications in a repo
 func discoverSubApps(repoPath string) []SubApp {
+	subApps, _ := discover(repoPath)
+	return subApps
+}
+
+// discover finds sub-applications, along with the directories that have a
+// stack but aren't treated as separate sub-apps
+func discover(repoPath string) ([]SubApp, []SkippedApp) {
 	var subApps []SubApp
+	var skipped []SkippedApp
 
 	// Check root first
 	rootStack := detectStackAt(repoPath)
@@ -203,70 +203,122 @@ func discoverSubApps(repoPath string) []SubApp {
 		subApps = append(subApps, SubApp{Path: "", Stack: rootStack})
 	}
 
-	// Check common subdirectories
-	subdirs := []string{
-		"go-api", "api", "backend", "server",
-		"nextapp", "web", "frontend", "app", "client",
+	// A JS workspace root runs each check across its packages (turbo run,
+	// pnpm -r, ...), so JS-only packages aren't checked again on their own
+	var runner string
+	if allJS(rootStack) {
+		if tool := node.Detect(repoPath, repoPath); tool.Root == filepath.Clean(repoPath) && tool.WorkspaceRuns() {
+			runner = tool.Runner()
+		}
 	}
 
-	for _, subdir := range subdirs {
-		subPath := filepath.Join(repoPath, subdir)
-		if info, err := os.Stat(subPath); err == nil && info.IsDir() {
-			stack := detectStackAt(subPath)
-			if len(stack) > 0 {
-				// Don't duplicate if root already covers this stack
-				if !stackOverlaps(rootStack, stack) {
-					subApps = append(subApps, SubApp{Path: subdir, Stack: stack})
-				}
+	// Check sub-app directories; globs like packages/* are always separate apps
+	for _, pattern := range stack.Default().SearchDirs() {
+		for _, dir := range stack.Expand(repoPath, pattern) {
+			st := detectStackAt(filepath.Join(repoPath, dir))
+			if len(st) == 0 {
+				continue
+			}
+			// Don't duplicate if root already covers this stack
+			if !stack.IsGlob(pattern) && stackOverlaps(rootStack, st) {
+				skipped = append(skipped, SkippedApp{
+					Path:   dir,
+					Stack:  st,
+					Reason: "root already covers " + strings.Join(overlap(rootStack, st), ", "),
+				})
+				continue
+			}
+			if runner != "" && allJS(st) {
+				skipped = append(skipped, SkippedApp{
+					Path:    dir,
+					Stack:   st,
+					Reason:  "covered by " + runner + " at root",
+					Covered: true,
+				})
+				continue
 			}
+			subApps = append(subApps, SubApp{Path: dir, Stack: st})
 		}
 	}
 
-	// Check packages/* and apps/* patterns
-	for _, pattern := range []string{"packages/*", "apps/*"} {
-		matches, _ := filepath.Glob(filepath.Join(repoPath, pattern))
-		for _, match := range matches {
-			if info, err := os.Stat(match); err == nil && info.IsDir() {
-				stack := detectStackAt(match)
-				if len(stack) > 0 {
-					relPath, _ := filepath.Rel(repoPath, match)
-					subApps = append(subApps, SubApp{Path: relPath, Stack: stack})
-				}
+	return subApps, skipped
+}
+
+// configFailure reports a .devbot.yaml that couldn't be loaded as a
+// failed check, so the run doesn't pass without running anything
+func configFailure(subDir string, err error) CheckResult {
+	return CheckResult{
+		Type:   "config",
+		SubDir: subDir,
+		Source: RepoConfigFile,
+		Status: "fail",
+		Output: err.Error(),
+		Error:  err,
+	}
+}
+
+// configuredSubApps adds sub-apps that have no detectable stack but are
+// given checks in .devbot.yaml (the repo root, or entries under apps:).
+// A root .devbot.yaml that can't be parsed is returned as an error, along
+// with the detected sub-apps.
+func configuredSubApps(repoPath string, subApps []SubApp) ([]SubApp, error) {
+	known := make(map[string]bool)
+	for _, app := range subApps {
+		known[app.Path] = true
+	}
+
+	root, err := LoadRepoConfig(repoPath)
+	if err != nil || root == nil {
+		return subApps, err
+	}
+
+	if !known[""] && len(root.Checks) > 0 {
+		subApps = append([]SubApp{{Path: ""}}, subApps...)
+	}
+
+	var extra []string
+	for path, app := range root.Apps {
+		if !known[path] && len(app.Checks) > 0 {
+			if info, err := os.Stat(filepath.Join(repoPath, path)); err == nil && info.IsDir() {
+				extra = append(extra, path)
 			}
 		}
 	}
+	sort.Strings(extra)
+	for _, path := range extra {
+		subApps = append(subApps, SubApp{Path: path, Stack: detectStackAt(filepath.Join(repoPath, path))})
+	}
 
-	return subApps
+	return subApps, nil
 }
 
 // detectStackAt detects the stack at a specific path
 func detectStackAt(path string) []string {
-	var stack []string
+	return stack.Default().DetectAt(path)
+}
 
-	// Check marker files
-	for marker, stackType := range stackMarkers {
-		if fileExists(filepath.Join(path, marker)) {
-			stack = appendUnique(stack, stackType)
+// overlap returns the stacks of b that are also in a
+func overlap(a, b []string) []string {
+	var both []string
+	for _, sb := range b {
+		for _, sa := range a {
+			if sa == sb {
+				both = append(both, sb)
+				break
+			}
 		}
 	}
+	return both
+}
 
-	// TypeScript/JavaScript detection
-	if fileExists(filepath.Join(path, "package.json")) {
-		if fileExists(filepath.Join(path, "tsconfig.json")) {
-			stack = appendUnique(stack, "ts")
-		} else {
-			stack = appendUnique(stack, "js")
-		}
-
-		// Check for Next.js
-		if fileExists(filepath.Join(path, "next.config.js")) ||
-			fileExists(filepath.Join(path, "next.config.mjs")) ||
-			fileExists(filepath.Join(path, "next.config.ts")) {
-			stack = appendUnique(stack, "nextjs")
+// allJS reports whether stacks is non-empty and every stack is a JS stack
+func allJS(stacks []string) bool {
+	for _, s := range stacks {
+		if def, ok := stack.Default().Lookup(s); !ok || !def.JS {
+			return false
 		}
 	}
-
-	return stack
+	return len(stacks) > 0
 }
 
 func stackOverlaps(a, b []string) bool {
@@ -280,7 +332,7 @@ func stackOverlaps(a, b []string) bool {
 	return false
 }
 
-func determineChecks(stack []string, only []CheckType) []CheckType {
+func determineChecks(stacks []string, only []CheckType) []CheckType {
 	if len(only) > 0 {
 		return only
 	}
@@ -289,78 +341,106 @@ func determineChecks(stack []string, only []CheckType) []CheckType {
 
 	var available []CheckType
 	for _, check := range allChecks {
-		for _, s := range stack {
-			if cmds, ok := stackCommands[s]; ok {
-				if _, hasCheck := cmds[check]; hasCheck {
-					available = appendCheckUnique(available, check)
-					break
-				}
-			}
+		if args, _ := stack.Default().Command(stacks, string(check)); args != nil {
+			available = append(available, check)
 		}
 	}
 
 	return available
 }
 
-func runCheck(workDir string, stack []string, checkType CheckType, fix bool) CheckResult {
-	start := time.Now()
-	result := CheckResult{Type: checkType}
+// runCheckCached serves a passing result from the cache when the fingerprint
+// matches, otherwise runs the check and caches it if it passes
+func runCheckCached(appDir string, stack []string, checkType CheckType, cfg appConfig, opts Options, fp *fingerprinter) CheckResult {
+	if opts.Cache == nil || opts.Fix {
+		return runCheck(appDir, stack, checkType, cfg, opts.Fix)
+	}
 
-	// Find command for this stack
-	var cmdArgs []string
-	var usedStack string
-	for _, s := range stack {
-		if cmds, ok := stackCommands[s]; ok {
-			if args, hasCheck := cmds[checkType]; hasCheck {
-				cmdArgs = args
-				usedStack = s
-				break
-			}
+	rc := cfg.resolve(stack, checkType)
+	if rc.disabled || len(rc.args) == 0 {
+		return runCheck(appDir, stack, checkType, cfg, false)
+	}
+
+	key := fp.key(appDir, checkType, rc)
+	if key != "" {
+		if cached, ok := opts.Cache.Get(key); ok {
+			return cached
 		}
 	}
 
-	result.Stack = usedStack
+	result := runCheck(appDir, stack, checkType, cfg, false)
+	if key != "" {
+		_ = opts.Cache.Put(key, result)
+	}
+	return result
+}
 
-	if len(cmdArgs) == 0 {
+func runCheck(appDir string, stack []string, checkType CheckType, cfg appConfig, fix bool) CheckResult {
+	start := time.Now()
+	result := CheckResult{Type: checkType}
+
+	// Find command: .devbot.yaml first, then the built-in for this stack
+	rc := cfg.resolve(stack, checkType)
+	cmdArgs := rc.args
+
+	result.Stack = rc.stack
+	result.Source = rc.source
+	result.Command = strings.Join(cmdArgs, " ")
+
+	if rc.disabled {
 		result.Status = "skip"
+		result.Output = "disabled in " + rc.source
 		return result
 	}
 
-	// SST project detection: skip root typecheck if types aren't generated
-	if checkType == CheckTypecheck {
-		sst := detectSST(workDir)
-		if sst.hasSST && !sst.typesReady {
-			result.Status = "skip"
-			result.Output = "SST project: run 'sst dev' to generate types first"
-			return result
-		}
+	if len(cmdArgs) == 0 {
+		result.Status = "skip"
+		return result
 	}
 
-	// For npm commands, check if the script exists in package.json before running
-	// This prevents false failures when sub-packages don't define certain scripts
-	if scriptName := isNpmRunCommand(cmdArgs); scriptName != "" {
-		if !npmScriptExists(workDir, scriptName) {
-			result.Status = "skip"
-			result.Output = "script not defined in package.json"
-			return result
-		}
+	workDir := appDir
+	if rc.dir != "" {
+		workDir = filepath.Join(appDir, rc.dir)
+	}
 
-		// Check if script uses bun but bun isn't installed
-		if scriptUsesBun(workDir, scriptName) && !bunAvailable() {
-			result.Status = "skip"
-			result.Output = "script requires bun runtime (not installed)"
-			return result
-		}
+	if reason := skipReason(workDir, checkType, cmdArgs); reason != "" {
+		result.Status = "skip"
+		result.Output = reason
+		return result
 	}
 
 	// Modify command for fix mode
 	if fix && checkType == CheckLint {
-		cmdArgs = modifyForFix(cmdArgs, stack)
+		if len(rc.fixArgs) > 0 {
+			cmdArgs = rc.fixArgs
+		} else if rc.builtin {
+			cmdArgs = modifyForFix(rc.base, stack)
+			if rc.js {
+				cmdArgs = cfg.adaptJS(cmdArgs, checkType)
+			}
+		}
+		result.Command = strings.Join(cmdArgs, " ")
+	}
+
+	// Ask known test runners for structured results
+	var rep *reporter
+	if checkType == CheckTest {
+		if r, ok := newReporter(workDir, cmdArgs); ok {
+			rep = r
+			defer rep.cleanup()
+		}
+	}
+	runArgs := cmdArgs
+	if rep != nil {
+		runArgs = rep.args
 	}
 
 	// Execute command
-	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	cmd := exec.Command(runArgs[0], runArgs[1:]...)
 	cmd.Dir = workDir
+	if len(rc.env) > 0 {
+		cmd.Env = append(os.Environ(), rc.env...)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -370,6 +450,9 @@ func runCheck(workDir string, stack []string, checkType CheckType, fix bool) Che
 	result.Duration = time.Since(start)
 
 	output := stdout.String()
+	if rep != nil {
+		result.Tests, output = rep.parse(output)
+	}
 	if stderr.Len() > 0 {
 		if output != "" {
 			output += "\n"
@@ -378,6 +461,15 @@ func runCheck(workDir string, stack []string, checkType CheckType, fix bool) Che
 	}
 	result.Output = strings.TrimSpace(output)
 
+	if checkType == CheckLint {
+		result.Findings = parseLintFindings(result.Output, workDir)
+		for i, f := range result.Findings {
+			if rc.dir != "" && !filepath.IsAbs(f.File) {
+				result.Findings[i].File = filepath.Join(rc.dir, f.File)
+			}
+		}
+	}
+
 	if err != nil {
 		result.Status = "fail"
 		result.Error = err
@@ -388,20 +480,34 @@ func runCheck(workDir string, stack []string, checkType CheckType, fix bool) Che
 	return result
 }
 
-func modifyForFix(args []string, stack []string) []string {
-	for _, s := range stack {
-		switch s {
-		case "ts", "js", "nextjs":
-			return append(args, "--", "--fix")
-		case "go":
-			return append(args, "--fix")
-		case "python":
-			return append(args, "--fix")
-		case "rust":
-			return append(args, "--fix")
+// skipReason returns why a check can't run in workDir as things stand, or ""
+func skipReason(workDir string, checkType CheckType, cmdArgs []string) string {
+	// SST project detection: skip root typecheck if types aren't generated
+	if checkType == CheckTypecheck {
+		sst := detectSST(workDir)
+		if sst.hasSST && !sst.typesReady {
+			return "SST project: run 'sst dev' to generate types first"
+		}
+	}
+
+	// For npm commands, check if the script exists in package.json before running
+	// This prevents false failures when sub-packages don't define certain scripts
+	if scriptName := isNpmRunCommand(cmdArgs); scriptName != "" {
+		if !npmScriptExists(workDir, scriptName) {
+			return "script not defined in package.json"
+		}
+
+		// Check if script uses bun but bun isn't installed
+		if scriptUsesBun(workDir, scriptName) && !bunAvailable() {
+			return "script requires bun runtime (not installed)"
 		}
 	}
-	return args
+
+	return ""
+}
+
+func modifyForFix(args []string, stacks []string) []string {
+	return stack.Default().FixCommand(args, stacks)
 }
 
 func fileExists(path string) bool {
@@ -411,47 +517,48 @@ func fileExists(path string) bool {
 
 // npmScriptExists checks if a script exists in the package.json at the given directory
 func npmScriptExists(dir string, scriptName string) bool {
-	pkgPath := filepath.Join(dir, "package.json")
-	data, err := os.ReadFile(pkgPath)
+	_, exists := packageScripts(dir)[scriptName]
+	return exists
+}
+
+// packageScripts returns the scripts in the package.json at dir
+func packageScripts(dir string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
 	if err != nil {
-		return false
+		return nil
 	}
 
 	var pkg struct {
 		Scripts map[string]string `json:"scripts"`
 	}
 	if err := json.Unmarshal(data, &pkg); err != nil {
-		return false
+		return nil
 	}
-
-	_, exists := pkg.Scripts[scriptName]
-	return exists
+	return pkg.Scripts
 }
 
-// isNpmRunCommand checks if a command is "npm run <script>" or "npm test"
+// isNpmRunCommand checks if a command is "npm run <script>" or "npm test",
+// or the pnpm, yarn or bun equivalent (bun test is bun's own test runner)
 // Returns the script name if it is, empty string otherwise
 func isNpmRunCommand(cmdArgs []string) string {
-	if len(cmdArgs) < 2 || cmdArgs[0] != "npm" {
+	if len(cmdArgs) < 2 {
+		return ""
+	}
+	switch cmdArgs[0] {
+	case "npm", "pnpm", "yarn", "bun":
+	default:
 		return ""
 	}
-	if cmdArgs[1] == "test" {
+	if cmdArgs[1] == "test" && cmdArgs[0] != "bun" {
 		return "test"
 	}
-	if cmdArgs[1] == "run" && len(cmdArgs) >= 3 {
+	// Flags after run (pnpm run -r, bun run --filter) mean a workspace run
+	if cmdArgs[1] == "run" && len(cmdArgs) >= 3 && !strings.HasPrefix(cmdArgs[2], "-") {
 		return cmdArgs[2]
 	}
 	return ""
 }
 
-func appendUnique(slice []string, item string) []string {
-	for _, s := range slice {
-		if s == item {
-			return slice
-		}
-	}
-	return append(slice, item)
-}
-
 func appendCheckUnique(slice []CheckType, item CheckType) []CheckType {
 	for _, s := range slice {
 		if s == item {
@@ -478,20 +585,7 @@ func detectSST(path string) sstProject {
 
 // scriptUsesBun checks if a test script in package.json uses bun
 func scriptUsesBun(dir string, scriptName string) bool {
-	pkgPath := filepath.Join(dir, "package.json")
-	data, err := os.ReadFile(pkgPath)
-	if err != nil {
-		return false
-	}
-
-	var pkg struct {
-		Scripts map[string]string `json:"scripts"`
-	}
-	if err := json.Unmarshal(data, &pkg); err != nil {
-		return false
-	}
-
-	script, exists := pkg.Scripts[scriptName]
+	script, exists := packageScripts(dir)[scriptName]
 	if !exists {
 		return false
 	}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/stack"
 )
 
 func TestStackOverlaps(t *testing.T) {
@@ -350,6 +352,59 @@ func TestDiscoverSubApps(t *testing.T) {
 			t.Errorf("discoverSubApps() = %d apps, want 1 (overlap skipped)", len(apps))
 		}
 	})
+
+	t.Run("configured stack and dirs", func(t *testing.T) {
+		reg, err := stack.New([]stack.Config{{
+			Name:    "gradle",
+			Markers: []string{"build.gradle.kts"},
+			Checks:  map[string]stack.Command{"build": {"./gradlew", "build"}, "test": {"./gradlew", "test"}},
+		}}, []string{"services/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stack.SetDefault(reg)
+		defer stack.SetDefault(nil)
+
+		tmpDir := t.TempDir()
+		svc := filepath.Join(tmpDir, "services", "billing")
+		_ = os.MkdirAll(svc, 0755)
+		_ = os.WriteFile(filepath.Join(svc, "build.gradle.kts"), []byte(""), 0644)
+
+		apps := discoverSubApps(tmpDir)
+
+		if len(apps) != 1 || apps[0].Path != filepath.Join("services", "billing") || apps[0].Stack[0] != "gradle" {
+			t.Fatalf("discoverSubApps() = %+v, want services/billing [gradle]", apps)
+		}
+		if checks := determineChecks(apps[0].Stack, nil); len(checks) != 2 || checks[0] != CheckBuild {
+			t.Errorf("determineChecks(gradle) = %v, want [build test]", checks)
+		}
+		cfg := appConfig{}
+		if rc := cfg.resolve(apps[0].Stack, CheckTest); rc.source != "config:gradle" || len(rc.args) != 2 {
+			t.Errorf("resolve(test) = %+v, want ./gradlew test from config:gradle", rc)
+		}
+	})
+
+	t.Run("js workspace root covers its packages", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		_ = os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{"workspaces": ["packages/*"]}`), 0644)
+		_ = os.WriteFile(filepath.Join(tmpDir, "tsconfig.json"), []byte("{}"), 0644)
+		_ = os.WriteFile(filepath.Join(tmpDir, "bun.lock"), []byte(""), 0644)
+		for _, pkg := range []string{"ui", "api"} {
+			dir := filepath.Join(tmpDir, "packages", pkg)
+			_ = os.MkdirAll(dir, 0755)
+			_ = os.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), 0644)
+		}
+		_ = os.WriteFile(filepath.Join(tmpDir, "packages", "api", "go.mod"), []byte("module api"), 0644)
+
+		apps, skipped := discover(tmpDir)
+
+		if len(apps) != 2 || apps[0].Path != "" || apps[1].Path != filepath.Join("packages", "api") {
+			t.Errorf("discover() apps = %+v, want root and packages/api (go+js)", apps)
+		}
+		if len(skipped) != 1 || skipped[0].Reason != "covered by bun --filter at root" || !skipped[0].Covered {
+			t.Errorf("discover() skipped = %+v, want packages/ui covered by bun", skipped)
+		}
+	})
 }
 
 func TestNpmScriptExists(t *testing.T) {
@@ -420,6 +475,12 @@ func TestIsNpmRunCommand(t *testing.T) {
 		{"empty", []string{}, ""},
 		{"single element", []string{"npm"}, ""},
 		{"npm without run or test", []string{"npm", "install"}, ""},
+		{"pnpm run lint", []string{"pnpm", "run", "lint", "--fix"}, "lint"},
+		{"yarn test", []string{"yarn", "test"}, "test"},
+		{"bun run build", []string{"bun", "run", "build"}, "build"},
+		{"bun test runner", []string{"bun", "test"}, ""},
+		{"pnpm recursive", []string{"pnpm", "-r", "--if-present", "run", "lint"}, ""},
+		{"bun filter", []string{"bun", "run", "--filter", "*", "lint"}, ""},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,191 @@
+package check
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/detect"
+	"github.com/sloanahrens/devbot-go/internal/stack"
+)
+
+// Explanation shows how a repo's stacks and sub-apps are detected and which
+// checks Run would run, without running anything
+type Explanation struct {
+	Path     string
+	Dirs     []DirEvidence // Root and every existing search dir, in search order
+	Absent   []string      // Search dirs with no match in the repo
+	Detected []string      // What detect.ProjectStack reports
+	Apps     []AppPlan     // Sub-apps as Run finds them
+	Skipped  []SkippedApp  // Directories with a stack that aren't separate sub-apps
+	Notes    []string      // Where detect and check disagree
+}
+
+// DirEvidence is the stack evidence for one examined directory
+type DirEvidence struct {
+	Path     string // Relative to the repo ("" = root)
+	Pattern  string // Search dir it was found through ("" = root)
+	Evidence []stack.Evidence
+}
+
+// SkippedApp is a directory with a stack that discovery passed over
+type SkippedApp struct {
+	Path    string
+	Stack   []string
+	Reason  string
+	Covered bool // Checked by the root's workspace-wide run
+}
+
+// AppPlan is a sub-app and the checks Run would run for it
+type AppPlan struct {
+	SubApp
+	Checks []PlannedCheck
+	Error  error // .devbot.yaml couldn't be loaded
+}
+
+// PlannedCheck is a check Run would run (or skip)
+type PlannedCheck struct {
+	Type    CheckType
+	Stack   string
+	Command string
+	Source  string
+	Skip    string // Why it would be skipped, if it would
+}
+
+// Explain examines repoPath the way detect and Run do
+func Explain(repoPath string) Explanation {
+	reg := stack.Default()
+	e := Explanation{
+		Path:     repoPath,
+		Detected: detect.ProjectStack(repoPath),
+	}
+
+	e.Dirs = append(e.Dirs, DirEvidence{Evidence: reg.Explain(repoPath)})
+	for _, pattern := range reg.SearchDirs() {
+		dirs := stack.Expand(repoPath, pattern)
+		if len(dirs) == 0 {
+			e.Absent = append(e.Absent, pattern)
+		}
+		for _, dir := range dirs {
+			e.Dirs = append(e.Dirs, DirEvidence{
+				Path:     dir,
+				Pattern:  pattern,
+				Evidence: reg.Explain(filepath.Join(repoPath, dir)),
+			})
+		}
+	}
+
+	subApps, skipped := discover(repoPath)
+	e.Skipped = skipped
+	apps, err := configuredSubApps(repoPath, subApps)
+	if err != nil && len(apps) == 0 {
+		// Nothing detected, so the config is all there is to plan from
+		e.Apps = append(e.Apps, AppPlan{Error: err})
+	}
+	for _, app := range apps {
+		e.Apps = append(e.Apps, planApp(repoPath, app))
+	}
+
+	e.Notes = e.discrepancies()
+	return e
+}
+
+func planApp(repoPath string, app SubApp) AppPlan {
+	plan := AppPlan{SubApp: app}
+	appDir := filepath.Join(repoPath, app.Path)
+
+	cfg, err := loadAppConfig(repoPath, app.Path)
+	if err != nil {
+		plan.Error = err
+		return plan
+	}
+
+	for _, ct := range cfg.checkTypes(app.Stack, nil) {
+		rc := cfg.resolve(app.Stack, ct)
+		pc := PlannedCheck{
+			Type:    ct,
+			Stack:   rc.stack,
+			Command: strings.Join(rc.args, " "),
+			Source:  rc.source,
+		}
+		switch {
+		case rc.disabled:
+			pc.Skip = "disabled in " + rc.source
+		case len(rc.args) == 0:
+			pc.Skip = "no command"
+		default:
+			workDir := appDir
+			if rc.dir != "" {
+				workDir = filepath.Join(appDir, rc.dir)
+			}
+			pc.Skip = skipReason(workDir, ct, rc.args)
+		}
+		plan.Checks = append(plan.Checks, pc)
+	}
+	return plan
+}
+
+// discrepancies lists the ways detect's answer differs from what check does
+func (e Explanation) discrepancies() []string {
+	var notes []string
+
+	inApps := make(map[string]bool)
+	for _, app := range e.Apps {
+		for _, s := range app.Stack {
+			inApps[s] = true
+		}
+	}
+	// Sub-dirs passed over because root covers part of their stack
+	explained := make(map[string]bool)
+	for _, sk := range e.Skipped {
+		var lost []string
+		for _, s := range sk.Stack {
+			if sk.Covered {
+				explained[s] = true
+			} else if !inApps[s] {
+				lost = append(lost, s)
+				explained[s] = true
+			}
+		}
+		if len(lost) > 0 {
+			notes = append(notes, fmt.Sprintf("%s: skipped (%s), so its %s is never checked",
+				appName(sk.Path), sk.Reason, strings.Join(lost, ", ")))
+		}
+	}
+
+	detected := make(map[string]bool)
+	for _, s := range e.Detected {
+		detected[s] = true
+		if !inApps[s] && !explained[s] {
+			notes = append(notes, fmt.Sprintf("detect reports %s, but no sub-app check runs has it", s))
+		}
+	}
+	for _, app := range e.Apps {
+		for _, s := range app.Stack {
+			if !detected[s] {
+				notes = append(notes, fmt.Sprintf("%s: check finds %s, but detect doesn't", appName(app.Path), s))
+			}
+		}
+	}
+
+	for _, app := range e.Apps {
+		runnable := 0
+		for _, c := range app.Checks {
+			if c.Skip == "" {
+				runnable++
+			}
+		}
+		if app.Error == nil && runnable == 0 {
+			notes = append(notes, fmt.Sprintf("%s: no checks would run", appName(app.Path)))
+		}
+	}
+
+	return notes
+}
+
+func appName(path string) string {
+	if path == "" {
+		return "root"
+	}
+	return path + "/"
+}
@@ -0,0 +1,98 @@
+package check
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/stack"
+)
+
+func TestExplain(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "go.mod"), "module root")
+	writeFile(t, filepath.Join(repo, "api", "go.mod"), "module api")
+	writeFile(t, filepath.Join(repo, "api", "pyproject.toml"), "")
+	writeFile(t, filepath.Join(repo, "web", "package.json"), `{"scripts": {"build": "tsc"}}`)
+	writeFile(t, filepath.Join(repo, "web", "tsconfig.json"), "{}")
+	writeFile(t, filepath.Join(repo, "web", RepoConfigFile), "checks:\n  typecheck:\n    disabled: true\n")
+
+	e := Explain(repo)
+
+	if strings.Join(e.Detected, ",") != "go,python,ts" {
+		t.Errorf("Detected = %v, want [go python ts]", e.Detected)
+	}
+
+	// Root, then api and web in search order
+	var paths []string
+	for _, d := range e.Dirs {
+		paths = append(paths, d.Path)
+	}
+	if strings.Join(paths, ",") != ",api,web" {
+		t.Errorf("Dirs = %v, want root, api, web", paths)
+	}
+	for _, ev := range e.Dirs[2].Evidence {
+		if ev.Stack == "js" && (ev.Matched || ev.Reason() != "package.json but tsconfig.json present") {
+			t.Errorf("web js evidence = %+v (%s)", ev, ev.Reason())
+		}
+	}
+	if !contains(e.Absent, "packages/*") || contains(e.Absent, "api") {
+		t.Errorf("Absent = %v", e.Absent)
+	}
+
+	// api overlaps root's go, so check passes over it and loses python
+	if len(e.Skipped) != 1 || e.Skipped[0].Path != "api" || e.Skipped[0].Reason != "root already covers go" {
+		t.Errorf("Skipped = %+v, want api covered by root", e.Skipped)
+	}
+
+	if len(e.Apps) != 2 || e.Apps[0].Path != "" || e.Apps[1].Path != "web" {
+		t.Fatalf("Apps = %+v, want root and web", e.Apps)
+	}
+	web := map[CheckType]PlannedCheck{}
+	for _, c := range e.Apps[1].Checks {
+		web[c.Type] = c
+	}
+	if web[CheckBuild].Command != "npm run build" || web[CheckBuild].Skip != "" || web[CheckBuild].Source != "builtin:ts" {
+		t.Errorf("web build = %+v", web[CheckBuild])
+	}
+	if web[CheckTest].Skip != "script not defined in package.json" {
+		t.Errorf("web test = %+v, want skipped for missing script", web[CheckTest])
+	}
+	if !strings.HasPrefix(web[CheckTypecheck].Skip, "disabled in ") {
+		t.Errorf("web typecheck = %+v, want disabled", web[CheckTypecheck])
+	}
+
+	if len(e.Notes) != 1 || !strings.Contains(e.Notes[0], "api/: skipped") || !strings.Contains(e.Notes[0], "python is never checked") {
+		t.Errorf("Notes = %v, want api's python flagged", e.Notes)
+	}
+}
+
+func TestExplainNoChecks(t *testing.T) {
+	reg, err := stack.New([]stack.Config{{Name: "docs", Markers: []string{"mkdocs.yml"}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stack.SetDefault(reg)
+	defer stack.SetDefault(nil)
+
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "mkdocs.yml"), "site_name: x")
+
+	e := Explain(repo)
+
+	if len(e.Apps) != 1 || len(e.Apps[0].Checks) != 0 {
+		t.Fatalf("Apps = %+v, want root with no checks", e.Apps)
+	}
+	if len(e.Notes) != 1 || e.Notes[0] != "root: no checks would run" {
+		t.Errorf("Notes = %v", e.Notes)
+	}
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
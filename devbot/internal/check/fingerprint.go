@@ -0,0 +1,288 @@
+package check
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sloanahrens/devbot-go/internal/git"
+)
+
+// fingerprintVersion is mixed into every key so cache entries from an
+// incompatible fingerprint scheme are never reused
+const fingerprintVersion = "2"
+
+// versionProbes are the toolchain binaries whose version is part of the
+// key, with the arguments that print it. Nothing else is ever run to
+// fingerprint a check.
+var versionProbes = map[string][]string{
+	"go":            {"version"},
+	"golangci-lint": {"--version"},
+	"node":          {"--version"},
+	"npm":           {"--version"},
+	"pnpm":          {"--version"},
+	"yarn":          {"--version"},
+	"bun":           {"--version"},
+	"python3":       {"--version"},
+	"uv":            {"--version"},
+	"ruff":          {"--version"},
+}
+
+// sharedFiles live in the repo root or a directory between it and a
+// sub-app, and change how the sub-app's checks behave: lockfiles and
+// workspace manifests, toolchain pins and lint/type configs that tools find
+// by searching upward.
+var sharedFiles = []string{
+	"go.work", "go.work.sum",
+	"package.json", "package-lock.json", "npm-shrinkwrap.json", "pnpm-lock.yaml",
+	"pnpm-workspace.yaml", "yarn.lock", "bun.lock", "bun.lockb", "turbo.json",
+	"uv.lock", "poetry.lock", "Cargo.toml", "Cargo.lock",
+	".nvmrc", ".node-version", ".python-version", ".tool-versions", "rust-toolchain.toml",
+	".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json",
+	"pyproject.toml", "setup.cfg", "ruff.toml", ".ruff.toml",
+	"tsconfig.json", "tsconfig.base.json",
+	"eslint.config.js", "eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts",
+	".eslintrc", ".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc.yaml",
+}
+
+// fingerprinter computes cache keys for checks in one repo. Tree hashes and
+// tool versions are memoized since they're shared across check types.
+type fingerprinter struct {
+	root     string
+	mu       sync.Mutex
+	trees    map[string]string
+	versions map[string]string
+}
+
+func newFingerprinter(root string) *fingerprinter {
+	return &fingerprinter{
+		root:     root,
+		trees:    make(map[string]string),
+		versions: make(map[string]string),
+	}
+}
+
+// key returns the cache key for running rc in appDir, or "" if the
+// directory isn't inside a git work tree (nothing stable to hash)
+func (f *fingerprinter) key(appDir string, checkType CheckType, rc resolvedCheck) string {
+	tree := f.treeHash(appDir)
+	if tree == "" {
+		return ""
+	}
+
+	workDir := appDir
+	if rc.dir != "" {
+		workDir = filepath.Join(appDir, rc.dir)
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "v%s\n", fingerprintVersion)
+	fmt.Fprintf(h, "tree %s\n", tree)
+	fmt.Fprintf(h, "type %s\n", checkType)
+	fmt.Fprintf(h, "stack %s\n", rc.stack)
+	fmt.Fprintf(h, "args %q\n", rc.args)
+	fmt.Fprintf(h, "env %q\n", rc.env)
+	fmt.Fprintf(h, "dir %s\n", rc.dir)
+	for _, tool := range toolsFor(rc.args) {
+		fmt.Fprintf(h, "tool %s %s\n", tool, f.toolVersion(workDir, tool))
+	}
+	for _, script := range scriptsFor(workDir, rc.args) {
+		fmt.Fprintf(h, "script %s %s\n", script, fileHash(filepath.Join(workDir, script)))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// treeHash hashes the tracked file contents under dir, dirty and untracked
+// files read from disk, and the shared files between dir and the repo root
+func (f *fingerprinter) treeHash(dir string) string {
+	f.mu.Lock()
+	if h, ok := f.trees[dir]; ok {
+		f.mu.Unlock()
+		return h
+	}
+	f.mu.Unlock()
+
+	h := computeTreeHash(f.root, dir)
+
+	f.mu.Lock()
+	f.trees[dir] = h
+	f.mu.Unlock()
+	return h
+}
+
+func computeTreeHash(root, dir string) string {
+	// Index entries: "<mode> <blob> <stage>\t<path>" for every tracked file
+	staged, err := git.OutputRaw(dir, "ls-files", "-s", "-z", "--", ".")
+	if err != nil {
+		return ""
+	}
+
+	// Tracked files whose working copy differs from the index
+	modified, err := git.OutputRaw(dir, "ls-files", "-m", "-z", "--", ".")
+	if err != nil {
+		return ""
+	}
+
+	// Untracked files that aren't ignored
+	untracked, err := git.OutputRaw(dir, "ls-files", "-o", "--exclude-standard", "-z", "--", ".")
+	if err != nil {
+		return ""
+	}
+
+	h := sha256.New()
+	h.Write([]byte(staged))
+
+	paths := splitNul(modified)
+	sort.Strings(paths)
+	for _, p := range paths {
+		fmt.Fprintf(h, "dirty %s %s\n", p, fileHash(filepath.Join(dir, p)))
+	}
+
+	// Binary untracked files are build outputs (a `go build` binary, .pyc,
+	// .o) rather than source, and would change the key after the first run
+	paths = splitNul(untracked)
+	sort.Strings(paths)
+	for _, p := range paths {
+		if isBinary(filepath.Join(dir, p)) {
+			continue
+		}
+		fmt.Fprintf(h, "untracked %s %s\n", p, fileHash(filepath.Join(dir, p)))
+	}
+
+	for _, p := range sharedPaths(root, dir) {
+		fmt.Fprintf(h, "shared %s %s\n", filepath.ToSlash(p), fileHash(filepath.Join(root, p)))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sharedPaths returns the shared files that exist in the directories above
+// dir up to and including root, relative to root
+func sharedPaths(root, dir string) []string {
+	root = filepath.Clean(root)
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+
+	var paths []string
+	for parent := filepath.Dir(rel); ; parent = filepath.Dir(parent) {
+		for _, name := range sharedFiles {
+			p := filepath.Join(parent, name)
+			if info, err := os.Stat(filepath.Join(root, p)); err == nil && info.Mode().IsRegular() {
+				paths = append(paths, p)
+			}
+		}
+		if parent == "." {
+			break
+		}
+	}
+	return paths
+}
+
+// fileHash hashes a file's contents, or returns "<missing>"
+func fileHash(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return "<missing>"
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "<unreadable>"
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isBinary uses git's heuristic: a NUL byte in the first 8000 bytes
+func isBinary(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	buf := make([]byte, 8000)
+	n, _ := io.ReadFull(file, buf)
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
+
+// toolVersion returns the first line of the tool's version output, run
+// from dir since go.mod toolchain lines and version pins are per directory
+func (f *fingerprinter) toolVersion(dir, tool string) string {
+	memo := dir + "\x00" + tool
+	f.mu.Lock()
+	if v, ok := f.versions[memo]; ok {
+		f.mu.Unlock()
+		return v
+	}
+	f.mu.Unlock()
+
+	v := "unknown"
+	cmd := exec.Command(tool, versionProbes[tool]...)
+	cmd.Dir = dir
+	if out, err := cmd.Output(); err == nil {
+		v = strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	}
+
+	f.mu.Lock()
+	f.versions[memo] = v
+	f.mu.Unlock()
+	return v
+}
+
+// toolsFor returns the toolchain binaries whose versions affect a command's
+// result. Anything not in versionProbes is left out.
+func toolsFor(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	var tools []string
+	if _, ok := versionProbes[args[0]]; ok {
+		tools = append(tools, args[0])
+	}
+	switch args[0] {
+	case "npm", "npx", "pnpm", "yarn":
+		tools = append(tools, "node")
+	case "uv":
+		tools = append(tools, "python3")
+	}
+	return tools
+}
+
+// scriptsFor returns the repo-local files a command runs, such as ./t.sh or
+// `sh ../scripts/check.sh`, as paths relative to workDir. Their contents are
+// hashed instead of running them; files outside the sub-app aren't covered
+// by the tree hash.
+func scriptsFor(workDir string, args []string) []string {
+	var scripts []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") || !strings.ContainsRune(arg, '/') || filepath.IsAbs(arg) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(workDir, arg)); err == nil && info.Mode().IsRegular() {
+			scripts = append(scripts, arg)
+		}
+	}
+	return scripts
+}
+
+func splitNul(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, "\x00") {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package check
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Finding is a single problem reported by a linter
+type Finding struct {
+	Tool    string // golangci-lint, ruff or eslint
+	Rule    string // Linter, rule code or rule name
+	Level   string // error or warning
+	File    string // Relative to the sub-app
+	Line    int
+	Column  int
+	Message string
+}
+
+var (
+	// "api/main.go:12:2: Error return value of `f.Close` is not checked (errcheck)"
+	golangciRe = regexp.MustCompile(`^(\S+\.go):(\d+)(?::(\d+))?: (.+) \(([\w-]+)\)$`)
+	// "app/main.py:1:8: F401 [*] `os` imported but unused"
+	ruffConciseRe = regexp.MustCompile(`^(\S+\.pyi?):(\d+):(\d+): ([A-Z]+[0-9]+) (?:\[\*\] )?(.+)$`)
+	// Ruff's full format puts the location on the line after the code:
+	// "F401 [*] `os` imported but unused" then " --> app/main.py:1:8"
+	ruffCodeRe     = regexp.MustCompile(`^([A-Z]+[0-9]+) (?:\[\*\] )?(.+)$`)
+	ruffLocationRe = regexp.MustCompile(`^\s*--> (\S+):(\d+):(\d+)$`)
+	// eslint's stylish format lists problems under each file:
+	// "  12:5  error  'x' is defined but never used  no-unused-vars"
+	// next lint writes them unindented, as "12:5  Error: ..."
+	eslintProblemRe = regexp.MustCompile(`^\s*(\d+):(\d+)\s+([Ee]rror|[Ww]arning):?\s+(.+?)(?:\s{2,}(\S+))?$`)
+	eslintFileRe    = regexp.MustCompile(`\.(?:[cm]?[jt]sx?|vue|svelte|astro)$`)
+)
+
+// parseLintFindings extracts golangci-lint, ruff and eslint problems from a
+// lint check's output. Paths are made relative to workDir.
+func parseLintFindings(out, workDir string) []Finding {
+	var findings []Finding
+	var eslintFile string
+	var ruffPending *Finding
+
+	for _, l := range strings.Split(out, "\n") {
+		l = strings.TrimRight(ansiRe.ReplaceAllString(l, ""), " \r")
+
+		if ruffPending != nil {
+			if m := ruffLocationRe.FindStringSubmatch(l); m != nil {
+				f := *ruffPending
+				f.File = m[1]
+				f.Line, _ = strconv.Atoi(m[2])
+				f.Column, _ = strconv.Atoi(m[3])
+				findings = append(findings, f)
+			}
+			ruffPending = nil
+		}
+
+		if m := golangciRe.FindStringSubmatch(l); m != nil {
+			f := Finding{Tool: "golangci-lint", Rule: m[5], Level: "error", File: m[1], Message: m[4]}
+			f.Line, _ = strconv.Atoi(m[2])
+			f.Column, _ = strconv.Atoi(m[3])
+			findings = append(findings, f)
+			continue
+		}
+		if m := ruffConciseRe.FindStringSubmatch(l); m != nil {
+			f := Finding{Tool: "ruff", Rule: m[4], Level: "error", File: m[1], Message: m[5]}
+			f.Line, _ = strconv.Atoi(m[2])
+			f.Column, _ = strconv.Atoi(m[3])
+			findings = append(findings, f)
+			continue
+		}
+		if m := ruffCodeRe.FindStringSubmatch(l); m != nil {
+			ruffPending = &Finding{Tool: "ruff", Rule: m[1], Level: "error", Message: m[2]}
+			continue
+		}
+
+		// A file header starts a block of eslint problems
+		if l != "" && !strings.HasPrefix(l, " ") && eslintFileRe.MatchString(l) &&
+			(filepath.IsAbs(l) || strings.HasPrefix(l, "./")) {
+			eslintFile = l
+			continue
+		}
+		if eslintFile != "" {
+			if m := eslintProblemRe.FindStringSubmatch(l); m != nil {
+				f := Finding{
+					Tool:    "eslint",
+					Rule:    m[5],
+					Level:   strings.ToLower(m[3]),
+					File:    eslintFile,
+					Message: m[4],
+				}
+				f.Line, _ = strconv.Atoi(m[1])
+				f.Column, _ = strconv.Atoi(m[2])
+				findings = append(findings, f)
+				continue
+			}
+			if l == "" || strings.HasPrefix(l, "✖") {
+				eslintFile = ""
+			}
+		}
+	}
+
+	for i, f := range findings {
+		findings[i].File = relPath(workDir, f.File)
+	}
+	return findings
+}
+
+// relPath makes an absolute path under dir relative to it, and cleans
+// relative ones
+func relPath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+		return path
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,79 @@
+package check
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLintFindings(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		out  string
+		want []Finding
+	}{
+		{
+			"golangci-lint",
+			"level=warning msg=\"[config_reader] deprecated option\"\n" +
+				"api/main.go:12:2: Error return value of `f.Close` is not checked (errcheck)\n" +
+				"\tf.Close()\n\t^\n" +
+				"util.go:7: File is not `gofmt`-ed with `-s` (gofmt)\n" +
+				"2 issues:\n",
+			[]Finding{
+				{Tool: "golangci-lint", Rule: "errcheck", Level: "error", File: filepath.Join("api", "main.go"), Line: 12, Column: 2, Message: "Error return value of `f.Close` is not checked"},
+				{Tool: "golangci-lint", Rule: "gofmt", Level: "error", File: "util.go", Line: 7, Message: "File is not `gofmt`-ed with `-s`"},
+			},
+		},
+		{
+			"ruff concise",
+			"app/main.py:1:8: F401 [*] `os` imported but unused\nFound 1 error.\n",
+			[]Finding{
+				{Tool: "ruff", Rule: "F401", Level: "error", File: filepath.Join("app", "main.py"), Line: 1, Column: 8, Message: "`os` imported but unused"},
+			},
+		},
+		{
+			"ruff full",
+			"E501 Line too long (120 > 88)\n --> app/models.py:14:89\n   |\n14 | x = 1\n   |\n\nFound 1 error.\n",
+			[]Finding{
+				{Tool: "ruff", Rule: "E501", Level: "error", File: filepath.Join("app", "models.py"), Line: 14, Column: 89, Message: "Line too long (120 > 88)"},
+			},
+		},
+		{
+			"eslint stylish",
+			"\n> web@1.0.0 lint\n> eslint .\n\n" +
+				filepath.Join(dir, "src", "a.js") + "\n" +
+				"  1:10  error    'x' is defined but never used  no-unused-vars\n" +
+				"  3:1   warning  Unexpected console statement   no-console\n" +
+				"  9:4   error    Parsing error: Unexpected token\n" +
+				"\n✖ 3 problems (2 errors, 1 warning)\n",
+			[]Finding{
+				{Tool: "eslint", Rule: "no-unused-vars", Level: "error", File: filepath.Join("src", "a.js"), Line: 1, Column: 10, Message: "'x' is defined but never used"},
+				{Tool: "eslint", Rule: "no-console", Level: "warning", File: filepath.Join("src", "a.js"), Line: 3, Column: 1, Message: "Unexpected console statement"},
+				{Tool: "eslint", Level: "error", File: filepath.Join("src", "a.js"), Line: 9, Column: 4, Message: "Parsing error: Unexpected token"},
+			},
+		},
+		{
+			"next lint",
+			"\n./app/page.tsx\n12:7  Error: 'data' is assigned a value but never used.  @typescript-eslint/no-unused-vars\n",
+			[]Finding{
+				{Tool: "eslint", Rule: "@typescript-eslint/no-unused-vars", Level: "error", File: filepath.Join("app", "page.tsx"), Line: 12, Column: 7, Message: "'data' is assigned a value but never used."},
+			},
+		},
+		{"no findings", "All checks passed!\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLintFindings(tt.out, dir)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseLintFindings = %+v, want %d findings", got, len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("finding %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sloanahrens/devbot-go/internal/node"
+	"github.com/sloanahrens/devbot-go/internal/stack"
+	"gopkg.in/yaml.v3"
+)
+
+// RepoConfigFile is the per-repo (or per-sub-app) override file read by Run
+const RepoConfigFile = ".devbot.yaml"
+
+// RepoConfig is the parsed contents of a .devbot.yaml file
+//
+// Example:
+//
+//	env:
+//	  CI: "1"
+//	checks:
+//	  test:
+//	    command: go test -tags integration ./...
+//	  lint:
+//	    disabled: true
+//	  e2e:
+//	    command: [make, e2e]
+//	    dir: tests
+//	apps:
+//	  nextapp:
+//	    checks:
+//	      lint:
+//	        command: pnpm lint
+type RepoConfig struct {
+	Env    map[string]string      `yaml:"env"`
+	Checks map[string]CheckConfig `yaml:"checks"`
+	Apps   map[string]RepoConfig  `yaml:"apps"` // per-sub-app overrides (repo root only)
+}
+
+// CheckConfig overrides or defines a single check
+type CheckConfig struct {
+	Command  Command           `yaml:"command"`  // replaces the built-in command
+	Fix      Command           `yaml:"fix"`      // command to use with --fix
+	Env      map[string]string `yaml:"env"`      // extra environment variables
+	Dir      string            `yaml:"dir"`      // working dir relative to the sub-app
+	Disabled bool              `yaml:"disabled"` // skip this check entirely
+}
+
+// Command is a command line, written in YAML as either a string or a list
+type Command = stack.Command
+
+// configuredCheck is a CheckConfig along with the file it came from
+type configuredCheck struct {
+	CheckConfig
+	source string
+}
+
+// appConfig is the merged .devbot.yaml configuration for one sub-app
+type appConfig struct {
+	env    map[string]string
+	checks map[CheckType]configuredCheck
+
+	js          *node.Tool // package manager for JS stacks
+	jsWorkspace bool       // the sub-app is a workspace root that runs scripts across packages
+}
+
+// resolvedCheck is the command that will actually run for a check
+type resolvedCheck struct {
+	args     []string
+	base     []string // built-in command before adapting it to the package manager
+	fixArgs  []string
+	env      []string // KEY=VALUE, sorted
+	dir      string   // relative to the sub-app
+	stack    string   // stack the built-in command came from
+	source   string   // "builtin:<stack>" or the .devbot.yaml that defined it
+	builtin  bool
+	js       bool // built-in command from a JS stack
+	disabled bool
+}
+
+// LoadRepoConfig reads a .devbot.yaml from dir. It returns nil, nil if the file doesn't exist.
+func LoadRepoConfig(dir string) (*RepoConfig, error) {
+	data, err := os.ReadFile(filepath.Join(dir, RepoConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cfg RepoConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Join(dir, RepoConfigFile), err)
+	}
+	return &cfg, nil
+}
+
+// loadAppConfig merges the configuration that applies to a sub-app.
+// Layers, lowest to highest priority:
+//  1. repo-root .devbot.yaml (env, and checks if the sub-app is the root)
+//  2. repo-root .devbot.yaml apps.<path>
+//  3. <path>/.devbot.yaml
+//
+// It also detects the package manager used for JS stacks.
+func loadAppConfig(repoPath, appPath string) (appConfig, error) {
+	cfg := appConfig{
+		env:    make(map[string]string),
+		checks: make(map[CheckType]configuredCheck),
+	}
+
+	root, err := LoadRepoConfig(repoPath)
+	if err != nil {
+		return cfg, err
+	}
+	if root != nil {
+		cfg.merge(root.Env, nil, "")
+		if appPath == "" {
+			cfg.merge(nil, root.Checks, RepoConfigFile)
+		} else if app, ok := root.Apps[appPath]; ok {
+			cfg.merge(app.Env, app.Checks, fmt.Sprintf("%s (apps.%s)", RepoConfigFile, appPath))
+		}
+	}
+
+	if appPath != "" {
+		sub, err := LoadRepoConfig(filepath.Join(repoPath, appPath))
+		if err != nil {
+			return cfg, err
+		}
+		if sub != nil {
+			cfg.merge(sub.Env, sub.Checks, filepath.Join(appPath, RepoConfigFile))
+		}
+	}
+
+	appDir := filepath.Join(repoPath, appPath)
+	tool := node.Detect(appDir, repoPath)
+	cfg.js = &tool
+	cfg.jsWorkspace = tool.Root == filepath.Clean(appDir) && tool.WorkspaceRuns()
+
+	return cfg, nil
+}
+
+// merge layers env vars and checks on top of the current config.
+// A check defined in a later layer replaces the earlier definition entirely.
+func (a *appConfig) merge(env map[string]string, checks map[string]CheckConfig, source string) {
+	for k, v := range env {
+		a.env[k] = v
+	}
+	for name, c := range checks {
+		a.checks[CheckType(name)] = configuredCheck{CheckConfig: c, source: source}
+	}
+}
+
+// checkTypes returns the checks to run: built-in checks for the stack,
+// plus any configured checks, in standard order followed by custom checks
+// sorted by name. An explicit --only list is returned unchanged.
+func (a *appConfig) checkTypes(stack []string, only []CheckType) []CheckType {
+	if len(only) > 0 {
+		return only
+	}
+
+	checks := determineChecks(stack, nil)
+
+	standard := map[CheckType]bool{}
+	for _, ct := range []CheckType{CheckLint, CheckTypecheck, CheckBuild, CheckTest} {
+		standard[ct] = true
+	}
+
+	var custom []CheckType
+	for ct, c := range a.checks {
+		if c.Disabled && !containsCheck(checks, ct) {
+			continue
+		}
+		if standard[ct] {
+			checks = appendCheckUnique(checks, ct)
+		} else {
+			custom = append(custom, ct)
+		}
+	}
+
+	// Keep standard checks in canonical order
+	var ordered []CheckType
+	for _, ct := range []CheckType{CheckLint, CheckTypecheck, CheckBuild, CheckTest} {
+		if containsCheck(checks, ct) {
+			ordered = append(ordered, ct)
+		}
+	}
+
+	sort.Slice(custom, func(i, j int) bool { return custom[i] < custom[j] })
+	return append(ordered, custom...)
+}
+
+// resolve returns the command for a check, preferring configuration over built-ins
+func (a *appConfig) resolve(stacks []string, checkType CheckType) resolvedCheck {
+	rc := resolvedCheck{}
+
+	// Built-in command for the stack
+	if args, s := stack.Default().Command(stacks, string(checkType)); args != nil {
+		def, _ := stack.Default().Lookup(s)
+		rc.args = args
+		rc.base = args
+		rc.stack = s
+		rc.source = def.Source + ":" + s
+		rc.builtin = true
+		if def.JS {
+			rc.js = true
+			rc.args = a.adaptJS(args, checkType)
+		}
+	}
+
+	envMap := make(map[string]string)
+	for k, v := range a.env {
+		envMap[k] = v
+	}
+
+	if c, ok := a.checks[checkType]; ok {
+		if c.Disabled {
+			rc.disabled = true
+			rc.source = c.source
+			return rc
+		}
+		if len(c.Command) > 0 {
+			rc.args = c.Command
+			rc.source = c.source
+			rc.builtin = false
+		}
+		rc.fixArgs = c.Fix
+		rc.dir = c.Dir
+		for k, v := range c.Env {
+			envMap[k] = v
+		}
+	}
+
+	rc.env = envList(envMap)
+	return rc
+}
+
+// adaptJS rewrites a built-in npm command for the sub-app's package manager.
+// At a workspace root the check instead runs the script of the same name in
+// every package, through turbo or the manager's recursive run.
+func (a *appConfig) adaptJS(args []string, checkType CheckType) []string {
+	if a.js == nil {
+		return args
+	}
+	if a.jsWorkspace {
+		return a.js.WorkspaceRun(string(checkType), node.ScriptArgs(args)...)
+	}
+	return a.js.Adapt(args)
+}
+
+// envList converts an env map into sorted KEY=VALUE pairs
+func envList(env map[string]string) []string {
+	var out []string
+	for k, v := range env {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func containsCheck(checks []CheckType, ct CheckType) bool {
+	for _, c := range checks {
+		if c == ct {
+			return true
+		}
+	}
+	return false
+}
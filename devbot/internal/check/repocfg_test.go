@@ -0,0 +1,263 @@
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestCommandUnmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"string", "command: make test", []string{"make", "test"}},
+		{"list", "command: [go, test, -run, 'Test Foo']", []string{"go", "test", "-run", "Test Foo"}},
+		{"extra spaces", "command: '  pnpm   lint '", []string{"pnpm", "lint"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c CheckConfig
+			if err := yaml.Unmarshal([]byte(tt.input), &c); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if len(c.Command) != len(tt.want) {
+				t.Fatalf("Command = %q, want %q", c.Command, tt.want)
+			}
+			for i := range tt.want {
+				if c.Command[i] != tt.want[i] {
+					t.Errorf("Command[%d] = %q, want %q", i, c.Command[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	var c CheckConfig
+	if err := yaml.Unmarshal([]byte("command: {a: b}"), &c); err == nil {
+		t.Error("expected error for map command")
+	}
+}
+
+func TestLoadAppConfigLayers(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, RepoConfigFile), `
+env:
+  SHARED: root
+checks:
+  test:
+    command: make test
+apps:
+  go-api:
+    env:
+      SHARED: app
+    checks:
+      lint:
+        command: golangci-lint run --fast
+      build:
+        disabled: true
+`)
+	writeFile(t, filepath.Join(repo, "go-api", RepoConfigFile), `
+checks:
+  lint:
+    command: [make, lint]
+`)
+
+	t.Run("root app", func(t *testing.T) {
+		cfg, err := loadAppConfig(repo, "")
+		if err != nil {
+			t.Fatalf("loadAppConfig failed: %v", err)
+		}
+		test, ok := cfg.checks[CheckTest]
+		if !ok {
+			t.Fatal("expected test check from root config")
+		}
+		if test.source != RepoConfigFile {
+			t.Errorf("source = %q, want %q", test.source, RepoConfigFile)
+		}
+		if cfg.env["SHARED"] != "root" {
+			t.Errorf("env SHARED = %q, want root", cfg.env["SHARED"])
+		}
+	})
+
+	t.Run("sub-app file wins over apps section", func(t *testing.T) {
+		cfg, err := loadAppConfig(repo, "go-api")
+		if err != nil {
+			t.Fatalf("loadAppConfig failed: %v", err)
+		}
+		if _, ok := cfg.checks[CheckTest]; ok {
+			t.Error("root checks should not apply to sub-apps")
+		}
+		lint := cfg.checks[CheckLint]
+		if lint.source != filepath.Join("go-api", RepoConfigFile) {
+			t.Errorf("lint source = %q, want go-api/.devbot.yaml", lint.source)
+		}
+		if len(lint.Command) != 2 || lint.Command[0] != "make" {
+			t.Errorf("lint command = %q, want [make lint]", lint.Command)
+		}
+		if !cfg.checks[CheckBuild].Disabled {
+			t.Error("build should be disabled via apps section")
+		}
+		if cfg.env["SHARED"] != "app" {
+			t.Errorf("env SHARED = %q, want app", cfg.env["SHARED"])
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		bad := t.TempDir()
+		writeFile(t, filepath.Join(bad, RepoConfigFile), "checks: [")
+		if _, err := loadAppConfig(bad, ""); err == nil {
+			t.Error("expected error for invalid yaml")
+		}
+
+		// With no detectable stack the config is the only source of checks;
+		// a parse error must fail the run rather than pass with nothing run
+		result := Run(workspace.RepoInfo{Name: "bad", Path: bad}, nil, false)
+		if result.Passed() || len(result.Checks) != 1 || result.Checks[0].Type != "config" {
+			t.Errorf("Checks = %+v, want one failed config check", result.Checks)
+		}
+		if e := Explain(bad); len(e.Apps) != 1 || e.Apps[0].Error == nil {
+			t.Errorf("Explain Apps = %+v, want the config error", e.Apps)
+		}
+	})
+}
+
+func TestAppConfigCheckTypes(t *testing.T) {
+	cfg := appConfig{checks: map[CheckType]configuredCheck{
+		"e2e":          {CheckConfig: CheckConfig{Command: Command{"make", "e2e"}}},
+		"audit":        {CheckConfig: CheckConfig{Command: Command{"make", "audit"}}},
+		CheckTypecheck: {CheckConfig: CheckConfig{Command: Command{"make", "typecheck"}}},
+		CheckLint:      {CheckConfig: CheckConfig{Disabled: true}},
+		"gone":         {CheckConfig: CheckConfig{Disabled: true}},
+	}}
+
+	got := cfg.checkTypes([]string{"go"}, nil)
+	want := []CheckType{CheckLint, CheckTypecheck, CheckBuild, CheckTest, "audit", "e2e"}
+
+	if len(got) != len(want) {
+		t.Fatalf("checkTypes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("checkTypes()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	only := cfg.checkTypes([]string{"go"}, []CheckType{"e2e"})
+	if len(only) != 1 || only[0] != "e2e" {
+		t.Errorf("checkTypes(only=e2e) = %v, want [e2e]", only)
+	}
+}
+
+func TestAppConfigResolve(t *testing.T) {
+	cfg := appConfig{
+		env: map[string]string{"B": "2", "A": "1"},
+		checks: map[CheckType]configuredCheck{
+			CheckTest:  {CheckConfig: CheckConfig{Command: Command{"make", "test"}, Env: map[string]string{"C": "3"}}, source: RepoConfigFile},
+			CheckBuild: {CheckConfig: CheckConfig{Dir: "cmd"}, source: RepoConfigFile},
+			CheckLint:  {CheckConfig: CheckConfig{Disabled: true}, source: RepoConfigFile},
+		},
+	}
+
+	test := cfg.resolve([]string{"go"}, CheckTest)
+	if test.builtin || test.source != RepoConfigFile {
+		t.Errorf("test should come from config, got source %q", test.source)
+	}
+	if len(test.env) != 3 || test.env[0] != "A=1" || test.env[2] != "C=3" {
+		t.Errorf("test env = %v, want sorted [A=1 B=2 C=3]", test.env)
+	}
+
+	build := cfg.resolve([]string{"go"}, CheckBuild)
+	if !build.builtin || build.source != "builtin:go" || build.dir != "cmd" {
+		t.Errorf("build = %+v, want builtin go command in cmd/", build)
+	}
+
+	lint := cfg.resolve([]string{"go"}, CheckLint)
+	if !lint.disabled {
+		t.Error("lint should be disabled")
+	}
+
+	empty := appConfig{}
+	typecheck := empty.resolve([]string{"go"}, CheckTypecheck)
+	if len(typecheck.args) != 0 {
+		t.Errorf("go has no built-in typecheck, got %v", typecheck.args)
+	}
+}
+
+func TestAppConfigPackageManager(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "pnpm-workspace.yaml"), "packages: [apps/*]")
+	writeFile(t, filepath.Join(repo, "pnpm-lock.yaml"), "")
+	writeFile(t, filepath.Join(repo, "apps", "web", "package.json"), "{}")
+
+	root, err := loadAppConfig(repo, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := root.resolve([]string{"monorepo"}, CheckLint); strings.Join(rc.args, " ") != "pnpm -r --if-present run lint" {
+		t.Errorf("root lint = %v, want a recursive pnpm run", rc.args)
+	}
+
+	web, err := loadAppConfig(repo, filepath.Join("apps", "web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := web.resolve([]string{"ts"}, CheckTypecheck)
+	if strings.Join(tc.args, " ") != "pnpm exec tsc --noEmit" || tc.source != "builtin:ts" {
+		t.Errorf("web typecheck = %+v, want pnpm exec tsc", tc)
+	}
+	if test := web.resolve([]string{"ts"}, CheckTest); strings.Join(test.args, " ") != "pnpm run test" {
+		t.Errorf("web test = %v, want pnpm run test", test.args)
+	}
+
+	// Configured commands are left alone
+	web.checks[CheckBuild] = configuredCheck{CheckConfig: CheckConfig{Command: Command{"npm", "run", "build"}}}
+	if build := web.resolve([]string{"ts"}, CheckBuild); strings.Join(build.args, " ") != "npm run build" {
+		t.Errorf("configured build = %v, want it unchanged", build.args)
+	}
+}
+
+func TestRunWithRepoConfig(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, RepoConfigFile), `
+checks:
+  smoke:
+    command: [sh, -c, 'test "$SMOKE" = yes']
+    env:
+      SMOKE: "yes"
+  lint:
+    disabled: true
+`)
+
+	result := Run(workspace.RepoInfo{Name: "cfg-only", Path: repo}, nil, false)
+
+	if len(result.SubApps) != 1 || result.SubApps[0].Path != "" {
+		t.Fatalf("SubApps = %+v, want root app from config", result.SubApps)
+	}
+	if len(result.Checks) != 1 {
+		t.Fatalf("Checks = %+v, want only smoke (lint disabled and not built-in)", result.Checks)
+	}
+
+	smoke := result.Checks[0]
+	if smoke.Type != "smoke" || smoke.Status != "pass" {
+		t.Errorf("smoke = %+v, want pass", smoke)
+	}
+	if smoke.Source != RepoConfigFile {
+		t.Errorf("smoke source = %q, want %q", smoke.Source, RepoConfigFile)
+	}
+}
@@ -0,0 +1,374 @@
+package check
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// "    handler_test.go:42: want 200, got 500"
+	goLocationRe = regexp.MustCompile(`^\s*([\w.\-]+\.go):(\d+):(?:\d+:)? ?`)
+	// "tests/test_api.py:17: AssertionError"
+	pyLocationRe = regexp.MustCompile(`^(\S+\.py):(\d+): `)
+	// "test tests::adds ... ok", "test src/lib.rs - add (line 5) ... ignored"
+	cargoResultRe = regexp.MustCompile(`^test (.+) \.\.\. (ok|FAILED|ignored)`)
+	// "thread 'tests::adds' panicked at src/lib.rs:10:5:" or, before Rust 1.73,
+	// "thread 'tests::adds' panicked at 'boom', src/lib.rs:10:5"
+	cargoPanicRe = regexp.MustCompile(`^thread '.*' panicked at (?:'(.*)', )?([^\s:]+):(\d+):\d+:?$`)
+	ansiRe       = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// goTestEvent is one line of `go test -json` output
+type goTestEvent struct {
+	Action      string
+	Package     string
+	Test        string
+	Elapsed     float64 // seconds
+	Output      string
+	ImportPath  string // build-output events (Go 1.24+)
+	FailedBuild string // package that failed to build
+}
+
+// parseGoTest reads `go test -json` output. It returns the report and the
+// plain test log rebuilt from the output events.
+func parseGoTest(out, modulePath string) (*TestReport, string) {
+	report := &TestReport{}
+	var text strings.Builder
+	output := make(map[string]*strings.Builder) // package + test -> output
+	failedTests := make(map[string]bool)        // packages with a failing test
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var ev goTestEvent
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &ev) != nil {
+			text.WriteString(line + "\n")
+			continue
+		}
+
+		key := ev.Package + "\x00" + ev.Test
+		if ev.Action == "build-output" {
+			key = ev.ImportPath + "\x00build"
+		}
+		switch ev.Action {
+		case "output", "build-output":
+			text.WriteString(ev.Output)
+			if output[key] == nil {
+				output[key] = &strings.Builder{}
+			}
+			output[key].WriteString(ev.Output)
+
+		case "pass", "fail", "skip":
+			tc := TestCase{
+				Suite:    ev.Package,
+				Name:     ev.Test,
+				Status:   ev.Action,
+				Duration: seconds(ev.Elapsed),
+			}
+			if ev.Action == "fail" {
+				var log string
+				if ev.FailedBuild != "" {
+					key = ev.FailedBuild + "\x00build"
+				}
+				if output[key] != nil {
+					log = output[key].String()
+				}
+				tc.Message, tc.File, tc.Line = goFailure(log)
+				if tc.File != "" {
+					tc.File = goSourcePath(ev.Package, modulePath, tc.File)
+				}
+			}
+
+			if ev.Test != "" {
+				if ev.Action == "fail" {
+					failedTests[ev.Package] = true
+				}
+				report.add(tc)
+			} else if ev.Action == "fail" && !failedTests[ev.Package] {
+				// The package failed outside any test: a build error, a panic
+				// in init or TestMain, or a timeout
+				report.add(tc)
+			}
+		}
+	}
+	return report, text.String()
+}
+
+// goFailure extracts the message and location from a failed test's output
+func goFailure(log string) (msg, file string, line int) {
+	var lines []string
+	indent, cont := -1, 0
+	for _, l := range strings.Split(log, "\n") {
+		trimmed := strings.TrimSpace(l)
+		if strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- ") ||
+			trimmed == "FAIL" || trimmed == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 {
+			indent, cont = n, n
+			// t.Error indents its continuation lines by another four spaces
+			if m := goLocationRe.FindStringSubmatch(l); m != nil {
+				file = m[1]
+				line, _ = strconv.Atoi(m[2])
+				l = l[len(m[0]):]
+				n, cont = 0, indent+4
+			}
+		} else if n > cont {
+			n = cont
+		}
+		lines = append(lines, l[n:])
+	}
+	return strings.Join(lines, "\n"), file, line
+}
+
+// goSourcePath turns a file name from test output into a path relative to
+// the module root, using the package's import path
+func goSourcePath(pkg, modulePath, file string) string {
+	if modulePath == "" || !strings.HasPrefix(pkg, modulePath) {
+		return file
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(pkg, modulePath), "/")
+	return filepath.Join(rel, file)
+}
+
+// junitCase is a <testcase> in a JUnit XML report
+type junitCase struct {
+	Classname string       `xml:"classname,attr"`
+	Name      string       `xml:"name,attr"`
+	File      string       `xml:"file,attr"`
+	Line      string       `xml:"line,attr"`
+	Time      string       `xml:"time,attr"`
+	Failure   *junitDetail `xml:"failure"`
+	Error     *junitDetail `xml:"error"`
+	Skipped   *junitDetail `xml:"skipped"`
+}
+
+type junitDetail struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// parseJUnit reads a JUnit XML report, as written by pytest --junitxml.
+// The root is either <testsuites> or a single <testsuite>.
+func parseJUnit(data []byte) *TestReport {
+	var doc struct {
+		Suites []struct {
+			Cases []junitCase `xml:"testcase"`
+		} `xml:"testsuite"`
+		Cases []junitCase `xml:"testcase"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	cases := doc.Cases
+	for _, s := range doc.Suites {
+		cases = append(cases, s.Cases...)
+	}
+
+	report := &TestReport{}
+	for _, c := range cases {
+		tc := TestCase{
+			Suite:  c.Classname,
+			Name:   c.Name,
+			Status: "pass",
+			File:   c.File,
+		}
+		if c.File != "" {
+			// pytest writes the 0-based line of the test function
+			if n, err := strconv.Atoi(c.Line); err == nil {
+				tc.Line = n + 1
+			}
+		}
+		if secs, err := strconv.ParseFloat(c.Time, 64); err == nil {
+			tc.Duration = seconds(secs)
+		}
+
+		detail := c.Failure
+		if detail == nil {
+			detail = c.Error
+		}
+		switch {
+		case detail != nil:
+			tc.Status = "fail"
+			tc.Message = strings.TrimSpace(detail.Message)
+			if tc.Message == "" {
+				tc.Message = strings.TrimSpace(detail.Text)
+			}
+			// The traceback's last frame is where the test failed
+			for _, l := range strings.Split(detail.Text, "\n") {
+				if m := pyLocationRe.FindStringSubmatch(l); m != nil {
+					tc.File = m[1]
+					tc.Line, _ = strconv.Atoi(m[2])
+				}
+			}
+		case c.Skipped != nil:
+			tc.Status = "skip"
+		}
+		report.add(tc)
+	}
+	return report
+}
+
+// jestReport is the output of jest --json, which vitest's json reporter
+// also writes
+type jestReport struct {
+	TestResults []struct {
+		Name             string `json:"name"` // Absolute path of the test file
+		Status           string `json:"status"`
+		Message          string `json:"message"`
+		AssertionResults []struct {
+			FullName        string   `json:"fullName"`
+			Title           string   `json:"title"`
+			Status          string   `json:"status"`
+			Duration        *float64 `json:"duration"` // Milliseconds
+			FailureMessages []string `json:"failureMessages"`
+			Location        *struct {
+				Line int `json:"line"`
+			} `json:"location"`
+		} `json:"assertionResults"`
+	} `json:"testResults"`
+}
+
+// parseJestJSON reads a jest or vitest JSON report, making test file paths
+// relative to workDir
+func parseJestJSON(data []byte, workDir string) *TestReport {
+	var doc jestReport
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	report := &TestReport{}
+	for _, file := range doc.TestResults {
+		rel := file.Name
+		if r, err := filepath.Rel(workDir, file.Name); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+
+		// A file that failed to load has no assertions, only a message
+		if len(file.AssertionResults) == 0 && file.Status == "failed" {
+			report.add(TestCase{
+				Suite:   rel,
+				Status:  "fail",
+				File:    rel,
+				Message: strings.TrimSpace(ansiRe.ReplaceAllString(file.Message, "")),
+			})
+			continue
+		}
+
+		for _, a := range file.AssertionResults {
+			tc := TestCase{Suite: rel, Name: a.FullName, File: rel}
+			if tc.Name == "" {
+				tc.Name = a.Title
+			}
+			switch a.Status {
+			case "passed":
+				tc.Status = "pass"
+			case "failed":
+				tc.Status = "fail"
+			default: // pending, skipped, todo, disabled
+				tc.Status = "skip"
+			}
+			if a.Duration != nil {
+				tc.Duration = time.Duration(*a.Duration * float64(time.Millisecond))
+			}
+			if a.Location != nil {
+				tc.Line = a.Location.Line
+			}
+			if tc.Status == "fail" {
+				msg := ansiRe.ReplaceAllString(strings.Join(a.FailureMessages, "\n"), "")
+				tc.Message = strings.TrimSpace(msg)
+				// The stack frame in the test file gives the failing line
+				if line := stackLine(msg, file.Name); line > 0 {
+					tc.Line = line
+				}
+			}
+			report.add(tc)
+		}
+	}
+	return report
+}
+
+// stackLine finds "<file>:<line>" in a stack trace and returns the line
+func stackLine(trace, file string) int {
+	i := strings.Index(trace, file+":")
+	if i < 0 {
+		return 0
+	}
+	rest := trace[i+len(file)+1:]
+	end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(rest)
+	}
+	n, _ := strconv.Atoi(rest[:end])
+	return n
+}
+
+// parseCargoTest reads the plain output of cargo test, which reports no
+// per-test durations
+func parseCargoTest(out string) *TestReport {
+	type detail struct {
+		msg  []string
+		file string
+		line int
+	}
+	var cases []TestCase
+	details := make(map[string]*detail)
+	var current *detail
+
+	for _, l := range strings.Split(out, "\n") {
+		if m := cargoResultRe.FindStringSubmatch(l); m != nil {
+			status := map[string]string{"ok": "pass", "FAILED": "fail", "ignored": "skip"}[m[2]]
+			cases = append(cases, TestCase{Name: m[1], Status: status})
+			continue
+		}
+
+		// "---- tests::adds stdout ----" starts a failure's captured output
+		if name, ok := strings.CutPrefix(l, "---- "); ok && strings.HasSuffix(name, " stdout ----") {
+			current = &detail{}
+			details[strings.TrimSuffix(name, " stdout ----")] = current
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if l == "failures:" || strings.HasPrefix(l, "test result:") {
+			current = nil
+			continue
+		}
+		if m := cargoPanicRe.FindStringSubmatch(l); m != nil {
+			current.file = m[2]
+			current.line, _ = strconv.Atoi(m[3])
+			if m[1] != "" {
+				current.msg = append(current.msg, m[1])
+			}
+			continue
+		}
+		if !strings.HasPrefix(l, "note: run with `RUST_BACKTRACE") {
+			current.msg = append(current.msg, l)
+		}
+	}
+
+	report := &TestReport{}
+	for _, tc := range cases {
+		if d := details[tc.Name]; d != nil && tc.Status == "fail" {
+			tc.Message = strings.TrimSpace(strings.Join(d.msg, "\n"))
+			tc.File = d.file
+			tc.Line = d.line
+		}
+		report.add(tc)
+	}
+	return report
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,260 @@
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TestReport is the per-test breakdown of a test check, parsed from the
+// runner's structured output
+type TestReport struct {
+	Format   string // go, junit, jest, vitest or cargo
+	Passed   int
+	Failed   int
+	Skipped  int
+	Duration time.Duration // Sum of the test durations the runner reported
+	Tests    []TestCase
+}
+
+// TestCase is a single test in a TestReport
+type TestCase struct {
+	Suite    string // Go package, test file or class
+	Name     string // Empty for a suite that failed outside any test (e.g. a build error)
+	Status   string // pass, fail, skip
+	File     string // Relative to the sub-app
+	Line     int
+	Message  string // Failure output
+	Duration time.Duration
+}
+
+// add appends a test case and counts it
+func (r *TestReport) add(tc TestCase) {
+	switch tc.Status {
+	case "pass":
+		r.Passed++
+	case "fail":
+		r.Failed++
+	case "skip":
+		r.Skipped++
+	}
+	r.Duration += tc.Duration
+	r.Tests = append(r.Tests, tc)
+}
+
+// Failures returns the failing tests. A Go test whose subtests failed is
+// left out, since the subtests carry the messages.
+func (r *TestReport) Failures() []TestCase {
+	failedChild := make(map[string]bool)
+	for _, tc := range r.Tests {
+		if tc.Status == "fail" {
+			if i := strings.LastIndex(tc.Name, "/"); i > 0 {
+				failedChild[tc.Suite+" "+tc.Name[:i]] = true
+			}
+		}
+	}
+
+	var failures []TestCase
+	for _, tc := range r.Tests {
+		if tc.Status == "fail" && !failedChild[tc.Suite+" "+tc.Name] {
+			failures = append(failures, tc)
+		}
+	}
+	return failures
+}
+
+// Summary returns counts like "41 passed, 2 failed, 1 skipped"
+func (r *TestReport) Summary() string {
+	parts := []string{fmt.Sprintf("%d passed", r.Passed)}
+	if r.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", r.Failed))
+	}
+	if r.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", r.Skipped))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Location returns "file:line", "file", or "" when the runner gave neither
+func (tc TestCase) Location() string {
+	switch {
+	case tc.File != "" && tc.Line > 0:
+		return fmt.Sprintf("%s:%d", tc.File, tc.Line)
+	case tc.File != "":
+		return tc.File
+	}
+	return ""
+}
+
+// reporter switches a test command to structured output and parses it
+type reporter struct {
+	format  string
+	args    []string // command with structured output enabled
+	file    string   // report file the command writes, if any
+	workDir string
+}
+
+// newReporter recognizes go test, pytest, jest, vitest and cargo test
+// commands. ok is false for other commands, which keep plain output.
+func newReporter(workDir string, args []string) (r *reporter, ok bool) {
+	if len(args) < 2 {
+		return nil, false
+	}
+	r = &reporter{workDir: workDir}
+
+	switch {
+	case args[0] == "go" && args[1] == "test":
+		r.format = "go"
+		r.args = args
+		if !containsArg(args, "-json") {
+			r.args = append([]string{"go", "test", "-json"}, args[2:]...)
+		}
+		return r, true
+
+	case args[0] == "cargo" && args[1] == "test":
+		r.format = "cargo"
+		r.args = args
+		return r, true
+
+	case containsArg(args, "pytest") || strings.HasSuffix(args[0], "/pytest"):
+		r.format = "junit"
+		if !r.tempFile("junit-*.xml") {
+			return nil, false
+		}
+		// xunit1 records each test's file and line
+		r.args = append(append([]string{}, args...), "--junitxml="+r.file, "-o", "junit_family=xunit1")
+		return r, true
+	}
+
+	runner := jsRunner(workDir, args)
+	if runner == "" {
+		return nil, false
+	}
+	r.format = runner
+	if !r.tempFile(runner + "-*.json") {
+		return nil, false
+	}
+	extra := []string{"--json", "--outputFile=" + r.file}
+	if runner == "vitest" {
+		// Keep the default reporter so the log still reads normally
+		extra = []string{"--reporter=default", "--reporter=json", "--outputFile=" + r.file}
+	}
+	r.args = append([]string{}, args...)
+	if args[0] == "npm" && !containsArg(args, "--") {
+		r.args = append(r.args, "--")
+	}
+	r.args = append(r.args, extra...)
+	return r, true
+}
+
+// jsRunner returns "jest" or "vitest" if the command runs one, directly or
+// through a package.json script. The reporter flags land at the end of the
+// command, so a script only counts when it is a single command whose
+// program is the runner: in "jest && eslint ." they'd be passed to eslint.
+func jsRunner(workDir string, args []string) string {
+	fields := args
+	if script := isNpmRunCommand(args); script != "" {
+		text := packageScripts(workDir)[script]
+		if strings.ContainsAny(text, "&|;<>`$()") {
+			return ""
+		}
+		fields = strings.Fields(text)
+	}
+	return jsProgram(fields)
+}
+
+// jsProgram returns the runner a command line starts, looking past
+// VAR=value assignments and launchers such as npx, cross-env or pnpm exec
+func jsProgram(fields []string) string {
+	for _, f := range fields {
+		switch {
+		case strings.Contains(f, "=") && !strings.HasPrefix(f, "-"):
+			continue
+		case f == "npx" || f == "bunx" || f == "cross-env" || f == "exec" || f == "dlx":
+			continue
+		case f == "npm" || f == "pnpm" || f == "yarn" || f == "bun":
+			continue
+		}
+		for _, runner := range []string{"vitest", "jest"} {
+			if f == runner || strings.HasSuffix(f, "/"+runner) {
+				return runner
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+func (r *reporter) tempFile(pattern string) bool {
+	f, err := os.CreateTemp("", "devbot-"+pattern)
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	// The runner creates the file; an empty one left behind would parse as a report
+	_ = os.Remove(f.Name())
+	r.file = f.Name()
+	return true
+}
+
+// parse reads the report from stdout or the report file. It also returns the
+// readable log when the structured output replaced it (go test -json).
+func (r *reporter) parse(stdout string) (*TestReport, string) {
+	var report *TestReport
+	text := stdout
+
+	switch r.format {
+	case "go":
+		report, text = parseGoTest(stdout, goModulePath(r.workDir))
+	case "cargo":
+		report = parseCargoTest(stdout)
+	case "junit", "jest", "vitest":
+		data, err := os.ReadFile(r.file)
+		if err != nil {
+			return nil, stdout
+		}
+		if r.format == "junit" {
+			report = parseJUnit(data)
+		} else {
+			report = parseJestJSON(data, r.workDir)
+		}
+	}
+
+	if report == nil || len(report.Tests) == 0 {
+		return nil, text
+	}
+	report.Format = r.format
+	return report, text
+}
+
+// cleanup removes the report file
+func (r *reporter) cleanup() {
+	if r.file != "" {
+		_ = os.Remove(r.file)
+	}
+}
+
+// goModulePath returns the module path declared in dir's go.mod
+func goModulePath(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+func containsArg(args []string, arg string) bool {
+	for _, a := range args {
+		if a == arg {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+package check
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewReporter(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "package.json"), `{"scripts": {
+		"test": "vitest run", "unit": "jest --ci", "ci": "NODE_ENV=test npx jest",
+		"both": "jest && eslint .", "lint-first": "eslint . && vitest run", "wrapped": "node scripts/test.js jest"
+	}}`)
+
+	tests := []struct {
+		name   string
+		args   []string
+		format string
+		want   string // command with structured output, report file as FILE
+	}{
+		{"go test", []string{"go", "test", "./..."}, "go", "go test -json ./..."},
+		{"go test -json", []string{"go", "test", "-json", "./..."}, "go", "go test -json ./..."},
+		{"cargo", []string{"cargo", "test"}, "cargo", "cargo test"},
+		{"pytest", []string{"uv", "run", "pytest"}, "junit", "uv run pytest --junitxml=FILE -o junit_family=xunit1"},
+		{"vitest script", []string{"npm", "test"}, "vitest", "npm test -- --reporter=default --reporter=json --outputFile=FILE"},
+		{"jest script", []string{"pnpm", "run", "unit"}, "jest", "pnpm run unit --json --outputFile=FILE"},
+		{"env and launcher", []string{"yarn", "run", "ci"}, "jest", "yarn run ci --json --outputFile=FILE"},
+		{"direct jest", []string{"npx", "jest"}, "jest", "npx jest --json --outputFile=FILE"},
+		{"pnpm exec", []string{"pnpm", "exec", "vitest"}, "vitest", "pnpm exec vitest --reporter=default --reporter=json --outputFile=FILE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := newReporter(dir, tt.args)
+			if !ok {
+				t.Fatalf("newReporter(%v) not recognized", tt.args)
+			}
+			defer r.cleanup()
+			got := strings.Join(r.args, " ")
+			if r.file != "" {
+				got = strings.ReplaceAll(got, r.file, "FILE")
+			}
+			if r.format != tt.format || got != tt.want {
+				t.Errorf("newReporter = %s %q, want %s %q", r.format, got, tt.format, tt.want)
+			}
+		})
+	}
+
+	for _, args := range [][]string{
+		{"make", "test"}, {"npm", "run", "lint"}, {"go", "build"},
+		{"pnpm", "run", "both"}, {"npm", "run", "lint-first"}, {"bun", "run", "wrapped"},
+	} {
+		if _, ok := newReporter(dir, args); ok {
+			t.Errorf("newReporter(%v) should not be recognized", args)
+		}
+	}
+}
+
+func TestParseGoTest(t *testing.T) {
+	out := `{"Action":"start","Package":"example.com/app/api"}
+{"Action":"run","Package":"example.com/app/api","Test":"TestGet"}
+{"Action":"output","Package":"example.com/app/api","Test":"TestGet","Output":"=== RUN   TestGet\n"}
+{"Action":"output","Package":"example.com/app/api","Test":"TestGet","Output":"--- PASS: TestGet (0.02s)\n"}
+{"Action":"pass","Package":"example.com/app/api","Test":"TestGet","Elapsed":0.02}
+{"Action":"run","Package":"example.com/app/api","Test":"TestPost"}
+{"Action":"run","Package":"example.com/app/api","Test":"TestPost/empty"}
+{"Action":"output","Package":"example.com/app/api","Test":"TestPost/empty","Output":"=== RUN   TestPost/empty\n"}
+{"Action":"output","Package":"example.com/app/api","Test":"TestPost/empty","Output":"    handler_test.go:42: status = 500\n"}
+{"Action":"output","Package":"example.com/app/api","Test":"TestPost/empty","Output":"        want 400\n"}
+{"Action":"output","Package":"example.com/app/api","Test":"TestPost/empty","Output":"    --- FAIL: TestPost/empty (0.10s)\n"}
+{"Action":"fail","Package":"example.com/app/api","Test":"TestPost/empty","Elapsed":0.1}
+{"Action":"output","Package":"example.com/app/api","Test":"TestPost","Output":"--- FAIL: TestPost (0.10s)\n"}
+{"Action":"fail","Package":"example.com/app/api","Test":"TestPost","Elapsed":0.1}
+{"Action":"output","Package":"example.com/app/api","Test":"TestSlow","Output":"--- SKIP: TestSlow (0.00s)\n"}
+{"Action":"skip","Package":"example.com/app/api","Test":"TestSlow","Elapsed":0}
+{"Action":"output","Package":"example.com/app/api","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/app/api","Elapsed":0.15}
+{"ImportPath":"example.com/app/db","Action":"build-output","Output":"# example.com/app/db\n"}
+{"ImportPath":"example.com/app/db","Action":"build-output","Output":"db/conn.go:3:2: undefined: sql\n"}
+{"Action":"start","Package":"example.com/app/db"}
+{"Action":"output","Package":"example.com/app/db","Output":"FAIL\texample.com/app/db [build failed]\n"}
+{"Action":"fail","Package":"example.com/app/db","Elapsed":0,"FailedBuild":"example.com/app/db"}
+`
+	report, text := parseGoTest(out, "example.com/app")
+
+	if report.Passed != 1 || report.Failed != 3 || report.Skipped != 1 {
+		t.Errorf("counts = %s, want 1 passed, 3 failed, 1 skipped", report.Summary())
+	}
+	if report.Duration != 220*time.Millisecond {
+		t.Errorf("Duration = %v", report.Duration)
+	}
+	if !strings.Contains(text, "--- FAIL: TestPost (0.10s)") || strings.Contains(text, `"Action"`) {
+		t.Errorf("text log = %q, want plain test output", text)
+	}
+
+	failures := report.Failures()
+	if len(failures) != 2 {
+		t.Fatalf("Failures = %+v, want TestPost/empty and the db build", failures)
+	}
+	sub := failures[0]
+	if sub.Name != "TestPost/empty" || sub.Location() != filepath.Join("api", "handler_test.go")+":42" {
+		t.Errorf("failure = %s at %s", sub.Name, sub.Location())
+	}
+	if sub.Message != "status = 500\nwant 400" {
+		t.Errorf("Message = %q", sub.Message)
+	}
+	build := failures[1]
+	if build.Suite != "example.com/app/db" || build.Name != "" || !strings.Contains(build.Message, "undefined: sql") {
+		t.Errorf("build failure = %+v", build)
+	}
+}
+
+func TestParseJUnit(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<testsuites><testsuite name="pytest" errors="0" failures="1" skipped="1" tests="3" time="0.5">
+<testcase classname="tests.test_api" name="test_get" file="tests/test_api.py" line="4" time="0.010" />
+<testcase classname="tests.test_api" name="test_post" file="tests/test_api.py" line="9" time="0.250">
+<failure message="assert 500 == 200">def test_post():
+&gt;       assert post().status == 200
+E       assert 500 == 200
+
+tests/test_api.py:11: AssertionError</failure></testcase>
+<testcase classname="tests.test_api" name="test_slow" file="tests/test_api.py" line="14" time="0.000">
+<skipped type="pytest.skip" message="slow">skipped</skipped></testcase>
+</testsuite></testsuites>`)
+
+	report := parseJUnit(data)
+	if report == nil || report.Summary() != "1 passed, 1 failed, 1 skipped" {
+		t.Fatalf("report = %+v", report)
+	}
+	if get := report.Tests[0]; get.Location() != "tests/test_api.py:5" || get.Duration != 10*time.Millisecond {
+		t.Errorf("test_get = %+v, want 1-based line", get)
+	}
+	f := report.Failures()[0]
+	if f.Name != "test_post" || f.Suite != "tests.test_api" || f.Location() != "tests/test_api.py:11" || f.Message != "assert 500 == 200" {
+		t.Errorf("failure = %+v", f)
+	}
+
+	// A bare <testsuite> root works too
+	single := parseJUnit([]byte(`<testsuite><testcase classname="a" name="b"/></testsuite>`))
+	if single == nil || single.Passed != 1 {
+		t.Errorf("single suite = %+v", single)
+	}
+}
+
+func TestParseJestJSON(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "src", "sum.test.ts")
+	data := []byte(`{
+  "numFailedTests": 1,
+  "testResults": [
+    {
+      "name": "` + file + `",
+      "status": "failed",
+      "assertionResults": [
+        {"fullName": "sum adds", "title": "adds", "status": "passed", "duration": 3, "failureMessages": []},
+        {"fullName": "sum subtracts", "title": "subtracts", "status": "failed", "duration": 5,
+         "failureMessages": ["\u001b[31mError: expect(received).toBe(expected)\u001b[39m\n    at Object.<anonymous> (` + file + `:12:17)"],
+         "location": {"line": 10, "column": 3}},
+        {"fullName": "sum later", "title": "later", "status": "todo", "duration": null, "failureMessages": []}
+      ]
+    },
+    {"name": "` + filepath.Join(dir, "src", "broken.test.ts") + `", "status": "failed", "message": "SyntaxError: Unexpected token", "assertionResults": []}
+  ]
+}`)
+
+	report := parseJestJSON(data, dir)
+	if report == nil || report.Summary() != "1 passed, 2 failed, 1 skipped" {
+		t.Fatalf("report = %+v", report)
+	}
+	failures := report.Failures()
+	sub := failures[0]
+	if sub.Name != "sum subtracts" || sub.Location() != filepath.Join("src", "sum.test.ts")+":12" {
+		t.Errorf("failure = %s at %s, want the stack frame line", sub.Name, sub.Location())
+	}
+	if strings.Contains(sub.Message, "\x1b") || !strings.HasPrefix(sub.Message, "Error: expect") {
+		t.Errorf("Message = %q, want ANSI codes stripped", sub.Message)
+	}
+	if sub.Duration != 5*time.Millisecond {
+		t.Errorf("Duration = %v", sub.Duration)
+	}
+	if broken := failures[1]; broken.File != filepath.Join("src", "broken.test.ts") || broken.Message != "SyntaxError: Unexpected token" {
+		t.Errorf("suite failure = %+v", broken)
+	}
+}
+
+func TestParseCargoTest(t *testing.T) {
+	out := `
+running 3 tests
+test tests::adds ... ok
+test tests::subtracts ... FAILED
+test tests::slow ... ignored
+
+failures:
+
+---- tests::subtracts stdout ----
+thread 'tests::subtracts' panicked at src/lib.rs:21:9:
+assertion ` + "`left == right`" + ` failed
+  left: 1
+ right: 2
+note: run with ` + "`RUST_BACKTRACE=1`" + ` environment variable to display a backtrace
+
+
+failures:
+    tests::subtracts
+
+test result: FAILED. 1 passed; 1 failed; 1 ignored; 0 measured; 0 filtered out; finished in 0.00s
+`
+	report := parseCargoTest(out)
+	if report.Summary() != "1 passed, 1 failed, 1 skipped" {
+		t.Fatalf("Summary = %q", report.Summary())
+	}
+	f := report.Failures()[0]
+	if f.Name != "tests::subtracts" || f.Location() != "src/lib.rs:21" {
+		t.Errorf("failure = %s at %s", f.Name, f.Location())
+	}
+	if f.Message != "assertion `left == right` failed\n  left: 1\n right: 2" {
+		t.Errorf("Message = %q", f.Message)
+	}
+
+	// Rust before 1.73 put the message on the panic line
+	old := parseCargoTest("test it ... FAILED\n---- it stdout ----\nthread 'it' panicked at 'boom', src/main.rs:3:5\n")
+	if f := old.Failures()[0]; f.Message != "boom" || f.Location() != "src/main.rs:3" {
+		t.Errorf("old-style failure = %+v", f)
+	}
+}
+
+func TestRunCheckParsesGoTests(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/calc\n\ngo 1.21\n")
+	writeFile(t, filepath.Join(dir, "calc", "calc_test.go"), `package calc
+
+import "testing"
+
+func TestPass(t *testing.T) {}
+
+func TestFail(t *testing.T) {
+	t.Errorf("got %d, want %d", 1, 2)
+}
+`)
+	result := runCheck(dir, []string{"go"}, CheckTest, appConfig{}, false)
+
+	if result.Status != "fail" || result.Tests == nil {
+		t.Fatalf("runCheck = %s with report %+v\n%s", result.Status, result.Tests, result.Output)
+	}
+	if result.Command != "go test ./..." {
+		t.Errorf("Command = %q, want the command as configured", result.Command)
+	}
+	if result.Tests.Passed != 1 || result.Tests.Failed != 1 {
+		t.Errorf("Summary = %s", result.Tests.Summary())
+	}
+	f := result.Tests.Failures()[0]
+	if f.Name != "TestFail" || f.Location() != filepath.Join("calc", "calc_test.go")+":8" || f.Message != "got 1, want 2" {
+		t.Errorf("failure = %+v", f)
+	}
+	if strings.Contains(result.Output, `"Action"`) {
+		t.Errorf("Output should be the plain log, got %q", result.Output)
+	}
+}
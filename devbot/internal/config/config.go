@@ -6,6 +6,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/sloanahrens/devbot-go/internal/stack"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
@@ -13,7 +14,7 @@ import (
 type ConfigFile struct {
 	Name    string // filename
 	RelPath string // relative path from repo root
-	Type    string // category: node, go, python, infra, iac, ci, config
+	Type    string // category: a stack's (node, go, python, rust, ...), infra, iac, ci, config
 }
 
 // RepoConfig holds config files for a repository
@@ -23,27 +24,9 @@ type RepoConfig struct {
 	Error error
 }
 
-// Known config files by type
+// Config files that don't belong to a stack, by type. Stack config files
+// (node, go, python, ...) come from the stack registry.
 var configPatterns = map[string][]string{
-	"node": {
-		"package.json",
-		"tsconfig.json",
-		"pnpm-workspace.yaml",
-		"pnpm-lock.yaml",
-		"yarn.lock",
-		"package-lock.json",
-	},
-	"go": {
-		"go.mod",
-		"go.sum",
-	},
-	"python": {
-		"pyproject.toml",
-		"requirements.txt",
-		"setup.py",
-		"setup.cfg",
-		"Pipfile",
-	},
 	"infra": {
 		"Makefile",
 		"Dockerfile",
@@ -71,6 +54,16 @@ var configPatterns = map[string][]string{
 	},
 }
 
+// knownPatterns returns the known config files by type: the fixed categories
+// plus each stack's config files
+func knownPatterns() map[string][]string {
+	all := stack.Default().ConfigPatterns()
+	for t, files := range configPatterns {
+		all[t] = append(all[t], files...)
+	}
+	return all
+}
+
 // ScanParallel scans all repos for config files in parallel
 func ScanParallel(repos []workspace.RepoInfo, typeFilter string) []RepoConfig {
 	var wg sync.WaitGroup
@@ -100,35 +93,26 @@ func ScanParallel(repos []workspace.RepoInfo, typeFilter string) []RepoConfig {
 func scanRepo(repo workspace.RepoInfo, typeFilter string) RepoConfig {
 	result := RepoConfig{Repo: repo}
 
+	// Check the root, common subdirectories and the stack registry's
+	// sub-app dirs (which include apps/* and packages/*)
+	dirs := []string{"."}
+	subdirs := []string{"go-api", "nextapp", "apps", "packages", "src", "api", "web"}
+	for _, pattern := range append(subdirs, stack.Default().SearchDirs()...) {
+		for _, dir := range stack.Expand(repo.Path, pattern) {
+			dirs = appendUnique(dirs, dir)
+		}
+	}
+
 	// Check each config pattern
-	for fileType, patterns := range configPatterns {
+	for fileType, patterns := range knownPatterns() {
 		// Skip if type filter doesn't match
 		if typeFilter != "" && fileType != typeFilter {
 			continue
 		}
 
 		for _, pattern := range patterns {
-			// Check root level
-			checkPath(repo.Path, pattern, fileType, &result)
-
-			// Check common subdirectories
-			subdirs := []string{"go-api", "nextapp", "apps", "packages", "src", "api", "web"}
-			for _, subdir := range subdirs {
-				subPath := filepath.Join(repo.Path, subdir)
-				if info, err := os.Stat(subPath); err == nil && info.IsDir() {
-					checkPath(subPath, pattern, fileType, &result)
-
-					// For monorepo dirs, check one level deeper
-					if subdir == "apps" || subdir == "packages" {
-						entries, _ := os.ReadDir(subPath)
-						for _, e := range entries {
-							if e.IsDir() {
-								deepPath := filepath.Join(subPath, e.Name())
-								checkPath(deepPath, pattern, fileType, &result)
-							}
-						}
-					}
-				}
+			for _, dir := range dirs {
+				checkPath(filepath.Join(repo.Path, dir), pattern, fileType, &result)
 			}
 		}
 	}
@@ -195,3 +179,12 @@ func FilterByType(files []ConfigFile, configType string) []ConfigFile {
 	}
 	return filtered
 }
+
+func appendUnique(slice []string, item string) []string {
+	for _, s := range slice {
+		if s == item {
+			return slice
+		}
+	}
+	return append(slice, item)
+}
@@ -0,0 +1,67 @@
+package deps
+
+import "path/filepath"
+
+// cargoTables maps Cargo.toml dependency tables to whether they are dev-only
+var cargoTables = map[string]bool{
+	"dependencies":       false,
+	"build-dependencies": false,
+	"dev-dependencies":   true,
+}
+
+// parseCargoToml reads Cargo.toml, including target-specific tables.
+// Optional dependencies (enabled by features) count as dev.
+func parseCargoToml(dir string) ([]Dependency, error) {
+	doc, err := readTOML(filepath.Join(dir, "Cargo.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	inherited := tomlTable(tomlTable(doc, "workspace"), "dependencies")
+
+	var deps []Dependency
+	addTable := func(table map[string]any, dev bool) {
+		for key, spec := range table {
+			d := Dependency{Name: key, Dev: dev}
+			switch s := spec.(type) {
+			case string:
+				d.Version = s
+			case map[string]any:
+				// dep = { workspace = true } takes its version from [workspace.dependencies]
+				if ws, _ := s["workspace"].(bool); ws {
+					if base, ok := inherited[key].(string); ok {
+						d.Version = base
+					} else if base, ok := inherited[key].(map[string]any); ok {
+						d.Version = tomlString(base, "version")
+						if pkg := tomlString(base, "package"); pkg != "" {
+							d.Name = pkg
+						}
+					}
+				}
+				if v := tomlString(s, "version"); v != "" {
+					d.Version = v
+				}
+				if pkg := tomlString(s, "package"); pkg != "" {
+					d.Name = pkg // Renamed: `json = { package = "serde_json" }`
+				}
+				if optional, _ := s["optional"].(bool); optional {
+					d.Dev = true
+				}
+			}
+			deps = append(deps, d)
+		}
+	}
+
+	for name, dev := range cargoTables {
+		addTable(tomlTable(doc, name), dev)
+	}
+	// [target.'cfg(unix)'.dependencies] and friends
+	for _, target := range tomlTable(doc, "target") {
+		if t, ok := target.(map[string]any); ok {
+			for name, dev := range cargoTables {
+				addTable(tomlTable(t, name), dev)
+			}
+		}
+	}
+	return deps, nil
+}
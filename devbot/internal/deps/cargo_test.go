@@ -0,0 +1,54 @@
+package deps
+
+import (
+	"testing"
+)
+
+func TestParseCargoToml(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"Cargo.toml": `[package]
+name = "app"
+version = "0.1.0"
+
+[workspace.dependencies]
+tokio = { version = "1.36", features = ["full"] }
+anyhow = "1.0.81"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+json = { package = "serde_json", version = "1.0.114" }
+tokio = { workspace = true }
+anyhow.workspace = true
+local-lib = { path = "../local-lib" }
+metrics = { version = "0.22", optional = true }
+
+[dev-dependencies]
+criterion = "0.5"
+
+[build-dependencies]
+cc = "1.0"
+
+[target.'cfg(unix)'.dependencies]
+nix = "0.28"
+`})
+
+	deps, err := parseCargoToml(dir)
+	if err != nil {
+		t.Fatalf("parseCargoToml failed: %v", err)
+	}
+	expectStrings(t, "deps", describe(deps), []string{
+		"anyhow 1.0.81",
+		"cc 1.0",
+		"criterion 0.5 [dev]",
+		"local-lib ",
+		"metrics 0.22 [dev]",
+		"nix 0.28",
+		"serde 1.0",
+		"serde_json 1.0.114",
+		"tokio 1.36",
+	})
+
+	if _, err := parseCargoToml(t.TempDir()); err == nil {
+		t.Error("parseCargoToml should fail for missing Cargo.toml")
+	}
+}
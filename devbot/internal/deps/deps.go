@@ -4,22 +4,74 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
+// Ecosystems a dependency can come from
+const (
+	EcosystemNPM    = "npm"
+	EcosystemGo     = "go"
+	EcosystemPyPI   = "pypi"
+	EcosystemCrates = "crates"
+)
+
 // Dependency represents a single dependency
 type Dependency struct {
-	Name    string
-	Version string
-	Dev     bool
+	Name       string
+	Version    string // Declared in the manifest, e.g. "^18.2.0"; empty if only locked
+	Resolved   string // Installed version from the lockfile, if there is one
+	Dev        bool
+	Transitive bool   // Pulled in by another dependency rather than declared
+	Indirect   bool   // Go: required in go.mod but marked "// indirect"
+	Replace    string // Go: replacement from a replace directive, e.g. "../fork"
+	Ecosystem  string
+
+	// Requires lists the "name@version" of the locked packages this one
+	// depends on, when the lockfile records them (go.sum doesn't)
+	Requires []string
+}
+
+// Direct reports whether the project itself depends on d
+func (d Dependency) Direct() bool {
+	return !d.Transitive && !d.Indirect
+}
+
+// InUse returns the resolved version, falling back to the declared one
+func (d Dependency) InUse() string {
+	if d.Resolved != "" {
+		return d.Resolved
+	}
+	return d.Version
+}
+
+// Exact returns the exact version in use: the resolved one, or a declared
+// version that is already exact ("v1.8.0" in go.mod, "==2.31.0" in
+// requirements.txt). It returns "" when only a range is known.
+func (d Dependency) Exact() string {
+	if d.Resolved != "" {
+		return d.Resolved
+	}
+	v := strings.TrimSpace(d.Version)
+	if strings.HasPrefix(v, "==") && !strings.ContainsAny(v, ",*") {
+		return strings.TrimPrefix(v, "==")
+	}
+	// A bare "18" or "1.2" is a range in npm and Cargo
+	if strings.Count(v, ".") >= 2 && strings.IndexAny(v, "^~<>=*| ") < 0 && ParseVersion(v).Valid {
+		return v
+	}
+	return ""
 }
 
 // RepoDeps holds dependencies for a repository
 type RepoDeps struct {
 	Repo         workspace.RepoInfo
 	Dependencies []Dependency
+	Lockfiles    []string   // Lockfiles read, relative to the repo root
+	Provides     []Provided // Packages and modules the repo itself declares
 	Error        error
 }
 
@@ -49,36 +101,187 @@ func AnalyzeParallel(repos []workspace.RepoInfo) []RepoDeps {
 	return out
 }
 
+// manifestParsers reads declared dependencies per ecosystem
+var manifestParsers = []struct {
+	ecosystem string
+	parse     func(dir string) ([]Dependency, error)
+}{
+	{EcosystemNPM, parsePackageJSON},
+	{EcosystemGo, parseGoMod},
+	{EcosystemPyPI, parsePythonDeps},
+	{EcosystemCrates, parseCargoToml},
+}
+
 func analyzeRepo(repo workspace.RepoInfo) RepoDeps {
 	result := RepoDeps{Repo: repo}
 
-	// Check for package.json (Node)
-	if deps, err := parsePackageJSON(repo.Path); err == nil {
-		result.Dependencies = append(result.Dependencies, deps...)
+	// Check the root, common subdirectories and declared workspace members
+	dirs := []string{"."}
+	for _, subdir := range []string{"go-api", "nextapp", "packages", "apps"} {
+		if info, err := os.Stat(filepath.Join(repo.Path, subdir)); err == nil && info.IsDir() {
+			dirs = append(dirs, subdir)
+		}
+	}
+	for _, member := range memberDirs(repo.Path) {
+		dirs = appendUnique(dirs, member)
 	}
 
-	// Check for go.mod (Go)
-	if deps, err := parseGoMod(repo.Path); err == nil {
-		result.Dependencies = append(result.Dependencies, deps...)
+	// Lockfiles found next to a manifest contribute their transitive
+	// packages; a repo-root lockfile also resolves subdirectory manifests,
+	// as in npm/pnpm/yarn workspaces. Each lockfile is used once.
+	var locks []*lockfile
+	used := make(map[*lockfile]bool)
+	use := func(dir string, lock *lockfile) {
+		if used[lock] {
+			return
+		}
+		used[lock] = true
+		locks = append(locks, lock)
+		result.Lockfiles = append(result.Lockfiles, filepath.Join(dir, lock.File))
+	}
+	declared := make(map[string]bool) // "ecosystem name@version" of direct deps
+	rootLocks := make(map[string]*lockfile)
+	for _, m := range manifestParsers {
+		if lock := findLockfile(repo.Path, m.ecosystem); lock != nil {
+			rootLocks[m.ecosystem] = lock
+		}
 	}
 
-	// Check subdirectories too
-	subdirs := []string{"go-api", "nextapp", "packages", "apps"}
-	for _, subdir := range subdirs {
-		subPath := filepath.Join(repo.Path, subdir)
-		if info, err := os.Stat(subPath); err == nil && info.IsDir() {
-			if deps, err := parsePackageJSON(subPath); err == nil {
-				result.Dependencies = append(result.Dependencies, deps...)
+	for _, dir := range dirs {
+		path := filepath.Join(repo.Path, dir)
+		result.Provides = append(result.Provides, providedIn(repo.Path, dir)...)
+		for _, m := range manifestParsers {
+			deps, err := m.parse(path)
+			if err != nil {
+				continue
+			}
+
+			lock, rel := rootLocks[m.ecosystem], "."
+			if dir != "." {
+				lock = findLockfile(path, m.ecosystem)
+			}
+			if lock != nil {
+				use(dir, lock)
+			} else if lock = rootLocks[m.ecosystem]; lock != nil {
+				use(".", lock)
+				rel = dir
+			}
+
+			for _, d := range deps {
+				d.Ecosystem = m.ecosystem
+				if lock != nil {
+					d.Resolved = lock.resolve(rel, d.Name, d.Version)
+					d.Requires = lock.requirements(d.Name, d.Resolved)
+					declared[d.Ecosystem+" "+d.Name+"@"+d.Resolved] = true
+				}
+				// A module replacement is what gets built; a directory has no version
+				if d.Replace != "" {
+					d.Resolved = ""
+					if fields := strings.Fields(d.Replace); len(fields) == 2 {
+						d.Resolved = fields[1]
+					}
+				}
+				declared[d.Ecosystem+" "+d.Name+"@"+d.Resolved] = true
+				result.Dependencies = append(result.Dependencies, d)
 			}
-			if deps, err := parseGoMod(subPath); err == nil {
-				result.Dependencies = append(result.Dependencies, deps...)
+		}
+	}
+
+	// Everything else in a lockfile is transitive
+	for _, lock := range locks {
+		for _, p := range lock.Packages {
+			key := lock.Ecosystem + " " + p.Name + "@" + p.Version
+			if declared[key] {
+				continue
 			}
+			declared[key] = true
+			result.Dependencies = append(result.Dependencies, Dependency{
+				Name:       p.Name,
+				Resolved:   p.Version,
+				Dev:        p.Dev,
+				Transitive: true,
+				Ecosystem:  lock.Ecosystem,
+				Requires:   lock.requirements(p.Name, p.Version),
+			})
 		}
 	}
 
 	return result
 }
 
+// Usage describes one dependency's use across repos
+type Usage struct {
+	Name      string
+	Ecosystem string
+	Repos     []string            // Sorted repo names
+	Versions  map[string][]string // Version in use -> sorted repo names
+}
+
+// Aggregate groups dependencies by ecosystem and name across results,
+// sorted by number of repos (descending), then name. Transitive and Go
+// indirect dependencies are included only if transitive is set.
+func Aggregate(results []RepoDeps, transitive bool) []Usage {
+	byKey := make(map[string]*Usage)
+	for _, r := range results {
+		for _, d := range r.Dependencies {
+			if !d.Direct() && !transitive {
+				continue
+			}
+			key := d.Ecosystem + " " + d.Name
+			u := byKey[key]
+			if u == nil {
+				u = &Usage{Name: d.Name, Ecosystem: d.Ecosystem, Versions: make(map[string][]string)}
+				byKey[key] = u
+			}
+			u.Repos = appendUnique(u.Repos, r.Repo.Name)
+			if v := d.InUse(); v != "" {
+				u.Versions[v] = appendUnique(u.Versions[v], r.Repo.Name)
+			}
+		}
+	}
+
+	usages := make([]Usage, 0, len(byKey))
+	for _, u := range byKey {
+		sort.Strings(u.Repos)
+		for _, repos := range u.Versions {
+			sort.Strings(repos)
+		}
+		usages = append(usages, *u)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if len(usages[i].Repos) != len(usages[j].Repos) {
+			return len(usages[i].Repos) > len(usages[j].Repos)
+		}
+		if usages[i].Name != usages[j].Name {
+			return usages[i].Name < usages[j].Name
+		}
+		return usages[i].Ecosystem < usages[j].Ecosystem
+	})
+	return usages
+}
+
+// SortedVersions returns u's versions in ascending order for its ecosystem
+func (u Usage) SortedVersions() []string {
+	versions := make([]string, 0, len(u.Versions))
+	for v := range u.Versions {
+		versions = append(versions, v)
+	}
+	compare := CompareFor(u.Ecosystem)
+	sort.Slice(versions, func(i, j int) bool {
+		return compare(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
 type packageJSON struct {
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
@@ -107,43 +310,6 @@ func parsePackageJSON(dir string) ([]Dependency, error) {
 	return deps, nil
 }
 
-func parseGoMod(dir string) ([]Dependency, error) {
-	path := filepath.Join(dir, "go.mod")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Simple go.mod parsing - just extract require lines
-	var deps []Dependency
-	lines := splitLines(string(data))
-	inRequire := false
-
-	for _, line := range lines {
-		line = trimSpace(line)
-		if line == "require (" {
-			inRequire = true
-			continue
-		}
-		if line == ")" {
-			inRequire = false
-			continue
-		}
-		if inRequire && line != "" && line[0] != '/' {
-			parts := splitFields(line)
-			if len(parts) >= 2 {
-				deps = append(deps, Dependency{
-					Name:    parts[0],
-					Version: parts[1],
-					Dev:     false,
-				})
-			}
-		}
-	}
-
-	return deps, nil
-}
-
 func splitLines(s string) []string {
 	var lines []string
 	start := 0
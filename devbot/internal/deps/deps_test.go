@@ -201,6 +201,25 @@ func TestDependency(t *testing.T) {
 	}
 }
 
+func TestDependencyExact(t *testing.T) {
+	tests := []struct {
+		dep  Dependency
+		want string
+	}{
+		{Dependency{Version: "^18.2.0", Resolved: "18.3.1"}, "18.3.1"},
+		{Dependency{Version: "v1.8.0"}, "v1.8.0"},
+		{Dependency{Version: "==2.31.0"}, "2.31.0"},
+		{Dependency{Version: "==2.*"}, ""},
+		{Dependency{Version: "^18.2.0"}, ""},
+		{Dependency{Version: "1.2"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.dep.Exact(); got != tt.want {
+			t.Errorf("Exact(%q) = %q, want %q", tt.dep.Version, got, tt.want)
+		}
+	}
+}
+
 func TestSplitLines(t *testing.T) {
 	tests := []struct {
 		input    string
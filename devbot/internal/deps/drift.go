@@ -0,0 +1,235 @@
+package deps
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// DriftLevel is how far apart the versions of a dependency are
+type DriftLevel int
+
+const (
+	DriftNone DriftLevel = iota
+	DriftPatch
+	DriftMinor
+	DriftMajor
+)
+
+// As a policy limit DriftNone means "never fail", hence its name
+var driftLevelNames = []string{"never", "patch", "minor", "major"}
+
+func (l DriftLevel) String() string {
+	return driftLevelNames[l]
+}
+
+// ParseDriftLevel reads a policy level: "major", "minor", "patch", or
+// "never" (also "none") to never fail
+func ParseDriftLevel(s string) (DriftLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "major":
+		return DriftMajor, nil
+	case "minor":
+		return DriftMinor, nil
+	case "patch":
+		return DriftPatch, nil
+	case "never", "none":
+		return DriftNone, nil
+	}
+	return DriftNone, fmt.Errorf("invalid drift level %q (want major, minor, patch or never)", s)
+}
+
+// DriftPolicy decides which drift fails. A limit of DriftNone never fails.
+type DriftPolicy struct {
+	FailOn   DriftLevel
+	Packages map[string]DriftLevel
+	Ignore   []string
+}
+
+// DefaultDriftPolicy fails only on major drift
+var DefaultDriftPolicy = DriftPolicy{FailOn: DriftMajor}
+
+// NewDriftPolicy builds a policy from config.yaml's deps.drift section
+func NewDriftPolicy(cfg workspace.DriftConfig) (DriftPolicy, error) {
+	policy := DefaultDriftPolicy
+	policy.Ignore = cfg.Ignore
+
+	if cfg.FailOn != "" {
+		level, err := ParseDriftLevel(cfg.FailOn)
+		if err != nil {
+			return policy, fmt.Errorf("deps.drift.fail_on: %w", err)
+		}
+		policy.FailOn = level
+	}
+
+	for name, s := range cfg.Packages {
+		level, err := ParseDriftLevel(s)
+		if err != nil {
+			return policy, fmt.Errorf("deps.drift.packages.%s: %w", name, err)
+		}
+		if policy.Packages == nil {
+			policy.Packages = make(map[string]DriftLevel)
+		}
+		policy.Packages[name] = level
+	}
+	return policy, nil
+}
+
+// Limit returns the smallest drift that fails for name
+func (p DriftPolicy) Limit(name string) DriftLevel {
+	if level, ok := p.Packages[name]; ok {
+		return level
+	}
+	return p.FailOn
+}
+
+// Ignored reports whether name matches an ignore glob
+func (p DriftPolicy) Ignored(name string) bool {
+	for _, pattern := range p.Ignore {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// VersionGroup is one version of a dependency and the repos using it
+type VersionGroup struct {
+	Version string
+	Repos   []string
+}
+
+// Drift describes a shared dependency whose repos disagree on version
+type Drift struct {
+	Name      string
+	Ecosystem string
+	Groups    []VersionGroup // Ascending by version
+	Level     DriftLevel     // Largest divergence between any two groups
+	Target    string         // Recommended version to converge on
+	Exceeds   bool           // Level is at or above the policy limit
+}
+
+// FindDrift reports shared dependencies (2+ repos) in use at more than one
+// version, worst first. Declared ranges without a lockfile count as their
+// lower bound; versions that aren't semver (tags, links) are skipped.
+func FindDrift(usages []Usage, policy DriftPolicy) []Drift {
+	var drifts []Drift
+	for _, u := range usages {
+		if len(u.Repos) < 2 || policy.Ignored(u.Name) {
+			continue
+		}
+
+		// Group by normalized version. u.Versions is a map, so walk it in a
+		// fixed order to keep the chosen spelling stable.
+		versions := make([]string, 0, len(u.Versions))
+		for v := range u.Versions {
+			versions = append(versions, v)
+		}
+		sort.Strings(versions)
+
+		byVersion := make(map[string][]string)
+		display := make(map[string]spelling)
+		for _, v := range versions {
+			parsed, shown := ParseVersion(v), spelling{text: strings.TrimSpace(v), exact: true}
+			if !parsed.Valid {
+				parsed = MinVersion(v)
+				shown = spelling{text: parsed.String()}
+			}
+			if !parsed.Valid {
+				continue
+			}
+			key := parsed.String()
+			if existing, ok := display[key]; !ok || shown.better(existing) {
+				display[key] = shown
+			}
+			for _, r := range u.Versions[v] {
+				byVersion[key] = appendUnique(byVersion[key], r)
+			}
+		}
+		if len(byVersion) < 2 {
+			continue
+		}
+
+		d := Drift{Name: u.Name, Ecosystem: u.Ecosystem}
+		for v, repos := range byVersion {
+			sort.Strings(repos)
+			d.Groups = append(d.Groups, VersionGroup{Version: display[v].text, Repos: repos})
+		}
+		compare := CompareFor(u.Ecosystem)
+		sort.Slice(d.Groups, func(i, j int) bool {
+			return compare(d.Groups[i].Version, d.Groups[j].Version) < 0
+		})
+
+		d.Level = driftBetween(ParseVersion(d.Groups[0].Version), ParseVersion(d.Groups[len(d.Groups)-1].Version))
+		d.Target = recommendTarget(d.Groups, u.Ecosystem)
+		limit := policy.Limit(u.Name)
+		d.Exceeds = limit != DriftNone && d.Level >= limit
+		drifts = append(drifts, d)
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Level != drifts[j].Level {
+			return drifts[i].Level > drifts[j].Level
+		}
+		return drifts[i].Name < drifts[j].Name
+	})
+	return drifts
+}
+
+// spelling is how a version group is shown: as written in a manifest or
+// lockfile where possible, and as a range's lower bound only when nothing
+// spells it exactly. Go writes "v1.8.0" where npm writes "1.8.0"; the
+// "v" form wins so Go groups read as they do in go.mod.
+type spelling struct {
+	text  string
+	exact bool
+}
+
+func (s spelling) better(than spelling) bool {
+	if s.exact != than.exact {
+		return s.exact
+	}
+	return strings.HasPrefix(s.text, "v") && !strings.HasPrefix(than.text, "v")
+}
+
+// driftBetween classifies the gap between two versions. Below 1.0 a minor
+// bump is breaking, so 0.x minor differences count as major.
+func driftBetween(a, b Version) DriftLevel {
+	switch {
+	case a.Major != b.Major:
+		return DriftMajor
+	case a.Minor != b.Minor && a.Major == 0:
+		return DriftMajor
+	case a.Minor != b.Minor:
+		return DriftMinor
+	case a.Patch != b.Patch || a.Pre != b.Pre:
+		return DriftPatch
+	}
+	return DriftNone
+}
+
+// recommendTarget picks the highest stable version already in use, since
+// at least one repo has shown it works; prereleases only if nothing else is
+// available
+func recommendTarget(groups []VersionGroup, ecosystem string) string {
+	for i := len(groups) - 1; i >= 0; i-- {
+		if !prerelease(groups[i].Version, ecosystem) {
+			return groups[i].Version
+		}
+	}
+	return groups[len(groups)-1].Version
+}
+
+// prerelease reports whether v is a prerelease. A PyPI post-release
+// ("1.2.3.post1") is a fix to a final release, not a prerelease.
+func prerelease(v, ecosystem string) bool {
+	if ecosystem == EcosystemPyPI {
+		if p, ok := parsePEP440(v); ok {
+			return p.hasPre || p.hasDev
+		}
+	}
+	return ParseVersion(v).Pre != ""
+}
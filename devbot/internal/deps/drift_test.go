@@ -0,0 +1,169 @@
+package deps
+
+import (
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func repoWith(name string, deps ...Dependency) RepoDeps {
+	return RepoDeps{Repo: workspace.RepoInfo{Name: name}, Dependencies: deps}
+}
+
+func npm(name, declared, resolved string) Dependency {
+	return Dependency{Name: name, Version: declared, Resolved: resolved, Ecosystem: EcosystemNPM}
+}
+
+func TestFindDrift(t *testing.T) {
+	results := []RepoDeps{
+		repoWith("web",
+			npm("react", "^18.2.0", "18.3.1"),
+			npm("next", "^14.0.0", "14.1.0"),
+			npm("zod", "^3.22.0", "3.22.4"),
+			npm("lodash", "^4.17.0", "4.17.21"),
+			npm("@types/node", "^20", "20.11.0"),
+			npm("alpha", "0.3.0", ""),
+		),
+		repoWith("admin",
+			npm("react", "^17.0.0", "17.0.2"),
+			npm("next", "^14.2.0", "14.2.3"),
+			npm("zod", "^3.22.0", "3.22.2"),
+			npm("lodash", "^4.17.0", "4.17.21"),
+			npm("@types/node", "^18", "18.19.0"),
+			npm("alpha", "0.2.1", ""),
+		),
+		repoWith("docs",
+			npm("react", "^18.2.0", ""), // No lockfile: counts as 18.2.0
+			npm("next", "canary", ""),   // Not a version: skipped
+			npm("zod", "3.23.0-beta.1", ""),
+		),
+	}
+
+	policy := DriftPolicy{FailOn: DriftMajor, Packages: map[string]DriftLevel{"next": DriftMinor}, Ignore: []string{"@types/*"}}
+	drifts := FindDrift(Aggregate(results, false), policy)
+
+	want := []struct {
+		name    string
+		level   DriftLevel
+		target  string
+		groups  int
+		exceeds bool
+	}{
+		{"alpha", DriftMajor, "0.3.0", 2, true}, // 0.x minor bump is breaking
+		{"react", DriftMajor, "18.3.1", 3, true},
+		{"next", DriftMinor, "14.2.3", 2, true}, // Stricter per-package limit
+		{"zod", DriftMinor, "3.22.4", 3, false}, // Prerelease isn't recommended
+	}
+	if len(drifts) != len(want) {
+		t.Fatalf("got %d drifts %+v, want %d", len(drifts), drifts, len(want))
+	}
+	for i, w := range want {
+		d := drifts[i]
+		if d.Name != w.name || d.Level != w.level || d.Target != w.target || len(d.Groups) != w.groups || d.Exceeds != w.exceeds {
+			t.Errorf("drift[%d] = {%s %s → %s, %d groups, exceeds %v}, want {%s %s → %s, %d groups, exceeds %v}",
+				i, d.Name, d.Level, d.Target, len(d.Groups), d.Exceeds, w.name, w.level, w.target, w.groups, w.exceeds)
+		}
+	}
+
+	react := drifts[1]
+	expectStrings(t, "react 17.0.2 repos", react.Groups[0].Repos, []string{"admin"})
+	expectStrings(t, "react 18.2.0 repos", react.Groups[1].Repos, []string{"docs"})
+	expectStrings(t, "react 18.3.1 repos", react.Groups[2].Repos, []string{"web"})
+
+	// "never" disables the gate entirely
+	for _, d := range FindDrift(Aggregate(results, false), DriftPolicy{FailOn: DriftNone}) {
+		if d.Exceeds {
+			t.Errorf("%s exceeds a never-fail policy", d.Name)
+		}
+	}
+}
+
+func TestFindDriftWildcardRange(t *testing.T) {
+	results := []RepoDeps{
+		repoWith("web", npm("react", "18.x", "")), // No lockfile: counts as 18.0.0
+		repoWith("admin", npm("react", "18.2.0", "")),
+	}
+	drifts := FindDrift(Aggregate(results, false), DefaultDriftPolicy)
+	if len(drifts) != 1 || drifts[0].Groups[0].Version != "18.0.0" || drifts[0].Level != DriftMinor {
+		t.Fatalf("FindDrift = %+v, want 18.x read as its lower bound 18.0.0", drifts)
+	}
+}
+
+func TestFindDriftKeepsGoVersions(t *testing.T) {
+	results := []RepoDeps{
+		repoWith("api", Dependency{Name: "github.com/spf13/cobra", Version: "v1.7.0", Resolved: "v1.7.0", Ecosystem: EcosystemGo}),
+		repoWith("cli", Dependency{Name: "github.com/spf13/cobra", Version: "v1.8.0", Resolved: "v1.8.0", Ecosystem: EcosystemGo}),
+	}
+	drifts := FindDrift(Aggregate(results, false), DefaultDriftPolicy)
+	if len(drifts) != 1 || drifts[0].Target != "v1.8.0" || drifts[0].Groups[0].Version != "v1.7.0" {
+		t.Fatalf("FindDrift = %+v, want v1.7.0 → v1.8.0", drifts)
+	}
+	if drifts[0].Exceeds {
+		t.Error("minor drift should not exceed the default policy")
+	}
+}
+
+func TestFindDriftPrefersVerbatimSpelling(t *testing.T) {
+	usages := []Usage{{
+		Name:      "github.com/spf13/cobra",
+		Ecosystem: EcosystemGo,
+		Repos:     []string{"api", "cli", "web"},
+		Versions: map[string][]string{
+			">=1.8.0": {"web"}, // Read as 1.8.0
+			"v1.8.0":  {"cli"},
+			"v1.7.0":  {"api"},
+		},
+	}}
+	drifts := FindDrift(usages, DefaultDriftPolicy)
+	if len(drifts) != 1 || len(drifts[0].Groups) != 2 || drifts[0].Groups[1].Version != "v1.8.0" {
+		t.Fatalf("FindDrift = %+v, want the 1.8.0 group shown as v1.8.0", drifts)
+	}
+	expectStrings(t, "v1.8.0 repos", drifts[0].Groups[1].Repos, []string{"cli", "web"})
+}
+
+func TestFindDriftPyPIPostRelease(t *testing.T) {
+	pypi := func(version string) Dependency {
+		return Dependency{Name: "requests", Version: version, Resolved: version, Ecosystem: EcosystemPyPI}
+	}
+	results := []RepoDeps{
+		repoWith("api", pypi("2.31.0")),
+		repoWith("worker", pypi("2.31.0.post1")),
+		repoWith("cli", pypi("2.30.0")),
+	}
+	drifts := FindDrift(Aggregate(results, false), DefaultDriftPolicy)
+	if len(drifts) != 1 {
+		t.Fatalf("FindDrift = %+v, want one drift", drifts)
+	}
+
+	var got []string
+	for _, g := range drifts[0].Groups {
+		got = append(got, g.Version)
+	}
+	expectStrings(t, "versions", got, []string{"2.30.0", "2.31.0", "2.31.0.post1"})
+	if drifts[0].Target != "2.31.0.post1" {
+		t.Errorf("Target = %q, want the post-release 2.31.0.post1", drifts[0].Target)
+	}
+}
+
+func TestNewDriftPolicy(t *testing.T) {
+	policy, err := NewDriftPolicy(workspace.DriftConfig{FailOn: "minor", Packages: map[string]string{"react": "patch", "lodash": "never"}})
+	if err != nil {
+		t.Fatalf("NewDriftPolicy failed: %v", err)
+	}
+	limits := map[string]DriftLevel{"react": DriftPatch, "lodash": DriftNone, "zod": DriftMinor}
+	for name, want := range limits {
+		if got := policy.Limit(name); got != want {
+			t.Errorf("Limit(%s) = %s, want %s", name, got, want)
+		}
+	}
+
+	if policy, _ := NewDriftPolicy(workspace.DriftConfig{}); policy.FailOn != DriftMajor {
+		t.Errorf("default FailOn = %s, want major", policy.FailOn)
+	}
+	if _, err := NewDriftPolicy(workspace.DriftConfig{FailOn: "huge"}); err == nil {
+		t.Error("invalid fail_on should fail")
+	}
+	if _, err := NewDriftPolicy(workspace.DriftConfig{Packages: map[string]string{"react": "sometimes"}}); err == nil {
+		t.Error("invalid package level should fail")
+	}
+}
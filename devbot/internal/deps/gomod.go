@@ -0,0 +1,201 @@
+package deps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// goModFile is the part of go.mod that matters for dependencies. go.work
+// shares the syntax and adds use directives.
+type goModFile struct {
+	Module    string
+	Go        string
+	Toolchain string
+	Require   []goRequire
+	Replace   []goReplace
+	Exclude   []goModule
+	Use       []string // go.work module directories
+}
+
+type goModule struct {
+	Path    string
+	Version string
+}
+
+type goRequire struct {
+	goModule
+	Indirect bool
+}
+
+// goReplace is `replace Old [version] => New [version]`. New is a local
+// directory when it has no version.
+type goReplace struct {
+	Old goModule
+	New goModule
+}
+
+// String formats the replacement target, e.g. "../fork" or "example.com/fork v1.2.0"
+func (r goReplace) String() string {
+	if r.New.Version == "" {
+		return r.New.Path
+	}
+	return r.New.Path + " " + r.New.Version
+}
+
+func parseGoMod(dir string) ([]Dependency, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	mod, err := parseGoModFile(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for _, req := range mod.Require {
+		dep := Dependency{Name: req.Path, Version: req.Version, Indirect: req.Indirect}
+		if r, ok := mod.replacement(req.goModule); ok {
+			dep.Replace = r.String()
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// replacement finds the replace directive that applies to m: one for its
+// exact version wins over one for all versions
+func (f *goModFile) replacement(m goModule) (goReplace, bool) {
+	var match goReplace
+	found := false
+	for _, r := range f.Replace {
+		if r.Old.Path != m.Path {
+			continue
+		}
+		if r.Old.Version == m.Version {
+			return r, true
+		}
+		if r.Old.Version == "" {
+			match, found = r, true
+		}
+	}
+	return match, found
+}
+
+// parseGoModFile parses go.mod directives in both line and block form
+func parseGoModFile(data string) (*goModFile, error) {
+	mod := &goModFile{}
+	block := ""
+
+	for i, line := range splitLines(data) {
+		line, comment := splitGoModComment(strings.TrimSuffix(line, "\r"))
+		fields := splitFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		verb, args := block, fields
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case block == "":
+			verb, args = fields[0], fields[1:]
+		}
+
+		if err := mod.directive(verb, args, comment); err != nil {
+			return nil, fmt.Errorf("go.mod:%d: %w", i+1, err)
+		}
+	}
+	return mod, nil
+}
+
+func (f *goModFile) directive(verb string, args []string, comment string) error {
+	for i := range args {
+		args[i] = strings.Trim(args[i], `"`)
+	}
+
+	switch verb {
+	case "module":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: module path")
+		}
+		f.Module = args[0]
+	case "go":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: go 1.23")
+		}
+		f.Go = args[0]
+	case "toolchain":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: toolchain go1.23.1")
+		}
+		f.Toolchain = args[0]
+	case "require":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: require module/path v1.2.3")
+		}
+		f.Require = append(f.Require, goRequire{goModule{args[0], args[1]}, isIndirect(comment)})
+	case "exclude":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: exclude module/path v1.2.3")
+		}
+		f.Exclude = append(f.Exclude, goModule{args[0], args[1]})
+	case "use":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: use ./dir")
+		}
+		f.Use = append(f.Use, args[0])
+	case "replace":
+		r, err := parseGoReplace(args)
+		if err != nil {
+			return err
+		}
+		f.Replace = append(f.Replace, r)
+	}
+	// retract, godebug, tool and anything newer don't affect dependencies
+	return nil
+}
+
+func parseGoReplace(args []string) (goReplace, error) {
+	arrow := -1
+	for i, a := range args {
+		if a == "=>" {
+			arrow = i
+		}
+	}
+	if arrow < 1 || arrow > 2 || len(args)-arrow-1 < 1 || len(args)-arrow-1 > 2 {
+		return goReplace{}, fmt.Errorf("usage: replace module/path [v1.2.3] => other/module v1.4.5 | ../local/dir")
+	}
+
+	var r goReplace
+	r.Old.Path = args[0]
+	if arrow == 2 {
+		r.Old.Version = args[1]
+	}
+	r.New.Path = args[arrow+1]
+	if len(args) > arrow+2 {
+		r.New.Version = args[arrow+2]
+	}
+	return r, nil
+}
+
+// splitGoModComment separates a line from its trailing // comment
+func splitGoModComment(line string) (string, string) {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i], trimSpace(line[i+2:])
+	}
+	return line, ""
+}
+
+// isIndirect reports whether a require comment marks the module indirect:
+// "indirect" or "indirect; other notes"
+func isIndirect(comment string) bool {
+	return comment == "indirect" || strings.HasPrefix(comment, "indirect;")
+}
@@ -0,0 +1,106 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGoModFile(t *testing.T) {
+	mod, err := parseGoModFile(`// Package comment
+module "github.com/test/repo"
+
+go 1.22.0
+
+toolchain go1.22.4
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/spf13/pflag v1.0.5 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect; used by tests
+	golang.org/x/sys v0.20.0 // pinned, not indirect
+)
+
+exclude golang.org/x/sys v0.19.0
+
+replace (
+	github.com/spf13/cobra => ../cobra
+	gopkg.in/yaml.v3 v3.0.1 => github.com/fork/yaml v3.0.2
+)
+
+retract v0.1.0
+`)
+	if err != nil {
+		t.Fatalf("parseGoModFile failed: %v", err)
+	}
+
+	if mod.Module != "github.com/test/repo" || mod.Go != "1.22.0" || mod.Toolchain != "go1.22.4" {
+		t.Errorf("module/go/toolchain = %q %q %q", mod.Module, mod.Go, mod.Toolchain)
+	}
+
+	wantRequire := []goRequire{
+		{goModule{"github.com/spf13/cobra", "v1.8.0"}, false},
+		{goModule{"github.com/spf13/pflag", "v1.0.5"}, true},
+		{goModule{"gopkg.in/yaml.v3", "v3.0.1"}, true},
+		{goModule{"golang.org/x/sys", "v0.20.0"}, false},
+	}
+	if len(mod.Require) != len(wantRequire) {
+		t.Fatalf("Require = %+v, want %+v", mod.Require, wantRequire)
+	}
+	for i, want := range wantRequire {
+		if mod.Require[i] != want {
+			t.Errorf("Require[%d] = %+v, want %+v", i, mod.Require[i], want)
+		}
+	}
+
+	if len(mod.Exclude) != 1 || mod.Exclude[0] != (goModule{"golang.org/x/sys", "v0.19.0"}) {
+		t.Errorf("Exclude = %+v", mod.Exclude)
+	}
+	if len(mod.Replace) != 2 || mod.Replace[0].String() != "../cobra" || mod.Replace[1].String() != "github.com/fork/yaml v3.0.2" {
+		t.Errorf("Replace = %+v", mod.Replace)
+	}
+}
+
+func TestParseGoModFileErrors(t *testing.T) {
+	tests := []string{
+		"require github.com/x",
+		"replace a => ",
+		"replace a b c => d",
+		"module",
+	}
+	for _, input := range tests {
+		if _, err := parseGoModFile(input); err == nil {
+			t.Errorf("parseGoModFile(%q) should fail", input)
+		}
+	}
+}
+
+func TestParseGoModReplace(t *testing.T) {
+	dir := t.TempDir()
+	goMod := "module example.com/app\n\n" +
+		"require (\n\texample.com/a v1.0.0\n\texample.com/b v1.0.0 // indirect\n\texample.com/c v1.0.0\n)\n\n" +
+		"replace example.com/a => ./local/a\n" +
+		"replace example.com/c v1.0.0 => example.com/c-fork v1.1.0\n" +
+		"replace example.com/c v0.9.0 => example.com/c-old v0.9.1\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{"go.sum": "example.com/a v1.0.0 h1:x=\nexample.com/b v1.0.0 h1:y=\nexample.com/c-fork v1.1.0 h1:z=\n"})
+
+	result := analyzeRepo(workspaceRepo(dir))
+	byName := make(map[string]Dependency)
+	for _, d := range result.Dependencies {
+		byName[d.Name] = d
+	}
+
+	if d := byName["example.com/a"]; d.Replace != "./local/a" || d.Resolved != "" {
+		t.Errorf("a = %+v, want local replacement with no resolved version", d)
+	}
+	if d := byName["example.com/b"]; !d.Indirect || d.Direct() || d.Resolved != "v1.0.0" {
+		t.Errorf("b = %+v, want indirect v1.0.0", d)
+	}
+	if d := byName["example.com/c"]; d.Replace != "example.com/c-fork v1.1.0" || d.Resolved != "v1.1.0" {
+		t.Errorf("c = %+v, want replaced by c-fork v1.1.0", d)
+	}
+}
@@ -0,0 +1,676 @@
+package deps
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lockedPackage is one resolved package from a lockfile
+type lockedPackage struct {
+	Name    string
+	Version string
+	Dev     bool
+}
+
+// lockfile holds what a lockfile says is installed
+type lockfile struct {
+	Ecosystem string
+	File      string // Base name, e.g. "pnpm-lock.yaml"
+	Packages  []lockedPackage
+
+	// scoped maps a project dir relative to the lockfile ("." for the root)
+	// to name -> version for packages installed directly in it
+	scoped map[string]map[string]string
+
+	// specs maps "name@range" to a version (yarn.lock keys)
+	specs map[string]string
+
+	// requires maps "name@version" to the "name@version" of the packages it
+	// depends on, for lockfiles that record the dependency graph
+	requires map[string][]string
+}
+
+// lockfileParsers lists lockfiles by ecosystem, in order of preference
+var lockfileParsers = []struct {
+	ecosystem string
+	file      string
+	parse     func(path string) (*lockfile, error)
+}{
+	{EcosystemNPM, "pnpm-lock.yaml", parsePnpmLock},
+	{EcosystemNPM, "yarn.lock", parseYarnLock},
+	{EcosystemNPM, "package-lock.json", parsePackageLock},
+	{EcosystemNPM, "npm-shrinkwrap.json", parsePackageLock},
+	{EcosystemGo, "go.sum", parseGoSum},
+	{EcosystemCrates, "Cargo.lock", parseCargoLock},
+	{EcosystemPyPI, "uv.lock", parseUvLock},
+	{EcosystemPyPI, "poetry.lock", parsePoetryLock},
+}
+
+// findLockfile returns the preferred lockfile for ecosystem in dir, or nil
+func findLockfile(dir, ecosystem string) *lockfile {
+	for _, p := range lockfileParsers {
+		if p.ecosystem != ecosystem {
+			continue
+		}
+		lock, err := p.parse(filepath.Join(dir, p.file))
+		if err == nil {
+			lock.Ecosystem, lock.File = p.ecosystem, p.file
+			return lock
+		}
+	}
+	return nil
+}
+
+func newLockfile() *lockfile {
+	return &lockfile{
+		scoped:   make(map[string]map[string]string),
+		specs:    make(map[string]string),
+		requires: make(map[string][]string),
+	}
+}
+
+func (l *lockfile) add(p lockedPackage) {
+	l.Packages = append(l.Packages, p)
+}
+
+func (l *lockfile) setScoped(dir, name, version string) {
+	if l.scoped[dir] == nil {
+		l.scoped[dir] = make(map[string]string)
+	}
+	l.scoped[dir][name] = version
+}
+
+// resolve returns the installed version of name, declared as declared in
+// the project at dir (relative to the lockfile), or "" if unknown
+func (l *lockfile) resolve(dir, name, declared string) string {
+	if v, ok := l.scoped[dir][name]; ok {
+		return v
+	}
+	if v, ok := l.scoped["."][name]; ok {
+		return v
+	}
+	for _, spec := range []string{name + "@" + declared, name + "@npm:" + declared} {
+		if v, ok := l.specs[spec]; ok {
+			return v
+		}
+	}
+
+	// Fall back to the only version of name in the lockfile, if unique
+	return l.uniqueVersion(name)
+}
+
+// uniqueVersion returns the only locked version of name, or "" if there are
+// none or several
+func (l *lockfile) uniqueVersion(name string) string {
+	version := ""
+	for _, p := range l.Packages {
+		if p.Name != name {
+			continue
+		}
+		if version != "" && version != p.Version {
+			return ""
+		}
+		version = p.Version
+	}
+	return version
+}
+
+// require records that name@version depends on dep@depVersion
+func (l *lockfile) require(name, version, dep, depVersion string) {
+	if dep == "" || depVersion == "" {
+		return
+	}
+	key := name + "@" + version
+	l.requires[key] = appendUnique(l.requires[key], dep+"@"+depVersion)
+}
+
+// requirements returns the sorted "name@version" dependencies of
+// name@version, or nil if the lockfile doesn't record them
+func (l *lockfile) requirements(name, version string) []string {
+	reqs := l.requires[name+"@"+version]
+	if len(reqs) == 0 {
+		return nil
+	}
+	reqs = append([]string(nil), reqs...)
+	sort.Strings(reqs)
+	return reqs
+}
+
+// npm: package-lock.json (v1 nested "dependencies", v2/v3 flat "packages")
+
+type packageLock struct {
+	Packages     map[string]packageLockEntry `json:"packages"`
+	Dependencies map[string]packageLockV1    `json:"dependencies"`
+}
+
+type packageLockEntry struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Dev                  bool              `json:"dev"`
+	Link                 bool              `json:"link"`
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+type packageLockV1 struct {
+	Version      string                   `json:"version"`
+	Dev          bool                     `json:"dev"`
+	Requires     map[string]string        `json:"requires"`
+	Dependencies map[string]packageLockV1 `json:"dependencies"`
+}
+
+func parsePackageLock(path string) (*lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw packageLock
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	lock := newLockfile()
+	if len(raw.Packages) > 0 {
+		for key, e := range raw.Packages {
+			i := strings.LastIndex(key, "node_modules/")
+			if i < 0 || e.Link || e.Version == "" {
+				continue // Root project, workspace package or symlink
+			}
+			name := key[i+len("node_modules/"):]
+			if e.Name != "" {
+				name = e.Name // Aliased install
+			}
+			lock.add(lockedPackage{Name: name, Version: e.Version, Dev: e.Dev})
+
+			// node_modules/x belongs to the root; apps/web/node_modules/x to apps/web
+			owner := strings.TrimSuffix(key[:i], "/")
+			if owner == "" {
+				owner = "."
+			}
+			if !strings.Contains(owner, "node_modules") {
+				lock.setScoped(owner, name, e.Version)
+			}
+
+			for _, group := range []map[string]string{e.Dependencies, e.OptionalDependencies} {
+				for dep := range group {
+					depName, depVersion := npmInstalled(raw.Packages, key, dep)
+					lock.require(name, e.Version, depName, depVersion)
+				}
+			}
+		}
+		return lock, nil
+	}
+
+	// v1 nests packages that couldn't be hoisted; a requirement resolves to
+	// the nearest enclosing copy, as in node_modules
+	var walk func(deps map[string]packageLockV1, parents []map[string]packageLockV1)
+	walk = func(deps map[string]packageLockV1, parents []map[string]packageLockV1) {
+		scopes := append([]map[string]packageLockV1{deps}, parents...)
+		for name, d := range deps {
+			lock.add(lockedPackage{Name: name, Version: d.Version, Dev: d.Dev})
+			if len(parents) == 0 {
+				lock.setScoped(".", name, d.Version)
+			}
+			for dep := range d.Requires {
+				for _, scope := range append([]map[string]packageLockV1{d.Dependencies}, scopes...) {
+					if installed, ok := scope[dep]; ok {
+						lock.require(name, d.Version, dep, installed.Version)
+						break
+					}
+				}
+			}
+			walk(d.Dependencies, scopes)
+		}
+	}
+	walk(raw.Dependencies, nil)
+	return lock, nil
+}
+
+// npmInstalled returns the name and version node loads for dep when it is
+// required from the package at key: the nearest node_modules/dep walking up
+// from the package, ending at the lockfile root
+func npmInstalled(packages map[string]packageLockEntry, key, dep string) (string, string) {
+	dir := key
+	for {
+		candidate := "node_modules/" + dep
+		if dir != "" {
+			candidate = dir + "/" + candidate
+		}
+		if e, ok := packages[candidate]; ok && !e.Link && e.Version != "" {
+			if e.Name != "" {
+				return e.Name, e.Version
+			}
+			return dep, e.Version
+		}
+		if dir == "" {
+			return "", ""
+		}
+		if i := strings.LastIndex(dir, "/node_modules/"); i >= 0 {
+			dir = dir[:i]
+		} else {
+			dir = "" // A top-level package or workspace: the root is next
+		}
+	}
+}
+
+// pnpm: pnpm-lock.yaml (v5 top-level dependencies, v6+ importers)
+
+type pnpmLock struct {
+	Importers       map[string]pnpmImporter `yaml:"importers"`
+	Dependencies    map[string]any          `yaml:"dependencies"`
+	DevDependencies map[string]any          `yaml:"devDependencies"`
+	Packages        map[string]pnpmPackage  `yaml:"packages"`
+	Snapshots       map[string]pnpmPackage  `yaml:"snapshots"` // v9: dependencies of each package
+}
+
+type pnpmPackage struct {
+	Version              string            `yaml:"version"`
+	Dev                  bool              `yaml:"dev"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+type pnpmImporter struct {
+	Dependencies         map[string]any `yaml:"dependencies"`
+	DevDependencies      map[string]any `yaml:"devDependencies"`
+	OptionalDependencies map[string]any `yaml:"optionalDependencies"`
+}
+
+func parsePnpmLock(path string) (*lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw pnpmLock
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	lock := newLockfile()
+	for key, p := range raw.Packages {
+		name, version := splitPnpmKey(key)
+		if p.Version != "" {
+			version = p.Version
+		}
+		if name != "" && version != "" {
+			lock.add(lockedPackage{Name: name, Version: version, Dev: p.Dev})
+		}
+	}
+	for _, packages := range []map[string]pnpmPackage{raw.Packages, raw.Snapshots} {
+		for key, p := range packages {
+			name, version := splitPnpmKey(key)
+			if p.Version != "" {
+				version = p.Version
+			}
+			for _, group := range []map[string]string{p.Dependencies, p.OptionalDependencies} {
+				for dep, ref := range group {
+					depName, depVersion := pnpmDependency(dep, ref)
+					lock.require(name, version, depName, depVersion)
+				}
+			}
+		}
+	}
+
+	importers := raw.Importers
+	if importers == nil {
+		// v5: a single project at the top level
+		importers = map[string]pnpmImporter{".": {Dependencies: raw.Dependencies, DevDependencies: raw.DevDependencies}}
+	}
+	for dir, imp := range importers {
+		for _, group := range []map[string]any{imp.Dependencies, imp.DevDependencies, imp.OptionalDependencies} {
+			for name, v := range group {
+				if version := pnpmVersion(v); version != "" {
+					lock.setScoped(dir, name, version)
+				}
+			}
+		}
+	}
+	return lock, nil
+}
+
+// pnpmVersion reads an importer entry: "1.2.3" (v5) or {specifier, version}
+func pnpmVersion(v any) string {
+	var s string
+	switch e := v.(type) {
+	case string:
+		s = e
+	case map[string]any:
+		s, _ = e["version"].(string)
+	}
+	if strings.HasPrefix(s, "link:") || strings.HasPrefix(s, "file:") {
+		return ""
+	}
+	return trimPnpmSuffix(s)
+}
+
+// pnpmDependency reads a package's dependency entry: a version, possibly
+// with a peer suffix, or for aliases the target's key ("string-width@4.2.3")
+func pnpmDependency(name, ref string) (string, string) {
+	if strings.HasPrefix(ref, "link:") || strings.HasPrefix(ref, "file:") {
+		return "", ""
+	}
+	if ref != "" && isDigit(ref[0]) {
+		return name, trimPnpmSuffix(ref)
+	}
+	return splitPnpmKey(ref)
+}
+
+// splitPnpmKey parses package keys: "react@18.2.0" (v9), "/react@18.2.0"
+// (v6) and "/react/18.2.0" (v5), each optionally with a peer suffix
+func splitPnpmKey(key string) (string, string) {
+	key = trimPnpmSuffix(strings.TrimPrefix(key, "/"))
+	if i := strings.LastIndex(key, "@"); i > 0 {
+		return key[:i], key[i+1:]
+	}
+	if i := strings.LastIndex(key, "/"); i > 0 {
+		return key[:i], key[i+1:]
+	}
+	return "", ""
+}
+
+// trimPnpmSuffix drops peer-dependency suffixes: "18.2.0(react@18)" (v6+)
+// and "18.2.0_react@18" (v5)
+func trimPnpmSuffix(s string) string {
+	if i := strings.IndexAny(s, "(_"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// yarn: yarn.lock (v1 and berry)
+
+func parseYarnLock(path string) (*lockfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lock := newLockfile()
+	var specs []string
+	seen := make(map[string]bool)
+
+	// Dependency ranges can only be resolved once every entry's specs are
+	// known, so they're collected and resolved at the end
+	type edge struct{ from, version, dep, spec string }
+	var edges []edge
+	var current, currentVersion string // The entry being read, once its version is seen
+	inDeps := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Unindented lines start an entry: `"a@^1.0.0", a@^1.1.0:`
+		if line[0] != ' ' {
+			specs, current, inDeps = nil, "", false
+			for _, spec := range strings.Split(strings.TrimSuffix(trimmed, ":"), ",") {
+				spec = strings.Trim(strings.TrimSpace(spec), `"`)
+				if spec != "" && spec != "__metadata" {
+					specs = append(specs, spec)
+				}
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(line, "   ") {
+			// Entry fields: `  dependencies:` opens a block of `    name range` lines
+			inDeps = trimmed == "dependencies:" || trimmed == "optionalDependencies:"
+		} else if inDeps && current != "" {
+			if dep, spec := yarnDependency(trimmed); dep != "" {
+				edges = append(edges, edge{current, currentVersion, dep, spec})
+			}
+			continue
+		}
+
+		// `  version "1.2.3"` (v1) or `  version: 1.2.3` (berry)
+		if strings.HasPrefix(line, "  version") && !strings.HasPrefix(line, "   ") && len(specs) > 0 {
+			version := strings.TrimPrefix(trimmed, "version")
+			version = strings.Trim(strings.TrimSpace(strings.TrimPrefix(version, ":")), `"`)
+			for _, spec := range specs {
+				lock.specs[spec] = version
+			}
+			name := yarnSpecName(specs[0])
+			if key := name + "@" + version; !seen[key] {
+				seen[key] = true
+				lock.add(lockedPackage{Name: name, Version: version})
+			}
+			current, currentVersion = name, version
+			specs = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, e := range edges {
+		if version, ok := lock.specs[e.dep+"@"+e.spec]; ok {
+			lock.require(e.from, e.version, e.dep, version)
+		} else if version, ok := lock.specs[e.dep+"@npm:"+e.spec]; ok {
+			lock.require(e.from, e.version, e.dep, version)
+		}
+	}
+	return lock, nil
+}
+
+// yarnDependency parses a dependency line: `name "^1.0.0"` (v1) or
+// `name: "npm:^1.0.0"` (berry), with the name optionally quoted
+func yarnDependency(line string) (string, string) {
+	var name, rest string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return "", ""
+		}
+		name, rest = line[1:end+1], line[end+2:]
+	} else {
+		i := strings.IndexAny(line, ": ")
+		if i < 0 {
+			return "", ""
+		}
+		name, rest = line[:i], line[i:]
+	}
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, ":"))
+	return name, strings.Trim(rest, `"`)
+}
+
+// yarnSpecName extracts the package name from "name@range" or "@scope/name@range"
+func yarnSpecName(spec string) string {
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		return spec[:i]
+	}
+	return spec
+}
+
+// Go: go.sum
+
+func parseGoSum(path string) (*lockfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Modules listed only with a /go.mod hash were consulted for version
+	// selection but never downloaded; the highest version with a full hash
+	// is the one MVS selected
+	selected := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		mod, version := fields[0], fields[1]
+		if cur, ok := selected[mod]; !ok || CompareVersions(version, cur) > 0 {
+			selected[mod] = version
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	lock := newLockfile()
+	for _, mod := range sortedKeys(selected) {
+		lock.add(lockedPackage{Name: mod, Version: selected[mod]})
+		lock.setScoped(".", mod, selected[mod])
+	}
+	return lock, nil
+}
+
+// Rust: Cargo.lock
+
+func parseCargoLock(path string) (*lockfile, error) {
+	doc, err := readTOML(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := newLockfile()
+	for _, p := range tomlTables(doc, "package") {
+		// Packages without a source are the workspace's own crates. They
+		// list "name version" when several versions of a dependency are
+		// locked, which pins which one they use.
+		if tomlString(p, "source") == "" {
+			deps, _ := p["dependencies"].([]any)
+			for _, d := range deps {
+				if s, ok := d.(string); ok {
+					if fields := strings.Fields(s); len(fields) >= 2 {
+						lock.setScoped(".", fields[0], fields[1])
+					}
+				}
+			}
+			continue
+		}
+		lock.add(lockedPackage{Name: tomlString(p, "name"), Version: tomlString(p, "version")})
+	}
+
+	// Dependencies are "name", or "name version" when the name is ambiguous
+	for _, p := range tomlTables(doc, "package") {
+		if tomlString(p, "source") == "" {
+			continue
+		}
+		deps, _ := p["dependencies"].([]any)
+		for _, d := range deps {
+			s, _ := d.(string)
+			fields := strings.Fields(s)
+			if len(fields) == 0 {
+				continue
+			}
+			version := lock.uniqueVersion(fields[0])
+			if len(fields) >= 2 {
+				version = fields[1]
+			}
+			lock.require(tomlString(p, "name"), tomlString(p, "version"), fields[0], version)
+		}
+	}
+	return lock, nil
+}
+
+// Python: uv.lock
+
+func parseUvLock(path string) (*lockfile, error) {
+	doc, err := readTOML(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := newLockfile()
+	for _, p := range tomlTables(doc, "package") {
+		source := tomlTable(p, "source")
+		if source["editable"] == "." || source["virtual"] == "." {
+			continue // The project itself
+		}
+		lock.add(lockedPackage{Name: NormalizePyName(tomlString(p, "name")), Version: tomlString(p, "version")})
+	}
+
+	// dependencies = [{ name = "x" }, { name = "y", version = "1.0" }]; the
+	// version is only given when several are locked
+	for _, p := range tomlTables(doc, "package") {
+		name := NormalizePyName(tomlString(p, "name"))
+		for _, dep := range tomlTables(p, "dependencies") {
+			depName := NormalizePyName(tomlString(dep, "name"))
+			version := tomlString(dep, "version")
+			if version == "" {
+				version = lock.uniqueVersion(depName)
+			}
+			lock.require(name, tomlString(p, "version"), depName, version)
+		}
+	}
+	return lock, nil
+}
+
+// Python: poetry.lock
+
+func parsePoetryLock(path string) (*lockfile, error) {
+	doc, err := readTOML(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := newLockfile()
+	for _, p := range tomlTables(doc, "package") {
+		// Poetry < 1.2 records category = "dev"; newer versions drop it
+		dev := tomlString(p, "category") == "dev"
+		lock.add(lockedPackage{Name: NormalizePyName(tomlString(p, "name")), Version: tomlString(p, "version"), Dev: dev})
+	}
+
+	// [package.dependencies] maps names to constraints; poetry locks a
+	// single version of each package
+	for _, p := range tomlTables(doc, "package") {
+		name := NormalizePyName(tomlString(p, "name"))
+		for dep := range tomlTable(p, "dependencies") {
+			depName := NormalizePyName(dep)
+			lock.require(name, tomlString(p, "version"), depName, lock.uniqueVersion(depName))
+		}
+	}
+	return lock, nil
+}
+
+// NormalizePyName applies PEP 503 normalization: lowercase, runs of -_.
+// collapsed to a single dash
+func NormalizePyName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(name) {
+		if c == '-' || c == '_' || c == '.' {
+			dash = true
+			continue
+		}
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		dash = false
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func readTOML(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTOML(string(data))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,705 @@
+package deps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+// packageVersions flattens a lockfile to sorted "name@version" strings
+func packageVersions(lock *lockfile) []string {
+	var out []string
+	for _, p := range lock.Packages {
+		out = append(out, p.Name+"@"+p.Version)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func expectStrings(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %v, want %v", what, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s = %v, want %v", what, got, want)
+		}
+	}
+}
+
+func TestParsePackageLock(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"v3.json": `{
+			"lockfileVersion": 3,
+			"packages": {
+				"": {"name": "root", "dependencies": {"react": "^18.2.0"}},
+				"node_modules/react": {"version": "18.3.1"},
+				"node_modules/loose-envify": {"version": "1.4.0"},
+				"node_modules/jest": {"version": "29.7.0", "dev": true},
+				"node_modules/jest/node_modules/chalk": {"version": "4.1.2", "dev": true},
+				"node_modules/web": {"resolved": "apps/web", "link": true},
+				"apps/web": {"name": "web", "version": "0.1.0"},
+				"apps/web/node_modules/react": {"version": "17.0.2"}
+			}
+		}`,
+		"v1.json": `{
+			"lockfileVersion": 1,
+			"dependencies": {
+				"lodash": {"version": "4.17.21"},
+				"jest": {"version": "29.7.0", "dev": true, "dependencies": {"chalk": {"version": "4.1.2", "dev": true}}}
+			}
+		}`,
+	})
+
+	lock, err := parsePackageLock(filepath.Join(dir, "v3.json"))
+	if err != nil {
+		t.Fatalf("parsePackageLock failed: %v", err)
+	}
+	expectStrings(t, "packages", packageVersions(lock), []string{"chalk@4.1.2", "jest@29.7.0", "loose-envify@1.4.0", "react@17.0.2", "react@18.3.1"})
+	if got := lock.resolve(".", "react", "^18.2.0"); got != "18.3.1" {
+		t.Errorf("root react = %q, want 18.3.1", got)
+	}
+	if got := lock.resolve("apps/web", "react", "^17.0.0"); got != "17.0.2" {
+		t.Errorf("apps/web react = %q, want 17.0.2", got)
+	}
+	if got := lock.resolve("apps/web", "jest", "^29.0.0"); got != "29.7.0" {
+		t.Errorf("apps/web jest (hoisted) = %q, want 29.7.0", got)
+	}
+
+	lock, err = parsePackageLock(filepath.Join(dir, "v1.json"))
+	if err != nil {
+		t.Fatalf("parsePackageLock v1 failed: %v", err)
+	}
+	expectStrings(t, "v1 packages", packageVersions(lock), []string{"chalk@4.1.2", "jest@29.7.0", "lodash@4.17.21"})
+	if got := lock.resolve(".", "chalk", "^4.0.0"); got != "4.1.2" {
+		t.Errorf("nested chalk = %q, want 4.1.2 (unique version)", got)
+	}
+}
+
+func TestParsePnpmLock(t *testing.T) {
+	tests := map[string]string{
+		"v9": `lockfileVersion: '9.0'
+importers:
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.3.1
+      '@scope/ui':
+        specifier: workspace:*
+        version: link:packages/ui
+    devDependencies:
+      react-dom:
+        specifier: ^18.2.0
+        version: 18.3.1(react@18.3.1)
+packages:
+  react@18.3.1:
+    resolution: {integrity: sha512-x}
+  react-dom@18.3.1:
+    resolution: {integrity: sha512-y}
+  '@babel/core@7.24.0':
+    resolution: {integrity: sha512-z}
+`,
+		"v6": `lockfileVersion: '6.0'
+dependencies:
+  react:
+    specifier: ^18.2.0
+    version: 18.3.1
+devDependencies:
+  react-dom:
+    specifier: ^18.2.0
+    version: 18.3.1(react@18.3.1)
+packages:
+  /react@18.3.1:
+    resolution: {integrity: sha512-x}
+  /react-dom@18.3.1(react@18.3.1):
+    resolution: {integrity: sha512-y}
+    dev: true
+  /@babel/core@7.24.0:
+    resolution: {integrity: sha512-z}
+    dev: true
+`,
+		"v5": `lockfileVersion: 5.4
+specifiers:
+  react: ^18.2.0
+dependencies:
+  react: 18.3.1
+devDependencies:
+  react-dom: 18.3.1_react@18.3.1
+packages:
+  /react/18.3.1:
+    resolution: {integrity: sha512-x}
+  /react-dom/18.3.1_react@18.3.1:
+    resolution: {integrity: sha512-y}
+    dev: true
+  /@babel/core/7.24.0:
+    resolution: {integrity: sha512-z}
+    dev: true
+`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"pnpm-lock.yaml": content})
+
+			lock, err := parsePnpmLock(filepath.Join(dir, "pnpm-lock.yaml"))
+			if err != nil {
+				t.Fatalf("parsePnpmLock failed: %v", err)
+			}
+			expectStrings(t, "packages", packageVersions(lock), []string{"@babel/core@7.24.0", "react-dom@18.3.1", "react@18.3.1"})
+			for _, dep := range []string{"react", "react-dom"} {
+				if got := lock.resolve(".", dep, "^18.2.0"); got != "18.3.1" {
+					t.Errorf("%s = %q, want 18.3.1", dep, got)
+				}
+			}
+			if got := lock.resolve(".", "@scope/ui", "workspace:*"); got != "" {
+				t.Errorf("workspace link resolved to %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestParseYarnLock(t *testing.T) {
+	tests := map[string]string{
+		"classic": `# yarn lockfile v1
+
+
+"@babel/core@^7.0.0", "@babel/core@^7.20.0":
+  version "7.24.0"
+  resolved "https://registry.yarnpkg.com/@babel/core/-/core-7.24.0.tgz"
+  dependencies:
+    debug "^4.1.0"
+
+debug@^4.1.0:
+  version "4.3.4"
+
+react@^17.0.0:
+  version "17.0.2"
+
+react@^18.2.0:
+  version "18.3.1"
+`,
+		"berry": `__metadata:
+  version: 8
+  cacheKey: 10
+
+"@babel/core@npm:^7.0.0, @babel/core@npm:^7.20.0":
+  version: 7.24.0
+  resolution: "@babel/core@npm:7.24.0"
+  dependencies:
+    debug: "npm:^4.1.0"
+
+"debug@npm:^4.1.0":
+  version: 4.3.4
+
+"react@npm:^17.0.0":
+  version: 17.0.2
+
+"react@npm:^18.2.0":
+  version: 18.3.1
+`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"yarn.lock": content})
+
+			lock, err := parseYarnLock(filepath.Join(dir, "yarn.lock"))
+			if err != nil {
+				t.Fatalf("parseYarnLock failed: %v", err)
+			}
+			expectStrings(t, "packages", packageVersions(lock), []string{"@babel/core@7.24.0", "debug@4.3.4", "react@17.0.2", "react@18.3.1"})
+
+			// Two versions of react: the declared range picks one
+			resolves := map[string]string{"^18.2.0": "18.3.1", "^17.0.0": "17.0.2", "^16.0.0": ""}
+			for declared, want := range resolves {
+				if got := lock.resolve(".", "react", declared); got != want {
+					t.Errorf("react %s = %q, want %q", declared, got, want)
+				}
+			}
+			if got := lock.resolve(".", "@babel/core", "^7.20.0"); got != "7.24.0" {
+				t.Errorf("@babel/core = %q, want 7.24.0", got)
+			}
+		})
+	}
+}
+
+func TestParseGoSum(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"go.sum": `github.com/spf13/cobra v1.7.0 h1:a=
+github.com/spf13/cobra v1.7.0/go.mod h1:b=
+github.com/spf13/cobra v1.8.0 h1:c=
+github.com/spf13/cobra v1.8.0/go.mod h1:d=
+github.com/spf13/pflag v1.0.5 h1:e=
+github.com/spf13/pflag v1.0.5/go.mod h1:f=
+gopkg.in/check.v1 v0.0.0-20161208181325-20d25e280405/go.mod h1:g=
+`})
+
+	lock, err := parseGoSum(filepath.Join(dir, "go.sum"))
+	if err != nil {
+		t.Fatalf("parseGoSum failed: %v", err)
+	}
+	// go.mod-only entries were never downloaded
+	expectStrings(t, "packages", packageVersions(lock), []string{"github.com/spf13/cobra@v1.8.0", "github.com/spf13/pflag@v1.0.5"})
+}
+
+func TestParseCargoLock(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"Cargo.lock": `version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "rand 0.8.5",
+ "serde",
+]
+
+[[package]]
+name = "rand"
+version = "0.7.3"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand"
+version = "0.8.5"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "serde"
+version = "1.0.197"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = ["serde_derive"]
+
+[[package]]
+name = "serde_derive"
+version = "1.0.197"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`})
+
+	lock, err := parseCargoLock(filepath.Join(dir, "Cargo.lock"))
+	if err != nil {
+		t.Fatalf("parseCargoLock failed: %v", err)
+	}
+	expectStrings(t, "packages", packageVersions(lock), []string{"rand@0.7.3", "rand@0.8.5", "serde@1.0.197", "serde_derive@1.0.197"})
+	// The root crate pins rand 0.8.5 among the two locked versions
+	resolves := map[string]string{"rand": "0.8.5", "serde": "1.0.197", "missing": ""}
+	for name, want := range resolves {
+		if got := lock.resolve(".", name, "1"); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestParsePythonLocks(t *testing.T) {
+	repoPath := t.TempDir()
+	writeFiles(t, repoPath, map[string]string{
+		"pyproject.toml": `[project]
+name = "app"
+dependencies = ["Requests>=2.31"]
+
+[dependency-groups]
+dev = ["pytest>=8"]
+`,
+		"uv.lock": `version = 1
+requires-python = ">=3.11"
+
+[[package]]
+name = "app"
+version = "0.1.0"
+source = { editable = "." }
+dependencies = [
+    { name = "requests" },
+]
+
+[package.dev-dependencies]
+dev = [
+    { name = "pytest" },
+]
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [{ name = "urllib3" }]
+
+[[package]]
+name = "urllib3"
+version = "2.2.1"
+source = { registry = "https://pypi.org/simple" }
+
+[[package]]
+name = "pytest"
+version = "8.1.1"
+source = { registry = "https://pypi.org/simple" }
+`,
+		"packages/pyproject.toml": `[tool.poetry.dependencies]
+python = "^3.11"
+Requests = "^2.31"
+
+[tool.poetry.group.dev.dependencies]
+pytest = "^8.0"
+`,
+		"packages/poetry.lock": `[[package]]
+name = "requests"
+version = "2.31.0"
+
+[[package]]
+name = "urllib3"
+version = "2.2.1"
+
+[[package]]
+name = "pytest"
+version = "8.1.1"
+category = "dev"
+`,
+	})
+
+	result := analyzeRepo(workspace.RepoInfo{Name: "py", Path: repoPath})
+
+	var got []string
+	for _, d := range result.Dependencies {
+		got = append(got, fmt.Sprintf("%s %s -> %s dev=%v transitive=%v", d.Name, d.Version, d.Resolved, d.Dev, d.Transitive))
+	}
+	sort.Strings(got)
+	expectStrings(t, "dependencies", got, []string{
+		"pytest >=8 -> 8.1.1 dev=true transitive=false",
+		"pytest ^8.0 -> 8.1.1 dev=true transitive=false",
+		"requests >=2.31 -> 2.31.0 dev=false transitive=false",
+		"requests ^2.31 -> 2.31.0 dev=false transitive=false",
+		"urllib3  -> 2.2.1 dev=false transitive=true",
+	})
+	expectStrings(t, "lockfiles", result.Lockfiles, []string{"uv.lock", "packages/poetry.lock"})
+}
+
+func TestNormalizePyName(t *testing.T) {
+	tests := map[string]string{
+		"Requests":          "requests",
+		"typing_extensions": "typing-extensions",
+		"zope.interface":    "zope-interface",
+		"Foo__Bar-.baz":     "foo-bar-baz",
+	}
+	for input, want := range tests {
+		if got := NormalizePyName(input); got != want {
+			t.Errorf("NormalizePyName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestLockfileRequirements(t *testing.T) {
+	tests := map[string]struct {
+		file  string
+		parse func(string) (*lockfile, error)
+		data  string
+		want  map[string][]string // "name@version" -> requirements
+	}{
+		"package-lock v3 nearest node_modules": {
+			file:  "package-lock.json",
+			parse: parsePackageLock,
+			data: `{"lockfileVersion": 3, "packages": {
+				"node_modules/jest": {"version": "29.7.0", "dependencies": {"chalk": "^4.0.0", "missing": "1"}},
+				"node_modules/jest/node_modules/chalk": {"version": "4.1.2"},
+				"node_modules/chalk": {"version": "5.3.0"},
+				"node_modules/ink": {"version": "4.4.1", "optionalDependencies": {"chalk": "^5.0.0"}}
+			}}`,
+			want: map[string][]string{
+				"jest@29.7.0": {"chalk@4.1.2"},
+				"ink@4.4.1":   {"chalk@5.3.0"},
+				"chalk@5.3.0": nil,
+			},
+		},
+		"package-lock v1 requires": {
+			file:  "package-lock.json",
+			parse: parsePackageLock,
+			data: `{"lockfileVersion": 1, "dependencies": {
+				"chalk": {"version": "5.3.0"},
+				"jest": {"version": "29.7.0", "requires": {"chalk": "^4.0.0"}, "dependencies": {"chalk": {"version": "4.1.2"}}},
+				"ink": {"version": "4.4.1", "requires": {"chalk": "^5.0.0"}}
+			}}`,
+			want: map[string][]string{
+				"jest@29.7.0": {"chalk@4.1.2"},
+				"ink@4.4.1":   {"chalk@5.3.0"},
+			},
+		},
+		"pnpm v9 snapshots": {
+			file:  "pnpm-lock.yaml",
+			parse: parsePnpmLock,
+			data: `lockfileVersion: '9.0'
+packages:
+  react-dom@18.2.0:
+    resolution: {integrity: sha512-x}
+  react@18.2.0:
+    resolution: {integrity: sha512-y}
+  loose-envify@1.4.0:
+    resolution: {integrity: sha512-z}
+snapshots:
+  react-dom@18.2.0(react@18.2.0):
+    dependencies:
+      loose-envify: 1.4.0
+      react: 18.2.0
+      string-width-cjs: string-width@4.2.3
+  react@18.2.0:
+    dependencies:
+      loose-envify: 1.4.0
+  loose-envify@1.4.0: {}
+`,
+			want: map[string][]string{
+				"react-dom@18.2.0":   {"loose-envify@1.4.0", "react@18.2.0", "string-width@4.2.3"},
+				"react@18.2.0":       {"loose-envify@1.4.0"},
+				"loose-envify@1.4.0": nil,
+			},
+		},
+		"pnpm v6 packages": {
+			file:  "pnpm-lock.yaml",
+			parse: parsePnpmLock,
+			data: `lockfileVersion: '6.0'
+packages:
+  /react-dom@18.2.0(react@18.2.0):
+    dependencies:
+      react: 18.2.0
+      scheduler: 0.23.0
+`,
+			want: map[string][]string{"react-dom@18.2.0": {"react@18.2.0", "scheduler@0.23.0"}},
+		},
+		"yarn v1": {
+			file:  "yarn.lock",
+			parse: parseYarnLock,
+			data: `react@^18.2.0:
+  version "18.2.0"
+  resolved "https://registry.yarnpkg.com/react/-/react-18.2.0.tgz"
+  dependencies:
+    loose-envify "^1.1.0"
+    "@scope/pkg" "~2.0.0"
+
+loose-envify@^1.1.0:
+  version "1.4.0"
+
+"@scope/pkg@~2.0.0":
+  version "2.0.1"
+`,
+			want: map[string][]string{
+				"react@18.2.0":       {"@scope/pkg@2.0.1", "loose-envify@1.4.0"},
+				"loose-envify@1.4.0": nil,
+			},
+		},
+		"yarn berry": {
+			file:  "yarn.lock",
+			parse: parseYarnLock,
+			data: `__metadata:
+  version: 6
+
+"react@npm:^18.2.0":
+  version: 18.2.0
+  dependencies:
+    loose-envify: "npm:^1.1.0"
+  checksum: abc
+
+"loose-envify@npm:^1.1.0":
+  version: 1.4.0
+`,
+			want: map[string][]string{"react@18.2.0": {"loose-envify@1.4.0"}},
+		},
+		"Cargo.lock": {
+			file:  "Cargo.lock",
+			parse: parseCargoLock,
+			data: `[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = ["serde"]
+
+[[package]]
+name = "serde"
+version = "1.0.200"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = ["serde_derive", "syn 2.0.60"]
+
+[[package]]
+name = "serde_derive"
+version = "1.0.200"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "syn"
+version = "1.0.109"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "syn"
+version = "2.0.60"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`,
+			want: map[string][]string{
+				"serde@1.0.200": {"serde_derive@1.0.200", "syn@2.0.60"},
+				"app@0.1.0":     nil,
+			},
+		},
+		"uv.lock": {
+			file:  "uv.lock",
+			parse: parseUvLock,
+			data: `[[package]]
+name = "requests"
+version = "2.32.3"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [
+    { name = "Charset_Normalizer" },
+    { name = "idna", version = "3.7" },
+]
+
+[[package]]
+name = "charset-normalizer"
+version = "3.3.2"
+source = { registry = "https://pypi.org/simple" }
+`,
+			want: map[string][]string{"requests@2.32.3": {"charset-normalizer@3.3.2", "idna@3.7"}},
+		},
+		"poetry.lock": {
+			file:  "poetry.lock",
+			parse: parsePoetryLock,
+			data: `[[package]]
+name = "requests"
+version = "2.32.3"
+
+[package.dependencies]
+certifi = ">=2017.4.17"
+urllib3 = {version = ">=1.21.1,<3", optional = true}
+
+[[package]]
+name = "certifi"
+version = "2024.2.2"
+
+[[package]]
+name = "urllib3"
+version = "2.2.1"
+`,
+			want: map[string][]string{"requests@2.32.3": {"certifi@2024.2.2", "urllib3@2.2.1"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{tt.file: tt.data})
+			lock, err := tt.parse(filepath.Join(dir, tt.file))
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			for pkg, want := range tt.want {
+				i := strings.LastIndex(pkg, "@")
+				expectStrings(t, pkg+" requirements", lock.requirements(pkg[:i], pkg[i+1:]), want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepoLockfiles(t *testing.T) {
+	repoPath := t.TempDir()
+	writeFiles(t, repoPath, map[string]string{
+		"package.json":      `{"dependencies": {"react": "^18.2.0"}, "devDependencies": {"jest": "^29.0.0"}}`,
+		"apps/package.json": `{"dependencies": {"react": "^17.0.0"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"node_modules/react": {"version": "18.3.1", "dependencies": {"loose-envify": "^1.1.0"}},
+			"node_modules/loose-envify": {"version": "1.4.0"},
+			"node_modules/jest": {"version": "29.7.0", "dev": true},
+			"apps/node_modules/react": {"version": "17.0.2", "dependencies": {"loose-envify": "^1.1.0"}}
+		}}`,
+		"go-api/go.mod": "module example.com/api\n\nrequire (\n\tgithub.com/spf13/cobra v1.8.0\n)\n",
+		"go-api/go.sum": "github.com/spf13/cobra v1.8.0 h1:a=\ngithub.com/spf13/pflag v1.0.5 h1:b=\n",
+	})
+
+	result := analyzeRepo(workspace.RepoInfo{Name: "mono", Path: repoPath})
+
+	var got []string
+	for _, d := range result.Dependencies {
+		kind := "direct"
+		if d.Transitive {
+			kind = "transitive"
+		}
+		got = append(got, d.Ecosystem+" "+d.Name+" "+d.Version+" -> "+d.Resolved+" "+kind+" "+fmt.Sprint(d.Requires))
+	}
+	sort.Strings(got)
+	expectStrings(t, "dependencies", got, []string{
+		"go github.com/spf13/cobra v1.8.0 -> v1.8.0 direct []",
+		"go github.com/spf13/pflag  -> v1.0.5 transitive []",
+		"npm jest ^29.0.0 -> 29.7.0 direct []",
+		"npm loose-envify  -> 1.4.0 transitive []",
+		"npm react ^17.0.0 -> 17.0.2 direct [loose-envify@1.4.0]",
+		"npm react ^18.2.0 -> 18.3.1 direct [loose-envify@1.4.0]",
+	})
+	expectStrings(t, "lockfiles", result.Lockfiles, []string{"package-lock.json", "go-api/go.sum"})
+}
+
+func TestAnalyzeRepoRootLockfileOnly(t *testing.T) {
+	// No root manifest: the root lockfile is only reached from apps/
+	repoPath := t.TempDir()
+	writeFiles(t, repoPath, map[string]string{
+		"apps/package.json":     `{"dependencies": {"react": "^17.0.0"}}`,
+		"packages/package.json": `{"dependencies": {"react": "^17.0.0"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"apps/node_modules/react": {"version": "17.0.2", "dependencies": {"loose-envify": "^1.1.0"}},
+			"node_modules/react": {"version": "17.0.2"},
+			"node_modules/loose-envify": {"version": "1.4.0"}
+		}}`,
+	})
+
+	result := analyzeRepo(workspace.RepoInfo{Name: "mono", Path: repoPath})
+
+	var transitive []string
+	for _, d := range result.Dependencies {
+		if d.Transitive {
+			transitive = append(transitive, d.Name+"@"+d.Resolved)
+		}
+	}
+	expectStrings(t, "transitive", transitive, []string{"loose-envify@1.4.0"})
+	expectStrings(t, "lockfiles", result.Lockfiles, []string{"package-lock.json"})
+}
+
+func TestAggregate(t *testing.T) {
+	results := []RepoDeps{
+		{Repo: workspace.RepoInfo{Name: "b"}, Dependencies: []Dependency{
+			{Name: "react", Version: "^18.2.0", Resolved: "18.3.1", Ecosystem: EcosystemNPM},
+			{Name: "loose-envify", Resolved: "1.4.0", Transitive: true, Ecosystem: EcosystemNPM},
+		}},
+		{Repo: workspace.RepoInfo{Name: "a"}, Dependencies: []Dependency{
+			{Name: "react", Version: "^17.0.0", Resolved: "17.0.2", Ecosystem: EcosystemNPM},
+			{Name: "lodash", Version: "^4.17.0", Ecosystem: EcosystemNPM},
+		}},
+	}
+
+	usages := Aggregate(results, false)
+	if len(usages) != 2 || usages[0].Name != "react" || usages[1].Name != "lodash" {
+		t.Fatalf("Aggregate = %+v, want react then lodash", usages)
+	}
+	expectStrings(t, "react repos", usages[0].Repos, []string{"a", "b"})
+	expectStrings(t, "react versions", usages[0].SortedVersions(), []string{"17.0.2", "18.3.1"})
+	expectStrings(t, "lodash versions", usages[1].SortedVersions(), []string{"^4.17.0"})
+
+	if usages := Aggregate(results, true); len(usages) != 3 {
+		t.Errorf("Aggregate with transitive = %d usages, want 3", len(usages))
+	}
+}
+
+func workspaceRepo(path string) workspace.RepoInfo {
+	return workspace.RepoInfo{Name: filepath.Base(path), Path: path}
+}
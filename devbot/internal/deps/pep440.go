@@ -0,0 +1,149 @@
+package deps
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440Re is the version pattern from PEP 440, with the usual spellings
+// ("1.0-1", "1.0.RC1", "1.0alpha") that normalize to canonical versions
+var pep440Re = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|alpha|b|beta|c|rc|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?dev[-_.]?(\d*))?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// pep440 is a parsed Python package version
+type pep440 struct {
+	epoch   int
+	release []int
+	pre     [2]int // Phase (0 a, 1 b, 2 rc) and number
+	post    int
+	dev     int
+	local   string
+	hasPre  bool
+	hasPost bool
+	hasDev  bool
+}
+
+func parsePEP440(s string) (pep440, bool) {
+	m := pep440Re.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return pep440{}, false
+	}
+
+	var v pep440
+	v.epoch, _ = strconv.Atoi(m[1])
+	for _, part := range strings.Split(m[2], ".") {
+		n, _ := strconv.Atoi(part)
+		v.release = append(v.release, n)
+	}
+	if m[3] != "" {
+		v.hasPre = true
+		switch m[3] {
+		case "a", "alpha":
+			v.pre[0] = 0
+		case "b", "beta":
+			v.pre[0] = 1
+		default: // c, rc, pre, preview
+			v.pre[0] = 2
+		}
+		v.pre[1], _ = strconv.Atoi(m[4])
+	}
+	switch {
+	case m[5] != "": // "1.0-1" is an implicit post-release
+		v.hasPost = true
+		v.post, _ = strconv.Atoi(m[5])
+	case m[6] != "":
+		v.hasPost = true
+		v.post, _ = strconv.Atoi(m[7])
+	}
+	if strings.Contains(m[0], "dev") {
+		v.hasDev = true
+		v.dev, _ = strconv.Atoi(m[8])
+	}
+	v.local = m[9]
+	return v, true
+}
+
+// ComparePEP440 orders two Python package versions per PEP 440: dev
+// releases before prereleases before the release, and post-releases after
+// it ("1.0.dev0" < "1.0a1" < "1.0" < "1.0.post1" < "1.0.1"). Versions that
+// don't parse fall back to CompareVersions.
+func ComparePEP440(a, b string) int {
+	va, okA := parsePEP440(a)
+	vb, okB := parsePEP440(b)
+	if !okA || !okB {
+		return CompareVersions(a, b)
+	}
+
+	if va.epoch != vb.epoch {
+		return sign(va.epoch - vb.epoch)
+	}
+	// Missing release components are zero: 1.0 == 1.0.0
+	for i := 0; i < len(va.release) || i < len(vb.release); i++ {
+		var x, y int
+		if i < len(va.release) {
+			x = va.release[i]
+		}
+		if i < len(vb.release) {
+			y = vb.release[i]
+		}
+		if x != y {
+			return sign(x - y)
+		}
+	}
+
+	pa, pb := va.preKey(), vb.preKey()
+	for i := range pa {
+		if pa[i] != pb[i] {
+			return sign(pa[i] - pb[i])
+		}
+	}
+	if c := sign(va.postKey() - vb.postKey()); c != 0 {
+		return c
+	}
+	if c := sign(va.devKey() - vb.devKey()); c != 0 {
+		return c
+	}
+	return strings.Compare(va.local, vb.local)
+}
+
+// CompareFor returns the version ordering for an ecosystem: PEP 440 for
+// PyPI, where "1.0.post1" is after "1.0" rather than a prerelease of it,
+// and semver for the rest
+func CompareFor(ecosystem string) func(a, b string) int {
+	if ecosystem == EcosystemPyPI {
+		return ComparePEP440
+	}
+	return CompareVersions
+}
+
+// preKey sorts a bare dev release (1.0.dev0) before any prerelease of the
+// same version, and a final or post release after all of them
+func (v pep440) preKey() [2]int {
+	switch {
+	case !v.hasPre && !v.hasPost && v.hasDev:
+		return [2]int{-1, 0}
+	case !v.hasPre:
+		return [2]int{3, 0}
+	}
+	return v.pre
+}
+
+// postKey sorts no post-release before any post-release
+func (v pep440) postKey() int {
+	if !v.hasPost {
+		return -1
+	}
+	return v.post
+}
+
+// devKey sorts a dev release before the same version without one
+func (v pep440) devKey() int {
+	if !v.hasDev {
+		return int(^uint(0) >> 1)
+	}
+	return v.dev
+}
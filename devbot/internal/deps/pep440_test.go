@@ -0,0 +1,43 @@
+package deps
+
+import "testing"
+
+func TestComparePEP440(t *testing.T) {
+	// Each version sorts after the one before it
+	ordered := []string{
+		"0.9",
+		"1.0.dev0",
+		"1.0a1.dev1",
+		"1.0a1",
+		"1.0b2",
+		"1.0rc1",
+		"1.0",
+		"1.0.post1.dev0",
+		"1.0.post1",
+		"1.0.post2",
+		"1.0.1",
+		"1.10",
+		"1!0.5",
+	}
+	for i := 1; i < len(ordered); i++ {
+		a, b := ordered[i-1], ordered[i]
+		if got := ComparePEP440(a, b); got != -1 {
+			t.Errorf("ComparePEP440(%q, %q) = %d, want -1", a, b, got)
+		}
+		if got := ComparePEP440(b, a); got != 1 {
+			t.Errorf("ComparePEP440(%q, %q) = %d, want 1", b, a, got)
+		}
+	}
+
+	equal := [][2]string{
+		{"1.0", "1.0.0"},
+		{"1.0-1", "1.0.post1"},
+		{"1.0.RC1", "1.0rc1"},
+		{"v2.31.0", "2.31.0"},
+	}
+	for _, e := range equal {
+		if got := ComparePEP440(e[0], e[1]); got != 0 {
+			t.Errorf("ComparePEP440(%q, %q) = %d, want 0", e[0], e[1], got)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package deps
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Provided is a package or module a repo declares, which other repos can
+// depend on: a go.mod module, package.json name, Python project or crate
+type Provided struct {
+	Name      string
+	Ecosystem string
+	Dir       string // Manifest directory relative to the repo root
+}
+
+// providedIn reads the names declared by the manifests in dir
+func providedIn(repoPath, dir string) []Provided {
+	abs := filepath.Join(repoPath, dir)
+	var out []Provided
+	add := func(name, ecosystem string) {
+		if name != "" {
+			out = append(out, Provided{Name: name, Ecosystem: ecosystem, Dir: filepath.ToSlash(dir)})
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(abs, "package.json")); err == nil {
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			add(pkg.Name, EcosystemNPM)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(abs, "go.mod")); err == nil {
+		if mod, err := parseGoModFile(string(data)); err == nil {
+			add(mod.Module, EcosystemGo)
+		}
+	}
+	if doc, err := readTOML(filepath.Join(abs, "pyproject.toml")); err == nil {
+		name := tomlString(tomlTable(doc, "project"), "name")
+		if name == "" {
+			name = tomlString(tomlTable(tomlTable(doc, "tool"), "poetry"), "name")
+		}
+		if name != "" {
+			add(NormalizePyName(name), EcosystemPyPI)
+		}
+	}
+	if doc, err := readTOML(filepath.Join(abs, "Cargo.toml")); err == nil {
+		add(tomlString(tomlTable(doc, "package"), "name"), EcosystemCrates)
+	}
+	return out
+}
+
+// memberDirs lists the workspace members declared at the repo root - npm
+// and yarn "workspaces", pnpm-workspace.yaml, go.work and Cargo
+// [workspace] members - as sorted slash-separated relative paths
+func memberDirs(repoPath string) []string {
+	var patterns, excludes []string
+
+	// "workspaces": ["packages/*"] or {"packages": ["packages/*"]}
+	if data, err := os.ReadFile(filepath.Join(repoPath, "package.json")); err == nil {
+		var pkg struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && len(pkg.Workspaces) > 0 {
+			var list []string
+			if json.Unmarshal(pkg.Workspaces, &list) != nil {
+				var yarn struct {
+					Packages []string `json:"packages"`
+				}
+				_ = json.Unmarshal(pkg.Workspaces, &yarn)
+				list = yarn.Packages
+			}
+			patterns = append(patterns, list...)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(repoPath, "pnpm-workspace.yaml")); err == nil {
+		var ws struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &ws) == nil {
+			patterns = append(patterns, ws.Packages...)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(repoPath, "go.work")); err == nil {
+		if work, err := parseGoModFile(string(data)); err == nil {
+			patterns = append(patterns, work.Use...)
+		}
+	}
+	if doc, err := readTOML(filepath.Join(repoPath, "Cargo.toml")); err == nil {
+		ws := tomlTable(doc, "workspace")
+		patterns = append(patterns, tomlStrings(ws, "members")...)
+		excludes = append(excludes, tomlStrings(ws, "exclude")...)
+	}
+
+	members := make(map[string]bool)
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			excludes = append(excludes, p[1:])
+			continue
+		}
+		// Globs match one level; "packages/**" is treated as "packages/*"
+		p = strings.ReplaceAll(cleanMember(p), "**", "*")
+		matches, _ := filepath.Glob(filepath.Join(repoPath, filepath.FromSlash(p)))
+		for _, m := range matches {
+			if info, err := os.Stat(m); err != nil || !info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(repoPath, m)
+			rel = filepath.ToSlash(rel)
+			if err != nil || rel == "." || strings.HasPrefix(rel, "..") || strings.Contains(rel, "node_modules") {
+				continue
+			}
+			members[rel] = true
+		}
+	}
+
+	var dirs []string
+	for dir := range members {
+		excluded := false
+		for _, ex := range excludes {
+			if ok, _ := path.Match(strings.ReplaceAll(cleanMember(ex), "**", "*"), dir); ok {
+				excluded = true
+			}
+		}
+		if !excluded {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// cleanMember normalizes a workspace pattern: "./packages/*/" -> "packages/*"
+func cleanMember(p string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(p), "./"), "/")
+}
@@ -0,0 +1,100 @@
+package deps
+
+import (
+	"testing"
+)
+
+func TestMemberDirs(t *testing.T) {
+	tests := map[string]struct {
+		files map[string]string
+		want  []string
+	}{
+		"npm workspaces": {
+			files: map[string]string{
+				"package.json":              `{"workspaces": ["packages/*", "tools/cli"]}`,
+				"packages/ui/package.json":  `{}`,
+				"packages/api/package.json": `{}`,
+				"packages/README.md":        "not a dir",
+				"tools/cli/package.json":    `{}`,
+				"tools/other/package.json":  `{}`,
+			},
+			want: []string{"packages/api", "packages/ui", "tools/cli"},
+		},
+		"yarn workspaces object": {
+			files: map[string]string{
+				"package.json":          `{"workspaces": {"packages": ["apps/*"]}}`,
+				"apps/web/package.json": `{}`,
+			},
+			want: []string{"apps/web"},
+		},
+		"pnpm with exclusion": {
+			files: map[string]string{
+				"pnpm-workspace.yaml":          "packages:\n  - 'packages/**'\n  - '!packages/legacy'\n",
+				"packages/a/package.json":      `{}`,
+				"packages/legacy/package.json": `{}`,
+			},
+			want: []string{"packages/a"},
+		},
+		"go.work": {
+			files: map[string]string{
+				"go.work":         "go 1.22\n\nuse (\n\t.\n\t./cmd/tool\n)\nuse ./lib\n",
+				"cmd/tool/go.mod": "module example.com/tool\n",
+				"lib/go.mod":      "module example.com/lib\n",
+			},
+			want: []string{"cmd/tool", "lib"},
+		},
+		"cargo workspace": {
+			files: map[string]string{
+				"Cargo.toml":                "[workspace]\nmembers = [\"crates/*\"]\nexclude = [\"crates/scratch\"]\n",
+				"crates/core/Cargo.toml":    "[package]\nname = \"core\"\n",
+				"crates/scratch/Cargo.toml": "[package]\nname = \"scratch\"\n",
+			},
+			want: []string{"crates/core"},
+		},
+		"no workspace": {
+			files: map[string]string{"package.json": `{"name": "solo"}`},
+			want:  nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			expectStrings(t, "members", memberDirs(dir), tt.want)
+		})
+	}
+}
+
+func TestAnalyzeRepoProvides(t *testing.T) {
+	repoPath := t.TempDir()
+	writeFiles(t, repoPath, map[string]string{
+		"package.json":                 `{"name": "@org/root", "private": true, "workspaces": ["packages/*"]}`,
+		"packages/client/package.json": `{"name": "@org/api-client", "dependencies": {"zod": "^3.22.0"}}`,
+		"go-api/go.mod":                "module github.com/org/api\n\ngo 1.22\n",
+		"pyproject.toml":               "[project]\nname = \"Org_Tools\"\n",
+		"Cargo.toml":                   "[package]\nname = \"org-core\"\nversion = \"0.1.0\"\n",
+	})
+
+	result := analyzeRepo(workspaceRepo(repoPath))
+
+	var got []string
+	for _, p := range result.Provides {
+		got = append(got, p.Ecosystem+" "+p.Name+" "+p.Dir)
+	}
+	expectStrings(t, "provides", got, []string{
+		"npm @org/root .",
+		"pypi org-tools .",
+		"crates org-core .",
+		"go github.com/org/api go-api",
+		"npm @org/api-client packages/client",
+	})
+
+	found := false
+	for _, d := range result.Dependencies {
+		found = found || d.Name == "zod"
+	}
+	if !found {
+		t.Error("workspace member dependencies should be analyzed")
+	}
+}
@@ -0,0 +1,205 @@
+package deps
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// requirementsFiles are the pip requirements files read, and whether their
+// packages are dev-only
+var requirementsFiles = []struct {
+	name string
+	dev  bool
+}{
+	{"requirements.txt", false},
+	{"requirements-dev.txt", true},
+	{"requirements_dev.txt", true},
+	{"dev-requirements.txt", true},
+	{"requirements-test.txt", true},
+	{"test-requirements.txt", true},
+}
+
+// parsePythonDeps reads pyproject.toml and requirements files in dir.
+// Optional extras and dev groups (PEP 735, Poetry, uv, PDM) count as dev.
+func parsePythonDeps(dir string) ([]Dependency, error) {
+	var deps []Dependency
+	seen := make(map[string]bool)
+	found := false
+
+	add := func(d Dependency) {
+		if d.Name == "" || seen[d.Name] {
+			return
+		}
+		seen[d.Name] = true
+		deps = append(deps, d)
+	}
+
+	pyproject, err := parsePyProject(filepath.Join(dir, "pyproject.toml"))
+	switch {
+	case err == nil:
+		found = true
+		for _, d := range pyproject {
+			add(d)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	for _, f := range requirementsFiles {
+		reqs, err := parseRequirements(filepath.Join(dir, f.name), f.dev, make(map[string]bool))
+		if err != nil {
+			continue
+		}
+		found = true
+		for _, d := range reqs {
+			add(d)
+		}
+	}
+
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return deps, nil
+}
+
+func parsePyProject(path string) ([]Dependency, error) {
+	doc, err := readTOML(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	addSpecs := func(list any, dev bool) {
+		items, _ := list.([]any)
+		for _, item := range items {
+			// Dependency groups may include other groups: { include-group = "test" }
+			if s, ok := item.(string); ok {
+				d := parsePEP508(s)
+				d.Dev = dev
+				deps = append(deps, d)
+			}
+		}
+	}
+	addGroups := func(groups map[string]any) {
+		for _, list := range groups {
+			addSpecs(list, true)
+		}
+	}
+
+	// PEP 621 and PEP 735
+	project := tomlTable(doc, "project")
+	addSpecs(project["dependencies"], false)
+	addGroups(tomlTable(project, "optional-dependencies"))
+	addGroups(tomlTable(doc, "dependency-groups"))
+
+	tool := tomlTable(doc, "tool")
+	addSpecs(tomlTable(tool, "uv")["dev-dependencies"], true)
+	addGroups(tomlTable(tomlTable(tool, "pdm"), "dev-dependencies"))
+
+	// Poetry: name = "^1.0" or name = { version = "^1.0", optional = true }
+	poetry := tomlTable(tool, "poetry")
+	addPoetry := func(table map[string]any, dev bool) {
+		for name, spec := range table {
+			if name == "python" {
+				continue
+			}
+			d := Dependency{Name: NormalizePyName(name), Dev: dev}
+			switch s := spec.(type) {
+			case string:
+				d.Version = s
+			case map[string]any:
+				d.Version = tomlString(s, "version")
+				if optional, _ := s["optional"].(bool); optional {
+					d.Dev = true
+				}
+			}
+			deps = append(deps, d)
+		}
+	}
+	addPoetry(tomlTable(poetry, "dependencies"), false)
+	addPoetry(tomlTable(poetry, "dev-dependencies"), true)
+	for _, group := range tomlTable(poetry, "group") {
+		if g, ok := group.(map[string]any); ok {
+			addPoetry(tomlTable(g, "dependencies"), true)
+		}
+	}
+
+	return deps, nil
+}
+
+// parseRequirements reads a pip requirements file, following -r includes
+func parseRequirements(path string, dev bool, visited map[string]bool) ([]Dependency, error) {
+	if visited[path] {
+		return nil, nil
+	}
+	visited[path] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Backslash continues a line
+	text := strings.ReplaceAll(strings.ReplaceAll(string(data), "\r\n", "\n"), "\\\n", " ")
+
+	var deps []Dependency
+	for _, line := range splitLines(text) {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '-' {
+			fields := strings.Fields(line)
+			if (fields[0] == "-r" || fields[0] == "--requirement") && len(fields) > 1 {
+				included, err := parseRequirements(filepath.Join(filepath.Dir(path), fields[1]), dev, visited)
+				if err == nil {
+					deps = append(deps, included...)
+				}
+			}
+			continue // -e, -c, --index-url and other options
+		}
+
+		// Per-requirement options such as --hash follow the spec
+		if i := strings.Index(line, " --"); i >= 0 {
+			line = line[:i]
+		}
+		d := parsePEP508(line)
+		d.Dev = dev
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// parsePEP508 reads a requirement such as "requests[socks]>=2.31,<3 ; python_version<'3.12'".
+// Direct URL requirements ("name @ https://...") have no version.
+func parsePEP508(spec string) Dependency {
+	if i := strings.Index(spec, ";"); i >= 0 {
+		spec = spec[:i] // Environment markers
+	}
+	spec = strings.TrimSpace(spec)
+
+	end := 0
+	for end < len(spec) && (isBareKeyChar(spec[end]) || spec[end] == '.') {
+		end++
+	}
+	d := Dependency{Name: NormalizePyName(spec[:end])}
+
+	rest := strings.TrimSpace(spec[end:])
+	if strings.HasPrefix(rest, "[") {
+		if i := strings.Index(rest, "]"); i >= 0 {
+			rest = strings.TrimSpace(rest[i+1:])
+		}
+	}
+	if strings.HasPrefix(rest, "@") {
+		return d
+	}
+	rest = strings.TrimSuffix(strings.TrimPrefix(rest, "("), ")")
+	d.Version = strings.ReplaceAll(strings.TrimSpace(rest), " ", "")
+	return d
+}
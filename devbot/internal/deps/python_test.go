@@ -0,0 +1,130 @@
+package deps
+
+import (
+	"sort"
+	"testing"
+)
+
+// describe flattens dependencies to sorted "name version [dev]" strings
+func describe(deps []Dependency) []string {
+	var out []string
+	for _, d := range deps {
+		s := d.Name + " " + d.Version
+		if d.Dev {
+			s += " [dev]"
+		}
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestParsePEP508(t *testing.T) {
+	tests := []struct {
+		spec, name, version string
+	}{
+		{"requests", "requests", ""},
+		{"Requests>=2.31,<3", "requests", ">=2.31,<3"},
+		{"requests[socks, security] >= 2.31", "requests", ">=2.31"},
+		{"Django (>=4.2)", "django", ">=4.2"},
+		{"typing_extensions==4.9.0 ; python_version < '3.11'", "typing-extensions", "==4.9.0"},
+		{"pkg @ https://example.com/pkg-1.0.tar.gz", "pkg", ""},
+		{"zope.interface~=6.0", "zope-interface", "~=6.0"},
+	}
+	for _, tt := range tests {
+		d := parsePEP508(tt.spec)
+		if d.Name != tt.name || d.Version != tt.version {
+			t.Errorf("parsePEP508(%q) = %q %q, want %q %q", tt.spec, d.Name, d.Version, tt.name, tt.version)
+		}
+	}
+}
+
+func TestParsePythonDeps(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "pep 621 with extras and groups",
+			files: map[string]string{"pyproject.toml": `[project]
+name = "app"
+dependencies = [
+    "fastapi>=0.110",
+    "pydantic~=2.6",
+]
+
+[project.optional-dependencies]
+postgres = ["psycopg[binary]>=3.1"]
+
+[dependency-groups]
+test = ["pytest>=8"]
+dev = [{ include-group = "test" }, "ruff>=0.3"]
+
+[tool.uv]
+dev-dependencies = ["mypy>=1.9"]
+
+[tool.ruff]
+line-length = 100
+`},
+			want: []string{"fastapi >=0.110", "mypy >=1.9 [dev]", "psycopg >=3.1 [dev]", "pydantic ~=2.6", "pytest >=8 [dev]", "ruff >=0.3 [dev]"},
+		},
+		{
+			name: "poetry",
+			files: map[string]string{"pyproject.toml": `[tool.poetry.dependencies]
+python = "^3.11"
+Flask = "^3.0"
+redis = { version = "^5.0", optional = true }
+
+[tool.poetry.dev-dependencies]
+black = "^24.0"
+
+[tool.poetry.group.test.dependencies]
+pytest = "^8.0"
+`},
+			want: []string{"black ^24.0 [dev]", "flask ^3.0", "pytest ^8.0 [dev]", "redis ^5.0 [dev]"},
+		},
+		{
+			name: "requirements with includes",
+			files: map[string]string{
+				"requirements.txt": `# Production
+-r requirements/base.txt
+-e git+https://github.com/org/pkg.git#egg=pkg
+--index-url https://pypi.org/simple
+gunicorn==21.2.0 \
+    --hash=sha256:abc
+`,
+				"requirements/base.txt":  "django>=4.2  # LTS\n-r ../requirements.txt\n",
+				"requirements-dev.txt":   "-r requirements.txt\npytest==8.1.1\n",
+				"dev-requirements.txt":   "black\n",
+				"requirements-other.txt": "ignored==1.0\n",
+			},
+			want: []string{"black  [dev]", "django >=4.2", "gunicorn ==21.2.0", "pytest ==8.1.1 [dev]"},
+		},
+		{
+			name: "pyproject wins over requirements",
+			files: map[string]string{
+				"pyproject.toml":   "[project]\ndependencies = [\"httpx>=0.27\"]\n",
+				"requirements.txt": "httpx==0.27.0\nanyio==4.3.0\n",
+			},
+			want: []string{"anyio ==4.3.0", "httpx >=0.27"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+
+			deps, err := parsePythonDeps(dir)
+			if err != nil {
+				t.Fatalf("parsePythonDeps failed: %v", err)
+			}
+			expectStrings(t, "deps", describe(deps), tt.want)
+		})
+	}
+
+	if _, err := parsePythonDeps(t.TempDir()); err == nil {
+		t.Error("parsePythonDeps should fail without any Python manifest")
+	}
+}
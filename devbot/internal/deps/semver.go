@@ -0,0 +1,156 @@
+package deps
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version. Non-semver schemes (Python's
+// "2.0rc1", "1.2.3.post1") are parsed as far as their leading numbers go.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string // Prerelease, e.g. "beta.1" or Go pseudo-version suffix
+	Valid               bool
+}
+
+// ParseVersion parses "v1.2.3", "1.2", "1.2.3-rc.1+build" and similar.
+// Range operators and wildcards ("18.x", "1.2.*") are not accepted; see
+// MinVersion for those.
+func ParseVersion(s string) Version {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i] // Build metadata never affects precedence
+	}
+
+	var v Version
+	nums := [3]*int{&v.Major, &v.Minor, &v.Patch}
+	for i := 0; i < 3; i++ {
+		end := 0
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			if i == 0 {
+				return Version{}
+			}
+			break
+		}
+		*nums[i], _ = strconv.Atoi(s[:end])
+		s = s[end:]
+		if i < 2 && strings.HasPrefix(s, ".") && len(s) > 1 && s[1] >= '0' && s[1] <= '9' {
+			s = s[1:]
+			continue
+		}
+		break
+	}
+
+	// A wildcard component makes it a range, not a prerelease
+	if len(s) > 1 && s[0] == '.' && strings.IndexByte("xX*", s[1]) >= 0 {
+		return Version{}
+	}
+
+	v.Pre = strings.TrimLeft(s, "-.")
+	v.Valid = true
+	return v
+}
+
+// MinVersion returns the lowest version a declared range admits, as far as
+// it can be read: "^18.2.0" and ">=18.2 <19" give 18.2.0, "~1.4 || ^2" gives
+// 1.4.0. Tags, URLs and workspace references give an invalid Version.
+func MinVersion(spec string) Version {
+	spec = strings.TrimSpace(spec)
+	spec = strings.TrimPrefix(spec, "npm:")
+	if i := strings.Index(spec, "||"); i >= 0 {
+		spec = spec[:i]
+	}
+	spec = strings.TrimLeft(spec, "^~>=< ")
+	if i := strings.IndexAny(spec, " ,"); i >= 0 {
+		spec = spec[:i]
+	}
+	for _, wildcard := range []string{".x", ".X", ".*"} {
+		for strings.HasSuffix(spec, wildcard) {
+			spec = strings.TrimSuffix(spec, wildcard)
+		}
+	}
+	return ParseVersion(spec)
+}
+
+// String formats v as major.minor.patch[-pre]
+func (v Version) String() string {
+	if !v.Valid {
+		return ""
+	}
+	s := strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// CompareVersions orders two version strings like semver: -1, 0 or 1.
+// Invalid versions sort before valid ones, and compare as strings with each
+// other.
+func CompareVersions(a, b string) int {
+	va, vb := ParseVersion(a), ParseVersion(b)
+	switch {
+	case !va.Valid && !vb.Valid:
+		return strings.Compare(a, b)
+	case !va.Valid:
+		return -1
+	case !vb.Valid:
+		return 1
+	}
+
+	for _, d := range []int{va.Major - vb.Major, va.Minor - vb.Minor, va.Patch - vb.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+
+	// A prerelease sorts before its release
+	switch {
+	case va.Pre == vb.Pre:
+		return 0
+	case va.Pre == "":
+		return 1
+	case vb.Pre == "":
+		return -1
+	}
+	return comparePre(va.Pre, vb.Pre)
+}
+
+// comparePre compares dot-separated prerelease identifiers per semver:
+// numeric identifiers numerically, others lexically, numbers first
+func comparePre(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return sign(na - nb)
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(pa) - len(pb))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package deps
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Version
+	}{
+		{"1.2.3", Version{1, 2, 3, "", true}},
+		{"v1.2.3", Version{1, 2, 3, "", true}},
+		{"1.2", Version{1, 2, 0, "", true}},
+		{"18", Version{18, 0, 0, "", true}},
+		{"1.2.3-rc.1+build.5", Version{1, 2, 3, "rc.1", true}},
+		{"v0.0.0-20231010123456-abcdef123456", Version{0, 0, 0, "20231010123456-abcdef123456", true}},
+		{"2.0rc1", Version{2, 0, 0, "rc1", true}},
+		{"1.2.3.post1", Version{1, 2, 3, "post1", true}},
+		{"^18.2.0", Version{}},
+		{"18.x", Version{}},
+		{"1.2.*", Version{}},
+		{"1.X", Version{}},
+		{"latest", Version{}},
+		{"", Version{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseVersion(tt.input); got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0", "10.0.0", -1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.2", "1.0.0-alpha.10", -1},
+		{"1.0.0-alpha.1", "1.0.0-beta", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0+build", "1.0.0", 0},
+		{"garbage", "0.0.1", -1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := CompareVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestMinVersion(t *testing.T) {
+	tests := map[string]string{
+		"^18.2.0":        "18.2.0",
+		"~1.4":           "1.4.0",
+		">=18.2 <19":     "18.2.0",
+		">=2.31,<3":      "2.31.0",
+		"~1.4 || ^2":     "1.4.0",
+		"18.x":           "18.0.0",
+		"1.x.x":          "1.0.0",
+		"1.2.*":          "1.2.0",
+		"npm:^4.17.21":   "4.17.21",
+		"==2.31.0":       "2.31.0",
+		"1.2.3-beta.1":   "1.2.3-beta.1",
+		"latest":         "",
+		"workspace:*":    "",
+		"github:org/pkg": "",
+	}
+	for spec, want := range tests {
+		if got := MinVersion(spec).String(); got != want {
+			t.Errorf("MinVersion(%q) = %q, want %q", spec, got, want)
+		}
+	}
+}
@@ -0,0 +1,485 @@
+package deps
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML decodes the subset of TOML found in lockfiles and manifests:
+// tables, arrays of tables, dotted and quoted keys, strings (basic, literal
+// and multi-line), integers, floats, booleans, arrays and inline tables.
+// Dates are kept as strings.
+func parseTOML(data string) (map[string]any, error) {
+	p := &tomlParser{src: data, line: 1}
+	root := make(map[string]any)
+	current := root
+
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return root, nil
+		}
+
+		switch {
+		case p.peek() == '[':
+			table, err := p.header(root)
+			if err != nil {
+				return nil, err
+			}
+			current = table
+		default:
+			if err := p.keyValue(current); err != nil {
+				return nil, err
+			}
+		}
+
+		p.skipSpace()
+		if !p.eof() && p.peek() != '\n' && p.peek() != '#' && p.peek() != '\r' {
+			return nil, p.errorf("unexpected %q after value", p.peek())
+		}
+	}
+}
+
+type tomlParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) eof() bool  { return p.pos >= len(p.src) }
+func (p *tomlParser) peek() byte { return p.src[p.pos] }
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("toml line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) advance() {
+	if p.src[p.pos] == '\n' {
+		p.line++
+	}
+	p.pos++
+}
+
+// skipSpace skips spaces and tabs on the current line
+func (p *tomlParser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments
+func (p *tomlParser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r', '\n':
+			p.advance()
+		case '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// header parses [table] or [[array]] and returns the table to fill
+func (p *tomlParser) header(root map[string]any) (map[string]any, error) {
+	p.pos++ // [
+	array := !p.eof() && p.peek() == '['
+	if array {
+		p.pos++
+	}
+
+	keys, err := p.keyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	end := "]"
+	if array {
+		end = "]]"
+	}
+	p.skipSpace()
+	if !strings.HasPrefix(p.src[p.pos:], end) {
+		return nil, p.errorf("expected %s", end)
+	}
+	p.pos += len(end)
+
+	parent, err := descend(root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	last := keys[len(keys)-1]
+
+	if array {
+		list, _ := parent[last].([]any)
+		table := make(map[string]any)
+		parent[last] = append(list, table)
+		return table, nil
+	}
+
+	switch existing := parent[last].(type) {
+	case map[string]any:
+		return existing, nil
+	case nil:
+		table := make(map[string]any)
+		parent[last] = table
+		return table, nil
+	default:
+		return nil, p.errorf("key %q is already a value", last)
+	}
+}
+
+// descend walks keys from table, creating tables as needed. An array of
+// tables resolves to its last element, as TOML specifies for [a.b] after
+// [[a]].
+func descend(table map[string]any, keys []string) (map[string]any, error) {
+	for _, k := range keys {
+		switch next := table[k].(type) {
+		case map[string]any:
+			table = next
+		case []any:
+			if len(next) == 0 {
+				return nil, fmt.Errorf("key %q is an empty array", k)
+			}
+			last, ok := next[len(next)-1].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("key %q is not a table", k)
+			}
+			table = last
+		case nil:
+			t := make(map[string]any)
+			table[k] = t
+			table = t
+		default:
+			return nil, fmt.Errorf("key %q is not a table", k)
+		}
+	}
+	return table, nil
+}
+
+func (p *tomlParser) keyValue(table map[string]any) error {
+	keys, err := p.keyPath()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.eof() || p.peek() != '=' {
+		return p.errorf("expected = after key")
+	}
+	p.pos++
+	p.skipSpace()
+
+	value, err := p.value()
+	if err != nil {
+		return err
+	}
+
+	parent, err := descend(table, keys[:len(keys)-1])
+	if err != nil {
+		return p.errorf("%v", err)
+	}
+	parent[keys[len(keys)-1]] = value
+	return nil
+}
+
+// keyPath parses a dotted key such as a."b.c".d
+func (p *tomlParser) keyPath() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil, p.errorf("expected key")
+		}
+
+		var key string
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if p.pos == start {
+				return nil, p.errorf("invalid key character %q", p.peek())
+			}
+			key = p.src[start:p.pos]
+		}
+		keys = append(keys, key)
+
+		p.skipSpace()
+		if p.eof() || p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) value() (any, error) {
+	if p.eof() {
+		return nil, p.errorf("expected value")
+	}
+
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.str()
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	case strings.HasPrefix(p.src[p.pos:], "true"):
+		p.pos += 4
+		return true, nil
+	case strings.HasPrefix(p.src[p.pos:], "false"):
+		p.pos += 5
+		return false, nil
+	default:
+		return p.scalar()
+	}
+}
+
+// scalar parses numbers and dates, keeping anything that isn't a number as
+// its literal text
+func (p *tomlParser) scalar() (any, error) {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		// A space ends the value, except between a date and time
+		if c == ' ' && p.pos-start == 10 && p.src[start+4] == '-' && p.pos+1 < len(p.src) && isDigit(p.src[p.pos+1]) {
+			p.pos++
+			continue
+		}
+		if c == ',' || c == ']' || c == '}' || c == '\n' || c == '\r' || c == '#' || c == ' ' || c == '\t' {
+			break
+		}
+		p.pos++
+	}
+	text := strings.TrimSpace(p.src[start:p.pos])
+	if text == "" {
+		return nil, p.errorf("expected value")
+	}
+
+	clean := strings.ReplaceAll(text, "_", "")
+	if n, err := strconv.ParseInt(clean, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(clean, 64); err == nil {
+		return f, nil
+	}
+	return text, nil
+}
+
+func (p *tomlParser) array() ([]any, error) {
+	p.pos++ // [
+	list := []any{}
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return list, nil
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+
+		p.skipBlank()
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected , or ] in array")
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (map[string]any, error) {
+	p.pos++ // {
+	table := make(map[string]any)
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return table, nil
+		}
+
+		if err := p.keyValue(table); err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, p.errorf("expected , or } in inline table")
+		}
+	}
+}
+
+// str parses basic ("..."), literal ('...') and multi-line strings
+func (p *tomlParser) str() (string, error) {
+	quote := p.peek()
+	if strings.HasPrefix(p.src[p.pos:], strings.Repeat(string(quote), 3)) {
+		return p.multilineStr(quote)
+	}
+
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && quote == '"':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *tomlParser) multilineStr(quote byte) (string, error) {
+	delim := strings.Repeat(string(quote), 3)
+	p.pos += 3
+	// A newline right after the opening delimiter is trimmed
+	if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+		p.pos += 2
+		p.line++
+	} else if !p.eof() && p.peek() == '\n' {
+		p.advance()
+	}
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], delim) {
+			p.pos += 3
+			return b.String(), nil
+		}
+		c := p.peek()
+		if c == '\\' && quote == '"' {
+			p.pos++
+			// Line-ending backslash trims the newline and leading space
+			if !p.eof() && (p.peek() == '\n' || p.peek() == '\r' || p.peek() == ' ') {
+				p.skipBlank()
+				continue
+			}
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b.WriteByte(c)
+		p.advance()
+	}
+}
+
+// escape decodes the escape sequence after a backslash
+func (p *tomlParser) escape(b *strings.Builder) error {
+	if p.eof() {
+		return p.errorf("unterminated escape")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'n':
+		b.WriteByte('\n')
+	case 't':
+		b.WriteByte('\t')
+	case 'r':
+		b.WriteByte('\r')
+	case '"', '\\':
+		b.WriteByte(c)
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return p.errorf("short unicode escape")
+		}
+		r, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil {
+			return p.errorf("bad unicode escape")
+		}
+		b.WriteRune(rune(r))
+		p.pos += n
+	default:
+		return p.errorf("unknown escape \\%c", c)
+	}
+	return nil
+}
+
+// Accessors for decoded TOML
+
+func tomlString(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func tomlTable(m map[string]any, key string) map[string]any {
+	t, _ := m[key].(map[string]any)
+	return t
+}
+
+func tomlTables(m map[string]any, key string) []map[string]any {
+	list, _ := m[key].([]any)
+	var out []map[string]any
+	for _, v := range list {
+		if t, ok := v.(map[string]any); ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func tomlStrings(m map[string]any, key string) []string {
+	list, _ := m[key].([]any)
+	var out []string
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package deps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	doc, err := parseTOML(`
+# comment
+version = 1
+name = "demo" # trailing comment
+literal = 'C:\path'
+escaped = "tab\there \u00e9"
+multi = """
+line one
+line two"""
+float = 1.5
+flag = true
+list = [
+  "a", # inline comment
+  "b",
+]
+inline = { editable = ".", "quoted key" = 2 }
+date = 2024-01-02T03:04:05Z
+spaced = 1979-05-27 07:32:00 # local
+
+[tool.poetry.dependencies]
+python = "^3.11"
+requests = { version = "^2.31", optional = true }
+
+[[package]]
+name = "serde"
+dependencies = ["serde_derive 1.0.1"]
+
+[[package]]
+name = "anyhow"
+
+[package.source]
+registry = "crates-io"
+
+[a."b.c"]
+d = 1
+`)
+	if err != nil {
+		t.Fatalf("parseTOML failed: %v", err)
+	}
+
+	checks := map[string]struct {
+		got, want any
+	}{
+		"int":     {doc["version"], int64(1)},
+		"string":  {doc["name"], "demo"},
+		"literal": {doc["literal"], `C:\path`},
+		"escaped": {doc["escaped"], "tab\there é"},
+		"multi":   {doc["multi"], "line one\nline two"},
+		"float":   {doc["float"], 1.5},
+		"bool":    {doc["flag"], true},
+		"list":    {doc["list"], []any{"a", "b"}},
+		"inline":  {doc["inline"], map[string]any{"editable": ".", "quoted key": int64(2)}},
+		"date":    {doc["date"], "2024-01-02T03:04:05Z"},
+		"spaced":  {doc["spaced"], "1979-05-27 07:32:00"},
+		"nested":  {tomlString(tomlTable(tomlTable(tomlTable(doc, "tool"), "poetry"), "dependencies"), "python"), "^3.11"},
+		"quoted":  {tomlTable(doc, "a")["b.c"], map[string]any{"d": int64(1)}},
+	}
+	for name, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %#v, want %#v", name, c.got, c.want)
+		}
+	}
+
+	packages := tomlTables(doc, "package")
+	if len(packages) != 2 {
+		t.Fatalf("got %d packages, want 2", len(packages))
+	}
+	// [package.source] after [[package]] belongs to the last element
+	if got := tomlString(tomlTable(packages[1], "source"), "registry"); got != "crates-io" {
+		t.Errorf("package[1].source.registry = %q, want crates-io", got)
+	}
+	if tomlTable(packages[0], "source") != nil {
+		t.Error("package[0] should have no source")
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []string{
+		`name = "unterminated`,
+		`name "missing equals"`,
+		`list = [1, 2`,
+		`[table`,
+		"a = 1\n[a]",
+		`x = "bad \q escape"`,
+		`x = 1 y = 2`,
+	}
+	for _, input := range tests {
+		if _, err := parseTOML(input); err == nil {
+			t.Errorf("parseTOML(%q) should fail", input)
+		}
+	}
+}
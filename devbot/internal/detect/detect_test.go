@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/stack"
 )
 
 func TestProjectStackGo(t *testing.T) {
@@ -216,30 +218,39 @@ func TestProjectStackPackagesGlob(t *testing.T) {
 	}
 }
 
-func TestProjectStackEmpty(t *testing.T) {
+func TestProjectStackConfigured(t *testing.T) {
+	reg, err := stack.New([]stack.Config{{Name: "terraform", Markers: []string{"*.tf"}}}, []string{"infra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stack.SetDefault(reg)
+	defer stack.SetDefault(nil)
+
 	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module test"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "infra"), 0755); err != nil {
+		t.Fatalf("Failed to create infra: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "infra", "main.tf"), []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to write main.tf: %v", err)
+	}
 
-	stack := ProjectStack(tmpDir)
+	stacks := ProjectStack(tmpDir)
 
-	if len(stack) != 0 {
-		t.Errorf("ProjectStack = %v, want empty slice", stack)
+	if len(stacks) != 2 || stacks[0] != "go" || stacks[1] != "terraform" {
+		t.Errorf("ProjectStack = %v, want [go terraform]", stacks)
 	}
 }
 
-func TestFileExists(t *testing.T) {
+func TestProjectStackEmpty(t *testing.T) {
 	tmpDir := t.TempDir()
-	testFile := filepath.Join(tmpDir, "test.txt")
-
-	if fileExists(testFile) {
-		t.Error("fileExists should return false for non-existent file")
-	}
 
-	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
-		t.Fatalf("Failed to write file: %v", err)
-	}
+	stack := ProjectStack(tmpDir)
 
-	if !fileExists(testFile) {
-		t.Error("fileExists should return true for existing file")
+	if len(stack) != 0 {
+		t.Errorf("ProjectStack = %v, want empty slice", stack)
 	}
 }
 
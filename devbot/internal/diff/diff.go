@@ -3,10 +3,10 @@ package diff
 import (
 	"bytes"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 
+	"github.com/sloanahrens/devbot-go/internal/git"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
@@ -33,19 +33,22 @@ func GetDiff(repo workspace.RepoInfo) DiffResult {
 	result := DiffResult{Repo: repo}
 
 	// Get current branch
-	result.Branch = gitCommand(repo.Path, "rev-parse", "--abbrev-ref", "HEAD")
+	result.Branch, _ = git.Output(repo.Path, "rev-parse", "--abbrev-ref", "HEAD")
 
 	// Get staged changes with stats
-	result.Staged = getChangesWithStats(repo.Path, true)
+	result.Staged, result.Error = getChangesWithStats(repo.Path, true)
+	if result.Error != nil {
+		return result
+	}
 
 	// Get unstaged changes with stats
-	result.Unstaged = getChangesWithStats(repo.Path, false)
+	result.Unstaged, result.Error = getChangesWithStats(repo.Path, false)
 
 	return result
 }
 
 // getChangesWithStats gets file changes with addition/deletion counts
-func getChangesWithStats(repoPath string, staged bool) []FileChange {
+func getChangesWithStats(repoPath string, staged bool) ([]FileChange, error) {
 	var changes []FileChange
 
 	// Get file list with status
@@ -56,11 +59,14 @@ func getChangesWithStats(repoPath string, staged bool) []FileChange {
 		statusArgs = []string{"diff", "--name-status"}
 	}
 
-	statusOutput := gitCommand(repoPath, statusArgs...)
+	statusOutput, err := git.Output(repoPath, statusArgs...)
+	if err != nil {
+		return nil, err
+	}
 	if statusOutput == "" {
 		// Also check for untracked files if looking at unstaged
 		if !staged {
-			untracked := gitCommand(repoPath, "ls-files", "--others", "--exclude-standard")
+			untracked, _ := git.Output(repoPath, "ls-files", "--others", "--exclude-standard")
 			if untracked != "" {
 				for _, file := range strings.Split(untracked, "\n") {
 					if file != "" {
@@ -72,7 +78,7 @@ func getChangesWithStats(repoPath string, staged bool) []FileChange {
 				}
 			}
 		}
-		return changes
+		return changes, nil
 	}
 
 	// Parse status output
@@ -97,7 +103,7 @@ func getChangesWithStats(repoPath string, staged bool) []FileChange {
 		numstatArgs = []string{"diff", "--numstat"}
 	}
 
-	numstatOutput := gitCommand(repoPath, numstatArgs...)
+	numstatOutput, _ := git.Output(repoPath, numstatArgs...)
 	for _, line := range strings.Split(numstatOutput, "\n") {
 		if line == "" {
 			continue
@@ -133,7 +139,7 @@ func getChangesWithStats(repoPath string, staged bool) []FileChange {
 
 	// Add untracked files if looking at unstaged
 	if !staged {
-		untracked := gitCommand(repoPath, "ls-files", "--others", "--exclude-standard")
+		untracked, _ := git.Output(repoPath, "ls-files", "--others", "--exclude-standard")
 		if untracked != "" {
 			for _, file := range strings.Split(untracked, "\n") {
 				if file != "" {
@@ -146,7 +152,7 @@ func getChangesWithStats(repoPath string, staged bool) []FileChange {
 		}
 	}
 
-	return changes
+	return changes, nil
 }
 
 // TotalAdditions returns total additions across all changes
@@ -173,28 +179,13 @@ func (d *DiffResult) TotalDeletions() int {
 	return total
 }
 
-func gitCommand(dir string, args ...string) string {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = nil
-
-	if err := cmd.Run(); err != nil {
-		return ""
-	}
-
-	return strings.TrimSpace(out.String())
-}
-
 // GetDiffFull retrieves diff with full content including untracked file content
 func GetDiffFull(repo workspace.RepoInfo) DiffResult {
 	result := GetDiff(repo)
 
 	// Add content for staged changes
 	for i := range result.Staged {
-		result.Staged[i].Content = gitCommand(repo.Path, "diff", "--cached", "--", result.Staged[i].Path)
+		result.Staged[i].Content, _ = git.Output(repo.Path, "diff", "--cached", "--", result.Staged[i].Path)
 	}
 
 	// Add content for unstaged changes
@@ -213,7 +204,7 @@ func GetDiffFull(repo workspace.RepoInfo) DiffResult {
 			}
 		} else {
 			// Tracked file - use git diff
-			result.Unstaged[i].Content = gitCommand(repo.Path, "diff", "--", result.Unstaged[i].Path)
+			result.Unstaged[i].Content, _ = git.Output(repo.Path, "diff", "--", result.Unstaged[i].Path)
 		}
 	}
 
@@ -0,0 +1,253 @@
+// Package git runs git commands for the rest of devbot. Every invocation
+// goes through Run, or Stream for output meant for the user, which apply
+// timeouts, classify failures into typed errors and trace calls to stderr
+// when DEVBOT_TRACE=1.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds commands run through Output and OutputRaw
+var DefaultTimeout = 30 * time.Second
+
+const waitDelay = 2 * time.Second
+
+// Failure kinds, matched with errors.Is
+var (
+	ErrNotFound   = errors.New("git executable not found")
+	ErrNotRepo    = errors.New("not a git repository")
+	ErrNoUpstream = errors.New("no upstream configured")
+	ErrNoCommits  = errors.New("no commits yet")
+	ErrTimeout    = errors.New("git command timed out")
+)
+
+// Error describes a failed git invocation
+type Error struct {
+	Dir      string
+	Args     []string
+	ExitCode int    // -1 if git didn't run to completion
+	Stderr   string // Trimmed stderr output
+	Kind     error  // One of the Err* values above, or nil
+	Err      error  // Underlying exec error
+}
+
+func (e *Error) Error() string {
+	msg := e.Stderr
+	if msg == "" && e.Kind != nil {
+		msg = e.Kind.Error()
+	}
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+	// Only the first line; git's hints follow on later lines
+	msg = strings.SplitN(msg, "\n", 2)[0]
+	msg = strings.TrimPrefix(msg, "fatal: ")
+	return fmt.Sprintf("git %s: %s", subcommand(e.Args), msg)
+}
+
+// Unwrap exposes both the failure kind and the underlying error
+func (e *Error) Unwrap() []error {
+	if e.Kind != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Err}
+}
+
+var (
+	envMu    sync.RWMutex
+	extraEnv []string
+)
+
+// SetEnv adds KEY=VALUE pairs to the environment of every git command
+// until the returned restore func is called. Unlike os.Setenv it doesn't
+// reach other subprocesses or outlive the caller.
+func SetEnv(vars ...string) (restore func()) {
+	envMu.Lock()
+	prev := extraEnv
+	extraEnv = append(append([]string(nil), prev...), vars...)
+	envMu.Unlock()
+
+	return func() {
+		envMu.Lock()
+		extraEnv = prev
+		envMu.Unlock()
+	}
+}
+
+// Run executes git with args in dir and returns stdout. ctx cancels the
+// command; failures are returned as *Error.
+func Run(ctx context.Context, dir string, args ...string) (string, error) {
+	start := time.Now()
+
+	var stdout, stderr bytes.Buffer
+	cmd := command(ctx, dir, args)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		gitErr := classify(ctx, dir, args, stderr.String(), err)
+		trace(dir, args, time.Since(start), gitErr)
+		return stdout.String(), gitErr
+	}
+	trace(dir, args, time.Since(start), nil)
+	return stdout.String(), nil
+}
+
+// Stream executes git with args in dir, writing its output to stdout and
+// stderr as it's produced. Passing os.Stdout keeps git's pager and colors,
+// as for log and show. Failures are returned as *Error, with Stderr holding
+// what was already written.
+func Stream(ctx context.Context, dir string, stdout, stderr io.Writer, args ...string) error {
+	start := time.Now()
+
+	var captured bytes.Buffer
+	cmd := command(ctx, dir, args)
+	cmd.Stdout = stdout
+	cmd.Stderr = io.MultiWriter(stderr, &captured)
+
+	if err := cmd.Run(); err != nil {
+		gitErr := classify(ctx, dir, args, captured.String(), err)
+		trace(dir, args, time.Since(start), gitErr)
+		return gitErr
+	}
+	trace(dir, args, time.Since(start), nil)
+	return nil
+}
+
+func command(ctx context.Context, dir string, args []string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Never block on a credential prompt
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	envMu.RLock()
+	cmd.Env = append(cmd.Env, extraEnv...)
+	envMu.RUnlock()
+	// Don't hang on pipes held open by children of a killed git
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+// Output runs git with DefaultTimeout and returns trimmed stdout
+func Output(dir string, args ...string) (string, error) {
+	out, err := OutputRaw(dir, args...)
+	return strings.TrimSpace(out), err
+}
+
+// OutputRaw is Output without trimming (for -z and other exact output)
+func OutputRaw(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	return Run(ctx, dir, args...)
+}
+
+// Lines runs git with DefaultTimeout and splits stdout into non-empty lines
+func Lines(dir string, args ...string) ([]string, error) {
+	out, err := Output(dir, args...)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+func classify(ctx context.Context, dir string, args []string, stderr string, err error) *Error {
+	e := &Error{
+		Dir:      dir,
+		Args:     args,
+		ExitCode: -1,
+		Stderr:   strings.TrimSpace(stderr),
+		Err:      err,
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		e.ExitCode = exitErr.ExitCode()
+	}
+
+	lower := strings.ToLower(e.Stderr)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		e.Kind = ErrNotFound
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		e.Kind = ErrTimeout
+	case strings.Contains(lower, "not a git repository"):
+		e.Kind = ErrNotRepo
+	case strings.Contains(lower, "no upstream configured"),
+		strings.Contains(lower, "no upstream branch"),
+		strings.Contains(lower, "does not point to a branch"),
+		strings.Contains(lower, "no such branch"):
+		e.Kind = ErrNoUpstream
+	case strings.Contains(lower, "does not have any commits yet"),
+		strings.Contains(lower, "ambiguous argument 'head'"):
+		e.Kind = ErrNoCommits
+	}
+
+	// A missing directory fails before git even starts
+	var pathErr *os.PathError
+	if e.Kind == nil && errors.As(err, &pathErr) {
+		e.Kind = ErrNotRepo
+	}
+
+	return e
+}
+
+// Tracing
+
+var (
+	traceMu  sync.Mutex
+	traceOut io.Writer = os.Stderr
+)
+
+// SetTraceOutput redirects trace lines (for tests); returns the previous writer
+func SetTraceOutput(w io.Writer) io.Writer {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	prev := traceOut
+	traceOut = w
+	return prev
+}
+
+// Tracing reports whether DEVBOT_TRACE is enabled
+func Tracing() bool {
+	v := os.Getenv("DEVBOT_TRACE")
+	return v != "" && v != "0"
+}
+
+func trace(dir string, args []string, elapsed time.Duration, err error) {
+	if !Tracing() {
+		return
+	}
+
+	status := "ok"
+	if err != nil {
+		status = "error: " + err.Error()
+	}
+
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	fmt.Fprintf(traceOut, "[git %6.1fms] %s: git %s (%s)\n",
+		float64(elapsed.Microseconds())/1000, dir, strings.Join(args, " "), status)
+}
+
+// subcommand returns the git subcommand in args, skipping global options
+// such as -C dir and -c key=value
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-C" || args[i] == "-c":
+			i++
+		case !strings.HasPrefix(args[i], "-"):
+			return args[i]
+		}
+	}
+	return ""
+}
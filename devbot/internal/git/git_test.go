@@ -0,0 +1,196 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func commitFile(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := Output(dir, "add", "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Output(dir, "commit", "--quiet", "-m", "init"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOutput(t *testing.T) {
+	dir := initRepo(t)
+	commitFile(t, dir)
+
+	out, err := Output(dir, "log", "--format=%s")
+	if err != nil {
+		t.Fatalf("Output failed: %v", err)
+	}
+	if out != "init" {
+		t.Errorf("Output = %q, want trimmed %q", out, "init")
+	}
+
+	raw, err := OutputRaw(dir, "log", "--format=%s")
+	if err != nil || raw != "init\n" {
+		t.Errorf("OutputRaw = %q, %v; want untrimmed", raw, err)
+	}
+
+	lines, err := Lines(dir, "ls-files")
+	if err != nil || len(lines) != 1 || lines[0] != "README.md" {
+		t.Errorf("Lines = %q, %v; want [README.md]", lines, err)
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	repo := initRepo(t)
+	committed := initRepo(t)
+	commitFile(t, committed)
+
+	tests := []struct {
+		name string
+		dir  string
+		args []string
+		kind error
+	}{
+		{"not a repo", t.TempDir(), []string{"status"}, ErrNotRepo},
+		{"missing dir", filepath.Join(t.TempDir(), "gone"), []string{"status"}, ErrNotRepo},
+		{"no upstream", committed, []string{"rev-parse", "--abbrev-ref", "@{upstream}"}, ErrNoUpstream},
+		{"no commits", repo, []string{"log", "-1"}, ErrNoCommits},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Output(tt.dir, tt.args...)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("err = %v, want %v", err, tt.kind)
+			}
+
+			var gitErr *Error
+			if !errors.As(err, &gitErr) {
+				t.Fatalf("err is %T, want *git.Error", err)
+			}
+			if strings.Contains(gitErr.Error(), "\n") || !strings.HasPrefix(gitErr.Error(), "git "+tt.args[0]+": ") {
+				t.Errorf("Error() = %q, want single line prefixed with the subcommand", gitErr.Error())
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	dir := initRepo(t)
+	_, err := Output(dir, "rev-parse", "--verify", "--quiet", "nope")
+
+	var gitErr *Error
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("err = %v, want *git.Error", err)
+	}
+	if gitErr.ExitCode != 1 || gitErr.Kind != nil {
+		t.Errorf("ExitCode = %d, Kind = %v; want 1 and no kind", gitErr.ExitCode, gitErr.Kind)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	dir := initRepo(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Run(ctx, dir, "-c", "alias.nap=!sleep 1", "nap")
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("err = %v, want ErrTimeout", err)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	dir := initRepo(t)
+
+	var buf bytes.Buffer
+	prev := SetTraceOutput(&buf)
+	defer SetTraceOutput(prev)
+
+	t.Setenv("DEVBOT_TRACE", "")
+	_, _ = Output(dir, "status")
+	if buf.Len() != 0 {
+		t.Errorf("trace written with DEVBOT_TRACE unset: %q", buf.String())
+	}
+
+	t.Setenv("DEVBOT_TRACE", "1")
+	_, _ = Output(dir, "status", "--porcelain")
+	_, _ = Output(t.TempDir(), "status")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("trace lines = %q, want 2", lines)
+	}
+	if !strings.Contains(lines[0], "git status --porcelain (ok)") {
+		t.Errorf("trace[0] = %q, want command and ok", lines[0])
+	}
+	if !strings.Contains(lines[1], "error: git status: not a git repository") {
+		t.Errorf("trace[1] = %q, want error", lines[1])
+	}
+}
+
+func TestSetEnv(t *testing.T) {
+	dir := initRepo(t)
+
+	restore := SetEnv("GIT_AUTHOR_NAME=Scoped")
+	got, err := Output(dir, "var", "GIT_AUTHOR_IDENT")
+	restore()
+	if err != nil || !strings.HasPrefix(got, "Scoped ") {
+		t.Errorf("ident with SetEnv = %q, %v; want Scoped", got, err)
+	}
+	if os.Getenv("GIT_AUTHOR_NAME") == "Scoped" {
+		t.Error("SetEnv should not change the process environment")
+	}
+
+	got, _ = Output(dir, "var", "GIT_AUTHOR_IDENT")
+	if strings.HasPrefix(got, "Scoped ") {
+		t.Errorf("ident after restore = %q, want the repo's user", got)
+	}
+}
+
+func TestStream(t *testing.T) {
+	dir := initRepo(t)
+
+	var stdout, stderr bytes.Buffer
+	if err := Stream(context.Background(), dir, &stdout, &stderr, "rev-parse", "--is-inside-work-tree"); err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	if strings.TrimSpace(stdout.String()) != "true" {
+		t.Errorf("stdout = %q, want true", stdout.String())
+	}
+
+	stdout.Reset()
+	err := Stream(context.Background(), dir, &stdout, &stderr, "switch", "no-such-branch")
+	var gitErr *Error
+	if !errors.As(err, &gitErr) || gitErr.Stderr == "" {
+		t.Fatalf("err = %#v, want *Error with stderr", err)
+	}
+	if !strings.Contains(stderr.String(), gitErr.Stderr) {
+		t.Errorf("stderr %q not passed through (captured %q)", stderr.String(), gitErr.Stderr)
+	}
+}
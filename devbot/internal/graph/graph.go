@@ -0,0 +1,248 @@
+// Package graph works out how repos in the workspace depend on each other,
+// by matching the modules and packages each repo declares against the
+// dependencies of the others.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+)
+
+// Link is one dependency that ties a repo to another
+type Link struct {
+	Name      string
+	Ecosystem string
+	Version   string // As declared, e.g. "^1.2.0", "v0.3.0" or "workspace:*"
+	Local     bool   // Consumed from disk (replace ../x, file:, link:) rather than a registry
+	Dev       bool   // Only dev dependencies use it
+}
+
+// Edge means From depends on To through one or more links
+type Edge struct {
+	From  string
+	To    string
+	Links []Link // Sorted by ecosystem and name
+}
+
+// Dev reports whether every link is a dev dependency
+func (e Edge) Dev() bool {
+	for _, l := range e.Links {
+		if !l.Dev {
+			return false
+		}
+	}
+	return true
+}
+
+// Graph is the internal dependency graph of a set of repos
+type Graph struct {
+	Repos    []string                   // Sorted
+	Provides map[string][]deps.Provided // Repo -> what it declares
+	Edges    []Edge                     // Sorted by From, then To
+}
+
+// Build matches every repo's declared dependencies (direct, dev and Go
+// indirect) against the packages the other repos provide. A name provided
+// by several repos links to all of them.
+func Build(results []deps.RepoDeps) *Graph {
+	g := &Graph{Provides: make(map[string][]deps.Provided)}
+	providers := make(map[string][]string) // "ecosystem name" -> repos
+	for _, r := range results {
+		g.Repos = append(g.Repos, r.Repo.Name)
+		g.Provides[r.Repo.Name] = r.Provides
+		for _, p := range r.Provides {
+			key := p.Ecosystem + " " + p.Name
+			providers[key] = appendUnique(providers[key], r.Repo.Name)
+		}
+	}
+	sort.Strings(g.Repos)
+
+	edges := make(map[[2]string]map[string]*Link)
+	for _, r := range results {
+		for _, d := range r.Dependencies {
+			if d.Transitive {
+				continue
+			}
+			for _, to := range providers[d.Ecosystem+" "+d.Name] {
+				if to == r.Repo.Name {
+					continue // A workspace member used by its own repo
+				}
+				pair := [2]string{r.Repo.Name, to}
+				if edges[pair] == nil {
+					edges[pair] = make(map[string]*Link)
+				}
+				key := d.Ecosystem + " " + d.Name
+				link := edges[pair][key]
+				if link == nil {
+					link = &Link{Name: d.Name, Ecosystem: d.Ecosystem, Version: d.Version, Dev: true}
+					edges[pair][key] = link
+				}
+				link.Local = link.Local || isLocal(d)
+				link.Dev = link.Dev && d.Dev
+			}
+		}
+	}
+
+	for pair, links := range edges {
+		e := Edge{From: pair[0], To: pair[1]}
+		for _, l := range links {
+			e.Links = append(e.Links, *l)
+		}
+		sort.Slice(e.Links, func(i, j int) bool {
+			if e.Links[i].Ecosystem != e.Links[j].Ecosystem {
+				return e.Links[i].Ecosystem < e.Links[j].Ecosystem
+			}
+			return e.Links[i].Name < e.Links[j].Name
+		})
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// isLocal reports whether d is consumed from a directory: a Go replace
+// without a version, or an npm file:/link:/portal: specifier
+func isLocal(d deps.Dependency) bool {
+	if d.Replace != "" && len(strings.Fields(d.Replace)) == 1 {
+		return true
+	}
+	for _, prefix := range []string{"file:", "link:", "portal:"} {
+		if strings.HasPrefix(d.Version, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Has reports whether repo is in the graph
+func (g *Graph) Has(repo string) bool {
+	i := sort.SearchStrings(g.Repos, repo)
+	return i < len(g.Repos) && g.Repos[i] == repo
+}
+
+// DependenciesOf returns the edges from repo
+func (g *Graph) DependenciesOf(repo string) []Edge {
+	var out []Edge
+	for _, e := range g.Edges {
+		if e.From == repo {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// DependentsOf returns the edges into repo
+func (g *Graph) DependentsOf(repo string) []Edge {
+	var out []Edge
+	for _, e := range g.Edges {
+		if e.To == repo {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Dependent is a repo affected by a change to another
+type Dependent struct {
+	Repo  string
+	Depth int    // 1 for direct dependents
+	Via   string // The repo it depends on that leads to the target
+}
+
+// Dependents returns every repo that depends on repo directly or through
+// other repos, nearest first, then by name. Each appears once, at its
+// shortest distance; cycles are followed only once.
+func (g *Graph) Dependents(repo string) []Dependent {
+	seen := map[string]bool{repo: true}
+	var out []Dependent
+	frontier := []string{repo}
+	for depth := 1; len(frontier) > 0; depth++ {
+		var level []Dependent
+		for _, target := range frontier {
+			for _, e := range g.DependentsOf(target) {
+				if seen[e.From] {
+					continue
+				}
+				seen[e.From] = true
+				level = append(level, Dependent{Repo: e.From, Depth: depth, Via: target})
+			}
+		}
+		sort.Slice(level, func(i, j int) bool { return level[i].Repo < level[j].Repo })
+		frontier = frontier[:0]
+		for _, d := range level {
+			frontier = append(frontier, d.Repo)
+		}
+		out = append(out, level...)
+	}
+	return out
+}
+
+// Subgraph keeps only the given repos and the edges between them
+func (g *Graph) Subgraph(repos []string) *Graph {
+	keep := make(map[string]bool)
+	for _, r := range repos {
+		keep[r] = true
+	}
+	sub := &Graph{Provides: make(map[string][]deps.Provided)}
+	for _, r := range g.Repos {
+		if keep[r] {
+			sub.Repos = append(sub.Repos, r)
+			sub.Provides[r] = g.Provides[r]
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub
+}
+
+// DOT renders the graph for Graphviz. Edges are labelled with the packages
+// that create them; dev-only edges are dashed.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph devbot {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, r := range g.Repos {
+		fmt.Fprintf(&b, "  %s;\n", dotQuote(r))
+	}
+	for _, e := range g.Edges {
+		var names []string
+		for _, l := range e.Links {
+			names = append(names, l.Name)
+		}
+		attrs := "label=" + dotQuote(strings.Join(names, "\n"))
+		if e.Dev() {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(e.From), dotQuote(e.To), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes s as a DOT ID
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
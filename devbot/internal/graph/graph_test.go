@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func repo(name string, provides []deps.Provided, dependencies ...deps.Dependency) deps.RepoDeps {
+	return deps.RepoDeps{Repo: workspace.RepoInfo{Name: name}, Provides: provides, Dependencies: dependencies}
+}
+
+// sample: web -> api-client (published from api) and ui; api -> shared via
+// a local replace; e2e -> web in dev only; shared <-> tools form a cycle
+func sample() []deps.RepoDeps {
+	return []deps.RepoDeps{
+		repo("web", []deps.Provided{{Name: "web", Ecosystem: deps.EcosystemNPM, Dir: "."}},
+			deps.Dependency{Name: "@org/api-client", Version: "^1.2.0", Ecosystem: deps.EcosystemNPM},
+			deps.Dependency{Name: "@org/ui", Version: "file:../ui", Ecosystem: deps.EcosystemNPM},
+			deps.Dependency{Name: "react", Version: "^18.2.0", Ecosystem: deps.EcosystemNPM},
+		),
+		repo("api", []deps.Provided{
+			{Name: "github.com/org/api", Ecosystem: deps.EcosystemGo, Dir: "."},
+			{Name: "@org/api-client", Ecosystem: deps.EcosystemNPM, Dir: "packages/client"},
+		},
+			deps.Dependency{Name: "github.com/org/shared", Version: "v0.3.0", Replace: "../shared", Ecosystem: deps.EcosystemGo},
+			deps.Dependency{Name: "@org/api-client", Version: "workspace:*", Ecosystem: deps.EcosystemNPM},
+		),
+		repo("ui", []deps.Provided{{Name: "@org/ui", Ecosystem: deps.EcosystemNPM, Dir: "."}}),
+		repo("shared", []deps.Provided{{Name: "github.com/org/shared", Ecosystem: deps.EcosystemGo, Dir: "."}},
+			deps.Dependency{Name: "github.com/org/tools", Version: "v1.0.0", Indirect: true, Ecosystem: deps.EcosystemGo},
+		),
+		repo("tools", []deps.Provided{{Name: "github.com/org/tools", Ecosystem: deps.EcosystemGo, Dir: "."}},
+			deps.Dependency{Name: "github.com/org/shared", Version: "v0.3.0", Ecosystem: deps.EcosystemGo},
+			deps.Dependency{Name: "github.com/org/api", Resolved: "v1.0.0", Transitive: true, Ecosystem: deps.EcosystemGo},
+		),
+		repo("e2e", nil,
+			deps.Dependency{Name: "web", Version: "link:../web", Dev: true, Ecosystem: deps.EcosystemNPM},
+		),
+	}
+}
+
+func describe(edges []Edge) []string {
+	var out []string
+	for _, e := range edges {
+		var links []string
+		for _, l := range e.Links {
+			s := l.Name + "@" + l.Version
+			if l.Local {
+				s += " local"
+			}
+			if l.Dev {
+				s += " dev"
+			}
+			links = append(links, s)
+		}
+		out = append(out, e.From+" -> "+e.To+": "+strings.Join(links, ", "))
+	}
+	return out
+}
+
+func expect(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("%s:\n%s\nwant:\n%s", what, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(sample())
+
+	expect(t, "repos", g.Repos, []string{"api", "e2e", "shared", "tools", "ui", "web"})
+	expect(t, "edges", describe(g.Edges), []string{
+		"api -> shared: github.com/org/shared@v0.3.0 local",
+		"e2e -> web: web@link:../web local dev",
+		"shared -> tools: github.com/org/tools@v1.0.0",
+		"tools -> shared: github.com/org/shared@v0.3.0",
+		"web -> api: @org/api-client@^1.2.0",
+		"web -> ui: @org/ui@file:../ui local",
+	})
+
+	expect(t, "dependencies of web", describe(g.DependenciesOf("web")), []string{
+		"web -> api: @org/api-client@^1.2.0",
+		"web -> ui: @org/ui@file:../ui local",
+	})
+	expect(t, "dependents of shared", describe(g.DependentsOf("shared")), []string{
+		"api -> shared: github.com/org/shared@v0.3.0 local",
+		"tools -> shared: github.com/org/shared@v0.3.0",
+	})
+	if !g.Has("ui") || g.Has("nope") {
+		t.Error("Has should report repos in the graph only")
+	}
+}
+
+func TestDependents(t *testing.T) {
+	g := Build(sample())
+
+	tests := map[string][]string{
+		"shared": {"api 1 shared", "tools 1 shared", "web 2 api", "e2e 3 web"},
+		"ui":     {"web 1 ui", "e2e 2 web"},
+		"e2e":    nil,
+	}
+	for target, want := range tests {
+		var got []string
+		for _, d := range g.Dependents(target) {
+			got = append(got, fmt.Sprintf("%s %d %s", d.Repo, d.Depth, d.Via))
+		}
+		expect(t, "dependents of "+target, got, want)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	g := Build(sample())
+	dot := g.Subgraph([]string{"web", "e2e", "ui"}).DOT()
+
+	for _, want := range []string{
+		"digraph devbot {",
+		`  "e2e" -> "web" [label="web", style=dashed];`,
+		`  "web" -> "ui" [label="@org/ui"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+	if strings.Contains(dot, `"api"`) {
+		t.Errorf("subgraph should drop repos outside it:\n%s", dot)
+	}
+}
@@ -0,0 +1,223 @@
+// Package ignore decides which files git ignores, so that directory walkers
+// skip the same paths git does. It reads .gitignore files at every level,
+// .git/info/exclude and the global excludes file (core.excludesFile).
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sloanahrens/devbot-go/internal/git"
+)
+
+// Defaults are ignored even without a .gitignore: dependency, build and
+// cache output that is never worth walking. A repo can re-include any of
+// them with a negated pattern such as "!build/".
+var Defaults = []string{
+	"node_modules/", "__pycache__/", ".pytest_cache/", "dist/", "build/",
+	".next/", ".turbo/", "coverage/", ".nyc_output/",
+	"*.pyc", "*.pyo", ".DS_Store", "Thumbs.db",
+}
+
+// Matcher reports whether paths under a root are ignored. .gitignore files
+// are read lazily as directories are visited; it is safe for concurrent use.
+type Matcher struct {
+	root   string // Walked directory
+	top    string // Enclosing repo's working tree, or root outside a repo
+	prefix string // root relative to top, slash separated; "" when equal
+
+	// Lowest precedence first: defaults, global excludes, info/exclude
+	base []pattern
+
+	mu   sync.Mutex
+	dirs map[string][]pattern // Directory relative to top -> its .gitignore
+	seen map[string]bool      // Directory relative to top -> ignored
+}
+
+// New returns a matcher for root. Extra patterns (as in a .gitignore) add
+// to Defaults, below anything git reads. When root is inside a git
+// working tree, the .gitignore files between the top of the tree and root
+// apply too.
+func New(root string, extra ...string) *Matcher {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	m := &Matcher{
+		root: abs,
+		top:  findTop(abs),
+		dirs: make(map[string][]pattern),
+		seen: make(map[string]bool),
+	}
+	if rel, err := filepath.Rel(m.top, abs); err == nil && rel != "." {
+		m.prefix = filepath.ToSlash(rel)
+	}
+
+	for _, line := range append(append([]string{}, Defaults...), extra...) {
+		if p, ok := parsePattern(line, ""); ok {
+			m.base = append(m.base, p)
+		}
+	}
+	if path := excludesFile(m.top); path != "" {
+		m.base = append(m.base, readPatterns(path, "")...)
+	}
+	if gitDir := findGitDir(m.top); gitDir != "" {
+		m.base = append(m.base, readPatterns(filepath.Join(gitDir, "info", "exclude"), "")...)
+	}
+	return m
+}
+
+// Match reports whether path, relative to the root (either separator), is
+// ignored. A path inside an ignored directory is ignored whatever its own
+// patterns say, as in git. The .git directory is always ignored.
+func (m *Matcher) Match(path string, isDir bool) bool {
+	rel := filepath.ToSlash(filepath.Clean(path))
+	if rel == "." || rel == "" {
+		return false
+	}
+	if m.prefix != "" {
+		rel = m.prefix + "/" + rel
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parts := strings.Split(rel, "/")
+	for i := range parts[:len(parts)-1] {
+		if m.dirIgnored(strings.Join(parts[:i+1], "/")) {
+			return true
+		}
+	}
+	if isDir {
+		return m.dirIgnored(rel)
+	}
+	return m.ignored(rel, false)
+}
+
+// dirIgnored caches directory decisions; callers check parents first
+func (m *Matcher) dirIgnored(rel string) bool {
+	if ignored, ok := m.seen[rel]; ok {
+		return ignored
+	}
+	ignored := m.ignored(rel, true)
+	m.seen[rel] = ignored
+	return ignored
+}
+
+// ignored applies every pattern in precedence order; the last match wins.
+// Deeper .gitignore files take precedence over shallower ones.
+func (m *Matcher) ignored(rel string, isDir bool) bool {
+	if name := rel[strings.LastIndex(rel, "/")+1:]; name == ".git" {
+		return true
+	}
+
+	ignored := false
+	apply := func(patterns []pattern) {
+		for _, p := range patterns {
+			if p.matches(rel, isDir) {
+				ignored = !p.negate
+			}
+		}
+	}
+	apply(m.base)
+	apply(m.gitignore(""))
+	for i, c := range rel {
+		if c == '/' {
+			apply(m.gitignore(rel[:i]))
+		}
+	}
+	return ignored
+}
+
+// gitignore returns the patterns of dir's .gitignore (dir relative to top)
+func (m *Matcher) gitignore(dir string) []pattern {
+	if patterns, ok := m.dirs[dir]; ok {
+		return patterns
+	}
+	patterns := readPatterns(filepath.Join(m.top, filepath.FromSlash(dir), ".gitignore"), dir)
+	m.dirs[dir] = patterns
+	return patterns
+}
+
+// Walk is filepath.Walk over the root that skips ignored files and
+// directories; fn sees only what git would consider
+func (m *Matcher) Walk(fn filepath.WalkFunc) error {
+	return filepath.Walk(m.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == m.root {
+			return fn(path, info, err)
+		}
+		rel, relErr := filepath.Rel(m.root, path)
+		if relErr == nil && m.Match(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(path, info, err)
+	})
+}
+
+// findTop walks up from dir to the nearest directory containing .git
+func findTop(dir string) string {
+	for d := dir; ; {
+		if _, err := os.Lstat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// findGitDir returns the git directory for a working tree. Linked
+// worktrees and submodules have a .git file pointing at it; info/exclude
+// then lives in the common directory shared by all worktrees.
+func findGitDir(top string) string {
+	dotGit := filepath.Join(top, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return dotGit
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(top, gitDir)
+	}
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		dir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(gitDir, dir)
+		}
+		return filepath.Clean(dir)
+	}
+	return gitDir
+}
+
+// excludesFile returns core.excludesFile, or git's default of
+// $XDG_CONFIG_HOME/git/ignore (~/.config/git/ignore)
+func excludesFile(top string) string {
+	if path, err := git.Output(top, "config", "--path", "core.excludesFile"); err == nil && path != "" {
+		return path
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git", "ignore")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "git", "ignore")
+	}
+	return ""
+}
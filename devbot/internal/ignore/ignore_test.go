@@ -0,0 +1,232 @@
+package ignore
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// isolate keeps the user's global git config and excludes out of the test
+func isolate(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	return home
+}
+
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.log", "debug.log", false, true},
+		{"*.log", "logs/deep/debug.log", false, true},
+		{"*.log", "debug.log.txt", false, false},
+		{"temp", "temp", true, true},
+		{"temp", "src/temp", false, true},
+		{"build/", "build", true, true},
+		{"build/", "build", false, false},
+		{"build/", "src/build", true, true},
+		{"/build", "build", true, true},
+		{"/build", "src/build", true, false},
+		{"doc/frotz", "doc/frotz", true, true},
+		{"doc/frotz", "a/doc/frotz", true, false},
+		{"**/foo", "foo", false, true},
+		{"**/foo", "a/b/foo", false, true},
+		{"**/foo/bar", "a/foo/bar", false, true},
+		{"abc/**", "abc/x/y", false, true},
+		{"abc/**", "abc", true, false},
+		{"a/**/b", "a/b", false, true},
+		{"a/**/b", "a/x/y/b", false, true},
+		{"a/**/b", "x/a/b", false, false},
+		{"foo*bar", "foo/bar", false, false},
+		{"?.txt", "a.txt", false, true},
+		{"?.txt", "ab.txt", false, false},
+		{"[ab].txt", "b.txt", false, true},
+		{"[!ab].txt", "b.txt", false, false},
+		{"[!ab].txt", "c.txt", false, true},
+		{`\#hash`, "#hash", false, true},
+		{`\!bang`, "!bang", false, true},
+		{"trailing   ", "trailing", false, true},
+		{`space\ `, "space ", false, true},
+	}
+	for _, tt := range tests {
+		p, ok := parsePattern(tt.pattern, "")
+		if !ok {
+			t.Errorf("parsePattern(%q) failed", tt.pattern)
+			continue
+		}
+		if got := p.matches(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("%q matches %q (dir %v) = %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	for _, skipped := range []string{"", "   ", "# comment", "!", "/"} {
+		if _, ok := parsePattern(skipped, ""); ok {
+			t.Errorf("parsePattern(%q) should be skipped", skipped)
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	isolate(t)
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore":              "*.log\n!keep.log\n/out\nsecrets/\n",
+		"src/.gitignore":          "generated/\n!important.log\n*.tmp\n",
+		"src/lib/.gitignore":      "!*.tmp\n",
+		"secrets/.gitignore":      "!allowed.txt\n",
+		"docs/build/index.html":   "",
+		"docs/build/.gitignore":   "",
+		"app.log":                 "",
+		"keep.log":                "",
+		"out/bin":                 "",
+		"src/out/bin":             "",
+		"src/important.log":       "",
+		"src/debug.log":           "",
+		"src/generated/x.go":      "",
+		"src/a.tmp":               "",
+		"src/lib/b.tmp":           "",
+		"secrets/allowed.txt":     "",
+		"node_modules/x/index.js": "",
+		"main.go":                 "",
+	})
+
+	m := New(root, "!build/")
+	tests := map[string]bool{
+		"app.log":                 true,
+		"keep.log":                false, // Negated
+		"out":                     true,  // Anchored
+		"out/bin":                 true,
+		"src/out":                 false, // Anchored to the root only
+		"src/important.log":       false, // Nested negation beats the root
+		"src/debug.log":           true,
+		"src/generated":           true,
+		"src/generated/x.go":      true,
+		"src/a.tmp":               true,
+		"src/lib/b.tmp":           false, // Deepest .gitignore wins
+		"secrets/allowed.txt":     true,  // Can't re-include inside an ignored directory
+		"node_modules/x/index.js": true,  // Defaults
+		"docs/build/index.html":   false, // Defaults re-included by the extra pattern
+		"main.go":                 false,
+		".git":                    true,
+	}
+	for path, want := range tests {
+		info, err := os.Stat(filepath.Join(root, path))
+		isDir := err == nil && info.IsDir()
+		if path == ".git" {
+			isDir = true
+		}
+		if got := m.Match(path, isDir); got != want {
+			t.Errorf("Match(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestMatcherGitExcludes(t *testing.T) {
+	home := isolate(t)
+	root := t.TempDir()
+	cmd := exec.Command("git", "init", "-q", root)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("git init: %v\n%s", err, out)
+	}
+	writeFiles(t, root, map[string]string{
+		".git/info/exclude":   "*.local\n",
+		"sub/.gitignore":      "",
+		"sub/dir/notes.local": "",
+		"sub/dir/x.swp":       "",
+		"sub/dir/x.bak":       "",
+		"sub/dir/main.go":     "",
+	})
+	writeFiles(t, home, map[string]string{".config/git/ignore": "*.swp\n"})
+
+	// Rooted below the top of the working tree, paths are still matched
+	// against patterns anchored at the top
+	m := New(filepath.Join(root, "sub"))
+	for path, want := range map[string]bool{
+		"dir/notes.local": true, // info/exclude
+		"dir/x.swp":       true, // XDG global excludes
+		"dir/x.bak":       false,
+		"dir/main.go":     false,
+	} {
+		if got := m.Match(path, false); got != want {
+			t.Errorf("Match(%s) = %v, want %v", path, got, want)
+		}
+	}
+
+	// core.excludesFile replaces the XDG default
+	writeFiles(t, home, map[string]string{".custom-ignore": "*.bak\n"})
+	if out, err := exec.Command("git", "-C", root, "config", "core.excludesFile", filepath.Join(home, ".custom-ignore")).CombinedOutput(); err != nil {
+		t.Fatalf("git config: %v\n%s", err, out)
+	}
+	m = New(filepath.Join(root, "sub"))
+	if !m.Match("dir/x.bak", false) || m.Match("dir/x.swp", false) {
+		t.Error("core.excludesFile should be used instead of the XDG default")
+	}
+}
+
+func TestWalk(t *testing.T) {
+	isolate(t)
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore":     "tmp/\n*.o\n",
+		"main.c":         "",
+		"main.o":         "",
+		"tmp/scratch.c":  "",
+		"lib/util.c":     "",
+		"lib/util.o":     "",
+		"dist/bundle.js": "",
+		"lib/.gitignore": "!util.o\n",
+	})
+
+	var got []string
+	err := New(root).Walk(func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(root, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	want := ".gitignore lib/.gitignore lib/util.c lib/util.o main.c"
+	if strings.Join(got, " ") != want {
+		t.Errorf("walked %v, want %s", got, want)
+	}
+}
+
+func TestFindGitDirWorktree(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main/.git/worktrees/feature/commondir": "../..\n",
+		"feature/.git":                          "gitdir: ../main/.git/worktrees/feature\n",
+	})
+	want := filepath.Join(dir, "main", ".git")
+	if got := findGitDir(filepath.Join(dir, "feature")); got != want {
+		t.Errorf("findGitDir = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package ignore
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// pattern is one line of a .gitignore, compiled to a regexp over slash
+// separated paths relative to the directory holding the file
+type pattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	base    string // Directory of the .gitignore relative to the root; "" at the root
+}
+
+// matches reports whether rel (relative to the root) matches p
+func (p pattern) matches(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if p.base != "" {
+		if !strings.HasPrefix(rel, p.base+"/") {
+			return false
+		}
+		rel = rel[len(p.base)+1:]
+	}
+	return p.re.MatchString(rel)
+}
+
+// parsePattern compiles one line, returning false for blanks and comments.
+// The rules follow gitignore(5):
+//   - "!" negates, "\!" and "\#" are literal
+//   - a trailing "/" matches directories only
+//   - a "/" at the start or in the middle anchors the pattern to base;
+//     otherwise it matches a name at any depth
+//   - "**/" matches any leading directories, "/**" everything inside,
+//     "/**/" zero or more directories
+func parsePattern(line, base string) (pattern, bool) {
+	line = trimTrailingSpace(strings.TrimSuffix(line, "\r"))
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false
+	}
+
+	p := pattern{base: base}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return pattern{}, false
+	}
+
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+
+	re, err := regexp.Compile("^" + globToRegexp(line) + "$")
+	if err != nil {
+		return pattern{}, false
+	}
+	p.re = re
+	return p, true
+}
+
+// trimTrailingSpace removes trailing spaces unless escaped with a backslash
+func trimTrailingSpace(s string) string {
+	for strings.HasSuffix(s, " ") && !strings.HasSuffix(s, `\ `) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// globToRegexp translates a gitignore glob. "*" and "?" never match "/";
+// "**" only has its special meaning as a whole path segment.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	segments := strings.Split(glob, "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		if seg == "**" {
+			switch {
+			case last && i == 0:
+				b.WriteString(".*")
+			case last:
+				b.WriteString(".*") // Preceded by "/": everything inside
+			default:
+				b.WriteString("(?:.*/)?") // Zero or more directories
+			}
+			continue
+		}
+		b.WriteString(segmentToRegexp(seg))
+		if !last {
+			b.WriteString("/")
+		}
+	}
+	return b.String()
+}
+
+func segmentToRegexp(seg string) string {
+	var b strings.Builder
+	for i := 0; i < len(seg); i++ {
+		c := seg[i]
+		switch c {
+		case '*':
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '\\':
+			if i+1 < len(seg) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(seg[i])))
+			}
+		case '[':
+			end := classEnd(seg, i)
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := seg[i+1 : end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// classEnd returns the index of the "]" closing the class opened at i, or -1
+func classEnd(seg string, i int) int {
+	j := i + 1
+	if j < len(seg) && (seg[j] == '!' || seg[j] == '^') {
+		j++
+	}
+	if j < len(seg) && seg[j] == ']' {
+		j++ // A leading "]" is literal
+	}
+	for ; j < len(seg); j++ {
+		if seg[j] == ']' {
+			return j
+		}
+	}
+	return -1
+}
+
+// readPatterns parses an ignore file; a missing file has no patterns
+func readPatterns(path, base string) []pattern {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+	return parsePatterns(file, base)
+}
+
+func parsePatterns(r io.Reader, base string) []pattern {
+	var patterns []pattern
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if p, ok := parsePattern(scanner.Text(), base); ok {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
@@ -1,11 +1,11 @@
 package lastcommit
 
 import (
-	"bytes"
-	"os/exec"
+	"errors"
 	"strings"
 	"time"
 
+	"github.com/sloanahrens/devbot-go/internal/git"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
@@ -34,7 +34,11 @@ func GetLastCommit(repo workspace.RepoInfo, file string) Result {
 		args = append(args, "--", file)
 	}
 
-	output := gitCommand(repo.Path, args...)
+	output, err := git.Output(repo.Path, args...)
+	if err != nil && !errors.Is(err, git.ErrNoCommits) {
+		result.Error = err
+		return result
+	}
 	if output == "" {
 		if file != "" {
 			result.RelativeAge = "no commits for file"
@@ -88,18 +92,3 @@ func (r *Result) IsStale(days int) bool {
 	d := r.DaysAgo()
 	return d >= 0 && d > days
 }
-
-func gitCommand(dir string, args ...string) string {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = nil
-
-	if err := cmd.Run(); err != nil {
-		return ""
-	}
-
-	return strings.TrimSpace(out.String())
-}
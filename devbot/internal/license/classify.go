@@ -0,0 +1,107 @@
+package license
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// textRules identifies a license from phrases in its text. Order matters:
+// more specific texts come before the ones they contain, e.g. ISC before
+// 0BSD and BSD-3-Clause before BSD-2-Clause. The GNU licenses match on
+// their title lines since their bodies mention each other.
+var textRules = []struct {
+	id      string
+	phrases []string
+}{
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"MPL-2.0", []string{"mozilla public license version 2.0"}},
+	{"AGPL-3.0-only", []string{"gnu affero general public license version 3"}},
+	{"LGPL-3.0-only", []string{"gnu lesser general public license version 3"}},
+	{"LGPL-2.1-only", []string{"gnu lesser general public license version 2.1"}},
+	{"GPL-3.0-only", []string{"gnu general public license version 3"}},
+	{"GPL-2.0-only", []string{"gnu general public license version 2"}},
+	{"BSL-1.0", []string{"boost software license"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+	{"MIT", []string{"permission is hereby granted, free of charge", "the above copyright notice and this permission notice shall be included"}},
+	{"MIT-0", []string{"permission is hereby granted, free of charge"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"distribute this software for any purpose with or without fee is hereby granted", "copyright notice and this permission notice appear in all copies"}},
+	{"0BSD", []string{"distribute this software for any purpose with or without fee is hereby granted"}},
+	{"Zlib", []string{"this software is provided 'as-is'", "altered source versions must be plainly marked"}},
+}
+
+// Classify identifies the license in a license file's text, or returns "".
+// Whitespace and case are ignored.
+func Classify(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	for _, rule := range textRules {
+		matched := true
+		for _, phrase := range rule.phrases {
+			if !strings.Contains(text, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule.id
+		}
+	}
+	return ""
+}
+
+// licenseFileNames are the base names (before any extension or suffix)
+// that hold a package's license text
+var licenseFileNames = []string{"license", "licence", "copying", "unlicense"}
+
+// licenseFiles returns the license files directly in dir, sorted
+func licenseFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		lower := strings.ToLower(e.Name())
+		for _, base := range licenseFileNames {
+			if lower == base || strings.HasPrefix(lower, base+".") || strings.HasPrefix(lower, base+"-") || strings.HasPrefix(lower, base+"_") {
+				out = append(out, filepath.Join(dir, e.Name()))
+				break
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// classifyDir identifies the license of the package in dir from its license
+// files. Several recognized licenses (LICENSE-MIT and LICENSE-APACHE) are
+// the usual way to offer a choice, so they're combined with OR.
+func classifyDir(dir string) string {
+	var ids []string
+	for _, path := range licenseFiles(dir) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if id := Classify(string(data)); id != "" && !contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return strings.Join(ids, " OR ")
+}
+
+func contains(list []string, s string) bool {
+	for _, existing := range list {
+		if existing == s {
+			return true
+		}
+	}
+	return false
+}
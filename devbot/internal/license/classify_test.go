@@ -0,0 +1,79 @@
+package license
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	mitText = `MIT License
+
+Copyright (c) 2024 Example
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction...
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.`
+
+	apacheText = `                                 Apache License
+                           Version 2.0, January 2004
+                        http://www.apache.org/licenses/`
+
+	bsd3Text = `Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+3. Neither the name of the copyright holder nor the names of its contributors`
+
+	iscText = `ISC License
+
+Permission to use, copy, modify, and/or distribute this software for any
+purpose with or without fee is hereby granted, provided that the above
+copyright notice and this permission notice appear in all copies.`
+
+	gpl3Text = `                    GNU GENERAL PUBLIC LICENSE
+                       Version 3, 29 June 2007
+...
+the GNU Lesser General Public License instead of this License.
+...
+version 3 of the GNU Affero General Public License`
+)
+
+func TestClassify(t *testing.T) {
+	tests := map[string]string{
+		"mit":    mitText,
+		"apache": apacheText,
+		"bsd3":   bsd3Text,
+		"isc":    iscText,
+		"gpl3":   gpl3Text,
+		"0bsd":   "Permission to use, copy, modify, and/or distribute this software for any purpose with or without fee is hereby granted.\n\nTHE SOFTWARE IS PROVIDED \"AS IS\"",
+		"lgpl21": "GNU LESSER GENERAL PUBLIC LICENSE\n   Version 2.1, February 1999",
+		"none":   "All rights reserved.",
+	}
+	want := map[string]string{
+		"mit": "MIT", "apache": "Apache-2.0", "bsd3": "BSD-3-Clause", "isc": "ISC",
+		"gpl3": "GPL-3.0-only", "0bsd": "0BSD", "lgpl21": "LGPL-2.1-only", "none": "",
+	}
+	for name, text := range tests {
+		if got := Classify(text); got != want[name] {
+			t.Errorf("Classify(%s) = %q, want %q", name, got, want[name])
+		}
+	}
+}
+
+func TestClassifyDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, text := range map[string]string{
+		"LICENSE-MIT":    mitText,
+		"LICENSE-APACHE": apacheText,
+		"README.md":      gpl3Text,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := classifyDir(dir); got != "Apache-2.0 OR MIT" {
+		t.Errorf("classifyDir = %q, want dual licenses joined with OR", got)
+	}
+}
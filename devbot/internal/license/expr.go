@@ -0,0 +1,136 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expr is a parsed SPDX license expression. Leaves carry an identifier and
+// an optional WITH exception; inner nodes combine their operands with Op.
+type expr struct {
+	Op        string // "", "AND" or "OR"
+	ID        string
+	Exception string
+	Operands  []*expr
+}
+
+// parseExpr parses a normalized expression. AND binds tighter than OR, as
+// in the SPDX specification.
+func parseExpr(s string) (*expr, error) {
+	p := &exprParser{tokens: strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s))}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) or() (*expr, error) {
+	return p.binary("OR", p.and)
+}
+
+func (p *exprParser) and() (*expr, error) {
+	return p.binary("AND", p.term)
+}
+
+func (p *exprParser) binary(op string, operand func() (*expr, error)) (*expr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*expr{first}
+	for p.peek() == op {
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &expr{Op: op, Operands: operands}, nil
+}
+
+func (p *exprParser) term() (*expr, error) {
+	tok := p.peek()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "(":
+		p.pos++
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return e, nil
+	case ")", "AND", "OR", "WITH":
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+	p.pos++
+	e := &expr{ID: tok}
+	if p.peek() == "WITH" {
+		p.pos++
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" {
+			return nil, fmt.Errorf("WITH needs an exception")
+		}
+		p.pos++
+		e.Exception = exception
+	}
+	return e, nil
+}
+
+// eval reports whether the expression is satisfied when ok accepts the
+// leaf identifiers: any operand of an OR, every operand of an AND
+func (e *expr) eval(ok func(id string) bool) bool {
+	switch e.Op {
+	case "OR":
+		for _, o := range e.Operands {
+			if o.eval(ok) {
+				return true
+			}
+		}
+		return false
+	case "AND":
+		for _, o := range e.Operands {
+			if !o.eval(ok) {
+				return false
+			}
+		}
+		return true
+	}
+	return ok(e.ID)
+}
+
+// ids returns every leaf identifier in order of appearance
+func (e *expr) ids() []string {
+	if e.Op == "" {
+		return []string{e.ID}
+	}
+	var out []string
+	for _, o := range e.Operands {
+		out = append(out, o.ids()...)
+	}
+	return out
+}
@@ -0,0 +1,453 @@
+package license
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+)
+
+// Found is the license metadata of one installed package
+type Found struct {
+	Version  string // Installed version, when the metadata records it
+	Declared string // As written in the metadata, or the license file's name
+	License  string // SPDX expression; "" if Declared isn't recognized
+	Source   string // File it was read from
+}
+
+// finder locates installed packages for one repo. Nothing is fetched: a
+// package that isn't installed locally has no license information.
+type finder struct {
+	repoPath   string
+	dirs       []string // Searched in order, relative to the repo
+	goModCache string
+	cargoHome  string
+	sites      map[string][]pyDist // site-packages dir -> distributions
+}
+
+func newFinder(r deps.RepoDeps) *finder {
+	f := &finder{repoPath: r.Repo.Path, dirs: []string{"."}, sites: make(map[string][]pyDist)}
+	for _, lock := range r.Lockfiles {
+		f.dirs = appendUnique(f.dirs, filepath.Dir(lock))
+	}
+	for _, p := range r.Provides {
+		f.dirs = appendUnique(f.dirs, p.Dir)
+	}
+	f.goModCache = goModCache()
+	f.cargoHome = os.Getenv("CARGO_HOME")
+	if f.cargoHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			f.cargoHome = filepath.Join(home, ".cargo")
+		}
+	}
+	return f
+}
+
+// goModCache returns where the go command keeps downloaded modules
+func goModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := filepath.SplitList(os.Getenv("GOPATH")); len(gopath) > 0 && gopath[0] != "" {
+		return filepath.Join(gopath[0], "pkg", "mod")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, "go", "pkg", "mod")
+	}
+	return ""
+}
+
+// find returns the license metadata for d, and false if d isn't installed
+func (f *finder) find(d deps.Dependency) (Found, bool) {
+	switch d.Ecosystem {
+	case deps.EcosystemNPM:
+		return f.npm(d.Name, d.Exact())
+	case deps.EcosystemGo:
+		return f.goModule(d)
+	case deps.EcosystemPyPI:
+		return f.python(d.Name, d.Exact())
+	case deps.EcosystemCrates:
+		return f.crate(d.Name, d.Exact())
+	}
+	return Found{}, false
+}
+
+// npm looks in node_modules next to each manifest, including pnpm's
+// virtual store, preferring a copy of the exact version
+func (f *finder) npm(name, version string) (Found, bool) {
+	var candidates []string
+	for _, dir := range f.dirs {
+		nm := filepath.Join(f.repoPath, dir, "node_modules")
+		candidates = append(candidates, filepath.Join(nm, name))
+		store, _ := filepath.Glob(filepath.Join(nm, ".pnpm", strings.ReplaceAll(name, "/", "+")+"@*", "node_modules", name))
+		candidates = append(candidates, store...)
+	}
+
+	var fallback *npmPackage
+	for _, dir := range candidates {
+		pkg, err := readNPMPackage(dir)
+		if err != nil {
+			continue
+		}
+		if version == "" || pkg.Version == version {
+			return f.npmFound(dir, pkg), true
+		}
+		if fallback == nil {
+			fallback = pkg
+			fallback.dir = dir
+		}
+	}
+	if fallback != nil {
+		return f.npmFound(fallback.dir, fallback), true
+	}
+	return Found{}, false
+}
+
+type npmPackage struct {
+	Version  string          `json:"version"`
+	License  json.RawMessage `json:"license"`
+	Licenses []struct {
+		Type string `json:"type"`
+	} `json:"licenses"`
+	dir string
+}
+
+func readNPMPackage(dir string) (*npmPackage, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pkg npmPackage
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+func (f *finder) npmFound(dir string, pkg *npmPackage) Found {
+	found := f.npmLicense(dir, pkg)
+	found.Version = pkg.Version
+	return found
+}
+
+// npmLicense reads "license" (a string, or {"type": ...} in old packages)
+// or the legacy "licenses" array, falling back to the license file
+func (f *finder) npmLicense(dir string, pkg *npmPackage) Found {
+	source := f.rel(filepath.Join(dir, "package.json"))
+	var declared string
+	if len(pkg.License) > 0 {
+		var s string
+		var obj struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(pkg.License, &s) == nil {
+			declared = s
+		} else if json.Unmarshal(pkg.License, &obj) == nil {
+			declared = obj.Type
+		}
+	}
+	if declared == "" && len(pkg.Licenses) > 0 {
+		var types []string
+		for _, l := range pkg.Licenses {
+			types = append(types, l.Type)
+		}
+		declared = strings.Join(types, " OR ")
+	}
+	// "SEE LICENSE IN <file>" points at the license file
+	if declared != "" && !strings.HasPrefix(strings.ToUpper(declared), "SEE LICENSE") {
+		return Found{Declared: declared, License: Normalize(declared), Source: source}
+	}
+	if found, ok := f.fromFiles(dir); ok {
+		return found
+	}
+	return Found{Declared: declared, Source: source}
+}
+
+// goModule looks in vendor/ next to each go.mod, then the module cache.
+// A replacement is what gets built, so its license is the one that applies.
+func (f *finder) goModule(d deps.Dependency) (Found, bool) {
+	name, version := d.Name, d.Exact()
+	if d.Replace != "" {
+		fields := strings.Fields(d.Replace)
+		if len(fields) == 1 {
+			for _, dir := range f.dirs {
+				if found, ok := f.fromFiles(filepath.Join(f.repoPath, dir, fields[0])); ok {
+					return found, true
+				}
+			}
+			return Found{}, false
+		}
+		name, version = fields[0], fields[1]
+	}
+
+	for _, dir := range f.dirs {
+		if found, ok := f.fromFiles(filepath.Join(f.repoPath, dir, "vendor", filepath.FromSlash(name))); ok {
+			return found, true
+		}
+	}
+	if f.goModCache == "" || version == "" {
+		return Found{}, false
+	}
+	return f.fromFiles(filepath.Join(f.goModCache, filepath.FromSlash(escapeModulePath(name))+"@"+version))
+}
+
+// escapeModulePath applies the module cache's case encoding: each upper
+// case letter becomes "!" and its lower case form
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// pyDist is an installed Python distribution
+type pyDist struct {
+	name     string // Normalized
+	version  string
+	metadata string // Path to METADATA or PKG-INFO
+}
+
+// python looks in the virtualenvs next to each manifest
+func (f *finder) python(name, version string) (Found, bool) {
+	name = deps.NormalizePyName(name)
+	var fallback *pyDist
+	for _, dir := range f.dirs {
+		for _, site := range f.sitePackages(filepath.Join(f.repoPath, dir)) {
+			for _, dist := range f.distributions(site) {
+				if dist.name != name {
+					continue
+				}
+				if version == "" || dist.version == version {
+					return f.pyFound(dist), true
+				}
+				if fallback == nil {
+					d := dist
+					fallback = &d
+				}
+			}
+		}
+	}
+	if fallback != nil {
+		return f.pyFound(*fallback), true
+	}
+	return Found{}, false
+}
+
+// sitePackages returns the site-packages dirs of virtualenvs in dir
+func (f *finder) sitePackages(dir string) []string {
+	var out []string
+	for _, venv := range []string{".venv", "venv", "env"} {
+		unix, _ := filepath.Glob(filepath.Join(dir, venv, "lib", "python*", "site-packages"))
+		out = append(out, unix...)
+		if info, err := os.Stat(filepath.Join(dir, venv, "Lib", "site-packages")); err == nil && info.IsDir() {
+			out = append(out, filepath.Join(dir, venv, "Lib", "site-packages"))
+		}
+	}
+	return out
+}
+
+// distributions lists the .dist-info and .egg-info entries of site,
+// reading each one's name and version from its metadata
+func (f *finder) distributions(site string) []pyDist {
+	if dists, ok := f.sites[site]; ok {
+		return dists
+	}
+	var dists []pyDist
+	entries, _ := os.ReadDir(site)
+	for _, e := range entries {
+		var metadata string
+		switch {
+		case strings.HasSuffix(e.Name(), ".dist-info"):
+			metadata = filepath.Join(site, e.Name(), "METADATA")
+		case strings.HasSuffix(e.Name(), ".egg-info"):
+			metadata = filepath.Join(site, e.Name(), "PKG-INFO")
+		default:
+			continue
+		}
+		headers := readPyMetadata(metadata)
+		if len(headers["name"]) == 0 {
+			continue
+		}
+		dist := pyDist{name: deps.NormalizePyName(headers["name"][0]), metadata: metadata}
+		if v := headers["version"]; len(v) > 0 {
+			dist.version = v[0]
+		}
+		dists = append(dists, dist)
+	}
+	f.sites[site] = dists
+	return dists
+}
+
+// readPyMetadata reads the email-style headers of a METADATA or PKG-INFO
+// file, keyed by lower case name. Continuation lines are joined.
+func readPyMetadata(path string) map[string][]string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	headers := make(map[string][]string)
+	var last string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break // The body (long description) follows
+		}
+		if (line[0] == ' ' || line[0] == '\t') && last != "" {
+			values := headers[last]
+			values[len(values)-1] += "\n" + strings.TrimSpace(line)
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		last = strings.ToLower(strings.TrimSpace(key))
+		headers[last] = append(headers[last], strings.TrimSpace(value))
+	}
+	return headers
+}
+
+func (f *finder) pyFound(dist pyDist) Found {
+	found := f.pyLicense(dist)
+	found.Version = dist.version
+	return found
+}
+
+// pyLicense prefers License-Expression (PEP 639), then "License ::"
+// classifiers, then a short License field, then the bundled license files
+func (f *finder) pyLicense(dist pyDist) Found {
+	headers := readPyMetadata(dist.metadata)
+	source := f.rel(dist.metadata)
+
+	if expr := headers["license-expression"]; len(expr) > 0 && expr[0] != "" {
+		return Found{Declared: expr[0], License: Normalize(expr[0]), Source: source}
+	}
+
+	var names, ids []string
+	for _, c := range headers["classifier"] {
+		parts := strings.Split(c, "::")
+		if len(parts) < 2 || strings.TrimSpace(parts[0]) != "License" {
+			continue
+		}
+		name := strings.TrimSpace(parts[len(parts)-1])
+		names = append(names, name)
+		if id := Normalize(name); id != "" && !contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 && len(ids) == len(names) {
+		return Found{Declared: strings.Join(names, ", "), License: strings.Join(ids, " OR "), Source: source}
+	}
+
+	// The License field sometimes holds the full text
+	var declared string
+	if field := headers["license"]; len(field) > 0 && field[0] != "" && !strings.EqualFold(field[0], "UNKNOWN") {
+		declared = field[0]
+		if id := Normalize(declared); id != "" {
+			return Found{Declared: declared, License: id, Source: source}
+		}
+		if id := Classify(declared); id != "" {
+			return Found{Declared: "License text", License: id, Source: source}
+		}
+	}
+
+	distInfo := filepath.Dir(dist.metadata)
+	for _, dir := range []string{filepath.Join(distInfo, "licenses"), distInfo} {
+		if found, ok := f.fromFiles(dir); ok {
+			return found
+		}
+	}
+	if declared == "" && len(names) > 0 {
+		declared = strings.Join(names, ", ")
+	}
+	if len(declared) > 80 {
+		declared = declared[:77] + "..."
+	}
+	return Found{Declared: declared, Source: source}
+}
+
+// crate reads "license" from the package's Cargo.toml in the registry
+// source cache that cargo unpacks downloaded crates into
+func (f *finder) crate(name, version string) (Found, bool) {
+	if f.cargoHome == "" || version == "" {
+		return Found{}, false
+	}
+	dirs, _ := filepath.Glob(filepath.Join(f.cargoHome, "registry", "src", "*", name+"-"+version))
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		manifest := filepath.Join(dir, "Cargo.toml")
+		if declared := cargoLicense(manifest); declared != "" {
+			return Found{Declared: declared, License: Normalize(declared), Source: f.rel(manifest)}, true
+		}
+		if found, ok := f.fromFiles(dir); ok {
+			return found, true
+		}
+		return Found{Source: f.rel(manifest)}, true
+	}
+	return Found{}, false
+}
+
+// cargoLicense returns the license key of a Cargo.toml's [package] table
+func cargoLicense(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	inPackage := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inPackage = line == "[package]"
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if inPackage && ok && strings.TrimSpace(key) == "license" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// fromFiles classifies the license files in dir
+func (f *finder) fromFiles(dir string) (Found, bool) {
+	files := licenseFiles(dir)
+	if len(files) == 0 {
+		return Found{}, false
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+	return Found{Declared: strings.Join(names, ", "), License: classifyDir(dir), Source: f.rel(files[0])}, true
+}
+
+// rel shows paths inside the repo relative to it, others (module and crate
+// caches) as they are
+func (f *finder) rel(path string) string {
+	if rel, err := filepath.Rel(f.repoPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
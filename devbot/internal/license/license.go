@@ -0,0 +1,166 @@
+// Package license reads the licenses of each repo's installed dependencies
+// and checks them against the workspace's allow and deny lists.
+package license
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+)
+
+// Package is one dependency of a repo and its license
+type Package struct {
+	Repo      string
+	Name      string
+	Version   string
+	Ecosystem string
+	Dev       bool
+	Declared  string // As found in the package metadata
+	License   string // SPDX expression; "" if unknown
+	Source    string // Where it was read from, or "config" for an override
+	Status    Status
+	Reason    string // Why it isn't allowed or known
+}
+
+// Report is the license inventory of a set of repos
+type Report struct {
+	Packages      []Package // Sorted by repo, ecosystem, name and version
+	FailOnUnknown bool
+}
+
+// Violation reports whether p fails the policy that produced the report
+func (r *Report) Violation(p Package) bool {
+	switch p.Status {
+	case StatusDenied, StatusNotAllowed:
+		return true
+	case StatusUnknown:
+		return r.FailOnUnknown
+	}
+	return false
+}
+
+// Violations returns the packages that fail the policy
+func (r *Report) Violations() []Package {
+	var out []Package
+	for _, p := range r.Packages {
+		if r.Violation(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Inventory finds the license of every resolved dependency, direct and
+// transitive, and checks it against policy. Dev dependencies don't ship
+// with the code, so they're only included when includeDev is set. Repos
+// that failed analysis are skipped, as are packages the workspace's own
+// repos and members provide.
+func Inventory(results []deps.RepoDeps, policy *Policy, includeDev bool) *Report {
+	report := &Report{FailOnUnknown: policy.FailOnUnknown}
+
+	// Packages declared by any analyzed repo or workspace member aren't
+	// third-party code, whichever repo depends on them
+	own := make(map[string]bool)
+	for _, r := range results {
+		for _, p := range r.Provides {
+			own[p.Ecosystem+" "+p.Name] = true
+		}
+	}
+
+	for _, r := range results {
+		if r.Error != nil {
+			continue
+		}
+		f := newFinder(r)
+
+		// A package is dev only if every occurrence of it is
+		byKey := make(map[string]deps.Dependency)
+		var keys []string
+		for _, d := range r.Dependencies {
+			if own[d.Ecosystem+" "+d.Name] || isLocal(d) {
+				continue
+			}
+			key := d.Ecosystem + " " + d.Name + "@" + d.Exact()
+			if existing, ok := byKey[key]; ok {
+				existing.Dev = existing.Dev && d.Dev
+				byKey[key] = existing
+				continue
+			}
+			byKey[key] = d
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			d := byKey[key]
+			if d.Dev && !includeDev {
+				continue
+			}
+			report.Packages = append(report.Packages, check(r.Repo.Name, d, f, policy))
+		}
+	}
+
+	sort.Slice(report.Packages, func(i, j int) bool {
+		a, b := report.Packages[i], report.Packages[j]
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return report
+}
+
+// isLocal reports whether d comes from a directory or workspace rather than
+// a registry: a Go replace without a version, or an npm file:, link:,
+// portal: or workspace: specifier
+func isLocal(d deps.Dependency) bool {
+	if d.Replace != "" && len(strings.Fields(d.Replace)) == 1 {
+		return true
+	}
+	for _, prefix := range []string{"file:", "link:", "portal:", "workspace:"} {
+		if strings.HasPrefix(d.Version, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// check finds and evaluates the license of one dependency
+func check(repo string, d deps.Dependency, f *finder, policy *Policy) Package {
+	p := Package{Repo: repo, Name: d.Name, Version: d.Exact(), Ecosystem: d.Ecosystem, Dev: d.Dev}
+	if p.Version == "" {
+		p.Version = d.Version
+	}
+	if policy.Ignored(d.Name) {
+		p.Status = StatusIgnored
+		return p
+	}
+
+	if license, ok := policy.Override(d.Name); ok {
+		p.Declared, p.License, p.Source = license, license, "config"
+	} else if found, ok := f.find(d); ok {
+		p.Declared, p.License, p.Source = found.Declared, found.License, found.Source
+		if d.Exact() == "" && found.Version != "" {
+			p.Version = found.Version
+		}
+	} else {
+		p.Status, p.Reason = StatusUnknown, "not installed"
+		return p
+	}
+
+	if p.License == "" {
+		p.Status, p.Reason = StatusUnknown, "no license found"
+		if p.Declared != "" {
+			p.Reason = "unrecognized license"
+		}
+		return p
+	}
+	p.Status, p.Reason = policy.Check(p.License)
+	return p
+}
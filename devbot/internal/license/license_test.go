@@ -0,0 +1,217 @@
+package license
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// fixture lays out installed packages for every ecosystem, with the Go
+// module and crate caches outside the repo
+func fixture(t *testing.T) deps.RepoDeps {
+	repoPath, cache := t.TempDir(), t.TempDir()
+	t.Setenv("GOMODCACHE", filepath.Join(cache, "mod"))
+	t.Setenv("CARGO_HOME", filepath.Join(cache, "cargo"))
+
+	writeFiles(t, repoPath, map[string]string{
+		"node_modules/react/package.json":                                              `{"name": "react", "version": "18.2.0", "license": "MIT"}`,
+		"node_modules/old/package.json":                                                `{"name": "old", "version": "1.0.0", "licenses": [{"type": "MIT"}, {"type": "Apache-2.0"}]}`,
+		"node_modules/typed/package.json":                                              `{"name": "typed", "version": "2.0.0", "license": {"type": "BSD-3-Clause"}}`,
+		"node_modules/see/package.json":                                                `{"name": "see", "version": "1.0.0", "license": "SEE LICENSE IN LICENSE.md"}`,
+		"node_modules/see/LICENSE.md":                                                  iscText,
+		"node_modules/closed/package.json":                                             `{"name": "closed", "version": "1.0.0", "license": "UNLICENSED"}`,
+		"node_modules/gpl/package.json":                                                `{"name": "gpl", "version": "1.0.0", "license": "GPL-3.0"}`,
+		"node_modules/jest/package.json":                                               `{"name": "jest", "version": "29.7.0", "license": "MIT"}`,
+		"web/node_modules/.pnpm/@scope+pkg@1.2.0/node_modules/@scope/pkg/package.json": `{"version": "1.2.0", "license": "ISC"}`,
+		"web/node_modules/.pnpm/@scope+pkg@1.1.0/node_modules/@scope/pkg/package.json": `{"version": "1.1.0", "license": "MIT"}`,
+
+		"api/vendor/github.com/vendored/lib/LICENSE": apacheText,
+
+		".venv/lib/python3.12/site-packages/requests-2.31.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: requests\nVersion: 2.31.0\nLicense: Apache 2.0\n\nLong description\n",
+		".venv/lib/python3.12/site-packages/Typing_Extensions-4.12.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: typing_extensions\nVersion: 4.12.0\n" +
+			"Classifier: Development Status :: 5 - Production/Stable\nClassifier: License :: OSI Approved :: Python Software Foundation License\n\n",
+		".venv/lib/python3.12/site-packages/modern-1.0.dist-info/METADATA":            "Metadata-Version: 2.4\nName: modern\nVersion: 1.0\nLicense-Expression: MIT OR Apache-2.0\n\n",
+		".venv/lib/python3.12/site-packages/texty-0.1.dist-info/METADATA":             "Metadata-Version: 2.1\nName: texty\nVersion: 0.1\nLicense: UNKNOWN\n\n",
+		".venv/lib/python3.12/site-packages/texty-0.1.dist-info/licenses/LICENSE.txt": bsd3Text,
+		".venv/lib/python3.12/site-packages/legacy.egg-info/PKG-INFO":                 "Metadata-Version: 1.0\nName: legacy\nVersion: 0.9\nLicense: BSD\n\n",
+	})
+	writeFiles(t, cache, map[string]string{
+		"mod/github.com/!burnt!sushi/toml@v1.3.2/COPYING":                              mitText,
+		"mod/golang.org/x/text@v0.14.0/LICENSE":                                        bsd3Text,
+		"cargo/registry/src/index.crates.io-6f17d22bba15001f/serde-1.0.200/Cargo.toml": "[package]\nname = \"serde\"\nversion = \"1.0.200\"\nlicense = \"MIT OR Apache-2.0\"\n\n[dependencies]\nlicense = \"nope\"\n",
+	})
+
+	npm := func(name, version string) deps.Dependency {
+		return deps.Dependency{Name: name, Resolved: version, Ecosystem: deps.EcosystemNPM}
+	}
+	py := func(name, version string) deps.Dependency {
+		return deps.Dependency{Name: name, Version: "==" + version, Ecosystem: deps.EcosystemPyPI}
+	}
+	jest := npm("jest", "29.7.0")
+	jest.Dev = true
+	return deps.RepoDeps{
+		Repo:      workspace.RepoInfo{Name: "app", Path: repoPath},
+		Lockfiles: []string{"package-lock.json", "web/pnpm-lock.yaml", "api/go.sum"},
+		Provides:  []deps.Provided{{Name: "@org/web", Ecosystem: deps.EcosystemNPM, Dir: "web"}},
+		Dependencies: []deps.Dependency{
+			npm("react", "18.2.0"), npm("old", "1.0.0"), npm("typed", "2.0.0"), npm("see", "1.0.0"),
+			npm("closed", "1.0.0"), npm("gpl", "1.0.0"), npm("@scope/pkg", "1.2.0"), npm("missing", "1.0.0"),
+			jest, npm("@org/web", "0.1.0"),
+			{Name: "github.com/vendored/lib", Version: "v1.0.0", Ecosystem: deps.EcosystemGo},
+			{Name: "github.com/BurntSushi/toml", Version: "v1.3.2", Ecosystem: deps.EcosystemGo},
+			{Name: "golang.org/x/text", Version: "v0.14.0", Indirect: true, Ecosystem: deps.EcosystemGo},
+			py("requests", "2.31.0"), py("typing-extensions", "4.12.0"), py("modern", "1.0"), py("Texty", "0.1"), py("legacy", "0.9"),
+			{Name: "serde", Resolved: "1.0.200", Ecosystem: deps.EcosystemCrates},
+		},
+	}
+}
+
+func describe(r *Report) []string {
+	var out []string
+	for _, p := range r.Packages {
+		line := p.Ecosystem + " " + p.Name + "@" + p.Version + ": " + string(p.Status)
+		if p.License != "" {
+			line += " " + p.License
+		}
+		if p.Reason != "" {
+			line += " (" + p.Reason + ")"
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+func TestInventory(t *testing.T) {
+	repo := fixture(t)
+	policy, err := NewPolicy(workspace.LicensesConfig{
+		Allow:    []string{"MIT", "Apache-2.0", "BSD-*", "ISC", "PSF-2.0"},
+		Deny:     []string{"GPL-*"},
+		Packages: map[string]string{"closed": "MIT"},
+		Ignore:   []string{"legacy"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := Inventory([]deps.RepoDeps{repo}, policy, false)
+	want := []string{
+		"crates serde@1.0.200: allowed MIT OR Apache-2.0",
+		"go github.com/BurntSushi/toml@v1.3.2: allowed MIT",
+		"go github.com/vendored/lib@v1.0.0: allowed Apache-2.0",
+		"go golang.org/x/text@v0.14.0: allowed BSD-3-Clause",
+		"npm @scope/pkg@1.2.0: allowed ISC",
+		"npm closed@1.0.0: allowed MIT",
+		"npm gpl@1.0.0: denied GPL-3.0-only (GPL-3.0-only denied)",
+		"npm missing@1.0.0: unknown (not installed)",
+		"npm old@1.0.0: allowed MIT OR Apache-2.0",
+		"npm react@18.2.0: allowed MIT",
+		"npm see@1.0.0: allowed ISC",
+		"npm typed@2.0.0: allowed BSD-3-Clause",
+		"pypi Texty@0.1: allowed BSD-3-Clause",
+		"pypi legacy@0.9: ignored",
+		"pypi modern@1.0: allowed MIT OR Apache-2.0",
+		"pypi requests@2.31.0: allowed Apache-2.0",
+		"pypi typing-extensions@4.12.0: allowed PSF-2.0",
+	}
+	if got := describe(report); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("inventory:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	violations := report.Violations()
+	if len(violations) != 1 || violations[0].Name != "gpl" {
+		t.Errorf("violations = %v, want only gpl", describe(&Report{Packages: violations}))
+	}
+	for _, p := range report.Packages {
+		if p.Name == "react" && p.Source != filepath.Join("node_modules", "react", "package.json") {
+			t.Errorf("react source = %s, want its package.json relative to the repo", p.Source)
+		}
+		if p.Name == "closed" && p.Source != "config" {
+			t.Errorf("closed source = %s, want config", p.Source)
+		}
+	}
+
+	policy.FailOnUnknown = true
+	report = Inventory([]deps.RepoDeps{repo}, policy, true)
+	var names []string
+	for _, p := range report.Violations() {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, " ") != "gpl missing" {
+		t.Errorf("violations with fail_on_unknown = %v, want gpl and missing", names)
+	}
+	found := false
+	for _, p := range report.Packages {
+		found = found || p.Name == "jest"
+	}
+	if !found {
+		t.Error("dev dependencies should be included when asked for")
+	}
+}
+
+func TestInventorySkipsWorkspacePackages(t *testing.T) {
+	policy, err := NewPolicy(workspace.LicensesConfig{Allow: []string{"MIT"}, FailOnUnknown: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []deps.RepoDeps{
+		{
+			Repo: workspace.RepoInfo{Name: "web", Path: t.TempDir()},
+			Dependencies: []deps.Dependency{
+				{Name: "@org/ui", Version: "^1.0.0", Ecosystem: deps.EcosystemNPM},             // Provided by the ui repo
+				{Name: "@org/tokens", Version: "file:../tokens", Ecosystem: deps.EcosystemNPM}, // Not analyzed, but local
+				{Name: "@org/icons", Version: "workspace:*", Ecosystem: deps.EcosystemNPM},
+				{Name: "example.com/shared", Version: "v0.1.0", Replace: "../shared", Ecosystem: deps.EcosystemGo},
+				{Name: "left-pad", Resolved: "1.3.0", Ecosystem: deps.EcosystemNPM},
+			},
+		},
+		{
+			Repo:     workspace.RepoInfo{Name: "ui", Path: t.TempDir()},
+			Provides: []deps.Provided{{Name: "@org/ui", Ecosystem: deps.EcosystemNPM, Dir: "."}},
+		},
+	}
+
+	report := Inventory(results, policy, false)
+	if got := describe(report); strings.Join(got, "\n") != "npm left-pad@1.3.0: unknown (not installed)" {
+		t.Errorf("inventory = %v, want only the registry package", got)
+	}
+}
+
+func TestFinderUnknown(t *testing.T) {
+	repo := fixture(t)
+	f := newFinder(repo)
+
+	tests := map[string]deps.Dependency{
+		"UNLICENSED": {Name: "closed", Resolved: "1.0.0", Ecosystem: deps.EcosystemNPM},
+		"BSD":        {Name: "legacy", Version: "==0.9", Ecosystem: deps.EcosystemPyPI},
+	}
+	for declared, d := range tests {
+		found, ok := f.find(d)
+		if !ok || found.Declared != declared || found.License != "" {
+			t.Errorf("find(%s) = %+v %v, want declared %q and no license", d.Name, found, ok, declared)
+		}
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	if got := escapeModulePath("github.com/BurntSushi/toml"); got != "github.com/!burnt!sushi/toml" {
+		t.Errorf("escapeModulePath = %s", got)
+	}
+}
@@ -0,0 +1,146 @@
+package license
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// Status is the outcome of checking one package against the policy
+type Status string
+
+const (
+	StatusAllowed    Status = "allowed"
+	StatusDenied     Status = "denied"      // Only denied licenses are on offer
+	StatusNotAllowed Status = "not-allowed" // None of its licenses are in the allow list
+	StatusUnknown    Status = "unknown"     // Not installed, or the license isn't recognized
+	StatusIgnored    Status = "ignored"
+)
+
+// Policy decides which licenses are acceptable. With no allow list every
+// license that isn't denied is allowed.
+type Policy struct {
+	Allow         []string // SPDX identifiers or globs
+	Deny          []string
+	Packages      map[string]string // Package name -> SPDX expression to use instead
+	Ignore        []string          // Package name globs
+	FailOnUnknown bool
+}
+
+// NewPolicy builds a policy from config.yaml's licenses section. Entries
+// are normalized, so "Apache 2.0" in the config matches Apache-2.0.
+func NewPolicy(cfg workspace.LicensesConfig) (*Policy, error) {
+	p := &Policy{Ignore: cfg.Ignore, FailOnUnknown: cfg.FailOnUnknown}
+
+	var err error
+	if p.Allow, err = policyList(cfg.Allow); err != nil {
+		return nil, fmt.Errorf("licenses.allow: %w", err)
+	}
+	if p.Deny, err = policyList(cfg.Deny); err != nil {
+		return nil, fmt.Errorf("licenses.deny: %w", err)
+	}
+	for _, pattern := range cfg.Ignore {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("licenses.ignore: %q: %w", pattern, err)
+		}
+	}
+
+	for name, declared := range cfg.Packages {
+		license := Normalize(declared)
+		if license == "" {
+			return nil, fmt.Errorf("licenses.packages.%s: unrecognized license %q", name, declared)
+		}
+		if p.Packages == nil {
+			p.Packages = make(map[string]string)
+		}
+		p.Packages[name] = license
+	}
+	return p, nil
+}
+
+// policyList normalizes identifiers and validates globs
+func policyList(entries []string) ([]string, error) {
+	var out []string
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.ContainsAny(entry, "*?[") {
+			if _, err := path.Match(entry, ""); err != nil {
+				return nil, fmt.Errorf("%q: %w", entry, err)
+			}
+			out = append(out, entry)
+			continue
+		}
+		id := lookup(entry)
+		if id == "" {
+			return nil, fmt.Errorf("unrecognized license %q", entry)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// Ignored reports whether name matches an ignore glob
+func (p *Policy) Ignored(name string) bool {
+	for _, pattern := range p.Ignore {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Override returns the configured license for name, if there is one
+func (p *Policy) Override(name string) (string, bool) {
+	license, ok := p.Packages[name]
+	return license, ok
+}
+
+// Check evaluates a normalized license expression. An OR is acceptable if
+// any choice is; an AND needs every part. WITH exceptions are judged by
+// the license they modify. The reason explains anything but StatusAllowed.
+func (p *Policy) Check(license string) (Status, string) {
+	e, err := parseExpr(license)
+	if license == "" || err != nil {
+		return StatusUnknown, "unrecognized license"
+	}
+	if e.eval(p.allowed) {
+		return StatusAllowed, ""
+	}
+
+	var denied, notAllowed []string
+	for _, id := range e.ids() {
+		if matchAny(p.Deny, id) {
+			denied = appendUnique(denied, id)
+		} else if !p.allowed(id) {
+			notAllowed = appendUnique(notAllowed, id)
+		}
+	}
+	sort.Strings(denied)
+	sort.Strings(notAllowed)
+	if len(denied) > 0 {
+		return StatusDenied, strings.Join(denied, ", ") + " denied"
+	}
+	return StatusNotAllowed, strings.Join(notAllowed, ", ") + " not in the allow list"
+}
+
+// allowed reports whether a single identifier is acceptable
+func (p *Policy) allowed(id string) bool {
+	if matchAny(p.Deny, id) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchAny(p.Allow, id)
+}
+
+// matchAny matches id against identifiers and globs, ignoring case
+func matchAny(patterns []string, id string) bool {
+	id = strings.ToLower(id)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), id); ok {
+			return true
+		}
+	}
+	return false
+}
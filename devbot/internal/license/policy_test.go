@@ -0,0 +1,83 @@
+package license
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	policy, err := NewPolicy(workspace.LicensesConfig{
+		Allow: []string{"MIT", "Apache 2.0", "BSD-*", "ISC"},
+		Deny:  []string{"GPL-*", "AGPL-*", "BSD-4-Clause"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		license string
+		status  Status
+		reason  string
+	}{
+		{"MIT", StatusAllowed, ""},
+		{"Apache-2.0", StatusAllowed, ""},
+		{"BSD-3-Clause", StatusAllowed, ""},
+		{"BSD-4-Clause", StatusDenied, "BSD-4-Clause denied"},
+		{"GPL-3.0-only", StatusDenied, "GPL-3.0-only denied"},
+		{"MPL-2.0", StatusNotAllowed, "MPL-2.0 not in the allow list"},
+		{"MIT OR GPL-3.0-only", StatusAllowed, ""},
+		{"MIT AND GPL-2.0-or-later", StatusDenied, "GPL-2.0-or-later denied"},
+		{"MPL-2.0 OR GPL-2.0-only", StatusDenied, "GPL-2.0-only denied"},
+		{"Apache-2.0 WITH LLVM-exception", StatusAllowed, ""},
+		{"", StatusUnknown, "unrecognized license"},
+	}
+	for _, tt := range tests {
+		status, reason := policy.Check(tt.license)
+		if status != tt.status || reason != tt.reason {
+			t.Errorf("Check(%q) = %s %q, want %s %q", tt.license, status, reason, tt.status, tt.reason)
+		}
+	}
+}
+
+func TestPolicyDenyOnly(t *testing.T) {
+	policy, err := NewPolicy(workspace.LicensesConfig{Deny: []string{"AGPL-3.0-only"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := policy.Check("MPL-2.0"); status != StatusAllowed {
+		t.Errorf("without an allow list MPL-2.0 = %s, want allowed", status)
+	}
+	if status, _ := policy.Check("AGPL-3.0-only"); status != StatusDenied {
+		t.Errorf("AGPL-3.0-only = %s, want denied", status)
+	}
+}
+
+func TestNewPolicyErrors(t *testing.T) {
+	tests := map[string]workspace.LicensesConfig{
+		"licenses.allow":              {Allow: []string{"Not A License"}},
+		"licenses.deny":               {Deny: []string{"GPL-["}},
+		"licenses.ignore":             {Ignore: []string{"[x"}},
+		"licenses.packages.left-pad:": {Packages: map[string]string{"left-pad": "Proprietary"}},
+	}
+	for want, cfg := range tests {
+		if _, err := NewPolicy(cfg); err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("NewPolicy error = %v, want prefix %q", err, want)
+		}
+	}
+
+	policy, err := NewPolicy(workspace.LicensesConfig{
+		Packages: map[string]string{"left-pad": "WTFPL"},
+		Ignore:   []string{"@internal/*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if license, ok := policy.Override("left-pad"); !ok || license != "WTFPL" {
+		t.Errorf("Override = %q %v, want WTFPL", license, ok)
+	}
+	if !policy.Ignored("@internal/ui") || policy.Ignored("react") {
+		t.Error("Ignored should match package name globs")
+	}
+}
@@ -0,0 +1,220 @@
+package license
+
+import (
+	"strings"
+)
+
+// spdxIDs lists the SPDX license identifiers devbot recognizes, covering
+// nearly everything published to npm, the Go proxy, PyPI and crates.io
+var spdxIDs = []string{
+	"0BSD", "AFL-2.1", "AFL-3.0", "AGPL-1.0-only", "AGPL-3.0-only", "AGPL-3.0-or-later",
+	"Apache-1.1", "Apache-2.0", "Artistic-1.0", "Artistic-2.0", "BlueOak-1.0.0",
+	"BSD-1-Clause", "BSD-2-Clause", "BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause",
+	"BSL-1.0", "CC-BY-3.0", "CC-BY-4.0", "CC-BY-SA-3.0", "CC-BY-SA-4.0", "CC0-1.0",
+	"CDDL-1.0", "CDDL-1.1", "ECL-2.0", "EPL-1.0", "EPL-2.0", "EUPL-1.2",
+	"GPL-1.0-or-later", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only", "GPL-3.0-or-later",
+	"HPND", "ISC", "LGPL-2.0-only", "LGPL-2.0-or-later", "LGPL-2.1-only", "LGPL-2.1-or-later",
+	"LGPL-3.0-only", "LGPL-3.0-or-later", "MIT", "MIT-0", "MPL-1.1", "MPL-2.0",
+	"MPL-2.0-no-copyleft-exception", "MS-PL", "NCSA", "ODC-By-1.0", "ODbL-1.0", "OFL-1.1",
+	"OpenSSL", "OSL-3.0", "PostgreSQL", "PSF-2.0", "Python-2.0", "Ruby", "SSPL-1.0",
+	"Unicode-3.0", "Unicode-DFS-2016", "Unlicense", "UPL-1.0", "W3C", "WTFPL", "X11",
+	"Zlib", "ZPL-2.1",
+}
+
+// spdxExceptions lists recognized identifiers for WITH clauses
+var spdxExceptions = []string{
+	"Classpath-exception-2.0", "GCC-exception-3.1", "LLVM-exception",
+}
+
+// aliases maps common non-SPDX spellings (keyed by aliasKey) to identifiers:
+// deprecated SPDX forms, PyPI classifiers and names found in the wild
+var aliases = map[string]string{
+	// Deprecated SPDX identifiers
+	"gpl2.0": "GPL-2.0-only", "gpl2.0+": "GPL-2.0-or-later",
+	"gpl3.0": "GPL-3.0-only", "gpl3.0+": "GPL-3.0-or-later",
+	"lgpl2.0": "LGPL-2.0-only", "lgpl2.0+": "LGPL-2.0-or-later",
+	"lgpl2.1": "LGPL-2.1-only", "lgpl2.1+": "LGPL-2.1-or-later",
+	"lgpl3.0": "LGPL-3.0-only", "lgpl3.0+": "LGPL-3.0-or-later",
+	"agpl3.0": "AGPL-3.0-only", "agpl3.0+": "AGPL-3.0-or-later",
+
+	// Names
+	"mitlicense": "MIT", "themitlicense": "MIT", "expat": "MIT", "mit/x11": "MIT",
+	"apache2": "Apache-2.0", "apache": "Apache-2.0", "apachelicense2.0": "Apache-2.0",
+	"apachelicenseversion2.0": "Apache-2.0", "apachesoftwarelicense": "Apache-2.0",
+	"apachesoftwarelicense2.0": "Apache-2.0", "asl2.0": "Apache-2.0", "apachev2": "Apache-2.0",
+	"apachelicensev2.0": "Apache-2.0", "apache2.0license": "Apache-2.0",
+	"isclicense": "ISC", "isclicense(iscl)": "ISC",
+	"bsd2clause": "BSD-2-Clause", "simplifiedbsd": "BSD-2-Clause", "freebsd": "BSD-2-Clause",
+	"bsd3clause": "BSD-3-Clause", "newbsd": "BSD-3-Clause", "modifiedbsd": "BSD-3-Clause",
+	"revisedbsd": "BSD-3-Clause", "bsd3": "BSD-3-Clause",
+	"mozillapubliclicense2.0": "MPL-2.0", "mozillapubliclicense2.0(mpl2.0)": "MPL-2.0", "mpl2": "MPL-2.0",
+	"gplv2": "GPL-2.0-only", "gpl2": "GPL-2.0-only", "gplv2+": "GPL-2.0-or-later",
+	"gplv3": "GPL-3.0-only", "gpl3": "GPL-3.0-only", "gplv3+": "GPL-3.0-or-later",
+	"lgplv2": "LGPL-2.0-only", "lgplv2+": "LGPL-2.0-or-later",
+	"lgplv3": "LGPL-3.0-only", "lgplv3+": "LGPL-3.0-or-later",
+	"agplv3": "AGPL-3.0-only", "agplv3+": "AGPL-3.0-or-later",
+	"gnugeneralpubliclicensev2(gplv2)":                           "GPL-2.0-only",
+	"gnugeneralpubliclicensev2orlater(gplv2+)":                   "GPL-2.0-or-later",
+	"gnugeneralpubliclicensev3(gplv3)":                           "GPL-3.0-only",
+	"gnugeneralpubliclicensev3orlater(gplv3+)":                   "GPL-3.0-or-later",
+	"gnulessergeneralpubliclicensev2(lgplv2)":                    "LGPL-2.0-only",
+	"gnulessergeneralpubliclicensev2orlater(lgplv2+)":            "LGPL-2.0-or-later",
+	"gnulessergeneralpubliclicensev3(lgplv3)":                    "LGPL-3.0-only",
+	"gnulessergeneralpubliclicensev3orlater(lgplv3+)":            "LGPL-3.0-or-later",
+	"gnuafferogeneralpubliclicensev3":                            "AGPL-3.0-only",
+	"gnuafferogeneralpubliclicensev3orlater(agplv3+)":            "AGPL-3.0-or-later",
+	"theunlicense(unlicense)":                                    "Unlicense",
+	"theunlicense":                                               "Unlicense",
+	"pythonsoftwarefoundationlicense":                            "PSF-2.0",
+	"psf":                                                        "PSF-2.0",
+	"zlib/libpng":                                                "Zlib",
+	"zliblicense":                                                "Zlib",
+	"boostsoftwarelicense1.0(bsl1.0)":                            "BSL-1.0",
+	"eclipsepubliclicense2.0(epl2.0)":                            "EPL-2.0",
+	"cc0":                                                        "CC0-1.0",
+	"cc01.0universal(cc01.0)publicdomaindedication":              "CC0-1.0",
+	"universalpermissivelicense(upl)":                            "UPL-1.0",
+	"historicalpermissionnoticeanddisclaimer(hpnd)":              "HPND",
+	"mitnoattributionlicense(mit0)":                              "MIT-0",
+	"mitlicense(mit)":                                            "MIT",
+	"apachesoftwarelicense(apache2.0)":                           "Apache-2.0",
+	"bsdlicense(bsd3clause)":                                     "BSD-3-Clause",
+	"opensoftwarelicense3.0(osl3.0)":                             "OSL-3.0",
+	"commondevelopmentanddistributionlicense1.0(cddl1.0)":        "CDDL-1.0",
+	"europeanunionpubliclicence1.2(eupl1.2)":                     "EUPL-1.2",
+	"unicodelicenseagreementfordatafilesandsoftware(unicodedfs)": "Unicode-DFS-2016",
+}
+
+// canonical maps aliasKey(id) to the identifier for every known id
+var canonical = func() map[string]string {
+	m := make(map[string]string)
+	for _, id := range append(append([]string{}, spdxIDs...), spdxExceptions...) {
+		m[aliasKey(id)] = id
+		m[strings.ToLower(id)] = id
+	}
+	return m
+}()
+
+// aliasKey folds case, whitespace, dashes, underscores and commas
+func aliasKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '-', '_', ',':
+			return -1
+		}
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+// lookup returns the identifier for one license name, or ""
+func lookup(name string) string {
+	name = strings.TrimSpace(name)
+	if id, ok := canonical[strings.ToLower(name)]; ok {
+		return id
+	}
+	key := aliasKey(name)
+	if id, ok := canonical[key]; ok {
+		return id
+	}
+	return aliases[key]
+}
+
+// Normalize converts a declared license to an SPDX expression: "MIT",
+// "Apache License 2.0" -> "Apache-2.0", "MIT/Apache-2.0" -> "MIT OR
+// Apache-2.0", "(BSD-3-Clause OR GPL-2.0)" -> "BSD-3-Clause OR
+// GPL-2.0-only". It returns "" when any part isn't recognized.
+func Normalize(declared string) string {
+	declared = strings.TrimSpace(declared)
+	if declared == "" {
+		return ""
+	}
+	if id := lookup(declared); id != "" {
+		return id
+	}
+
+	tokens := tokenize(declared)
+	var out []string
+	var phrase []string
+	flush := func() bool {
+		if len(phrase) == 0 {
+			return true
+		}
+		id := lookup(strings.Join(phrase, " "))
+		phrase = nil
+		if id == "" {
+			return false
+		}
+		out = append(out, id)
+		return true
+	}
+	for _, tok := range tokens {
+		switch op := strings.ToUpper(tok); {
+		case op == "OR" || op == "AND" || op == "WITH" || tok == "(" || tok == ")":
+			if !flush() {
+				return ""
+			}
+			if tok == "(" || tok == ")" {
+				out = append(out, tok)
+			} else {
+				out = append(out, op)
+			}
+		default:
+			phrase = append(phrase, tok)
+		}
+	}
+	if !flush() {
+		return ""
+	}
+
+	expr := strings.Join(out, " ")
+	expr = strings.ReplaceAll(strings.ReplaceAll(expr, "( ", "("), " )", ")")
+	// Outer parentheses around a whole expression add nothing
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") && balanced(expr[1:len(expr)-1]) {
+		expr = expr[1 : len(expr)-1]
+	}
+	if _, err := parseExpr(expr); err != nil {
+		return ""
+	}
+	return expr
+}
+
+// tokenize splits an expression into words and parentheses. A slash between
+// names is the old Cargo spelling of OR.
+func tokenize(s string) []string {
+	s = strings.ReplaceAll(s, "(", " ( ")
+	s = strings.ReplaceAll(s, ")", " ) ")
+	var tokens []string
+	for _, f := range strings.Fields(s) {
+		if lookup(f) == "" && strings.Contains(f, "/") {
+			for i, part := range strings.Split(f, "/") {
+				if i > 0 {
+					tokens = append(tokens, "OR")
+				}
+				tokens = append(tokens, part)
+			}
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
+
+// balanced reports whether s has matching parentheses throughout
+func balanced(s string) bool {
+	depth := 0
+	for _, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
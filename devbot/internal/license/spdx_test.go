@@ -0,0 +1,80 @@
+package license
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"MIT":                                   "MIT",
+		"mit":                                   "MIT",
+		"MIT License":                           "MIT",
+		"Expat":                                 "MIT",
+		"Apache License, Version 2.0":           "Apache-2.0",
+		"Apache 2.0":                            "Apache-2.0",
+		"apache-2.0":                            "Apache-2.0",
+		"BSD-3-Clause":                          "BSD-3-Clause",
+		"New BSD":                               "BSD-3-Clause",
+		"GPL-2.0":                               "GPL-2.0-only",
+		"GPL-3.0+":                              "GPL-3.0-or-later",
+		"LGPLv3+":                               "LGPL-3.0-or-later",
+		"(MIT OR Apache-2.0)":                   "MIT OR Apache-2.0",
+		"MIT/Apache-2.0":                        "MIT OR Apache-2.0",
+		"mit or apache-2.0":                     "MIT OR Apache-2.0",
+		"(BSD-3-Clause OR GPL-2.0)":             "BSD-3-Clause OR GPL-2.0-only",
+		"Apache-2.0 WITH LLVM-exception":        "Apache-2.0 WITH LLVM-exception",
+		"MIT AND (Apache-2.0 OR BSD-2-Clause)":  "MIT AND (Apache-2.0 OR BSD-2-Clause)",
+		"MIT License OR Apache License 2.0":     "MIT OR Apache-2.0",
+		"Mozilla Public License 2.0 (MPL 2.0)":  "MPL-2.0",
+		"":                                      "",
+		"UNLICENSED":                            "",
+		"SEE LICENSE IN LICENSE.txt":            "",
+		"MIT OR Proprietary":                    "",
+		"MIT OR":                                "",
+		"(MIT":                                  "",
+		"Some License Text\nPermission granted": "",
+	}
+	for declared, want := range tests {
+		if got := Normalize(declared); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", declared, got, want)
+		}
+	}
+}
+
+func TestParseExpr(t *testing.T) {
+	allowMIT := func(id string) bool { return id == "MIT" }
+	tests := []struct {
+		expr string
+		ids  []string
+		want bool
+	}{
+		{"MIT", []string{"MIT"}, true},
+		{"GPL-3.0-only", []string{"GPL-3.0-only"}, false},
+		{"MIT OR GPL-3.0-only", []string{"MIT", "GPL-3.0-only"}, true},
+		{"MIT AND GPL-3.0-only", []string{"MIT", "GPL-3.0-only"}, false},
+		// AND binds tighter than OR
+		{"GPL-3.0-only AND ISC OR MIT", []string{"GPL-3.0-only", "ISC", "MIT"}, true},
+		{"GPL-3.0-only AND (ISC OR MIT)", []string{"GPL-3.0-only", "ISC", "MIT"}, false},
+		{"MIT WITH LLVM-exception", []string{"MIT"}, true},
+	}
+	for _, tt := range tests {
+		e, err := parseExpr(tt.expr)
+		if err != nil {
+			t.Errorf("parseExpr(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := e.eval(allowMIT); got != tt.want {
+			t.Errorf("eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+		if got, want := strings.Join(e.ids(), " "), strings.Join(tt.ids, " "); got != want {
+			t.Errorf("ids(%q) = %v, want %v", tt.expr, got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "MIT OR", "(MIT", "MIT)", "AND MIT", "MIT WITH"} {
+		if _, err := parseExpr(bad); err == nil {
+			t.Errorf("parseExpr(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Package node detects the package manager (npm, pnpm, yarn or bun) and
+// workspace layout of a JavaScript project, and builds the matching command
+// lines for running scripts and package binaries.
+package node
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Manager is a JavaScript package manager
+type Manager string
+
+const (
+	NPM  Manager = "npm"
+	PNPM Manager = "pnpm"
+	Yarn Manager = "yarn"
+	Bun  Manager = "bun"
+)
+
+// lockfiles identify the manager when package.json doesn't pin one
+var lockfiles = []struct {
+	file    string
+	manager Manager
+}{
+	{"pnpm-lock.yaml", PNPM},
+	{"bun.lockb", Bun},
+	{"bun.lock", Bun},
+	{"yarn.lock", Yarn},
+	{"package-lock.json", NPM},
+	{"npm-shrinkwrap.json", NPM},
+	{"pnpm-workspace.yaml", PNPM},
+}
+
+// Tool is the package manager setup for a project directory
+type Tool struct {
+	Manager   Manager
+	Version   string // Pinned by packageManager, e.g. "8.15.0"
+	Source    string // What decided the manager: "packageManager", a lockfile, or "default"
+	Root      string // Directory the manager was found in (the workspace root, if any)
+	Workspace bool   // Root declares workspaces (pnpm-workspace.yaml or package.json workspaces)
+	Turbo     bool   // Root has turbo.json
+}
+
+// Detect finds the package manager for dir from the packageManager field
+// of package.json or a lockfile, in dir or the nearest parent that has one.
+// The search stops at root, or at the enclosing git repo if root is "".
+// Without either, the manager is npm.
+func Detect(dir, root string) Tool {
+	dir = filepath.Clean(dir)
+	if root != "" {
+		root = filepath.Clean(root)
+	}
+	for d := dir; ; {
+		if t, ok := detectAt(d); ok {
+			return t
+		}
+		if d == root || exists(filepath.Join(d, ".git")) {
+			break
+		}
+		parent := filepath.Dir(d)
+		if parent == d || (root != "" && !strings.HasPrefix(d, root)) {
+			break
+		}
+		d = parent
+	}
+
+	t, _ := detectAt(dir)
+	t.Manager = NPM
+	t.Source = "default"
+	return t
+}
+
+// detectAt reads the manager and workspace layout declared in dir; ok is
+// false if dir declares no manager
+func detectAt(dir string) (Tool, bool) {
+	t := Tool{Root: dir, Turbo: exists(filepath.Join(dir, "turbo.json"))}
+
+	var pkg struct {
+		PackageManager string          `json:"packageManager"`
+		Workspaces     json.RawMessage `json:"workspaces"`
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		_ = json.Unmarshal(data, &pkg)
+	}
+	t.Workspace = hasWorkspaces(pkg.Workspaces) || exists(filepath.Join(dir, "pnpm-workspace.yaml"))
+
+	// "pnpm@8.15.0" or "yarn@4.1.0+sha256.abc..."
+	if name, version, ok := strings.Cut(pkg.PackageManager, "@"); ok {
+		switch m := Manager(name); m {
+		case NPM, PNPM, Yarn, Bun:
+			t.Manager = m
+			t.Version, _, _ = strings.Cut(version, "+")
+			t.Source = "packageManager"
+			return t, true
+		}
+	}
+
+	for _, l := range lockfiles {
+		if exists(filepath.Join(dir, l.file)) {
+			t.Manager = l.manager
+			t.Source = l.file
+			return t, true
+		}
+	}
+	return t, false
+}
+
+// hasWorkspaces reports whether a package.json workspaces value (a list,
+// or an object with a packages list) names any packages
+func hasWorkspaces(raw json.RawMessage) bool {
+	if len(raw) == 0 {
+		return false
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return len(list) > 0
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	return json.Unmarshal(raw, &obj) == nil && len(obj.Packages) > 0
+}
+
+// Binary returns the manager's executable
+func (t Tool) Binary() string {
+	return string(t.Manager)
+}
+
+// Install returns the command that installs dependencies
+func (t Tool) Install() string {
+	return t.Binary() + " install"
+}
+
+// Run returns the command that runs a package.json script with args
+func (t Tool) Run(script string, args ...string) []string {
+	if t.Manager == NPM {
+		cmd := []string{"npm", "run", script}
+		if script == "test" {
+			cmd = []string{"npm", "test"}
+		}
+		return withArgs(cmd, args)
+	}
+	// pnpm, yarn and bun pass everything after the script name through
+	return append([]string{t.Binary(), "run", script}, args...)
+}
+
+// Exec returns the command that runs a binary from the project's packages
+func (t Tool) Exec(bin string, args ...string) []string {
+	var cmd []string
+	switch t.Manager {
+	case PNPM:
+		cmd = []string{"pnpm", "exec", bin}
+	case Yarn:
+		cmd = []string{"yarn", bin}
+	case Bun:
+		cmd = []string{"bunx", bin}
+	default:
+		cmd = []string{"npx", bin}
+	}
+	return append(cmd, args...)
+}
+
+// WorkspaceRuns reports whether scripts can run across every workspace
+// package from Root (yarn classic has no runner that skips packages
+// without the script, so it needs turbo)
+func (t Tool) WorkspaceRuns() bool {
+	return t.Turbo || (t.Workspace && !t.yarnClassic())
+}
+
+// Runner names what WorkspaceRun uses, e.g. "turbo" or "pnpm -r"
+func (t Tool) Runner() string {
+	switch {
+	case t.Turbo:
+		return "turbo"
+	case t.Manager == PNPM:
+		return "pnpm -r"
+	case t.Manager == Yarn:
+		return "yarn workspaces foreach"
+	case t.Manager == Bun:
+		return "bun --filter"
+	}
+	return "npm --workspaces"
+}
+
+// WorkspaceRun returns the command that runs a script in every workspace
+// package that defines it, passing args to each
+func (t Tool) WorkspaceRun(script string, args ...string) []string {
+	switch {
+	case t.Turbo:
+		return withArgs(t.Exec("turbo", "run", script), args)
+	case t.Manager == PNPM:
+		return append([]string{"pnpm", "-r", "--if-present", "run", script}, args...)
+	case t.Manager == Yarn:
+		return append([]string{"yarn", "workspaces", "foreach", "-A", "run", script}, args...)
+	case t.Manager == Bun:
+		return append([]string{"bun", "run", "--filter", "*", script}, args...)
+	}
+	return withArgs([]string{"npm", "--workspaces", "--if-present", "run", script}, args)
+}
+
+// Adapt rewrites an npm command line ("npm run <script> [-- args]",
+// "npm test [-- args]" or "npx <bin> [args]") for this manager. Other
+// commands are returned unchanged.
+func (t Tool) Adapt(args []string) []string {
+	if t.Manager == NPM || len(args) < 2 {
+		return args
+	}
+	switch {
+	case args[0] == "npx":
+		return t.Exec(args[1], args[2:]...)
+	case args[0] == "npm" && args[1] == "test":
+		return t.Run("test", ScriptArgs(args[2:])...)
+	case args[0] == "npm" && args[1] == "run" && len(args) > 2:
+		return t.Run(args[2], ScriptArgs(args[3:])...)
+	}
+	return args
+}
+
+// ScriptArgs returns the arguments after "--" in the rest of an npm
+// command, which npm passes on to the script
+func ScriptArgs(rest []string) []string {
+	for i, a := range rest {
+		if a == "--" {
+			return rest[i+1:]
+		}
+	}
+	return nil
+}
+
+// yarnClassic reports whether yarn is 1.x, judging by the pinned version
+// or, without one, the absence of a Yarn 2+ .yarnrc.yml
+func (t Tool) yarnClassic() bool {
+	if t.Manager != Yarn {
+		return false
+	}
+	if t.Version != "" {
+		major, _, _ := strings.Cut(t.Version, ".")
+		n, err := strconv.Atoi(major)
+		return err == nil && n < 2
+	}
+	return !exists(filepath.Join(t.Root, ".yarnrc.yml"))
+}
+
+// withArgs appends args after "--", the way npm and turbo pass them on
+func withArgs(cmd, args []string) []string {
+	if len(args) == 0 {
+		return cmd
+	}
+	return append(append(cmd, "--"), args...)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,146 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		manager Manager
+		version string
+		source  string
+	}{
+		{"no lockfile", map[string]string{"package.json": "{}"}, NPM, "", "default"},
+		{"package-lock", map[string]string{"package-lock.json": "{}"}, NPM, "", "package-lock.json"},
+		{"pnpm lock", map[string]string{"pnpm-lock.yaml": ""}, PNPM, "", "pnpm-lock.yaml"},
+		{"yarn lock", map[string]string{"yarn.lock": ""}, Yarn, "", "yarn.lock"},
+		{"bun lockb", map[string]string{"bun.lockb": ""}, Bun, "", "bun.lockb"},
+		{"packageManager wins", map[string]string{
+			"package.json":      `{"packageManager": "yarn@4.1.0+sha256.abc"}`,
+			"package-lock.json": "{}",
+		}, Yarn, "4.1.0", "packageManager"},
+		{"unknown packageManager", map[string]string{
+			"package.json":   `{"packageManager": "deno@1.0.0"}`,
+			"pnpm-lock.yaml": "",
+		}, PNPM, "", "pnpm-lock.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(dir, name), content)
+			}
+			got := Detect(dir, dir)
+			if got.Manager != tt.manager || got.Version != tt.version || got.Source != tt.source {
+				t.Errorf("Detect = %+v, want %s %q from %s", got, tt.manager, tt.version, tt.source)
+			}
+		})
+	}
+}
+
+func TestDetectWorkspace(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "pnpm-workspace.yaml"), "packages: [packages/*]")
+	writeFile(t, filepath.Join(repo, "pnpm-lock.yaml"), "")
+	writeFile(t, filepath.Join(repo, "turbo.json"), "{}")
+	pkg := filepath.Join(repo, "packages", "ui")
+	writeFile(t, filepath.Join(pkg, "package.json"), "{}")
+
+	// A package inherits the manager from the workspace root
+	got := Detect(pkg, repo)
+	if got.Manager != PNPM || got.Root != repo || !got.Workspace || !got.Turbo {
+		t.Errorf("Detect(package) = %+v, want pnpm turbo workspace at root", got)
+	}
+	if !got.WorkspaceRuns() || got.Runner() != "turbo" {
+		t.Errorf("WorkspaceRuns = %v, Runner = %q", got.WorkspaceRuns(), got.Runner())
+	}
+
+	// The search stops at root
+	if got := Detect(pkg, pkg); got.Manager != NPM || got.Source != "default" {
+		t.Errorf("Detect(package, package) = %+v, want npm default", got)
+	}
+}
+
+func TestAdapt(t *testing.T) {
+	tests := []struct {
+		manager Manager
+		args    string
+		want    string
+	}{
+		{NPM, "npm run lint -- --fix", "npm run lint -- --fix"},
+		{PNPM, "npm run lint -- --fix", "pnpm run lint --fix"},
+		{PNPM, "npm test", "pnpm run test"},
+		{PNPM, "npx tsc --noEmit", "pnpm exec tsc --noEmit"},
+		{Yarn, "npm test -- --passWithNoTests", "yarn run test --passWithNoTests"},
+		{Yarn, "npx tsc --noEmit", "yarn tsc --noEmit"},
+		{Bun, "npm run build", "bun run build"},
+		{Bun, "npx tsc --noEmit", "bunx tsc --noEmit"},
+		{PNPM, "go test ./...", "go test ./..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.manager)+" "+tt.args, func(t *testing.T) {
+			got := Tool{Manager: tt.manager}.Adapt(strings.Fields(tt.args))
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("Adapt = %q, want %q", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkspaceRun(t *testing.T) {
+	tests := []struct {
+		tool Tool
+		want string
+	}{
+		{Tool{Manager: PNPM, Turbo: true}, "pnpm exec turbo run lint -- --fix"},
+		{Tool{Manager: NPM, Turbo: true}, "npx turbo run lint -- --fix"},
+		{Tool{Manager: PNPM}, "pnpm -r --if-present run lint --fix"},
+		{Tool{Manager: Yarn}, "yarn workspaces foreach -A run lint --fix"},
+		{Tool{Manager: Bun}, "bun run --filter * lint --fix"},
+		{Tool{Manager: NPM}, "npm --workspaces --if-present run lint -- --fix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := strings.Join(tt.tool.WorkspaceRun("lint", "--fix"), " "); got != tt.want {
+				t.Errorf("WorkspaceRun = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYarnClassic(t *testing.T) {
+	dir := t.TempDir()
+	classic := Tool{Manager: Yarn, Root: dir, Workspace: true}
+	if classic.WorkspaceRuns() {
+		t.Error("yarn 1 without turbo shouldn't run across workspaces")
+	}
+	if (Tool{Manager: Yarn, Version: "1.22.19", Root: dir, Workspace: true}).WorkspaceRuns() {
+		t.Error("pinned yarn 1 shouldn't run across workspaces")
+	}
+
+	writeFile(t, filepath.Join(dir, ".yarnrc.yml"), "nodeLinker: node-modules")
+	if !classic.WorkspaceRuns() {
+		t.Error("yarn with .yarnrc.yml should run across workspaces")
+	}
+	if !(Tool{Manager: Yarn, Version: "4.1.0", Workspace: true}).WorkspaceRuns() {
+		t.Error("pinned yarn 4 should run across workspaces")
+	}
+}
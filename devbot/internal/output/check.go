@@ -0,0 +1,248 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/check"
+	"github.com/sloanahrens/devbot-go/internal/prereq"
+)
+
+// RenderText prints any prerequisite results, then each check grouped by
+// sub-app, with failing tests or the start of the output for failures
+func (v CheckView) RenderText(elapsed time.Duration) {
+	if v.Prereq != nil {
+		v.Prereq.RenderText(elapsed)
+		if !v.Prereq.Passed {
+			fmt.Fprintln(os.Stderr, "Prereq checks failed. Fix issues before running checks.")
+			return
+		}
+		fmt.Println() // Blank line between prereq and check output
+	}
+
+	result := v.result
+	fmt.Printf("\n%s/ (%s)\n", result.Repo.Name, result.StackSummary())
+	fmt.Println(strings.Repeat("─", 60))
+
+	if len(result.Checks) == 0 {
+		fmt.Println("  No checks available for this stack")
+		return
+	}
+
+	// Group checks by sub-app
+	type subAppChecks struct {
+		path   string
+		checks []check.CheckResult
+	}
+	var grouped []subAppChecks
+	seen := make(map[string]int)
+
+	for _, c := range result.Checks {
+		idx, ok := seen[c.SubDir]
+		if !ok {
+			idx = len(grouped)
+			seen[c.SubDir] = idx
+			grouped = append(grouped, subAppChecks{path: c.SubDir})
+		}
+		grouped[idx].checks = append(grouped[idx].checks, c)
+	}
+
+	// Display each sub-app's results
+	for _, sg := range grouped {
+		if sg.path != "" {
+			fmt.Printf("\n  %s/\n", sg.path)
+		}
+
+		for _, c := range sg.checks {
+			status := c.Status
+			switch status {
+			case "pass":
+				status = "✓ PASS"
+				if c.Cached {
+					status = "✓ PASS (cached)"
+				}
+			case "fail":
+				status = "✗ FAIL"
+			case "skip":
+				status = "- SKIP"
+			}
+
+			duration := ""
+			if c.Duration > 0 {
+				duration = fmt.Sprintf("%.1fs", c.Duration.Seconds())
+			}
+
+			prefix := "  "
+			if sg.path != "" {
+				prefix = "    "
+			}
+			source := ""
+			if c.Source != "" {
+				source = fmt.Sprintf("[%s]", c.Source)
+			}
+			tests := ""
+			if c.Tests != nil {
+				tests = "  " + c.Tests.Summary()
+			}
+			fmt.Printf("%s%-12s %-8s %-6s %s%s\n", prefix, c.Type, status, duration, source, tests)
+
+			// Show failing tests, or else the error output, for failed checks
+			if c.Status == "fail" && c.Tests != nil && len(c.Tests.Failures()) > 0 {
+				printTestFailures(prefix, c.Tests.Failures())
+			} else if c.Status == "fail" && c.Output != "" {
+				lines := strings.Split(c.Output, "\n")
+				maxLines := 10
+				if len(lines) > maxLines {
+					lines = lines[:maxLines]
+					lines = append(lines, fmt.Sprintf("... (%d more lines)", len(strings.Split(c.Output, "\n"))-maxLines))
+				}
+				for _, line := range lines {
+					fmt.Printf("%s  %s\n", prefix, line)
+				}
+			}
+		}
+	}
+
+	fmt.Printf("\n  %s\n", strings.Repeat("─", 40))
+	fmt.Printf("  Total: %s (%.1fs)\n", result.Summary(), result.Duration.Seconds())
+	for _, path := range v.Reports {
+		fmt.Printf("  Report: %s\n", path)
+	}
+}
+
+// printTestFailures lists failing tests with their location and the start
+// of each failure message
+func printTestFailures(prefix string, failures []check.TestCase) {
+	const maxTests, maxLines = 10, 5
+	for i, tc := range failures {
+		if i == maxTests {
+			fmt.Printf("%s  ... (%d more failing tests)\n", prefix, len(failures)-maxTests)
+			break
+		}
+		name := tc.Suite
+		if tc.Name != "" {
+			name = tc.Name
+			if tc.Suite != "" {
+				name = tc.Suite + " " + tc.Name
+			}
+		}
+		loc := ""
+		if l := tc.Location(); l != "" {
+			loc = " (" + l + ")"
+		}
+		fmt.Printf("%s  ✗ %s%s\n", prefix, name, loc)
+
+		lines := strings.Split(tc.Message, "\n")
+		if tc.Message == "" {
+			lines = nil
+		}
+		if len(lines) > maxLines {
+			lines = append(lines[:maxLines], fmt.Sprintf("... (%d more lines)", len(lines)-maxLines))
+		}
+		for _, line := range lines {
+			fmt.Printf("%s      %s\n", prefix, line)
+		}
+	}
+}
+
+// RenderText prints the cache's location, size and age range
+func (v CacheStatsView) RenderText(elapsed time.Duration) {
+	fmt.Printf("\nCheck cache: %s\n", v.Dir)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("  Entries: %d\n", v.Entries)
+	fmt.Printf("  Size:    %.1f KB\n", float64(v.Bytes)/1024)
+	if v.Entries > 0 {
+		fmt.Printf("  Oldest:  %s\n", v.stats.Oldest.Format("2006-01-02 15:04"))
+		fmt.Printf("  Newest:  %s\n", v.stats.Newest.Format("2006-01-02 15:04"))
+	}
+	fmt.Println()
+}
+
+// RenderText reports how many results were removed
+func (v CacheClearView) RenderText(elapsed time.Duration) {
+	fmt.Printf("Removed %d cached results from %s\n", v.Removed, v.Dir)
+}
+
+// RenderText prints each prerequisite check; see prereq.Render
+func (v PrereqView) RenderText(elapsed time.Duration) {
+	prereq.Render(v.result)
+}
+
+// RenderText prints the detected stacks
+func (v DetectView) RenderText(elapsed time.Duration) {
+	if len(v.Stack) == 0 {
+		fmt.Println("No project stack detected")
+		return
+	}
+
+	fmt.Printf("Detected: %s\n", strings.Join(v.Stack, ", "))
+}
+
+// RenderText prints the evidence for each stack, the sub-apps check would
+// run and any discrepancies between the two
+func (v DetectExplainView) RenderText(elapsed time.Duration) {
+	detected := "none"
+	if len(v.Stack) > 0 {
+		detected = strings.Join(v.Stack, ", ")
+	}
+	fmt.Printf("\n%s\n", v.Path)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("Detected: %s\n", detected)
+
+	fmt.Println("\nPaths examined")
+	for _, d := range v.Dirs {
+		name := "./"
+		if d.Path != "" {
+			name = d.Path + "/"
+		}
+		if d.Pattern != "" && d.Pattern != d.Path {
+			name += fmt.Sprintf(" (from %s)", d.Pattern)
+		}
+		fmt.Printf("  %s\n", name)
+		for _, ev := range d.Stacks {
+			mark := "✗"
+			if ev.Matched {
+				mark = "✓"
+			}
+			fmt.Printf("    %s %-10s %s\n", mark, ev.Stack, ev.Reason)
+		}
+	}
+	if len(v.Absent) > 0 {
+		fmt.Printf("  Not present: %s\n", strings.Join(v.Absent, ", "))
+	}
+
+	fmt.Println("\nSub-apps (as 'devbot check' sees them)")
+	if len(v.SubApps) == 0 {
+		fmt.Println("  none")
+	}
+	for _, app := range v.SubApps {
+		name := "./"
+		if app.Path != "" {
+			name = app.Path + "/"
+		}
+		fmt.Printf("  %s (%s)\n", name, strings.Join(app.Stack, ", "))
+		if app.Error != "" {
+			fmt.Printf("    ✗ %s\n", app.Error)
+			continue
+		}
+		for _, c := range app.Checks {
+			if c.Skip != "" {
+				fmt.Printf("    %-10s - skip: %s\n", c.Type, c.Skip)
+				continue
+			}
+			fmt.Printf("    %-10s %-36s [%s]\n", c.Type, c.Command, c.Source)
+		}
+	}
+	for _, sk := range v.Skipped {
+		fmt.Printf("  %s/ (%s): not a separate sub-app, %s\n", sk.Path, strings.Join(sk.Stack, ", "), sk.Reason)
+	}
+
+	if len(v.Notes) > 0 {
+		fmt.Println("\nDiscrepancies")
+		for _, n := range v.Notes {
+			fmt.Printf("  ⚠ %s\n", n)
+		}
+	}
+}
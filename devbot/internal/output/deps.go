@@ -0,0 +1,338 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+	"github.com/sloanahrens/devbot-go/internal/graph"
+)
+
+// RenderText prints shared dependencies, every dependency with All, or
+// per-repo counts with Count, then any replaced modules
+func (v DepsView) RenderText(elapsed time.Duration) {
+	switch {
+	case v.Count:
+		fmt.Println("\nDependency counts:")
+		fmt.Println(strings.Repeat("─", 60))
+		for _, r := range v.results {
+			if len(r.Dependencies) > 0 {
+				prod, dev, transitive := 0, 0, 0
+				for _, d := range r.Dependencies {
+					switch {
+					case !d.Direct():
+						transitive++
+					case d.Dev:
+						dev++
+					default:
+						prod++
+					}
+				}
+				fmt.Printf("  %-25s %3d prod, %3d dev, %4d transitive\n", r.Repo.Name, prod, dev, transitive)
+			}
+		}
+	case v.All:
+		// Sorted by usage, with the versions actually installed
+		fmt.Println("\nAll dependencies (by usage):")
+		fmt.Println(strings.Repeat("─", 60))
+		for _, u := range deps.Aggregate(v.results, v.Transitive) {
+			fmt.Printf("  %-40s (%d repos)\n", u.Name, len(u.Repos))
+			for _, ver := range u.SortedVersions() {
+				fmt.Printf("      %-36s %s\n", ver, strings.Join(u.Versions[ver], ", "))
+			}
+		}
+	default:
+		fmt.Println("\nShared dependencies (2+ repos):")
+		fmt.Println(strings.Repeat("─", 60))
+		for _, u := range deps.Aggregate(v.results, v.Transitive) {
+			if len(u.Repos) >= 2 {
+				fmt.Printf("  %-40s %v\n", u.Name, u.Repos)
+			}
+		}
+	}
+
+	// Replace directives change what actually gets built
+	if !v.Count {
+		printed := false
+		for _, r := range v.results {
+			for _, d := range r.Dependencies {
+				if d.Replace == "" {
+					continue
+				}
+				if !printed {
+					fmt.Println("\nReplaced modules:")
+					fmt.Println(strings.Repeat("─", 60))
+					printed = true
+				}
+				fmt.Printf("  %-20s %s %s => %s\n", r.Repo.Name, d.Name, d.Version, d.Replace)
+			}
+		}
+	}
+
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
+
+// RenderText prints each drifted dependency with the repos on each version
+func (v DriftView) RenderText(elapsed time.Duration) {
+	fmt.Println("\nDependency drift (shared by 2+ repos):")
+	fmt.Println(strings.Repeat("─", 60))
+	if len(v.Drift) == 0 {
+		fmt.Println("  No drift: shared dependencies agree on versions")
+	}
+	for _, d := range v.Drift {
+		mark := " "
+		if d.Exceeds {
+			mark = "✗"
+		}
+		fmt.Printf("%s %-40s %-6s → %s\n", mark, d.Name, strings.ToUpper(d.Level), d.Target)
+		for _, g := range d.Versions {
+			fmt.Printf("      %-36s %s\n", g.Version, strings.Join(g.Repos, ", "))
+		}
+	}
+
+	fmt.Printf("\n%d drifted, %d exceed policy (fail on %s) (%.2fs)\n", len(v.Drift), v.Exceeded, v.FailOn, elapsed.Seconds())
+}
+
+// RenderText prints each finding and how current the database is
+func (v AuditView) RenderText(elapsed time.Duration) {
+	fmt.Println("\nVulnerability audit:")
+	fmt.Println(strings.Repeat("─", 60))
+	if len(v.Findings) == 0 {
+		fmt.Println("  No known vulnerabilities")
+	}
+	for _, f := range v.Findings {
+		fixed := "no fix available"
+		if f.FixedIn != "" {
+			fixed = "fixed in " + f.FixedIn
+		}
+		kind := ""
+		if f.Transitive {
+			kind = " (transitive)"
+		}
+		if f.Replaces != "" {
+			kind += " (replaces " + f.Replaces + ")"
+		}
+		fmt.Printf("  %-8s %-20s %s@%s%s\n", strings.ToUpper(f.Severity), f.Repo, f.Package, f.Version, kind)
+		fmt.Printf("           %s: %s (%s)\n", f.ID, f.Summary, fixed)
+	}
+
+	fmt.Printf("\n%d findings, %d dependencies checked against %d advisories", len(v.Findings), v.Checked, v.Advisories)
+	if v.Unresolved > 0 {
+		fmt.Printf(", %d without an exact version", v.Unresolved)
+	}
+	fmt.Println()
+	if !v.importedAt.IsZero() {
+		fmt.Printf("Advisories imported %s ago (%.2fs)\n", formatAge(time.Since(v.importedAt)), elapsed.Seconds())
+	}
+}
+
+// RenderText prints the database location, size and last import
+func (v AdvisoryDBView) RenderText(elapsed time.Duration) {
+	fmt.Printf("Advisory database: %s\n", v.Database)
+	fmt.Printf("  Advisories: %d\n", v.Advisories)
+	if v.importedAt.IsZero() {
+		fmt.Println("  Never imported (run 'devbot advisories import <dir|zip>')")
+		return
+	}
+	fmt.Printf("  Imported:   %s ago\n", formatAge(time.Since(v.importedAt)))
+	for _, src := range v.Sources {
+		fmt.Printf("  Source:     %s\n", src)
+	}
+}
+
+// RenderText prints what each source added to the database
+func (v AdvisoryImportsView) RenderText(elapsed time.Duration) {
+	for _, i := range v.Imports {
+		fmt.Printf("%s: %d imported, %d unchanged, %d other ecosystems, %d invalid\n", i.Source, i.Imported, i.Unchanged, i.Unsupported, i.Invalid)
+	}
+	fmt.Printf("Database: %s (%.2fs)\n", v.Database, elapsed.Seconds())
+}
+
+// RenderText lists the files written
+func (v SBOMFilesView) RenderText(elapsed time.Duration) {
+	for _, f := range v.Files {
+		commit := f.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		} else if commit == "" {
+			commit = "no commit"
+		}
+		fmt.Printf("  %-25s %-9s %4d components  %s  %s\n", f.Repo, f.Format, f.Components, commit, f.Path)
+	}
+	fmt.Printf("\n%d SBOMs written to %s (%.2fs)\n", len(v.Files), v.Dir, elapsed.Seconds())
+}
+
+// RenderText lists every internal edge grouped by the depending repo, or
+// with Focus, what that repo provides, uses and is used by
+func (v GraphView) RenderText(elapsed time.Duration) {
+	if v.Focus != "" {
+		renderRepoGraph(v.graph, v.Focus, elapsed)
+		return
+	}
+
+	g := v.graph
+	fmt.Println("\nInternal dependencies:")
+	fmt.Println(strings.Repeat("─", 60))
+	if len(g.Edges) == 0 {
+		fmt.Println("  No repo depends on another")
+	}
+
+	connected := make(map[string]bool)
+	from := ""
+	for _, e := range g.Edges {
+		connected[e.From], connected[e.To] = true, true
+		if e.From != from {
+			fmt.Printf("  %s\n", e.From)
+			from = e.From
+		}
+		printEdge("→", e.To, e)
+	}
+
+	var standalone []string
+	for _, r := range g.Repos {
+		if !connected[r] {
+			standalone = append(standalone, r)
+		}
+	}
+	fmt.Printf("\n%d edges between %d repos", len(g.Edges), len(g.Repos)-len(standalone))
+	if len(standalone) > 0 {
+		fmt.Printf("; standalone: %s", strings.Join(standalone, ", "))
+	}
+	fmt.Printf(" (%.2fs)\n", elapsed.Seconds())
+}
+
+// renderRepoGraph shows what repo provides, uses and is used by
+func renderRepoGraph(g *graph.Graph, repo string, elapsed time.Duration) {
+	fmt.Printf("\n%s\n", repo)
+	fmt.Println(strings.Repeat("─", 60))
+
+	fmt.Println("  Provides:")
+	if len(g.Provides[repo]) == 0 {
+		fmt.Println("    (no named modules or packages)")
+	}
+	for _, p := range g.Provides[repo] {
+		fmt.Printf("    %-7s %s (%s)\n", p.Ecosystem, p.Name, p.Dir)
+	}
+
+	fmt.Println("  Depends on:")
+	if len(g.DependenciesOf(repo)) == 0 {
+		fmt.Println("    (none)")
+	}
+	for _, e := range g.DependenciesOf(repo) {
+		printEdge("→", e.To, e)
+	}
+
+	fmt.Println("  Used by:")
+	if len(g.DependentsOf(repo)) == 0 {
+		fmt.Println("    (none)")
+	}
+	for _, e := range g.DependentsOf(repo) {
+		printEdge("←", e.From, e)
+	}
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
+
+// printEdge prints an edge's other end and the packages linking them
+func printEdge(arrow, other string, e graph.Edge) {
+	for i, l := range e.Links {
+		var notes []string
+		if l.Local {
+			notes = append(notes, "local")
+		}
+		if l.Dev {
+			notes = append(notes, "dev")
+		}
+		link := strings.TrimSpace(l.Name + " " + l.Version)
+		if len(notes) > 0 {
+			link += " (" + strings.Join(notes, ", ") + ")"
+		}
+		if i == 0 {
+			fmt.Printf("    %s %-20s %s\n", arrow, other, link)
+		} else {
+			fmt.Printf("      %-20s %s\n", "", link)
+		}
+	}
+}
+
+// RenderText lists the repos to re-check after changing Repo
+func (v DependentsView) RenderText(elapsed time.Duration) {
+	if len(v.Dependents) == 0 {
+		fmt.Printf("No repos depend on %s (%.2fs)\n", v.Repo, elapsed.Seconds())
+		return
+	}
+
+	fmt.Printf("\nRepos depending on %s (%d):\n", v.Repo, len(v.Dependents))
+	fmt.Println(strings.Repeat("─", 60))
+	for _, d := range v.Dependents {
+		how := "direct"
+		if d.Depth > 1 {
+			how = "via " + d.Via
+		}
+		fmt.Printf("  %-25s %s\n", d.Repo, how)
+	}
+	fmt.Printf("\n%d repos to re-check with 'devbot check <repo>' (%.2fs)\n", len(v.Dependents), elapsed.Seconds())
+}
+
+// RenderText shows a summary by license, then the packages that need
+// attention grouped by repo
+func (v LicensesView) RenderText(elapsed time.Duration) {
+	fmt.Println("\nLicenses:")
+	fmt.Println(strings.Repeat("─", 60))
+	var names []string
+	for name := range v.Counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if v.Counts[names[i]] != v.Counts[names[j]] {
+			return v.Counts[names[i]] > v.Counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		fmt.Printf("  %5d  %s\n", v.Counts[name], name)
+	}
+	if v.Unknown > 0 {
+		fmt.Printf("  %5d  (unknown)\n", v.Unknown)
+	}
+	if len(names) == 0 && v.Unknown == 0 {
+		fmt.Println("  No dependencies")
+	}
+
+	if len(v.Packages) > 0 {
+		title := "Violations and unknown licenses:"
+		if v.all {
+			title = "Packages:"
+		}
+		fmt.Println("\n" + title)
+		fmt.Println(strings.Repeat("─", 60))
+	}
+	repo := ""
+	for _, p := range v.Packages {
+		if p.Repo != repo {
+			fmt.Printf("  %s\n", p.Repo)
+			repo = p.Repo
+		}
+		mark := " "
+		if p.Violation {
+			mark = "✗"
+		}
+		shown := p.License
+		if shown == "" {
+			shown = p.Declared
+		}
+		detail := p.Reason
+		if shown != "" && detail != "" {
+			detail = shown + " - " + detail
+		} else if shown != "" {
+			detail = shown
+		}
+		fmt.Printf("    %s %-40s %s\n", mark, p.Package+"@"+p.Version, detail)
+	}
+
+	fmt.Printf("\n%d packages checked, %d violations, %d unknown (%.2fs)\n", v.Checked, v.Violations, v.Unknown, elapsed.Seconds())
+	if v.Unknown > 0 && !v.failOnUnknown {
+		fmt.Println("Unknown licenses don't fail; install dependencies or add licenses.packages overrides to resolve them")
+	}
+}
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Format selects how command results are rendered
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// SchemaVersion is bumped whenever a JSON document changes incompatibly
+const SchemaVersion = 1
+
+// Document is the envelope every JSON result is wrapped in
+type Document struct {
+	SchemaVersion int    `json:"schema_version"`
+	Command       string `json:"command"`
+	ElapsedMS     int64  `json:"elapsed_ms"`
+	Data          any    `json:"data"`
+}
+
+var currentFormat = FormatText
+
+// SetFormat sets the global output format ("text" or "json")
+func SetFormat(f string) error {
+	switch Format(f) {
+	case FormatText, FormatJSON:
+		currentFormat = Format(f)
+		return nil
+	case "":
+		currentFormat = FormatText
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q (want text or json)", f)
+}
+
+// CurrentFormat returns the active output format
+func CurrentFormat() Format {
+	return currentFormat
+}
+
+// IsJSON returns true if results should be rendered as JSON
+func IsJSON() bool {
+	return currentFormat == FormatJSON
+}
+
+// WriteJSON writes data wrapped in a versioned Document
+func WriteJSON(w io.Writer, command string, elapsed time.Duration, data any) error {
+	doc := Document{
+		SchemaVersion: SchemaVersion,
+		Command:       command,
+		ElapsedMS:     durationMS(elapsed),
+		Data:          data,
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
+
+// PrintJSON writes a JSON document to stdout
+func PrintJSON(command string, elapsed time.Duration, data any) {
+	if err := WriteJSON(os.Stdout, command, elapsed, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// errString converts an error to a string, empty if nil
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// durationMS converts a duration to whole milliseconds
+func durationMS(d time.Duration) int64 {
+	return d.Milliseconds()
+}
@@ -0,0 +1,171 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/check"
+	"github.com/sloanahrens/devbot-go/internal/prereq"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func TestSetFormat(t *testing.T) {
+	defer func() { _ = SetFormat("text") }()
+
+	tests := []struct {
+		input    string
+		want     Format
+		wantErr  bool
+		wantJSON bool
+	}{
+		{"text", FormatText, false, false},
+		{"json", FormatJSON, false, true},
+		{"", FormatText, false, false},
+		{"xml", FormatText, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_ = SetFormat("text")
+			err := SetFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if CurrentFormat() != tt.want {
+				t.Errorf("CurrentFormat() = %q, want %q", CurrentFormat(), tt.want)
+			}
+			if IsJSON() != tt.wantJSON {
+				t.Errorf("IsJSON() = %v, want %v", IsJSON(), tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestWriteJSONEnvelope(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteJSON(&buf, "detect", 1500*time.Millisecond, DetectView{Path: "/tmp/x", Stack: []string{"go"}})
+	if err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	var doc struct {
+		SchemaVersion int        `json:"schema_version"`
+		Command       string     `json:"command"`
+		ElapsedMS     int64      `json:"elapsed_ms"`
+		Data          DetectView `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if doc.SchemaVersion != SchemaVersion {
+		t.Errorf("schema_version = %d, want %d", doc.SchemaVersion, SchemaVersion)
+	}
+	if doc.Command != "detect" {
+		t.Errorf("command = %q, want detect", doc.Command)
+	}
+	if doc.ElapsedMS != 1500 {
+		t.Errorf("elapsed_ms = %d, want 1500", doc.ElapsedMS)
+	}
+	if doc.Data.Path != "/tmp/x" || len(doc.Data.Stack) != 1 {
+		t.Errorf("data = %+v, want path /tmp/x with one stack", doc.Data)
+	}
+}
+
+func TestNewStatusViewErrorsAsStrings(t *testing.T) {
+	statuses := []workspace.RepoStatus{
+		{RepoInfo: workspace.RepoInfo{Name: "b-repo"}, Branch: "main"},
+		{RepoInfo: workspace.RepoInfo{Name: "a-repo"}, Error: errors.New("not a git repository")},
+	}
+
+	v := NewStatusView(statuses, "/code")
+
+	if len(v.Repos) != 2 {
+		t.Fatalf("Repos len = %d, want 2", len(v.Repos))
+	}
+	if v.Repos[0].Name != "a-repo" {
+		t.Errorf("Repos[0].Name = %q, want a-repo (sorted)", v.Repos[0].Name)
+	}
+	if v.Repos[0].Error != "not a git repository" {
+		t.Errorf("Repos[0].Error = %q, want error string", v.Repos[0].Error)
+	}
+	if v.Repos[1].Stack == nil {
+		t.Error("Stack should be an empty slice, not nil")
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if bytes.Contains(data, []byte(`"stack":null`)) {
+		t.Errorf("JSON should not contain null stacks: %s", data)
+	}
+}
+
+func TestNewCheckViewDurations(t *testing.T) {
+	r := check.Result{
+		Repo: workspace.RepoInfo{Name: "repo"},
+		Checks: []check.CheckResult{
+			{Type: check.CheckLint, Status: "pass", Duration: 2500 * time.Millisecond},
+			{Type: check.CheckTest, Status: "fail", Duration: time.Second, Error: errors.New("exit status 1")},
+		},
+		Duration: 3500 * time.Millisecond,
+	}
+
+	v := NewCheckView(r)
+
+	if v.DurationMS != 3500 {
+		t.Errorf("DurationMS = %d, want 3500", v.DurationMS)
+	}
+	if v.Checks[0].DurationMS != 2500 {
+		t.Errorf("Checks[0].DurationMS = %d, want 2500", v.Checks[0].DurationMS)
+	}
+	if v.Checks[1].Error != "exit status 1" {
+		t.Errorf("Checks[1].Error = %q, want exit status 1", v.Checks[1].Error)
+	}
+	if v.Passed {
+		t.Error("Passed should be false when a check failed")
+	}
+	if v.Summary != "FAIL" {
+		t.Errorf("Summary = %q, want FAIL", v.Summary)
+	}
+}
+
+func TestNewPrereqViewStatusNames(t *testing.T) {
+	r := &prereq.Result{
+		Path: "/repo",
+		Checks: []prereq.Check{
+			{Name: "go", Status: prereq.Pass},
+			{Name: "deps", Status: prereq.Fail},
+			{Name: "env", Status: prereq.Warn},
+		},
+	}
+
+	v := NewPrereqView(r)
+
+	want := []string{"pass", "fail", "warn"}
+	for i, c := range v.Checks {
+		if c.Status != want[i] {
+			t.Errorf("Checks[%d].Status = %q, want %q", i, c.Status, want[i])
+		}
+	}
+	if v.Passed {
+		t.Error("Passed should be false with a failing check")
+	}
+}
+
+func TestNewPrereqFailedCheckView(t *testing.T) {
+	r := &prereq.Result{Path: "/repo", Checks: []prereq.Check{{Name: "deps", Status: prereq.Fail}}}
+
+	v := NewPrereqFailedCheckView(workspace.RepoInfo{Name: "repo", Path: "/repo"}, r)
+
+	if v.Passed || len(v.Checks) != 0 {
+		t.Errorf("view = %+v, want a failed check with no checks run", v)
+	}
+	if v.Prereq == nil || v.Prereq.Passed || len(v.Prereq.Checks) != 1 {
+		t.Errorf("Prereq = %+v, want the failing prereq result", v.Prereq)
+	}
+}
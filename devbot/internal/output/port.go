@@ -0,0 +1,33 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// RenderText lists the processes on the port, or with Kill, whether each
+// one was killed
+func (v PortView) RenderText(elapsed time.Duration) {
+	if len(v.Processes) == 0 {
+		fmt.Printf("Port %d: nothing running\n", v.Port)
+		return
+	}
+
+	if !v.Kill {
+		fmt.Printf("Port %d:\n", v.Port)
+		for _, p := range v.Processes {
+			fmt.Printf("  %s (PID %d) - %s\n", p.Command, p.PID, p.User)
+		}
+		return
+	}
+
+	for _, p := range v.Processes {
+		fmt.Printf("Killing %s (PID %d) on port %d...\n", p.Command, p.PID, v.Port)
+		if p.Killed {
+			fmt.Printf("  Killed PID %d\n", p.PID)
+		} else {
+			fmt.Fprintf(os.Stderr, "  Failed to kill PID %d: %s\n", p.PID, p.Error)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/pull"
+	"github.com/sloanahrens/devbot-go/internal/reposync"
+)
+
+// RenderText prints the outcome table; see RenderPull
+func (v PullView) RenderText(elapsed time.Duration) {
+	RenderPull(v.results, elapsed, v.Title)
+}
+
+// RenderPull prints a table of fetch or pull outcomes, one repo per line
+func RenderPull(results []pull.Result, elapsed time.Duration, title string) {
+	fmt.Printf("\n  %s%s\n", title, formatElapsed(elapsed))
+	fmt.Println(strings.Repeat("─", 70))
+
+	counts := make(map[pull.Outcome]int)
+	moved := 0
+	for _, r := range results {
+		counts[r.Outcome]++
+		moved += r.Moved
+		printPullLine(r)
+	}
+
+	var parts []string
+	for _, o := range []struct {
+		outcome pull.Outcome
+		label   string
+	}{
+		{pull.OutcomeUpdated, "updated"},
+		{pull.OutcomeUpToDate, "up-to-date"},
+		{pull.OutcomeSkipped, "skipped"},
+		{pull.OutcomeFailed, "failed"},
+	} {
+		if counts[o.outcome] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[o.outcome], o.label))
+		}
+	}
+
+	fmt.Printf("\n  (%d repos: %s; %d commits)\n\n", len(results), strings.Join(parts, ", "), moved)
+}
+
+func printPullLine(r pull.Result) {
+	name := r.Repo.Name
+	if len(name) > 22 {
+		name = name[:19] + "..."
+	}
+
+	branch := r.Branch
+	if branch == "" {
+		branch = "-"
+	}
+	if len(branch) > 12 {
+		branch = branch[:9] + "..."
+	}
+
+	var status, detail string
+	switch r.Outcome {
+	case pull.OutcomeUpdated:
+		status = fmt.Sprintf("↓ %d commit", r.Moved)
+		if r.Moved != 1 {
+			status += "s"
+		}
+		detail = r.Before + ".." + r.After
+		if r.Behind > 0 {
+			detail += fmt.Sprintf(" (%d behind)", r.Behind)
+		}
+	case pull.OutcomeUpToDate:
+		status = "✓ up-to-date"
+		if r.Behind > 0 {
+			detail = fmt.Sprintf("%d behind", r.Behind)
+		}
+	case pull.OutcomeSkipped:
+		status, detail = "- skipped", r.Reason
+	case pull.OutcomeFailed:
+		status, detail = "✗ failed", fmt.Sprint(r.Error)
+	}
+
+	line := fmt.Sprintf("  %-22s %-12s %-13s %s", name, branch, status, detail)
+	fmt.Println(strings.TrimRight(line, " "))
+}
+
+// RenderText prints each repo's sync action, then counts of each
+func (v SyncView) RenderText(elapsed time.Duration) {
+	if len(v.results) == 0 {
+		fmt.Println("No repositories configured or found")
+		return
+	}
+
+	counts := make(map[reposync.Action]int)
+	for _, r := range v.results {
+		counts[r.Action]++
+
+		var status, detail string
+		switch r.Action {
+		case reposync.ActionOK:
+			status = "✓ ok"
+		case reposync.ActionCloned:
+			status, detail = "✓ cloned", r.URL
+		case reposync.ActionWouldClone:
+			status, detail = "→ clone", r.URL
+		case reposync.ActionNoRemote:
+			status, detail = "✗ missing", "no remote in config.yaml"
+		case reposync.ActionMismatch:
+			status, detail = "! mismatch", fmt.Sprintf("origin %s, config %s", r.Origin, r.URL)
+		case reposync.ActionUntracked:
+			status, detail = "? untracked", "not in config.yaml"
+		case reposync.ActionAdded:
+			status, detail = "+ added", "to config.yaml"
+		case reposync.ActionFailed:
+			status, detail = "✗ failed", fmt.Sprint(r.Error)
+		}
+
+		line := fmt.Sprintf("  %-30s %-12s %s", r.Name, status, detail)
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	var parts []string
+	for _, a := range []reposync.Action{reposync.ActionCloned, reposync.ActionWouldClone, reposync.ActionMismatch, reposync.ActionNoRemote, reposync.ActionUntracked, reposync.ActionAdded, reposync.ActionFailed} {
+		if counts[a] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[a], strings.ReplaceAll(string(a), "_", " ")))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "in sync")
+	}
+
+	fmt.Printf("\n(%d repos, %s, %.2fs)\n", len(v.results), strings.Join(parts, ", "), elapsed.Seconds())
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/pull"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func TestRenderPull(t *testing.T) {
+	results := []pull.Result{
+		{Repo: workspace.RepoInfo{Name: "api"}, Branch: "main", Outcome: pull.OutcomeUpdated, Moved: 3, Before: "abc1234", After: "def5678"},
+		{Repo: workspace.RepoInfo{Name: "web"}, Branch: "main", Outcome: pull.OutcomeUpToDate},
+		{Repo: workspace.RepoInfo{Name: "infra"}, Branch: "wip", Outcome: pull.OutcomeSkipped, Reason: "uncommitted changes"},
+		{Repo: workspace.RepoInfo{Name: "docs"}, Outcome: pull.OutcomeFailed, Error: errors.New("could not resolve host")},
+	}
+
+	out := captureOutput(func() {
+		RenderPull(results, 100*time.Millisecond, "pull")
+	})
+
+	for _, want := range []string{
+		"↓ 3 commits",
+		"abc1234..def5678",
+		"✓ up-to-date",
+		"uncommitted changes",
+		"could not resolve host",
+		"4 repos: 1 updated, 1 up-to-date, 1 skipped, 1 failed; 3 commits",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
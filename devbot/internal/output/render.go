@@ -0,0 +1,38 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// TextRenderer is a command result that can print itself for a terminal.
+// Every view passed to Render is also the command's JSON shape.
+type TextRenderer interface {
+	RenderText(elapsed time.Duration)
+}
+
+// Render prints a command's result to stdout: the view as a JSON document
+// with --json, or its text rendering otherwise
+func Render(command string, elapsed time.Duration, view TextRenderer) {
+	if IsJSON() {
+		PrintJSON(command, elapsed, view)
+		return
+	}
+	view.RenderText(elapsed)
+}
+
+// jsonArray encodes items as a JSON array ([] when empty) for views whose
+// JSON shape is a list. HTML characters are left alone, as in WriteJSON.
+func jsonArray[T any](items []T) ([]byte, error) {
+	if items == nil {
+		items = []T{}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(items); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
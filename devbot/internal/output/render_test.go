@@ -0,0 +1,77 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	defer func() { _ = SetFormat("text") }()
+	view := DetectView{Path: "/tmp/x", Stack: []string{"go", "node"}}
+
+	_ = SetFormat("text")
+	got := captureOutput(func() { Render("detect", time.Second, view) })
+	if got != "Detected: go, node\n" {
+		t.Errorf("text output = %q", got)
+	}
+
+	_ = SetFormat("json")
+	got = captureOutput(func() { Render("detect", time.Second, view) })
+	var doc Document
+	if err := json.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("JSON output is not valid: %v\n%s", err, got)
+	}
+	if doc.Command != "detect" || doc.ElapsedMS != 1000 {
+		t.Errorf("doc = %+v, want command detect, 1000ms", doc)
+	}
+}
+
+func TestListViewsMarshalAsArrays(t *testing.T) {
+	tests := []struct {
+		name string
+		view any
+		want string
+	}{
+		{"no imports", AdvisoryImportsView{Database: "/db"}, `[]`},
+		{"imports", AdvisoryImportsView{Database: "/db", Imports: []AdvisoryImportView{{Database: "/db", Source: "a&b.zip", Imported: 2}}},
+			`[{"database":"/db","source":"a&b.zip","imported":2,"unchanged":0,"unsupported":0,"invalid":0}]`},
+		{"no files", SBOMFilesView{Dir: "/out"}, `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(tt.view); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("JSON = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortViewRenderTextKill(t *testing.T) {
+	view := PortView{Port: 3000, Kill: true, Processes: []ProcessView{
+		{PID: 10, Command: "node", Killed: true},
+		{PID: 11, Command: "node", Error: "operation not permitted"},
+	}}
+
+	got := captureOutput(func() { view.RenderText(0) })
+	for _, want := range []string{
+		"Killing node (PID 10) on port 3000...\n  Killed PID 10\n",
+		"Killing node (PID 11) on port 3000...\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Killed PID 11") {
+		t.Errorf("failed kill reported as killed:\n%s", got)
+	}
+}
@@ -0,0 +1,239 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RenderText prints a summary of staged and unstaged changes, with each
+// file's diff when Full is set
+func (v DiffView) RenderText(elapsed time.Duration) {
+	if len(v.Staged) == 0 && len(v.Unstaged) == 0 {
+		fmt.Printf("\n%s/ (clean)\n", v.Repo.Name)
+		fmt.Printf("  Branch: %s\n", v.Branch)
+		fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+		return
+	}
+
+	// Header
+	fmt.Printf("\n%s/\n", v.Repo.Name)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("  Branch:   %s\n", v.Branch)
+
+	// Summary
+	if len(v.Staged) > 0 {
+		add, del := changeTotals(v.Staged)
+		fmt.Printf("  Staged:   %d files (+%d, -%d)\n", len(v.Staged), add, del)
+	}
+	if len(v.Unstaged) > 0 {
+		add, del := changeTotals(v.Unstaged)
+		fmt.Printf("  Unstaged: %d files (+%d, -%d)\n", len(v.Unstaged), add, del)
+	}
+
+	if len(v.Staged) > 0 {
+		fmt.Printf("\n  Staged:\n")
+		v.printChanges(v.Staged)
+	}
+	if len(v.Unstaged) > 0 {
+		fmt.Printf("\n  Unstaged:\n")
+		v.printChanges(v.Unstaged)
+	}
+
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
+
+func (v DiffView) printChanges(changes []FileChangeView) {
+	for _, c := range changes {
+		stats := ""
+		if c.Additions > 0 || c.Deletions > 0 {
+			stats = fmt.Sprintf(" (+%d, -%d)", c.Additions, c.Deletions)
+		}
+		fmt.Printf("    %s  %s%s\n", c.Status, c.Path, stats)
+		if v.Full && c.Content != "" {
+			// Diff content, indented under the file
+			fmt.Println()
+			for _, line := range strings.Split(c.Content, "\n") {
+				fmt.Printf("      %s\n", line)
+			}
+		}
+	}
+}
+
+func changeTotals(changes []FileChangeView) (additions, deletions int) {
+	for _, c := range changes {
+		additions += c.Additions
+		deletions += c.Deletions
+	}
+	return additions, deletions
+}
+
+// RenderText prints the branch, its upstream and the commits to push
+func (v BranchView) RenderText(elapsed time.Duration) {
+	// Header
+	fmt.Printf("\n%s/\n", v.Repo.Name)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("  Branch:   %s\n", v.Branch)
+
+	if v.HasUpstream {
+		fmt.Printf("  Tracking: %s\n", v.Tracking)
+	} else if v.Tracking != "" {
+		fmt.Printf("  Remote:   %s\n", v.Tracking)
+	} else {
+		fmt.Printf("  Tracking: (none - new branch)\n")
+	}
+
+	// Ahead/behind
+	if v.Ahead > 0 || v.Behind > 0 {
+		fmt.Printf("  Ahead:    %d commits\n", v.Ahead)
+		fmt.Printf("  Behind:   %d commits\n", v.Behind)
+	}
+
+	// Commits to push
+	if len(v.Commits) > 0 {
+		fmt.Printf("\n  Commits to push:\n")
+		for i, c := range v.Commits {
+			if i >= 10 {
+				fmt.Printf("    ... and %d more\n", len(v.Commits)-10)
+				break
+			}
+			subject := c.Subject
+			if len(subject) > 50 {
+				subject = subject[:47] + "..."
+			}
+			fmt.Printf("    %s %s\n", c.Hash, subject)
+		}
+	}
+
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
+
+// RenderText lists each remote and its GitHub repo
+func (v RemoteView) RenderText(elapsed time.Duration) {
+	// Header
+	fmt.Printf("\n%s/\n", v.Repo.Name)
+	fmt.Println(strings.Repeat("─", 60))
+
+	if len(v.Remotes) == 0 {
+		fmt.Println("  No remotes configured")
+	} else {
+		for _, r := range v.Remotes {
+			fmt.Printf("  %-10s %s\n", r.Name+":", r.URL)
+			if r.GitHub != "" {
+				fmt.Printf("             GitHub: %s\n", r.GitHub)
+			}
+		}
+	}
+
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
+
+// RenderText prints the matching local repo and remote
+func (v FindRepoView) RenderText(elapsed time.Duration) {
+	if !v.Found {
+		fmt.Printf("No local repo found for '%s'\n", v.Identifier)
+		fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+		return
+	}
+
+	fmt.Printf("\n%s\n", v.Repo.Name)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("  Path:   %s\n", v.Repo.Path)
+	fmt.Printf("  GitHub: %s\n", v.Remote.GitHub)
+	fmt.Printf("  Remote: %s (%s)\n", v.Remote.Name, v.Remote.URL)
+
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
+
+// RenderText prints just the path, for use in scripts
+func (v PathView) RenderText(elapsed time.Duration) {
+	fmt.Println(v.Path)
+}
+
+// RenderText prints just the relative age, for use in scripts
+func (v LastCommitView) RenderText(elapsed time.Duration) {
+	fmt.Println(v.RelativeAge)
+}
+
+// RenderText prints each Pulumi project's stacks and resources, then which
+// commands are safe to run given the existing infrastructure
+func (v PulumiView) RenderText(elapsed time.Duration) {
+	// Header
+	fmt.Printf("\n%s/ - Pulumi Infrastructure State\n", v.Repo.Name)
+	fmt.Println(strings.Repeat("═", 60))
+
+	if len(v.Projects) == 0 {
+		fmt.Println("  No Pulumi.yaml found in this repository")
+		fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+		return
+	}
+
+	hasExistingInfra := false
+
+	for _, p := range v.Projects {
+		dirLabel := p.Dir
+		if dirLabel == "" {
+			dirLabel = "(root)"
+		}
+
+		fmt.Printf("\n  📁 %s/\n", dirLabel)
+		fmt.Println(strings.Repeat("─", 50))
+
+		if p.Error != "" {
+			fmt.Printf("    ⚠️  Error: %s\n", p.Error)
+			continue
+		}
+
+		// Stacks
+		if len(p.Stacks) == 0 {
+			fmt.Println("    Stacks:    (none)")
+		} else {
+			fmt.Printf("    Stacks:    %s\n", strings.Join(p.Stacks, ", "))
+		}
+
+		// Current stack
+		if p.CurrentStack != "" {
+			fmt.Printf("    Current:   %s\n", p.CurrentStack)
+			fmt.Printf("    Resources: %d\n", p.ResourceCount)
+
+			if p.HasInfra {
+				hasExistingInfra = true
+				fmt.Println()
+				fmt.Println("    ⚠️  INFRASTRUCTURE EXISTS - DO NOT run 'pulumi stack init'")
+			}
+		} else {
+			fmt.Println("    Current:   (no stack selected)")
+			if len(p.Stacks) > 0 {
+				fmt.Printf("\n    ℹ️  Run: pulumi stack select %s\n", p.Stacks[0])
+			}
+		}
+	}
+
+	// Safety guidance
+	fmt.Println()
+	fmt.Println(strings.Repeat("═", 60))
+	if hasExistingInfra {
+		fmt.Println("🛑 EXISTING INFRASTRUCTURE DETECTED")
+		fmt.Println()
+		fmt.Println("   Safe commands:")
+		fmt.Println("     pulumi preview       # See what would change")
+		fmt.Println("     pulumi up            # Apply changes")
+		fmt.Println("     pulumi stack         # Show current state")
+		fmt.Println()
+		fmt.Println("   DANGEROUS - DO NOT RUN:")
+		fmt.Println("     pulumi stack init    # Would orphan existing infra!")
+		fmt.Println("     pulumi destroy       # Would delete everything!")
+		fmt.Println("     pulumi stack rm      # Would lose state!")
+	} else if len(v.Projects[0].Stacks) > 0 {
+		fmt.Println("📋 STACKS EXIST - Select one before running commands")
+		fmt.Println()
+		fmt.Printf("   Run: cd <pulumi-dir> && pulumi stack select %s\n", v.Projects[0].Stacks[0])
+	} else {
+		fmt.Println("✅ NO EXISTING INFRASTRUCTURE")
+		fmt.Println()
+		fmt.Println("   Safe to initialize:")
+		fmt.Println("     pulumi stack init dev")
+	}
+
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/runner"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// repoColors are the ANSI foreground colors cycled across repos
+var repoColors = []string{"36", "33", "35", "32", "34", "96", "93", "95", "92", "94"}
+
+// LinePrinter writes streamed run output, one prefixed line at a time.
+// Stdout lines go to Stdout and stderr lines to Stderr.
+type LinePrinter struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	mu       sync.Mutex
+	width    int
+	prefixes map[string]string
+}
+
+// NewLinePrinter returns a printer for the given repos. Prefixes are padded
+// to the longest name and, when color is true, colorized per repo.
+func NewLinePrinter(repos []workspace.RepoInfo, color bool) *LinePrinter {
+	names := make([]string, 0, len(repos))
+	width := 0
+	for _, r := range repos {
+		names = append(names, r.Name)
+		if len(r.Name) > width {
+			width = len(r.Name)
+		}
+	}
+	sort.Strings(names)
+
+	prefixes := make(map[string]string, len(names))
+	for i, name := range names {
+		prefix := fmt.Sprintf("%-*s │", width, name)
+		if color {
+			prefix = fmt.Sprintf("\033[%sm%s\033[0m", repoColors[i%len(repoColors)], prefix)
+		}
+		prefixes[name] = prefix
+	}
+
+	return &LinePrinter{
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+		width:    width,
+		prefixes: prefixes,
+	}
+}
+
+// Print writes a single line. It's safe to call from multiple goroutines.
+func (p *LinePrinter) Print(line runner.Line) {
+	prefix, ok := p.prefixes[line.Repo.Name]
+	if !ok {
+		prefix = fmt.Sprintf("%-*s │", p.width, line.Repo.Name)
+	}
+
+	w := p.Stdout
+	if line.Stderr {
+		w = p.Stderr
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(w, "%s %s\n", prefix, line.Text)
+}
+
+// IsTerminal reports whether f is a character device (and NO_COLOR is unset)
+func IsTerminal(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// RenderText prints each repo's output, or just the summary table when
+// the output was already streamed
+func (v RunView) RenderText(elapsed time.Duration) {
+	if v.Stream {
+		RenderRunSummary(v.results, elapsed)
+		return
+	}
+
+	for _, r := range v.results {
+		if v.Quiet && r.Output == "" && r.Error == nil {
+			continue
+		}
+
+		fmt.Printf("── %s ", r.Repo.Name)
+		switch r.Status {
+		case runner.StatusTimedOut:
+			fmt.Printf("(%v)\n", r.Error)
+		case runner.StatusCancelled:
+			fmt.Println("(cancelled)")
+		default:
+			if r.Error != nil {
+				fmt.Printf("(error: %v)\n", r.Error)
+			} else {
+				fmt.Println()
+			}
+		}
+
+		if r.Output != "" {
+			// Indent output
+			lines := strings.Split(strings.TrimSpace(r.Output), "\n")
+			for _, line := range lines {
+				fmt.Printf("   %s\n", line)
+			}
+		}
+	}
+
+	fmt.Printf("\n(%s, %.2fs)\n", RunCounts(v.results), elapsed.Seconds())
+}
+
+// RenderRunSummary prints a table of per-repo status, exit code and duration
+func RenderRunSummary(results []runner.Result, elapsed time.Duration) {
+	fmt.Printf("\n  %-22s %-12s %-5s %s\n", "REPO", "STATUS", "EXIT", "TIME")
+	fmt.Println(strings.Repeat("─", 50))
+
+	for _, r := range results {
+		name := r.Repo.Name
+		if len(name) > 22 {
+			name = name[:19] + "..."
+		}
+
+		status := "✓ ok"
+		switch r.Status {
+		case runner.StatusFailed:
+			status = "✗ failed"
+		case runner.StatusTimedOut:
+			status = "✗ timed out"
+		case runner.StatusCancelled:
+			status = "- cancelled"
+		}
+
+		exit := "-"
+		if r.ExitCode >= 0 {
+			exit = fmt.Sprintf("%d", r.ExitCode)
+		}
+
+		fmt.Printf("  %-22s %-12s %-5s %.2fs\n", name, status, exit, r.Duration.Seconds())
+	}
+
+	fmt.Printf("\n  (%s, %.2fs)\n", RunCounts(results), elapsed.Seconds())
+}
+
+// RunCounts summarizes results as "N repos" plus any non-ok counts,
+// e.g. "12 repos, 1 failed, 2 timed out"
+func RunCounts(results []runner.Result) string {
+	counts := make(map[runner.Status]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	parts := []string{fmt.Sprintf("%d repos", len(results))}
+	for _, s := range []struct {
+		status runner.Status
+		label  string
+	}{
+		{runner.StatusFailed, "failed"},
+		{runner.StatusTimedOut, "timed out"},
+		{runner.StatusCancelled, "cancelled"},
+	} {
+		if counts[s.status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[s.status], s.label))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/runner"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func TestLinePrinter(t *testing.T) {
+	a := workspace.RepoInfo{Name: "api"}
+	b := workspace.RepoInfo{Name: "frontend"}
+
+	var stdout, stderr bytes.Buffer
+	p := NewLinePrinter([]workspace.RepoInfo{a, b}, false)
+	p.Stdout = &stdout
+	p.Stderr = &stderr
+
+	p.Print(runner.Line{Repo: a, Text: "building"})
+	p.Print(runner.Line{Repo: b, Stderr: true, Text: "warning"})
+
+	if got := stdout.String(); got != "api      │ building\n" {
+		t.Errorf("stdout = %q, want padded prefix", got)
+	}
+	if got := stderr.String(); got != "frontend │ warning\n" {
+		t.Errorf("stderr = %q, want stderr line kept separate", got)
+	}
+}
+
+func TestLinePrinterColor(t *testing.T) {
+	a := workspace.RepoInfo{Name: "api"}
+	b := workspace.RepoInfo{Name: "web"}
+
+	var stdout bytes.Buffer
+	p := NewLinePrinter([]workspace.RepoInfo{b, a}, true)
+	p.Stdout = &stdout
+
+	p.Print(runner.Line{Repo: a, Text: "x"})
+	p.Print(runner.Line{Repo: b, Text: "y"})
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if !strings.HasPrefix(lines[0], "\033[36m") || !strings.HasPrefix(lines[1], "\033[33m") {
+		t.Errorf("lines = %q, want distinct colors assigned in name order", lines)
+	}
+}
+
+func TestRenderRunSummary(t *testing.T) {
+	results := []runner.Result{
+		{Repo: workspace.RepoInfo{Name: "repo-a"}, Status: runner.StatusOK, ExitCode: 0, Duration: 1500 * time.Millisecond},
+		{Repo: workspace.RepoInfo{Name: "repo-b"}, Status: runner.StatusFailed, ExitCode: 2, Error: errors.New("exit status 2")},
+		{Repo: workspace.RepoInfo{Name: "repo-c"}, Status: runner.StatusTimedOut, ExitCode: -1},
+	}
+
+	out := captureOutput(func() {
+		RenderRunSummary(results, 2*time.Second)
+	})
+
+	for _, want := range []string{"repo-a", "1.50s", "✗ failed", "2 ", "✗ timed out", "3 repos, 1 failed, 1 timed out, 2.00s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/makefile"
+	"github.com/sloanahrens/devbot-go/internal/stats"
+	"github.com/sloanahrens/devbot-go/internal/tree"
+)
+
+// RenderText lists each repo's worktrees under .trees/
+func (v WorktreesView) RenderText(elapsed time.Duration) {
+	reposWithWorktrees := 0
+	totalWorktrees := 0
+
+	for _, r := range v.Repos {
+		if len(r.Worktrees) == 0 {
+			continue
+		}
+		reposWithWorktrees++
+		totalWorktrees += len(r.Worktrees)
+
+		fmt.Printf("\n%s/\n", r.Repo.Name)
+		for _, wt := range r.Worktrees {
+			status := "clean"
+			if wt.Error != "" {
+				status = "error: " + wt.Error
+			} else if wt.DirtyFiles > 0 {
+				status = fmt.Sprintf("%d modified", wt.DirtyFiles)
+			}
+			fmt.Printf("  .trees/%-25s → %s (%s)\n", wt.Name, wt.Branch, status)
+		}
+	}
+
+	if totalWorktrees == 0 {
+		fmt.Println("\nNo worktrees found")
+	}
+
+	fmt.Printf("\n(%d repos, %d with worktrees, %d total, %.2fs)\n",
+		len(v.Repos), reposWithWorktrees, totalWorktrees, elapsed.Seconds())
+}
+
+// RenderText lists each repo's Makefile targets: grouped by category with
+// Detailed, all of them with AllTargets, or else the first few
+func (v MakeView) RenderText(elapsed time.Duration) {
+	reposWithMakefiles := 0
+	totalTargets := 0
+
+	for _, r := range v.results {
+		if len(r.Targets) == 0 {
+			continue
+		}
+		reposWithMakefiles++
+		totalTargets += len(r.Targets)
+
+		if v.Detailed {
+			fmt.Printf("\n%s/%s - %d targets\n\n", r.Repo.Name, r.Path, len(r.Targets))
+
+			groups := makefile.GroupByCategory(r.Targets)
+			for _, cat := range makefile.CategoryOrder() {
+				targets := groups[cat]
+				if len(targets) == 0 {
+					continue
+				}
+
+				var names []string
+				for _, t := range targets {
+					names = append(names, t.Name)
+				}
+				fmt.Printf("  %-10s %s\n", cat+":", strings.Join(names, ", "))
+			}
+		} else if v.AllTargets {
+			var names []string
+			for _, t := range r.Targets {
+				names = append(names, t.Name)
+			}
+			fmt.Printf("  %-25s %d targets  (%s)\n", r.Repo.Name, len(r.Targets), strings.Join(names, ", "))
+		} else {
+			var names []string
+			for i, t := range r.Targets {
+				if i >= 3 {
+					names = append(names, "...")
+					break
+				}
+				names = append(names, t.Name)
+			}
+			fmt.Printf("  %-25s %2d targets  (%s)\n", r.Repo.Name, len(r.Targets), strings.Join(names, ", "))
+		}
+	}
+
+	fmt.Printf("\n(%d repos, %d with Makefiles, %d targets, %.2fs)\n",
+		len(v.results), reposWithMakefiles, totalTargets, elapsed.Seconds())
+}
+
+// RenderText lists each repo's config files
+func (v ConfigView) RenderText(elapsed time.Duration) {
+	totalFiles := 0
+	reposWithConfigs := 0
+
+	for _, r := range v.Repos {
+		if len(r.Files) == 0 {
+			continue
+		}
+		reposWithConfigs++
+		totalFiles += len(r.Files)
+
+		fmt.Printf("\n%s/\n", r.Repo.Name)
+
+		var fileNames []string
+		for _, f := range r.Files {
+			fileNames = append(fileNames, f.RelPath)
+		}
+		fmt.Printf("  %s\n", strings.Join(fileNames, ", "))
+	}
+
+	fmt.Printf("\n(%d repos, %d config files, %.2fs)\n", reposWithConfigs, totalFiles, elapsed.Seconds())
+}
+
+// RenderText lists each repo's TODO items, or with Count, how many of each
+// type it has
+func (v TodosView) RenderText(elapsed time.Duration) {
+	totalItems := 0
+	reposWithTodos := 0
+
+	for _, r := range v.Repos {
+		if len(r.Items) == 0 {
+			continue
+		}
+		reposWithTodos++
+		totalItems += len(r.Items)
+
+		if v.Count {
+			parts := []string{}
+			for t, c := range r.Counts {
+				parts = append(parts, fmt.Sprintf("%s:%d", t, c))
+			}
+			fmt.Printf("  %-25s %d items (%s)\n", r.Repo.Name, len(r.Items), strings.Join(parts, ", "))
+		} else {
+			fmt.Printf("\n%s/\n", r.Repo.Name)
+			for _, item := range r.Items {
+				text := item.Text
+				if len(text) > 60 {
+					text = text[:57] + "..."
+				}
+				fmt.Printf("  %-40s %s: %s\n",
+					fmt.Sprintf("%s:%d", item.RelPath, item.Line),
+					item.Type,
+					text)
+			}
+		}
+	}
+
+	if v.Count {
+		fmt.Println()
+	}
+	fmt.Printf("\n(%d repos, %d items, %.2fs)\n", reposWithTodos, totalItems, elapsed.Seconds())
+}
+
+// RenderText prints line counts for the directory and flags its largest
+// files, longest functions and deepest nesting
+func (v DirStatsView) RenderText(elapsed time.Duration) {
+	if v.TotalFiles == 0 {
+		fmt.Println("No source files found")
+		return
+	}
+
+	// Summary
+	fmt.Printf("\n%s/\n", v.Path)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("  Files:     %d\n", v.TotalFiles)
+	fmt.Printf("  Total:     %d lines\n", v.TotalLines)
+	fmt.Printf("  Code:      %d lines (%.1f%%)\n", v.CodeLines, float64(v.CodeLines)/float64(v.TotalLines)*100)
+	fmt.Printf("  Comments:  %d lines (%.1f%%)\n", v.CommentLines, float64(v.CommentLines)/float64(v.TotalLines)*100)
+	fmt.Printf("  Blank:     %d lines\n", v.BlankLines)
+	fmt.Printf("  Functions: %d (avg %d lines)\n", v.TotalFunctions, v.AvgFuncLength)
+
+	// Complexity flags
+	if len(v.LargeFiles) > 0 {
+		fmt.Printf("\n  ⚠ Large files (>%d lines):\n", stats.LargeFileThreshold)
+		for i, f := range v.LargeFiles {
+			if i >= 5 {
+				fmt.Printf("    ... and %d more\n", len(v.LargeFiles)-5)
+				break
+			}
+			relPath, _ := filepath.Rel(v.Path, f.Path)
+			fmt.Printf("    %s (%d lines)\n", relPath, f.TotalLines)
+		}
+	}
+
+	if len(v.LongFunctions) > 0 {
+		fmt.Printf("\n  ⚠ Long functions (>%d lines):\n", stats.LongFunctionThreshold)
+		for i, lf := range v.LongFunctions {
+			if i >= 5 {
+				fmt.Printf("    ... and %d more\n", len(v.LongFunctions)-5)
+				break
+			}
+			relPath, _ := filepath.Rel(v.Path, lf.File)
+			fmt.Printf("    %s:%d %s (%d lines)\n", relPath, lf.Line, lf.Name, lf.Lines)
+		}
+	}
+
+	if len(v.DeepNesting) > 0 {
+		fmt.Printf("\n  ⚠ Deep nesting (>%d levels):\n", stats.DeepNestingThreshold)
+		for i, f := range v.DeepNesting {
+			if i >= 5 {
+				fmt.Printf("    ... and %d more\n", len(v.DeepNesting)-5)
+				break
+			}
+			relPath, _ := filepath.Rel(v.Path, f.Path)
+			fmt.Printf("    %s (max %d levels)\n", relPath, f.MaxNesting)
+		}
+	}
+
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
+
+// RenderText prints line counts for the file and lists its functions,
+// flagging long ones
+func (v FileStatsView) RenderText(elapsed time.Duration) {
+	if v.Language == "" {
+		fmt.Println("Unsupported file type")
+		return
+	}
+
+	fmt.Printf("\n%s (%s)\n", v.Path, v.Language)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("  Total:     %d lines\n", v.TotalLines)
+	fmt.Printf("  Code:      %d lines\n", v.CodeLines)
+	fmt.Printf("  Comments:  %d lines\n", v.CommentLines)
+	fmt.Printf("  Blank:     %d lines\n", v.BlankLines)
+	fmt.Printf("  Imports:   %d\n", v.Imports)
+	fmt.Printf("  Functions: %d\n", len(v.Functions))
+	fmt.Printf("  Max nest:  %d levels\n", v.MaxNesting)
+
+	if len(v.Functions) > 0 {
+		fmt.Printf("\n  Functions:\n")
+		for _, fn := range v.Functions {
+			flag := ""
+			if fn.Lines > stats.LongFunctionThreshold {
+				flag = " ⚠"
+			}
+			fmt.Printf("    L%-4d %-30s %3d lines%s\n", fn.Line, fn.Name, fn.Lines, flag)
+		}
+	}
+
+	fmt.Printf("\n(%.2fs)\n", elapsed.Seconds())
+}
+
+// RenderText draws the tree; see tree.Render
+func (v TreeView) RenderText(elapsed time.Duration) {
+	fmt.Print(tree.Render(v.entry, "", true, true))
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
+// RenderText prints the status table; see RenderStatus
+func (v StatusView) RenderText(elapsed time.Duration) {
+	RenderStatus(v.statuses, elapsed, v.ShowAll, v.Workspace)
+}
+
 // RenderStatus prints a formatted table of repository statuses
 func RenderStatus(statuses []workspace.RepoStatus, elapsed time.Duration, showAll bool, workspacePath string) {
 	// Sort by name
@@ -17,10 +22,10 @@ func RenderStatus(statuses []workspace.RepoStatus, elapsed time.Duration, showAl
 		return statuses[i].Name < statuses[j].Name
 	})
 
-	// Filter if needed: repos needing attention have dirty files OR unpushed commits
+	// Filter if needed: see AttentionReasons
 	var needsAttention, upToDate []workspace.RepoStatus
 	for _, s := range statuses {
-		if s.DirtyFiles > 0 || s.Ahead > 0 {
+		if len(AttentionReasons(s)) > 0 {
 			needsAttention = append(needsAttention, s)
 		} else {
 			upToDate = append(upToDate, s)
@@ -57,12 +62,25 @@ func RenderStatus(statuses []workspace.RepoStatus, elapsed time.Duration, showAl
 }
 
 func printRepoLine(s workspace.RepoStatus) {
+	fmt.Println("  " + FormatRepoLine(s))
+}
+
+// FormatRepoLine formats one status row (name, stack, status, branch, sync
+// and details) without leading indent or trailing newline
+func FormatRepoLine(s workspace.RepoStatus) string {
 	// Name (truncate if too long)
 	name := s.Name
 	if len(name) > 22 {
 		name = name[:19] + "..."
 	}
 
+	if s.Missing {
+		return fmt.Sprintf("%-22s %-8s %s", name, "-", "✗ missing (in config.yaml, not on disk)")
+	}
+	if s.Error != nil {
+		return fmt.Sprintf("%-22s %-8s ✗ error: %v", name, "-", s.Error)
+	}
+
 	// Stack
 	stack := strings.Join(s.Stack, "+")
 	if stack == "" {
@@ -97,7 +115,105 @@ func printRepoLine(s workspace.RepoStatus) {
 		sync = fmt.Sprintf("%d behind", s.Behind)
 	}
 
-	fmt.Printf("  %-22s %-8s %-10s %-12s %s\n", name, stack, status, branch, sync)
+	line := fmt.Sprintf("%-22s %-8s %-10s %-12s %-10s %s", name, stack, status, branch, sync, strings.Join(statusDetails(s), ", "))
+	return strings.TrimRight(line, " ")
+}
+
+// StaleFetchAge is how old FETCH_HEAD can get before a repo needs attention
+var StaleFetchAge = 14 * 24 * time.Hour
+
+// AttentionReasons lists why a repo needs attention: missing or unreadable,
+// uncommitted or unpushed work, stashes, conflicts, an in-progress
+// operation, or upstream data from a stale fetch. Empty means up-to-date.
+func AttentionReasons(s workspace.RepoStatus) []string {
+	var reasons []string
+	if s.Missing {
+		reasons = append(reasons, "missing")
+	}
+	if s.Error != nil {
+		reasons = append(reasons, "error")
+	}
+	if s.Operation != "" {
+		reasons = append(reasons, s.Operation+" in progress")
+	}
+	if s.Conflicts > 0 {
+		reasons = append(reasons, plural(s.Conflicts, "conflict"))
+	}
+	if s.DirtyFiles > s.Conflicts {
+		reasons = append(reasons, "uncommitted changes")
+	}
+	if s.Ahead > 0 {
+		reasons = append(reasons, "unpushed commits")
+	}
+	if s.Stashes > 0 {
+		reasons = append(reasons, plural(s.Stashes, "stash"))
+	}
+	if fetchStale(s) {
+		reasons = append(reasons, "stale fetch")
+	}
+	return reasons
+}
+
+// statusDetails returns the extras shown after the sync column
+func statusDetails(s workspace.RepoStatus) []string {
+	var details []string
+	if s.Operation != "" {
+		details = append(details, strings.ToUpper(s.Operation)+" IN PROGRESS")
+	}
+	if s.Conflicts > 0 {
+		details = append(details, plural(s.Conflicts, "conflict"))
+	}
+
+	var kinds []string
+	for _, k := range []struct {
+		n     int
+		label string
+	}{
+		{s.Staged, "staged"},
+		{s.Modified, "modified"},
+		{s.Untracked, "untracked"},
+	} {
+		if k.n > 0 {
+			kinds = append(kinds, fmt.Sprintf("%d %s", k.n, k.label))
+		}
+	}
+	if len(kinds) > 0 {
+		details = append(details, strings.Join(kinds, " "))
+	}
+
+	if s.Stashes > 0 {
+		details = append(details, plural(s.Stashes, "stash"))
+	}
+	// Ahead/behind counts are only as fresh as the last fetch
+	if !s.LastFetch.IsZero() {
+		details = append(details, "fetched "+formatAge(time.Since(s.LastFetch))+" ago")
+	}
+	return details
+}
+
+// formatAge rounds d down to the largest whole unit: "5m", "3h" or "21d"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+func fetchStale(s workspace.RepoStatus) bool {
+	return !s.LastFetch.IsZero() && time.Since(s.LastFetch) > StaleFetchAge
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	if strings.HasSuffix(noun, "sh") {
+		return fmt.Sprintf("%d %ses", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
 }
 
 func formatElapsed(d time.Duration) string {
@@ -2,6 +2,7 @@ package output
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"os"
 	"strings"
@@ -319,3 +320,82 @@ func TestPrintRepoLine(t *testing.T) {
 		t.Error("Should contain ahead count")
 	}
 }
+
+func TestRenderStatusMissing(t *testing.T) {
+	statuses := []workspace.RepoStatus{
+		{RepoInfo: workspace.RepoInfo{Name: "clean", Stack: []string{"go"}}, Branch: "main"},
+		{RepoInfo: workspace.RepoInfo{Name: "gone", Missing: true}},
+	}
+
+	output := captureOutput(func() {
+		RenderStatus(statuses, 0, false, "/tmp/code")
+	})
+
+	if !strings.Contains(output, "gone") || !strings.Contains(output, "missing") {
+		t.Errorf("missing repo should need attention, got:\n%s", output)
+	}
+	if strings.Contains(output, "  clean ") {
+		t.Error("clean repo should be hidden without --all")
+	}
+}
+
+func TestRenderStatusError(t *testing.T) {
+	statuses := []workspace.RepoStatus{
+		{RepoInfo: workspace.RepoInfo{Name: "broken"}, Error: errors.New("git status: not a git repository")},
+	}
+
+	output := captureOutput(func() {
+		RenderStatus(statuses, 0, false, "/tmp/code")
+	})
+
+	if !strings.Contains(output, "broken") || !strings.Contains(output, "✗ error: git status: not a git repository") {
+		t.Errorf("repo with an error should need attention, got:\n%s", output)
+	}
+}
+
+func TestAttentionReasons(t *testing.T) {
+	tests := []struct {
+		name   string
+		status workspace.RepoStatus
+		want   []string
+	}{
+		{"clean", workspace.RepoStatus{Behind: 3, LastFetch: time.Now()}, nil},
+		{"dirty and ahead", workspace.RepoStatus{DirtyFiles: 2, Modified: 2, Ahead: 1}, []string{"uncommitted changes", "unpushed commits"}},
+		{"stashes", workspace.RepoStatus{Stashes: 2}, []string{"2 stashes"}},
+		{"rebase with conflicts", workspace.RepoStatus{Operation: "rebase", DirtyFiles: 1, Conflicts: 1}, []string{"rebase in progress", "1 conflict"}},
+		{"stale fetch", workspace.RepoStatus{LastFetch: time.Now().Add(-30 * 24 * time.Hour)}, []string{"stale fetch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AttentionReasons(tt.status)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("AttentionReasons() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderStatusDetails(t *testing.T) {
+	statuses := []workspace.RepoStatus{
+		{
+			RepoInfo:   workspace.RepoInfo{Name: "busy", Stack: []string{"go"}},
+			Branch:     "feature",
+			DirtyFiles: 4, Staged: 1, Modified: 1, Untracked: 1, Conflicts: 1,
+			Operation: "rebase",
+			Stashes:   1,
+			LastFetch: time.Now().Add(-21 * 24 * time.Hour),
+		},
+		{RepoInfo: workspace.RepoInfo{Name: "stashed"}, Stashes: 1, LastFetch: time.Now().Add(-3*time.Hour - time.Minute)},
+	}
+
+	output := captureOutput(func() {
+		RenderStatus(statuses, 0, false, "/tmp/code")
+	})
+
+	for _, want := range []string{"REBASE IN PROGRESS", "1 conflict", "1 staged 1 modified 1 untracked", "1 stash", "fetched 21d ago", "stashed", "fetched 3h ago"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,1435 @@
+package output
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/advisory"
+	"github.com/sloanahrens/devbot-go/internal/branch"
+	"github.com/sloanahrens/devbot-go/internal/check"
+	"github.com/sloanahrens/devbot-go/internal/config"
+	"github.com/sloanahrens/devbot-go/internal/deps"
+	"github.com/sloanahrens/devbot-go/internal/diff"
+	"github.com/sloanahrens/devbot-go/internal/graph"
+	"github.com/sloanahrens/devbot-go/internal/lastcommit"
+	"github.com/sloanahrens/devbot-go/internal/license"
+	"github.com/sloanahrens/devbot-go/internal/makefile"
+	"github.com/sloanahrens/devbot-go/internal/port"
+	"github.com/sloanahrens/devbot-go/internal/prereq"
+	"github.com/sloanahrens/devbot-go/internal/pull"
+	"github.com/sloanahrens/devbot-go/internal/pulumi"
+	"github.com/sloanahrens/devbot-go/internal/remote"
+	"github.com/sloanahrens/devbot-go/internal/reposync"
+	"github.com/sloanahrens/devbot-go/internal/runner"
+	"github.com/sloanahrens/devbot-go/internal/stats"
+	"github.com/sloanahrens/devbot-go/internal/todos"
+	"github.com/sloanahrens/devbot-go/internal/tree"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+	"github.com/sloanahrens/devbot-go/internal/worktrees"
+)
+
+// The types in this file are the stable JSON shapes for each command.
+// Errors are rendered as strings and all durations are in milliseconds.
+
+// RepoRef identifies a repository in JSON output
+type RepoRef struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+func repoRef(r workspace.RepoInfo) RepoRef {
+	return RepoRef{Name: r.Name, Path: r.Path}
+}
+
+// StatusView is the JSON shape for `devbot status`
+type StatusView struct {
+	Workspace string           `json:"workspace"`
+	Repos     []RepoStatusView `json:"repos"`
+	ShowAll   bool             `json:"-"` // Text lists up-to-date repos too
+
+	statuses []workspace.RepoStatus
+}
+
+// RepoStatusView is a single repo in StatusView
+type RepoStatusView struct {
+	RepoRef
+	Stack      []string `json:"stack"`
+	Branch     string   `json:"branch"`
+	DirtyFiles int      `json:"dirty_files"`
+	Staged     int      `json:"staged"`
+	Modified   int      `json:"modified"`
+	Untracked  int      `json:"untracked"`
+	Conflicts  int      `json:"conflicts"`
+	Stashes    int      `json:"stashes"`
+	Operation  string   `json:"operation,omitempty"`
+	Ahead      int      `json:"ahead"`
+	Behind     int      `json:"behind"`
+	LastFetch  string   `json:"last_fetch,omitempty"`
+	Missing    bool     `json:"missing"`
+	Attention  []string `json:"attention"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// NewStatusView builds the JSON view for repository statuses
+func NewStatusView(statuses []workspace.RepoStatus, workspacePath string) StatusView {
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	v := StatusView{Workspace: workspacePath, Repos: []RepoStatusView{}, statuses: statuses}
+	for _, s := range statuses {
+		rv := RepoStatusView{
+			RepoRef:    repoRef(s.RepoInfo),
+			Stack:      nonNil(s.Stack),
+			Branch:     s.Branch,
+			DirtyFiles: s.DirtyFiles,
+			Staged:     s.Staged,
+			Modified:   s.Modified,
+			Untracked:  s.Untracked,
+			Conflicts:  s.Conflicts,
+			Stashes:    s.Stashes,
+			Operation:  s.Operation,
+			Ahead:      s.Ahead,
+			Behind:     s.Behind,
+			Missing:    s.Missing,
+			Attention:  nonNil(AttentionReasons(s)),
+			Error:      errString(s.Error),
+		}
+		if !s.LastFetch.IsZero() {
+			rv.LastFetch = s.LastFetch.Format(time.RFC3339)
+		}
+		v.Repos = append(v.Repos, rv)
+	}
+	return v
+}
+
+// CheckView is the JSON shape for `devbot check`
+type CheckView struct {
+	Repo       RepoRef           `json:"repo"`
+	SubApps    []SubAppView      `json:"sub_apps"`
+	Checks     []CheckResultView `json:"checks"`
+	Summary    string            `json:"summary"`
+	Passed     bool              `json:"passed"`
+	DurationMS int64             `json:"duration_ms"`
+	Reports    []string          `json:"reports,omitempty"` // Report files written by --report
+	Prereq     *PrereqView       `json:"prereq,omitempty"`  // With --prereq
+
+	result check.Result
+}
+
+// SubAppView is a detected sub-application in CheckView
+type SubAppView struct {
+	Path  string   `json:"path"`
+	Stack []string `json:"stack"`
+}
+
+// CheckResultView is a single check in CheckView
+type CheckResultView struct {
+	Type       string          `json:"type"`
+	SubDir     string          `json:"sub_dir"`
+	Stack      string          `json:"stack"`
+	Command    string          `json:"command"`
+	Source     string          `json:"source"`
+	Status     string          `json:"status"`
+	DurationMS int64           `json:"duration_ms"`
+	Output     string          `json:"output,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Cached     bool            `json:"cached"`
+	Tests      *TestReportView `json:"tests,omitempty"`
+	Findings   []FindingView   `json:"findings,omitempty"`
+}
+
+// FindingView is a lint problem in CheckResultView
+type FindingView struct {
+	Tool    string `json:"tool"`
+	Rule    string `json:"rule,omitempty"`
+	Level   string `json:"level"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// TestReportView is the per-test breakdown of a test check
+type TestReportView struct {
+	Format     string         `json:"format"` // go, junit, jest, vitest or cargo
+	Passed     int            `json:"passed"`
+	Failed     int            `json:"failed"`
+	Skipped    int            `json:"skipped"`
+	DurationMS int64          `json:"duration_ms"`
+	Tests      []TestCaseView `json:"tests"`
+}
+
+// TestCaseView is a single test in TestReportView
+type TestCaseView struct {
+	Suite      string `json:"suite"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	File       string `json:"file,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Message    string `json:"message,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// newTestReportView converts a test report, or returns nil without one
+func newTestReportView(r *check.TestReport) *TestReportView {
+	if r == nil {
+		return nil
+	}
+	v := &TestReportView{
+		Format:     r.Format,
+		Passed:     r.Passed,
+		Failed:     r.Failed,
+		Skipped:    r.Skipped,
+		DurationMS: durationMS(r.Duration),
+		Tests:      []TestCaseView{},
+	}
+	for _, tc := range r.Tests {
+		v.Tests = append(v.Tests, TestCaseView{
+			Suite:      tc.Suite,
+			Name:       tc.Name,
+			Status:     tc.Status,
+			File:       tc.File,
+			Line:       tc.Line,
+			Message:    tc.Message,
+			DurationMS: durationMS(tc.Duration),
+		})
+	}
+	return v
+}
+
+// NewCheckView builds the JSON view for check results
+func NewCheckView(r check.Result) CheckView {
+	v := CheckView{
+		Repo:       repoRef(r.Repo),
+		SubApps:    []SubAppView{},
+		Checks:     []CheckResultView{},
+		Summary:    r.Summary(),
+		Passed:     r.Passed(),
+		DurationMS: durationMS(r.Duration),
+		result:     r,
+	}
+	for _, app := range r.SubApps {
+		v.SubApps = append(v.SubApps, SubAppView{Path: app.Path, Stack: nonNil(app.Stack)})
+	}
+	for _, c := range r.Checks {
+		v.Checks = append(v.Checks, CheckResultView{
+			Type:       string(c.Type),
+			SubDir:     c.SubDir,
+			Stack:      c.Stack,
+			Command:    c.Command,
+			Source:     c.Source,
+			Status:     c.Status,
+			DurationMS: durationMS(c.Duration),
+			Output:     c.Output,
+			Error:      errString(c.Error),
+			Cached:     c.Cached,
+			Tests:      newTestReportView(c.Tests),
+		})
+		for _, f := range c.Findings {
+			cv := &v.Checks[len(v.Checks)-1]
+			cv.Findings = append(cv.Findings, FindingView{
+				Tool:    f.Tool,
+				Rule:    f.Rule,
+				Level:   f.Level,
+				File:    f.File,
+				Line:    f.Line,
+				Column:  f.Column,
+				Message: f.Message,
+			})
+		}
+	}
+	return v
+}
+
+// NewPrereqFailedCheckView builds the check view for a run stopped by
+// failing prerequisites: no checks ran, and Prereq says why
+func NewPrereqFailedCheckView(repo workspace.RepoInfo, r *prereq.Result) CheckView {
+	prereqView := NewPrereqView(r)
+	return CheckView{
+		Repo:    repoRef(repo),
+		SubApps: []SubAppView{},
+		Checks:  []CheckResultView{},
+		Summary: "prereq checks failed",
+		Passed:  false,
+		Prereq:  &prereqView,
+	}
+}
+
+// CacheStatsView is the JSON shape for `devbot check --cache-stats`
+type CacheStatsView struct {
+	Dir     string `json:"dir"`
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+	Oldest  string `json:"oldest,omitempty"` // RFC 3339
+	Newest  string `json:"newest,omitempty"` // RFC 3339
+
+	stats check.CacheStats
+}
+
+// NewCacheStatsView builds the JSON view for check cache statistics
+func NewCacheStatsView(s check.CacheStats) CacheStatsView {
+	v := CacheStatsView{Dir: s.Dir, Entries: s.Entries, Bytes: s.Bytes, stats: s}
+	if !s.Oldest.IsZero() {
+		v.Oldest = s.Oldest.Format(time.RFC3339)
+		v.Newest = s.Newest.Format(time.RFC3339)
+	}
+	return v
+}
+
+// CacheClearView is the JSON shape for `devbot check --cache-clear`
+type CacheClearView struct {
+	Dir     string `json:"dir"`
+	Removed int    `json:"removed"`
+}
+
+// DiffView is the JSON shape for `devbot diff`
+type DiffView struct {
+	Repo      RepoRef          `json:"repo"`
+	Branch    string           `json:"branch"`
+	Staged    []FileChangeView `json:"staged"`
+	Unstaged  []FileChangeView `json:"unstaged"`
+	Additions int              `json:"additions"`
+	Deletions int              `json:"deletions"`
+	Error     string           `json:"error,omitempty"`
+	Full      bool             `json:"-"` // Text shows each file's diff
+}
+
+// FileChangeView is a single changed file in DiffView
+type FileChangeView struct {
+	Status    string `json:"status"`
+	Path      string `json:"path"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Content   string `json:"content,omitempty"`
+}
+
+// NewDiffView builds the JSON view for a diff result
+func NewDiffView(r diff.DiffResult) DiffView {
+	return DiffView{
+		Repo:      repoRef(r.Repo),
+		Branch:    r.Branch,
+		Staged:    fileChangeViews(r.Staged),
+		Unstaged:  fileChangeViews(r.Unstaged),
+		Additions: r.TotalAdditions(),
+		Deletions: r.TotalDeletions(),
+		Error:     errString(r.Error),
+	}
+}
+
+func fileChangeViews(changes []diff.FileChange) []FileChangeView {
+	out := []FileChangeView{}
+	for _, c := range changes {
+		out = append(out, FileChangeView{
+			Status:    c.Status,
+			Path:      c.Path,
+			Additions: c.Additions,
+			Deletions: c.Deletions,
+			Content:   c.Content,
+		})
+	}
+	return out
+}
+
+// BranchView is the JSON shape for `devbot branch`
+type BranchView struct {
+	Repo        RepoRef      `json:"repo"`
+	Branch      string       `json:"branch"`
+	Tracking    string       `json:"tracking"`
+	HasUpstream bool         `json:"has_upstream"`
+	Ahead       int          `json:"ahead"`
+	Behind      int          `json:"behind"`
+	Commits     []CommitView `json:"commits"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// CommitView is a single commit in BranchView
+type CommitView struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+}
+
+// NewBranchView builds the JSON view for a branch result
+func NewBranchView(r branch.BranchResult) BranchView {
+	v := BranchView{
+		Repo:        repoRef(r.Repo),
+		Branch:      r.Branch,
+		Tracking:    r.Tracking,
+		HasUpstream: r.HasUpstream,
+		Ahead:       r.Ahead,
+		Behind:      r.Behind,
+		Commits:     []CommitView{},
+		Error:       errString(r.Error),
+	}
+	for _, c := range r.Commits {
+		v.Commits = append(v.Commits, CommitView{Hash: c.Hash, Subject: c.Subject})
+	}
+	return v
+}
+
+// RemoteView is the JSON shape for `devbot remote`
+type RemoteView struct {
+	Repo    RepoRef          `json:"repo"`
+	Remotes []RemoteInfoView `json:"remotes"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// RemoteInfoView is a single git remote
+type RemoteInfoView struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	GitHub string `json:"github,omitempty"`
+}
+
+// NewRemoteView builds the JSON view for a remote result
+func NewRemoteView(r remote.RemoteResult) RemoteView {
+	v := RemoteView{Repo: repoRef(r.Repo), Remotes: []RemoteInfoView{}, Error: errString(r.Error)}
+	for _, rm := range r.Remotes {
+		v.Remotes = append(v.Remotes, RemoteInfoView{Name: rm.Name, URL: rm.URL, GitHub: rm.GitHub})
+	}
+	return v
+}
+
+// FindRepoView is the JSON shape for `devbot find-repo`
+type FindRepoView struct {
+	Identifier string          `json:"identifier"`
+	Found      bool            `json:"found"`
+	Repo       *RepoRef        `json:"repo,omitempty"`
+	Remote     *RemoteInfoView `json:"remote,omitempty"`
+}
+
+// NewFindRepoView builds the JSON view for a find-repo result
+func NewFindRepoView(identifier string, r remote.FindResult) FindRepoView {
+	v := FindRepoView{Identifier: identifier, Found: r.Found}
+	if r.Found {
+		ref := repoRef(r.Repo)
+		v.Repo = &ref
+		v.Remote = &RemoteInfoView{Name: r.Remote.Name, URL: r.Remote.URL, GitHub: r.Remote.GitHub}
+	}
+	return v
+}
+
+// DepsView is the JSON shape for `devbot deps`
+type DepsView struct {
+	Repos      []RepoDepsView `json:"repos"`
+	Usage      []DepUsageView `json:"usage"`
+	Count      bool           `json:"-"` // Text shows per-repo counts only
+	All        bool           `json:"-"` // Text lists every dependency, not just shared ones
+	Transitive bool           `json:"-"` // Text includes transitive dependencies
+
+	results []deps.RepoDeps
+}
+
+// RepoDepsView holds one repo's dependencies
+type RepoDepsView struct {
+	Repo         RepoRef          `json:"repo"`
+	Dependencies []DependencyView `json:"dependencies"`
+	Lockfiles    []string         `json:"lockfiles"`
+	Error        string           `json:"error,omitempty"`
+}
+
+// DependencyView is a single dependency
+type DependencyView struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Resolved   string `json:"resolved,omitempty"`
+	Dev        bool   `json:"dev"`
+	Transitive bool   `json:"transitive"`
+	Indirect   bool   `json:"indirect"`
+	Replace    string `json:"replace,omitempty"`
+	Ecosystem  string `json:"ecosystem"`
+}
+
+// DepUsageView lists the repos that use a dependency directly, and the
+// versions each of them has installed
+type DepUsageView struct {
+	Name      string              `json:"name"`
+	Ecosystem string              `json:"ecosystem"`
+	Repos     []string            `json:"repos"`
+	Versions  map[string][]string `json:"versions"`
+}
+
+// NewDepsView builds the JSON view for dependency analysis.
+// Usage is sorted by number of repos (descending), then name.
+func NewDepsView(results []deps.RepoDeps) DepsView {
+	v := DepsView{Repos: []RepoDepsView{}, Usage: []DepUsageView{}, results: results}
+
+	for _, r := range results {
+		rv := RepoDepsView{Repo: repoRef(r.Repo), Dependencies: []DependencyView{}, Lockfiles: nonNil(r.Lockfiles), Error: errString(r.Error)}
+		sorted := append([]deps.Dependency(nil), r.Dependencies...)
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].Transitive != sorted[j].Transitive {
+				return !sorted[i].Transitive
+			}
+			if sorted[i].Name != sorted[j].Name {
+				return sorted[i].Name < sorted[j].Name
+			}
+			return deps.CompareVersions(sorted[i].InUse(), sorted[j].InUse()) < 0
+		})
+		for _, d := range sorted {
+			rv.Dependencies = append(rv.Dependencies, DependencyView{
+				Name:       d.Name,
+				Version:    d.Version,
+				Resolved:   d.Resolved,
+				Dev:        d.Dev,
+				Transitive: d.Transitive,
+				Indirect:   d.Indirect,
+				Replace:    d.Replace,
+				Ecosystem:  d.Ecosystem,
+			})
+		}
+		v.Repos = append(v.Repos, rv)
+	}
+
+	for _, u := range deps.Aggregate(results, false) {
+		v.Usage = append(v.Usage, DepUsageView{Name: u.Name, Ecosystem: u.Ecosystem, Repos: u.Repos, Versions: u.Versions})
+	}
+
+	return v
+}
+
+// DriftView is the JSON shape for `devbot deps --drift`
+type DriftView struct {
+	FailOn   string         `json:"fail_on"`
+	Drift    []DepDriftView `json:"drift"`
+	Exceeded int            `json:"exceeded"`
+}
+
+// DepDriftView is one shared dependency used at several versions
+type DepDriftView struct {
+	Name      string             `json:"name"`
+	Ecosystem string             `json:"ecosystem"`
+	Level     string             `json:"level"`
+	Target    string             `json:"target"`
+	Exceeds   bool               `json:"exceeds_policy"`
+	Versions  []VersionGroupView `json:"versions"`
+}
+
+// VersionGroupView lists the repos on one version
+type VersionGroupView struct {
+	Version string   `json:"version"`
+	Repos   []string `json:"repos"`
+}
+
+// NewDriftView builds the JSON view for a drift report
+func NewDriftView(drifts []deps.Drift, policy deps.DriftPolicy) DriftView {
+	v := DriftView{FailOn: policy.FailOn.String(), Drift: []DepDriftView{}}
+	for _, d := range drifts {
+		dv := DepDriftView{Name: d.Name, Ecosystem: d.Ecosystem, Level: d.Level.String(), Target: d.Target, Exceeds: d.Exceeds}
+		for _, g := range d.Groups {
+			dv.Versions = append(dv.Versions, VersionGroupView{Version: g.Version, Repos: g.Repos})
+		}
+		if d.Exceeds {
+			v.Exceeded++
+		}
+		v.Drift = append(v.Drift, dv)
+	}
+	return v
+}
+
+// AuditView is the JSON shape for `devbot deps --audit`
+type AuditView struct {
+	Database    string             `json:"database"`
+	Advisories  int                `json:"advisories"`
+	ImportedAt  string             `json:"imported_at,omitempty"`
+	MinSeverity string             `json:"min_severity"`
+	Checked     int                `json:"checked"`
+	Unresolved  int                `json:"unresolved"`
+	Counts      map[string]int     `json:"counts"`
+	Findings    []AuditFindingView `json:"findings"`
+
+	importedAt time.Time
+}
+
+// AuditFindingView is one advisory affecting one repo's dependency
+type AuditFindingView struct {
+	Repo       string   `json:"repo"`
+	Package    string   `json:"package"`
+	Replaces   string   `json:"replaces,omitempty"` // Go: the required module a replace swapped out
+	Ecosystem  string   `json:"ecosystem"`
+	Version    string   `json:"version"`
+	Transitive bool     `json:"transitive"`
+	ID         string   `json:"id"`
+	Aliases    []string `json:"aliases"`
+	Summary    string   `json:"summary"`
+	Severity   string   `json:"severity"`
+	FixedIn    string   `json:"fixed_in,omitempty"`
+}
+
+// NewAuditView builds the JSON view for an audit report
+func NewAuditView(db *advisory.DB, meta advisory.Meta, report advisory.Report, minLevel advisory.Level) AuditView {
+	v := AuditView{
+		Database:    db.Dir,
+		Advisories:  db.Advisories,
+		MinSeverity: minLevel.String(),
+		Checked:     report.Checked,
+		Unresolved:  report.Unresolved,
+		Counts:      map[string]int{},
+		Findings:    []AuditFindingView{},
+		importedAt:  meta.ImportedAt,
+	}
+	if !meta.ImportedAt.IsZero() {
+		v.ImportedAt = meta.ImportedAt.Format(time.RFC3339)
+	}
+	for _, f := range report.Findings {
+		v.Counts[f.Severity.String()]++
+		v.Findings = append(v.Findings, AuditFindingView{
+			Repo:       f.Repo,
+			Package:    f.Package,
+			Replaces:   replaced(f),
+			Ecosystem:  f.Dependency.Ecosystem,
+			Version:    f.Version,
+			Transitive: !f.Dependency.Direct(),
+			ID:         f.Advisory.ID,
+			Aliases:    nonNil(f.Advisory.Aliases),
+			Summary:    f.Advisory.Summary,
+			Severity:   f.Severity.String(),
+			FixedIn:    f.FixedIn(),
+		})
+	}
+	return v
+}
+
+// replaced returns the module a finding's package replaces, if any
+func replaced(f advisory.Finding) string {
+	if f.Package == f.Dependency.Name {
+		return ""
+	}
+	return f.Dependency.Name
+}
+
+// AdvisoryDBView is the JSON shape for `devbot advisories`
+type AdvisoryDBView struct {
+	Database   string   `json:"database"`
+	Advisories int      `json:"advisories"`
+	ImportedAt string   `json:"imported_at,omitempty"` // RFC 3339
+	Sources    []string `json:"sources"`
+
+	importedAt time.Time
+}
+
+// NewAdvisoryDBView builds the JSON view for the advisory database
+func NewAdvisoryDBView(db *advisory.DB, meta advisory.Meta) AdvisoryDBView {
+	v := AdvisoryDBView{Database: db.Dir, Advisories: db.Advisories, Sources: nonNil(meta.Sources), importedAt: meta.ImportedAt}
+	if !meta.ImportedAt.IsZero() {
+		v.ImportedAt = meta.ImportedAt.Format(time.RFC3339)
+	}
+	return v
+}
+
+// AdvisoryImportsView is the JSON shape for `devbot advisories import`: a
+// list with one AdvisoryImportView per source imported
+type AdvisoryImportsView struct {
+	Database string
+	Imports  []AdvisoryImportView
+}
+
+// MarshalJSON renders the imports as a bare list
+func (v AdvisoryImportsView) MarshalJSON() ([]byte, error) {
+	return jsonArray(v.Imports)
+}
+
+// AdvisoryImportView is one source imported into the advisory database
+type AdvisoryImportView struct {
+	Database    string `json:"database"`
+	Source      string `json:"source"`
+	Imported    int    `json:"imported"`
+	Unchanged   int    `json:"unchanged"`
+	Unsupported int    `json:"unsupported"`
+	Invalid     int    `json:"invalid"`
+}
+
+// SBOMFilesView is the JSON shape for `devbot sbom`: a list with one
+// SBOMFileView per file written
+type SBOMFilesView struct {
+	Dir   string
+	Files []SBOMFileView
+}
+
+// MarshalJSON renders the files as a bare list
+func (v SBOMFilesView) MarshalJSON() ([]byte, error) {
+	return jsonArray(v.Files)
+}
+
+// SBOMFileView is one file written by `devbot sbom`
+type SBOMFileView struct {
+	Repo       string `json:"repo"`
+	Format     string `json:"format"`
+	Path       string `json:"path"`
+	Commit     string `json:"commit,omitempty"`
+	Components int    `json:"components"`
+}
+
+// GraphView is the JSON shape for `devbot graph`
+type GraphView struct {
+	Repos []GraphRepoView `json:"repos"`
+	Edges []GraphEdgeView `json:"edges"`
+	Focus string          `json:"-"` // Text shows only this repo's links
+
+	graph *graph.Graph
+}
+
+// GraphRepoView is one repo and what it provides
+type GraphRepoView struct {
+	Name     string         `json:"name"`
+	Provides []ProvidedView `json:"provides"`
+}
+
+// ProvidedView is a module or package a repo declares
+type ProvidedView struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+	Dir       string `json:"dir"`
+}
+
+// GraphEdgeView means from depends on to
+type GraphEdgeView struct {
+	From  string          `json:"from"`
+	To    string          `json:"to"`
+	Dev   bool            `json:"dev"`
+	Links []GraphLinkView `json:"links"`
+}
+
+// GraphLinkView is a dependency that creates an edge
+type GraphLinkView struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+	Version   string `json:"version,omitempty"`
+	Local     bool   `json:"local"`
+	Dev       bool   `json:"dev"`
+}
+
+// NewGraphView converts a graph to its JSON view
+func NewGraphView(g *graph.Graph) GraphView {
+	v := GraphView{Repos: []GraphRepoView{}, Edges: []GraphEdgeView{}, graph: g}
+	for _, name := range g.Repos {
+		rv := GraphRepoView{Name: name, Provides: []ProvidedView{}}
+		for _, p := range g.Provides[name] {
+			rv.Provides = append(rv.Provides, ProvidedView{Name: p.Name, Ecosystem: p.Ecosystem, Dir: p.Dir})
+		}
+		v.Repos = append(v.Repos, rv)
+	}
+	for _, e := range g.Edges {
+		ev := GraphEdgeView{From: e.From, To: e.To, Dev: e.Dev()}
+		for _, l := range e.Links {
+			ev.Links = append(ev.Links, GraphLinkView{Name: l.Name, Ecosystem: l.Ecosystem, Version: l.Version, Local: l.Local, Dev: l.Dev})
+		}
+		v.Edges = append(v.Edges, ev)
+	}
+	return v
+}
+
+// DependentsView is the JSON shape for `devbot graph --dependents`
+type DependentsView struct {
+	Repo       string          `json:"repo"`
+	Dependents []DependentView `json:"dependents"`
+}
+
+// DependentView is a repo affected by a change to the target
+type DependentView struct {
+	Repo  string `json:"repo"`
+	Depth int    `json:"depth"`
+	Via   string `json:"via"`
+}
+
+// NewDependentsView converts graph dependents to their JSON view
+func NewDependentsView(repo string, dependents []graph.Dependent) DependentsView {
+	v := DependentsView{Repo: repo, Dependents: []DependentView{}}
+	for _, d := range dependents {
+		v.Dependents = append(v.Dependents, DependentView{Repo: d.Repo, Depth: d.Depth, Via: d.Via})
+	}
+	return v
+}
+
+// LicensesView is the JSON shape for `devbot licenses`
+type LicensesView struct {
+	Checked    int                  `json:"checked"`
+	Counts     map[string]int       `json:"counts"` // SPDX expression -> packages
+	Violations int                  `json:"violations"`
+	Unknown    int                  `json:"unknown"`
+	Packages   []LicensePackageView `json:"packages"`
+
+	all           bool
+	failOnUnknown bool
+}
+
+// LicensePackageView is one dependency of one repo and its license
+type LicensePackageView struct {
+	Repo      string `json:"repo"`
+	Package   string `json:"package"`
+	Ecosystem string `json:"ecosystem"`
+	Version   string `json:"version"`
+	Dev       bool   `json:"dev"`
+	Declared  string `json:"declared,omitempty"`
+	License   string `json:"license,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	Violation bool   `json:"violation"`
+}
+
+// NewLicensesView builds the JSON view for a license report. Only
+// violations and unknown licenses are listed unless all is set.
+func NewLicensesView(report *license.Report, all bool) LicensesView {
+	v := LicensesView{Counts: map[string]int{}, Packages: []LicensePackageView{}, all: all, failOnUnknown: report.FailOnUnknown}
+	for _, p := range report.Packages {
+		violation := report.Violation(p)
+		if p.Status != license.StatusIgnored {
+			v.Checked++
+		}
+		if p.License != "" {
+			v.Counts[p.License]++
+		}
+		if violation {
+			v.Violations++
+		}
+		if p.Status == license.StatusUnknown {
+			v.Unknown++
+		}
+		if !all && !violation && p.Status != license.StatusUnknown {
+			continue
+		}
+		v.Packages = append(v.Packages, LicensePackageView{
+			Repo:      p.Repo,
+			Package:   p.Name,
+			Ecosystem: p.Ecosystem,
+			Version:   p.Version,
+			Dev:       p.Dev,
+			Declared:  p.Declared,
+			License:   p.License,
+			Source:    p.Source,
+			Status:    string(p.Status),
+			Reason:    p.Reason,
+			Violation: violation,
+		})
+	}
+	return v
+}
+
+// TodosView is the JSON shape for `devbot todos`
+type TodosView struct {
+	Repos []RepoTodosView `json:"repos"`
+	Count bool            `json:"-"` // Text shows per-repo counts only
+}
+
+// RepoTodosView holds one repo's TODO items
+type RepoTodosView struct {
+	Repo   RepoRef        `json:"repo"`
+	Items  []TodoItemView `json:"items"`
+	Counts map[string]int `json:"counts"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// TodoItemView is a single TODO/FIXME comment
+type TodoItemView struct {
+	File    string `json:"file"`
+	RelPath string `json:"rel_path"`
+	Line    int    `json:"line"`
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+}
+
+// NewTodosView builds the JSON view for TODO scan results
+func NewTodosView(results []todos.RepoTodos) TodosView {
+	v := TodosView{Repos: []RepoTodosView{}}
+	for _, r := range results {
+		rv := RepoTodosView{
+			Repo:   repoRef(r.Repo),
+			Items:  []TodoItemView{},
+			Counts: todos.CountByType(r.Items),
+			Error:  errString(r.Error),
+		}
+		for _, item := range r.Items {
+			rv.Items = append(rv.Items, TodoItemView{
+				File:    item.File,
+				RelPath: item.RelPath,
+				Line:    item.Line,
+				Type:    item.Type,
+				Text:    item.Text,
+			})
+		}
+		v.Repos = append(v.Repos, rv)
+	}
+	return v
+}
+
+// ConfigView is the JSON shape for `devbot config`
+type ConfigView struct {
+	Repos []RepoConfigView `json:"repos"`
+}
+
+// RepoConfigView holds one repo's config files
+type RepoConfigView struct {
+	Repo  RepoRef          `json:"repo"`
+	Files []ConfigFileView `json:"files"`
+	Error string           `json:"error,omitempty"`
+}
+
+// ConfigFileView is a single config file
+type ConfigFileView struct {
+	Name    string `json:"name"`
+	RelPath string `json:"rel_path"`
+	Type    string `json:"type"`
+}
+
+// NewConfigView builds the JSON view for config scan results
+func NewConfigView(results []config.RepoConfig) ConfigView {
+	v := ConfigView{Repos: []RepoConfigView{}}
+	for _, r := range results {
+		rv := RepoConfigView{Repo: repoRef(r.Repo), Files: []ConfigFileView{}, Error: errString(r.Error)}
+		for _, f := range r.Files {
+			rv.Files = append(rv.Files, ConfigFileView{Name: f.Name, RelPath: f.RelPath, Type: f.Type})
+		}
+		v.Repos = append(v.Repos, rv)
+	}
+	return v
+}
+
+// MakeView is the JSON shape for `devbot make`
+type MakeView struct {
+	Repos      []RepoMakefileView `json:"repos"`
+	Detailed   bool               `json:"-"` // Text groups each repo's targets by category
+	AllTargets bool               `json:"-"` // Text lists every target, not just the first few
+
+	results []makefile.RepoMakefile
+}
+
+// RepoMakefileView holds one repo's Makefile targets
+type RepoMakefileView struct {
+	Repo    RepoRef      `json:"repo"`
+	Path    string       `json:"path"`
+	Targets []TargetView `json:"targets"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// TargetView is a single Makefile target
+type TargetView struct {
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Description string `json:"description,omitempty"`
+	Phony       bool   `json:"phony"`
+}
+
+// NewMakeView builds the JSON view for Makefile scan results
+func NewMakeView(results []makefile.RepoMakefile) MakeView {
+	v := MakeView{Repos: []RepoMakefileView{}, results: results}
+	for _, r := range results {
+		rv := RepoMakefileView{Repo: repoRef(r.Repo), Path: r.Path, Targets: []TargetView{}, Error: errString(r.Error)}
+		for _, t := range r.Targets {
+			rv.Targets = append(rv.Targets, TargetView{
+				Name:        t.Name,
+				Category:    t.Category,
+				Description: t.Description,
+				Phony:       t.IsPhony,
+			})
+		}
+		v.Repos = append(v.Repos, rv)
+	}
+	return v
+}
+
+// WorktreesView is the JSON shape for `devbot worktrees`
+type WorktreesView struct {
+	Repos []RepoWorktreesView `json:"repos"`
+}
+
+// RepoWorktreesView holds one repo's worktrees
+type RepoWorktreesView struct {
+	Repo      RepoRef        `json:"repo"`
+	Worktrees []WorktreeView `json:"worktrees"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// WorktreeView is a single git worktree
+type WorktreeView struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Branch     string `json:"branch"`
+	DirtyFiles int    `json:"dirty_files"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NewWorktreesView builds the JSON view for worktree scan results
+func NewWorktreesView(results []worktrees.RepoWorktrees) WorktreesView {
+	v := WorktreesView{Repos: []RepoWorktreesView{}}
+	for _, r := range results {
+		rv := RepoWorktreesView{Repo: repoRef(r.Repo), Worktrees: []WorktreeView{}, Error: errString(r.Error)}
+		for _, wt := range r.Worktrees {
+			rv.Worktrees = append(rv.Worktrees, WorktreeView{
+				Name:       wt.Name,
+				Path:       wt.Path,
+				Branch:     wt.Branch,
+				DirtyFiles: wt.DirtyFiles,
+				Error:      errString(wt.Error),
+			})
+		}
+		v.Repos = append(v.Repos, rv)
+	}
+	return v
+}
+
+// PulumiView is the JSON shape for `devbot pulumi`
+type PulumiView struct {
+	Repo     RepoRef           `json:"repo"`
+	Projects []PulumiStateView `json:"projects"`
+}
+
+// PulumiStateView is the state of a single Pulumi project directory
+type PulumiStateView struct {
+	Dir           string   `json:"dir"`
+	HasPulumiYaml bool     `json:"has_pulumi_yaml"`
+	Stacks        []string `json:"stacks"`
+	CurrentStack  string   `json:"current_stack"`
+	ResourceCount int      `json:"resource_count"`
+	HasInfra      bool     `json:"has_infra"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// NewPulumiView builds the JSON view for Pulumi state results
+func NewPulumiView(repo workspace.RepoInfo, results []pulumi.StateResult) PulumiView {
+	v := PulumiView{Repo: repoRef(repo), Projects: []PulumiStateView{}}
+	for _, r := range results {
+		if !r.HasPulumiYaml {
+			continue
+		}
+		v.Projects = append(v.Projects, PulumiStateView{
+			Dir:           r.PulumiDir,
+			HasPulumiYaml: r.HasPulumiYaml,
+			Stacks:        nonNil(r.Stacks),
+			CurrentStack:  r.CurrentStack,
+			ResourceCount: r.ResourceCount,
+			HasInfra:      r.HasInfra,
+			Error:         errString(r.Error),
+		})
+	}
+	return v
+}
+
+// PrereqView is the JSON shape for `devbot prereq`
+type PrereqView struct {
+	Path   string            `json:"path"`
+	Stack  []string          `json:"stack"`
+	Checks []PrereqCheckView `json:"checks"`
+	Passed bool              `json:"passed"`
+
+	result *prereq.Result
+}
+
+// PrereqCheckView is a single prerequisite check
+type PrereqCheckView struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// NewPrereqView builds the JSON view for prerequisite results
+func NewPrereqView(r *prereq.Result) PrereqView {
+	v := PrereqView{Path: r.Path, Stack: nonNil(r.Stack), Checks: []PrereqCheckView{}, Passed: r.Passed(), result: r}
+	for _, c := range r.Checks {
+		v.Checks = append(v.Checks, PrereqCheckView{Name: c.Name, Status: c.Status.String(), Detail: c.Detail})
+	}
+	return v
+}
+
+// FileStatsView is the JSON shape for `devbot stats <file>`
+type FileStatsView struct {
+	Path         string         `json:"path"`
+	Language     string         `json:"language"`
+	TotalLines   int            `json:"total_lines"`
+	CodeLines    int            `json:"code_lines"`
+	CommentLines int            `json:"comment_lines"`
+	BlankLines   int            `json:"blank_lines"`
+	Imports      int            `json:"imports"`
+	MaxNesting   int            `json:"max_nesting"`
+	Functions    []FunctionView `json:"functions"`
+}
+
+// FunctionView is a single function in FileStatsView
+type FunctionView struct {
+	Name  string `json:"name"`
+	Line  int    `json:"line"`
+	Lines int    `json:"lines"`
+}
+
+// NewFileStatsView builds the JSON view for single-file stats
+func NewFileStatsView(fs stats.FileStats) FileStatsView {
+	v := FileStatsView{
+		Path:         fs.Path,
+		Language:     fs.Language,
+		TotalLines:   fs.TotalLines,
+		CodeLines:    fs.CodeLines,
+		CommentLines: fs.CommentLines,
+		BlankLines:   fs.BlankLines,
+		Imports:      fs.Imports,
+		MaxNesting:   fs.MaxNesting,
+		Functions:    []FunctionView{},
+	}
+	for _, fn := range fs.Functions {
+		v.Functions = append(v.Functions, FunctionView{Name: fn.Name, Line: fn.Line, Lines: fn.Lines})
+	}
+	return v
+}
+
+// DirStatsView is the JSON shape for `devbot stats <dir>`
+type DirStatsView struct {
+	Path           string         `json:"path"`
+	TotalFiles     int            `json:"total_files"`
+	TotalLines     int            `json:"total_lines"`
+	CodeLines      int            `json:"code_lines"`
+	CommentLines   int            `json:"comment_lines"`
+	BlankLines     int            `json:"blank_lines"`
+	TotalFunctions int            `json:"total_functions"`
+	AvgFuncLength  int            `json:"avg_func_length"`
+	LargeFiles     []FileRefView  `json:"large_files"`
+	LongFunctions  []LongFuncView `json:"long_functions"`
+	DeepNesting    []FileRefView  `json:"deep_nesting"`
+}
+
+// FileRefView is a flagged file in DirStatsView
+type FileRefView struct {
+	Path       string `json:"path"`
+	TotalLines int    `json:"total_lines"`
+	MaxNesting int    `json:"max_nesting"`
+}
+
+// LongFuncView is a flagged function in DirStatsView
+type LongFuncView struct {
+	File  string `json:"file"`
+	Name  string `json:"name"`
+	Line  int    `json:"line"`
+	Lines int    `json:"lines"`
+}
+
+// NewDirStatsView builds the JSON view for directory stats
+func NewDirStatsView(ds stats.DirStats) DirStatsView {
+	v := DirStatsView{
+		Path:           ds.Path,
+		TotalFiles:     ds.TotalFiles,
+		TotalLines:     ds.TotalLines,
+		CodeLines:      ds.CodeLines,
+		CommentLines:   ds.CommentLines,
+		BlankLines:     ds.BlankLines,
+		TotalFunctions: ds.TotalFunctions,
+		AvgFuncLength:  ds.AvgFuncLength,
+		LargeFiles:     fileRefViews(ds.LargeFiles),
+		LongFunctions:  []LongFuncView{},
+		DeepNesting:    fileRefViews(ds.DeepNesting),
+	}
+	for _, lf := range ds.LongFunctions {
+		v.LongFunctions = append(v.LongFunctions, LongFuncView{
+			File:  lf.File,
+			Name:  lf.Function.Name,
+			Line:  lf.Function.Line,
+			Lines: lf.Function.Lines,
+		})
+	}
+	return v
+}
+
+func fileRefViews(files []stats.FileStats) []FileRefView {
+	out := []FileRefView{}
+	for _, f := range files {
+		out = append(out, FileRefView{Path: f.Path, TotalLines: f.TotalLines, MaxNesting: f.MaxNesting})
+	}
+	return out
+}
+
+// LastCommitView is the JSON shape for `devbot last-commit`
+type LastCommitView struct {
+	Repo        RepoRef `json:"repo"`
+	File        string  `json:"file,omitempty"`
+	Hash        string  `json:"hash"`
+	Subject     string  `json:"subject"`
+	Author      string  `json:"author"`
+	Date        string  `json:"date,omitempty"` // RFC 3339
+	RelativeAge string  `json:"relative_age"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// NewLastCommitView builds the JSON view for a last-commit result
+func NewLastCommitView(r lastcommit.Result) LastCommitView {
+	v := LastCommitView{
+		Repo:        repoRef(r.Repo),
+		File:        r.File,
+		Hash:        r.Hash,
+		Subject:     r.Subject,
+		Author:      r.Author,
+		RelativeAge: r.RelativeAge,
+		Error:       errString(r.Error),
+	}
+	if !r.Date.IsZero() {
+		v.Date = r.Date.Format(time.RFC3339)
+	}
+	return v
+}
+
+// RunView is the JSON shape for `devbot run`
+type RunView struct {
+	Command []string        `json:"command"`
+	Results []RunResultView `json:"results"`
+	Stream  bool            `json:"-"` // Output was streamed; text shows a summary table
+	Quiet   bool            `json:"-"` // Text skips repos with no output
+
+	results []runner.Result
+}
+
+// RunResultView is the outcome of a command in a single repo
+type RunResultView struct {
+	Repo       RepoRef `json:"repo"`
+	Status     string  `json:"status"`
+	ExitCode   int     `json:"exit_code"`
+	DurationMS int64   `json:"duration_ms"`
+	Output     string  `json:"output"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// NewRunView builds the JSON view for parallel run results
+func NewRunView(command []string, results []runner.Result) RunView {
+	v := RunView{Command: command, Results: []RunResultView{}, results: results}
+	for _, r := range results {
+		v.Results = append(v.Results, RunResultView{
+			Repo:       repoRef(r.Repo),
+			Status:     string(r.Status),
+			ExitCode:   r.ExitCode,
+			DurationMS: durationMS(r.Duration),
+			Output:     r.Output,
+			Error:      errString(r.Error),
+		})
+	}
+	return v
+}
+
+// TreeView is the JSON shape for `devbot tree`
+type TreeView struct {
+	Name     string     `json:"name"`
+	IsDir    bool       `json:"is_dir"`
+	Children []TreeView `json:"children,omitempty"`
+
+	entry tree.Entry
+}
+
+// NewTreeView builds the JSON view for a directory tree
+func NewTreeView(e tree.Entry) TreeView {
+	v := TreeView{Name: e.Name, IsDir: e.IsDir, entry: e}
+	for _, c := range e.Children {
+		v.Children = append(v.Children, NewTreeView(c))
+	}
+	return v
+}
+
+// DetectView is the JSON shape for `devbot detect`
+type DetectView struct {
+	Path  string   `json:"path"`
+	Stack []string `json:"stack"`
+}
+
+// DetectExplainView is the JSON shape for `devbot detect --explain`
+type DetectExplainView struct {
+	Path    string           `json:"path"`
+	Stack   []string         `json:"stack"`
+	Dirs    []DetectDirView  `json:"dirs"`
+	Absent  []string         `json:"absent"`
+	SubApps []PlannedAppView `json:"sub_apps"`
+	Skipped []SkippedAppView `json:"skipped"`
+	Notes   []string         `json:"notes"`
+}
+
+// DetectDirView is one examined directory in DetectExplainView
+type DetectDirView struct {
+	Path    string              `json:"path"`
+	Pattern string              `json:"pattern,omitempty"`
+	Stacks  []StackEvidenceView `json:"stacks"`
+}
+
+// StackEvidenceView is how one stack fared in a directory
+type StackEvidenceView struct {
+	Stack    string   `json:"stack"`
+	Matched  bool     `json:"matched"`
+	Found    []string `json:"found"`
+	Missing  []string `json:"missing"`
+	Excluded []string `json:"excluded"`
+	Reason   string   `json:"reason"`
+}
+
+// PlannedAppView is a sub-app and the checks check would run for it
+type PlannedAppView struct {
+	Path   string             `json:"path"`
+	Stack  []string           `json:"stack"`
+	Checks []PlannedCheckView `json:"checks"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// PlannedCheckView is a check check would run (or skip)
+type PlannedCheckView struct {
+	Type    string `json:"type"`
+	Stack   string `json:"stack"`
+	Command string `json:"command"`
+	Source  string `json:"source"`
+	Skip    string `json:"skip,omitempty"`
+}
+
+// SkippedAppView is a directory with a stack that isn't a separate sub-app
+type SkippedAppView struct {
+	Path    string   `json:"path"`
+	Stack   []string `json:"stack"`
+	Reason  string   `json:"reason"`
+	Covered bool     `json:"covered"` // Checked by the root's workspace-wide run
+}
+
+// NewDetectExplainView builds the JSON view for detect --explain
+func NewDetectExplainView(e check.Explanation) DetectExplainView {
+	v := DetectExplainView{
+		Path:    e.Path,
+		Stack:   nonNil(e.Detected),
+		Dirs:    []DetectDirView{},
+		Absent:  nonNil(e.Absent),
+		SubApps: []PlannedAppView{},
+		Skipped: []SkippedAppView{},
+		Notes:   nonNil(e.Notes),
+	}
+	for _, d := range e.Dirs {
+		dv := DetectDirView{Path: d.Path, Pattern: d.Pattern, Stacks: []StackEvidenceView{}}
+		for _, ev := range d.Evidence {
+			dv.Stacks = append(dv.Stacks, StackEvidenceView{
+				Stack:    ev.Stack,
+				Matched:  ev.Matched,
+				Found:    nonNil(ev.Found),
+				Missing:  nonNil(ev.Missing),
+				Excluded: nonNil(ev.Excluded),
+				Reason:   ev.Reason(),
+			})
+		}
+		v.Dirs = append(v.Dirs, dv)
+	}
+	for _, app := range e.Apps {
+		av := PlannedAppView{Path: app.Path, Stack: nonNil(app.Stack), Checks: []PlannedCheckView{}, Error: errString(app.Error)}
+		for _, c := range app.Checks {
+			av.Checks = append(av.Checks, PlannedCheckView{
+				Type:    string(c.Type),
+				Stack:   c.Stack,
+				Command: c.Command,
+				Source:  c.Source,
+				Skip:    c.Skip,
+			})
+		}
+		v.SubApps = append(v.SubApps, av)
+	}
+	for _, sk := range e.Skipped {
+		v.Skipped = append(v.Skipped, SkippedAppView{Path: sk.Path, Stack: nonNil(sk.Stack), Reason: sk.Reason, Covered: sk.Covered})
+	}
+	return v
+}
+
+// PathView is the JSON shape for `devbot path`
+type PathView struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// PortView is the JSON shape for `devbot port`
+type PortView struct {
+	Port      int           `json:"port"`
+	Processes []ProcessView `json:"processes"`
+	Kill      bool          `json:"-"` // The processes were sent a kill signal
+}
+
+// ProcessView is a single process bound to a port
+type ProcessView struct {
+	PID     int    `json:"pid"`
+	Command string `json:"command"`
+	User    string `json:"user"`
+	Killed  bool   `json:"killed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NewPortView builds the JSON view for processes on a port
+func NewPortView(portNum int, processes []port.ProcessInfo) PortView {
+	v := PortView{Port: portNum, Processes: []ProcessView{}}
+	for _, p := range processes {
+		v.Processes = append(v.Processes, ProcessView{PID: p.PID, Command: p.Command, User: p.User})
+	}
+	return v
+}
+
+// SyncView is the JSON shape for `devbot sync`
+type SyncView struct {
+	Repos []SyncResultView `json:"repos"`
+
+	results []reposync.Result
+}
+
+// SyncResultView is the sync outcome for a single repo
+type SyncResultView struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	URL    string `json:"url,omitempty"`
+	Origin string `json:"origin,omitempty"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewSyncView builds the JSON view for sync results
+func NewSyncView(results []reposync.Result) SyncView {
+	v := SyncView{Repos: []SyncResultView{}, results: results}
+	for _, r := range results {
+		v.Repos = append(v.Repos, SyncResultView{
+			Name:   r.Name,
+			Path:   r.Path,
+			URL:    r.URL,
+			Origin: r.Origin,
+			Action: string(r.Action),
+			Error:  errString(r.Error),
+		})
+	}
+	return v
+}
+
+// PullView is the JSON shape for `devbot pull` and `devbot fetch`
+type PullView struct {
+	Repos []PullResultView `json:"repos"`
+	Title string           `json:"-"` // Text heading: "fetch" or "pull"
+
+	results []pull.Result
+}
+
+// PullResultView is the fetch/pull outcome for a single repo
+type PullResultView struct {
+	Repo     RepoRef `json:"repo"`
+	Branch   string  `json:"branch"`
+	Upstream string  `json:"upstream,omitempty"`
+	Outcome  string  `json:"outcome"`
+	Moved    int     `json:"moved"`
+	Behind   int     `json:"behind"`
+	Before   string  `json:"before,omitempty"`
+	After    string  `json:"after,omitempty"`
+	Reason   string  `json:"reason,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// NewPullView builds the JSON view for fetch/pull results
+func NewPullView(results []pull.Result) PullView {
+	v := PullView{Repos: []PullResultView{}, results: results}
+	for _, r := range results {
+		v.Repos = append(v.Repos, PullResultView{
+			Repo:     repoRef(r.Repo),
+			Branch:   r.Branch,
+			Upstream: r.Upstream,
+			Outcome:  string(r.Outcome),
+			Moved:    r.Moved,
+			Behind:   r.Behind,
+			Before:   r.Before,
+			After:    r.After,
+			Reason:   r.Reason,
+			Error:    errString(r.Error),
+		})
+	}
+	return v
+}
+
+// nonNil returns an empty slice instead of nil so JSON renders [] not null
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -7,6 +7,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/node"
+	"github.com/sloanahrens/devbot-go/internal/stack"
 )
 
 // envVarRegex matches environment variable definitions like FOO= or FOO_BAR=
@@ -127,17 +130,19 @@ func dirExists(path string) bool {
 }
 
 // checkDeps verifies dependencies are installed for the detected stack
-func checkDeps(path string, stack []string) Check {
+func checkDeps(path string, stacks []string) Check {
 	// Check based on detected stack (order matters for priority)
-	for _, s := range stack {
-		switch s {
-		case "ts", "js", "nextjs":
-			nodeModules := filepath.Join(path, "node_modules")
-			if dirExists(nodeModules) {
+	for _, s := range stacks {
+		if def, ok := stack.Default().Lookup(s); ok && def.JS {
+			// Workspaces may hoist node_modules to the root
+			tool := node.Detect(path, "")
+			if dirExists(filepath.Join(path, "node_modules")) || dirExists(filepath.Join(tool.Root, "node_modules")) {
 				return Check{Name: "deps", Status: Pass, Detail: "node_modules present"}
 			}
-			return Check{Name: "deps", Status: Fail, Detail: "run: npm install"}
+			return Check{Name: "deps", Status: Fail, Detail: "run: " + tool.Install()}
+		}
 
+		switch s {
 		case "go":
 			goSum := filepath.Join(path, "go.sum")
 			if fileExists(goSum) {
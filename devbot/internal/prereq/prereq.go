@@ -16,6 +16,19 @@ const (
 	Warn
 )
 
+// String returns the lowercase name of the status
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "pass"
+	case Fail:
+		return "fail"
+	case Warn:
+		return "warn"
+	}
+	return "unknown"
+}
+
 // Check represents a single prerequisite check result
 type Check struct {
 	Name   string
@@ -59,7 +72,7 @@ func Run(path string) *Result {
 	}
 
 	// Tool checks based on detected stack
-	result.Checks = append(result.Checks, checkTools(result.Stack)...)
+	result.Checks = append(result.Checks, checkTools(path, result.Stack)...)
 
 	// Dependency checks
 	result.Checks = append(result.Checks, checkDeps(path, result.Stack))
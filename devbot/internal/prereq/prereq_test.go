@@ -3,7 +3,10 @@ package prereq
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/node"
 )
 
 func TestCheckTool(t *testing.T) {
@@ -28,7 +31,7 @@ func TestCheckTool(t *testing.T) {
 
 func TestCheckTools(t *testing.T) {
 	// Go stack should check for 'go' binary
-	checks := checkTools([]string{"go"})
+	checks := checkTools(t.TempDir(), []string{"go"})
 	if len(checks) != 1 {
 		t.Errorf("Expected 1 check for go stack, got %d", len(checks))
 	}
@@ -37,24 +40,54 @@ func TestCheckTools(t *testing.T) {
 	}
 
 	// TypeScript stack should check node and npm
-	checks = checkTools([]string{"ts"})
+	checks = checkTools(t.TempDir(), []string{"ts"})
 	if len(checks) != 2 {
 		t.Errorf("Expected 2 checks for ts stack, got %d", len(checks))
 	}
 
 	// Multiple stacks should deduplicate
-	checks = checkTools([]string{"ts", "js", "nextjs"})
+	checks = checkTools(t.TempDir(), []string{"ts", "js", "nextjs"})
 	if len(checks) != 2 {
 		t.Errorf("Expected 2 unique checks for overlapping stacks, got %d", len(checks))
 	}
 
 	// Unknown stack should return no checks
-	checks = checkTools([]string{"unknown"})
+	checks = checkTools(t.TempDir(), []string{"unknown"})
 	if len(checks) != 0 {
 		t.Errorf("Expected 0 checks for unknown stack, got %d", len(checks))
 	}
 }
 
+func TestCheckToolsPackageManager(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"packageManager": "pnpm@8.15.0"}`), 0644)
+
+	var names []string
+	for _, c := range checkTools(dir, []string{"monorepo", "ts"}) {
+		names = append(names, c.Name)
+	}
+	if len(names) != 2 || names[0] != "node" || names[1] != "pnpm" {
+		t.Errorf("checkTools = %v, want [node pnpm]", names)
+	}
+}
+
+func TestCheckManager(t *testing.T) {
+	// go stands in for a manager binary that is installed
+	c := checkManager(node.Tool{Manager: "go", Version: "0.1.0", Source: "packageManager"})
+	if c.Status != Warn || !strings.Contains(c.Detail, "packageManager wants 0.1.0") {
+		t.Errorf("checkManager(mismatch) = %+v, want version warning", c)
+	}
+
+	c = checkManager(node.Tool{Manager: "definitely-not-a-real-pm", Source: "yarn.lock"})
+	if c.Status != Fail || c.Detail != "not found (yarn.lock uses definitely-not-a-real-pm)" {
+		t.Errorf("checkManager(missing) = %+v", c)
+	}
+
+	if got := majorVersion("v8.15.0"); got != "8" {
+		t.Errorf("majorVersion = %q, want 8", got)
+	}
+}
+
 func TestParseEnvVars(t *testing.T) {
 	// Create temp file
 	dir := t.TempDir()
@@ -166,6 +199,34 @@ func TestCheckDeps(t *testing.T) {
 		}
 	})
 
+	t.Run("node_modules missing with yarn", func(t *testing.T) {
+		os.WriteFile(filepath.Join(dir, "yarn.lock"), nil, 0644)
+		defer os.Remove(filepath.Join(dir, "yarn.lock"))
+
+		check := checkDeps(dir, []string{"js"})
+		if check.Status != Fail || check.Detail != "run: yarn install" {
+			t.Errorf("Expected yarn install hint, got %+v", check)
+		}
+	})
+
+	t.Run("node_modules at workspace root", func(t *testing.T) {
+		os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"workspaces": ["packages/*"]}`), 0644)
+		os.WriteFile(filepath.Join(dir, "package-lock.json"), nil, 0644)
+		os.Mkdir(filepath.Join(dir, "node_modules"), 0755)
+		pkg := filepath.Join(dir, "packages", "ui")
+		os.MkdirAll(pkg, 0755)
+		defer func() {
+			for _, name := range []string{"package.json", "package-lock.json", "node_modules", "packages"} {
+				os.RemoveAll(filepath.Join(dir, name))
+			}
+		}()
+
+		check := checkDeps(pkg, []string{"ts"})
+		if check.Status != Pass {
+			t.Errorf("Expected Pass with hoisted node_modules, got %+v", check)
+		}
+	})
+
 	t.Run("go.sum present", func(t *testing.T) {
 		goSum := filepath.Join(dir, "go.sum")
 		os.WriteFile(goSum, []byte("module deps"), 0644)
@@ -1,42 +1,75 @@
 package prereq
 
 import (
+	"fmt"
 	"os/exec"
 	"strings"
-)
 
-// toolsByStack maps stack types to required tool binaries
-var toolsByStack = map[string][]string{
-	"go":     {"go"},
-	"ts":     {"node", "npm"},
-	"js":     {"node", "npm"},
-	"nextjs": {"node", "npm"},
-	"python": {"python3", "pip3"},
-	"rust":   {"cargo"},
-}
+	"github.com/sloanahrens/devbot-go/internal/node"
+	"github.com/sloanahrens/devbot-go/internal/stack"
+)
 
-// checkTools verifies required tools exist for the detected stack
-func checkTools(stack []string) []Check {
+// checkTools verifies required tools exist for the detected stack. JS stacks
+// need the project's package manager in place of npm.
+func checkTools(path string, stacks []string) []Check {
 	var checks []Check
 	seen := make(map[string]bool)
 
-	for _, s := range stack {
-		tools, ok := toolsByStack[s]
+	reg := stack.Default()
+	var js *node.Tool
+	for _, s := range stacks {
+		def, ok := reg.Lookup(s)
 		if !ok {
 			continue
 		}
-		for _, tool := range tools {
+		for _, tool := range def.Tools {
+			manager := def.JS && tool == "npm"
+			if manager {
+				if js == nil {
+					t := node.Detect(path, "")
+					js = &t
+				}
+				tool = js.Binary()
+			}
 			if seen[tool] {
 				continue
 			}
 			seen[tool] = true
-			checks = append(checks, checkTool(tool))
+			if manager {
+				checks = append(checks, checkManager(*js))
+			} else {
+				checks = append(checks, checkTool(tool))
+			}
 		}
 	}
 
 	return checks
 }
 
+// checkManager verifies the package manager is installed, and that its
+// major version matches the packageManager pin in package.json
+func checkManager(t node.Tool) Check {
+	c := checkTool(t.Binary())
+	if t.Source == "default" {
+		return c
+	}
+	if c.Status == Fail {
+		c.Detail = fmt.Sprintf("not found (%s uses %s)", t.Source, t.Binary())
+		return c
+	}
+	if t.Version != "" && majorVersion(c.Detail) != majorVersion(t.Version) {
+		c.Status = Warn
+		c.Detail = fmt.Sprintf("%s, but packageManager wants %s", c.Detail, t.Version)
+	}
+	return c
+}
+
+// majorVersion returns the leading number of a version like "v8.15.0"
+func majorVersion(v string) string {
+	major, _, _ := strings.Cut(strings.TrimPrefix(v, "v"), ".")
+	return major
+}
+
 // checkTool verifies a single tool binary exists and gets its version
 func checkTool(name string) Check {
 	_, err := exec.LookPath(name)
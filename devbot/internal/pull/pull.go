@@ -0,0 +1,217 @@
+package pull
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/git"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// Outcome is what happened to a repo during fetch or pull
+type Outcome string
+
+const (
+	OutcomeUpdated  Outcome = "updated"    // Pull fast-forwarded / fetch brought new commits
+	OutcomeUpToDate Outcome = "up_to_date" // Nothing new
+	OutcomeSkipped  Outcome = "skipped"    // Pull refused; see Reason
+	OutcomeFailed   Outcome = "failed"
+)
+
+// Result holds the fetch or pull outcome for a repository
+type Result struct {
+	Repo     workspace.RepoInfo
+	Branch   string
+	Upstream string
+	Before   string // Short hash before (HEAD for pull, upstream for fetch)
+	After    string // Short hash after
+	Moved    int    // Commits fast-forwarded (pull) or fetched onto upstream (fetch)
+	Behind   int    // Commits HEAD is behind upstream afterwards
+	Outcome  Outcome
+	Reason   string // Why a pull was skipped
+	Error    error
+}
+
+// DefaultJobs is the default number of repos processed at once
+const DefaultJobs = 8
+
+// FetchTimeout bounds a single repo's fetch (network-bound, so longer
+// than git.DefaultTimeout)
+var FetchTimeout = 5 * time.Minute
+
+// FetchParallel runs Fetch in all repos, at most jobs at a time
+func FetchParallel(repos []workspace.RepoInfo, jobs int) []Result {
+	return parallel(repos, jobs, Fetch)
+}
+
+// PullParallel runs Pull in all repos, at most jobs at a time
+func PullParallel(repos []workspace.RepoInfo, jobs int) []Result {
+	return parallel(repos, jobs, Pull)
+}
+
+func parallel(repos []workspace.RepoInfo, jobs int, fn func(workspace.RepoInfo) Result) []Result {
+	if jobs <= 0 {
+		jobs = DefaultJobs
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan Result, len(repos))
+	sem := make(chan struct{}, jobs)
+
+	for _, repo := range repos {
+		wg.Add(1)
+		go func(r workspace.RepoInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results <- fn(r)
+		}(repo)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var out []Result
+	for result := range results {
+		out = append(out, result)
+	}
+
+	return out
+}
+
+// Fetch fetches all remotes (pruning stale refs) and reports how many
+// commits arrived on the current branch's upstream
+func Fetch(repo workspace.RepoInfo) Result {
+	result := Result{Repo: repo}
+	result.Branch, _ = git.Output(repo.Path, "symbolic-ref", "--short", "-q", "HEAD")
+	result.Upstream, _ = git.Output(repo.Path, "rev-parse", "--abbrev-ref", "@{upstream}")
+
+	if result.Upstream != "" {
+		result.Before, _ = git.Output(repo.Path, "rev-parse", "--short", "@{upstream}")
+	}
+
+	if err := fetch(repo.Path); err != nil {
+		result.Outcome = OutcomeFailed
+		result.Error = err
+		return result
+	}
+
+	result.Outcome = OutcomeUpToDate
+	if result.Upstream == "" {
+		return result
+	}
+
+	result.After, _ = git.Output(repo.Path, "rev-parse", "--short", "@{upstream}")
+	if result.Before != "" && result.After != result.Before {
+		result.Moved = countCommits(repo.Path, result.Before+".."+result.After)
+		result.Outcome = OutcomeUpdated
+	}
+	_, result.Behind = aheadBehind(repo.Path)
+
+	return result
+}
+
+// Pull fetches and then fast-forwards the current branch to its upstream.
+// Repos that are detached, have no upstream, have uncommitted changes to
+// tracked files, or have diverged are skipped with a Reason.
+func Pull(repo workspace.RepoInfo) Result {
+	result := Result{Repo: repo}
+
+	result.Branch, _ = git.Output(repo.Path, "symbolic-ref", "--short", "-q", "HEAD")
+	if result.Branch == "" {
+		return skip(result, "detached HEAD")
+	}
+
+	result.Upstream, _ = git.Output(repo.Path, "rev-parse", "--abbrev-ref", "@{upstream}")
+	if result.Upstream == "" {
+		return skip(result, "no upstream for "+result.Branch)
+	}
+
+	if err := fetch(repo.Path); err != nil {
+		result.Outcome = OutcomeFailed
+		result.Error = err
+		return result
+	}
+
+	ahead, behind := aheadBehind(repo.Path)
+	result.Behind = behind
+	if behind == 0 {
+		result.Outcome = OutcomeUpToDate
+		return result
+	}
+	if ahead > 0 {
+		return skip(result, fmt.Sprintf("diverged (%d ahead, %d behind %s)", ahead, behind, result.Upstream))
+	}
+
+	// Untracked files don't block a fast-forward; git refuses itself if
+	// one would be overwritten
+	if dirty, _ := git.Output(repo.Path, "status", "--porcelain", "--untracked-files=no"); dirty != "" {
+		return skip(result, "uncommitted changes")
+	}
+
+	result.Before, _ = git.Output(repo.Path, "rev-parse", "--short", "HEAD")
+	if _, err := git.Output(repo.Path, "merge", "--ff-only", "--quiet", "@{upstream}"); err != nil {
+		result.Outcome = OutcomeFailed
+		result.Error = err
+		return result
+	}
+	result.After, _ = git.Output(repo.Path, "rev-parse", "--short", "HEAD")
+
+	result.Moved = behind
+	result.Behind = 0
+	result.Outcome = OutcomeUpdated
+	return result
+}
+
+// Failed reports whether any result failed outright (skips don't count)
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Outcome == OutcomeFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func skip(result Result, reason string) Result {
+	result.Outcome = OutcomeSkipped
+	result.Reason = reason
+	return result
+}
+
+// aheadBehind returns how far HEAD is ahead of and behind its upstream
+func aheadBehind(dir string) (int, int) {
+	out, err := git.Output(dir, "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	if err != nil {
+		return 0, 0
+	}
+	parts := strings.Fields(out)
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	ahead, _ := strconv.Atoi(parts[0])
+	behind, _ := strconv.Atoi(parts[1])
+	return ahead, behind
+}
+
+func countCommits(dir, rangeSpec string) int {
+	out, err := git.Output(dir, "rev-list", "--count", rangeSpec)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(out)
+	return n
+}
+
+func fetch(dir string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), FetchTimeout)
+	defer cancel()
+	_, err := git.Run(ctx, dir, "fetch", "--all", "--prune", "--quiet")
+	return err
+}
@@ -0,0 +1,169 @@
+package pull
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func commit(t *testing.T, dir, file string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	runGit(t, dir, "add", file)
+	runGit(t, dir, "commit", "-m", "add "+file)
+}
+
+func cloneRepo(t *testing.T, remote, dest string) {
+	t.Helper()
+	runGit(t, filepath.Dir(remote), "clone", "--quiet", remote, dest)
+	runGit(t, dest, "config", "user.email", "test@test.com")
+	runGit(t, dest, "config", "user.name", "Test User")
+}
+
+// setup returns a bare remote with one commit, a local clone tracking it,
+// and a second "upstream" clone used to push new commits
+func setup(t *testing.T) (local, upstream string) {
+	t.Helper()
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	runGit(t, root, "init", "--quiet", "--bare", remote)
+
+	upstream = filepath.Join(root, "upstream")
+	cloneRepo(t, remote, upstream)
+	commit(t, upstream, "base.txt")
+	runGit(t, upstream, "push", "--quiet", "origin", "HEAD")
+
+	local = filepath.Join(root, "local")
+	cloneRepo(t, remote, local)
+	return local, upstream
+}
+
+func TestPull(t *testing.T) {
+	t.Run("fast-forwards when behind", func(t *testing.T) {
+		local, upstream := setup(t)
+		commit(t, upstream, "a.txt")
+		commit(t, upstream, "b.txt")
+		runGit(t, upstream, "push", "--quiet", "origin", "HEAD")
+
+		// Untracked files don't block a fast-forward
+		_ = os.WriteFile(filepath.Join(local, "scratch.txt"), []byte("x"), 0644)
+
+		result := Pull(workspace.RepoInfo{Name: "local", Path: local})
+		if result.Outcome != OutcomeUpdated || result.Moved != 2 {
+			t.Fatalf("result = %+v, want updated by 2", result)
+		}
+		if result.Before == result.After || result.After != runGit(t, local, "rev-parse", "--short", "HEAD") {
+			t.Errorf("Before/After = %s/%s, want HEAD to move", result.Before, result.After)
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		local, _ := setup(t)
+		result := Pull(workspace.RepoInfo{Name: "local", Path: local})
+		if result.Outcome != OutcomeUpToDate || result.Moved != 0 {
+			t.Errorf("result = %+v, want up to date", result)
+		}
+	})
+
+	skips := []struct {
+		name   string
+		prep   func(t *testing.T, local, upstream string)
+		reason string
+	}{
+		{"dirty", func(t *testing.T, local, _ string) {
+			_ = os.WriteFile(filepath.Join(local, "base.txt"), []byte("changed"), 0644)
+		}, "uncommitted changes"},
+		{"detached", func(t *testing.T, local, _ string) {
+			runGit(t, local, "checkout", "--quiet", "--detach")
+		}, "detached HEAD"},
+		{"no upstream", func(t *testing.T, local, _ string) {
+			runGit(t, local, "checkout", "--quiet", "-b", "feature")
+		}, "no upstream"},
+		{"diverged", func(t *testing.T, local, _ string) {
+			commit(t, local, "local.txt")
+		}, "diverged (1 ahead, 1 behind"},
+	}
+
+	for _, tt := range skips {
+		t.Run("skips "+tt.name, func(t *testing.T) {
+			local, upstream := setup(t)
+			commit(t, upstream, "new.txt")
+			runGit(t, upstream, "push", "--quiet", "origin", "HEAD")
+			tt.prep(t, local, upstream)
+
+			before := runGit(t, local, "rev-parse", "HEAD")
+			result := Pull(workspace.RepoInfo{Name: "local", Path: local})
+
+			if result.Outcome != OutcomeSkipped || !strings.HasPrefix(result.Reason, tt.reason) {
+				t.Errorf("result = %+v, want skipped with %q", result, tt.reason)
+			}
+			if after := runGit(t, local, "rev-parse", "HEAD"); after != before {
+				t.Error("skipped repo's HEAD should not move")
+			}
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	local, upstream := setup(t)
+	commit(t, upstream, "a.txt")
+	commit(t, upstream, "b.txt")
+	commit(t, upstream, "c.txt")
+	runGit(t, upstream, "push", "--quiet", "origin", "HEAD")
+
+	result := Fetch(workspace.RepoInfo{Name: "local", Path: local})
+	if result.Outcome != OutcomeUpdated || result.Moved != 3 || result.Behind != 3 {
+		t.Errorf("result = %+v, want 3 commits fetched and 3 behind", result)
+	}
+
+	// HEAD doesn't move on fetch
+	if runGit(t, local, "rev-list", "--count", "HEAD") != "1" {
+		t.Error("fetch should not move HEAD")
+	}
+
+	again := Fetch(workspace.RepoInfo{Name: "local", Path: local})
+	if again.Outcome != OutcomeUpToDate || again.Moved != 0 {
+		t.Errorf("second fetch = %+v, want up to date", again)
+	}
+}
+
+func TestPullParallel(t *testing.T) {
+	a, upA := setup(t)
+	b, _ := setup(t)
+	commit(t, upA, "x.txt")
+	runGit(t, upA, "push", "--quiet", "origin", "HEAD")
+
+	repos := []workspace.RepoInfo{{Name: "a", Path: a}, {Name: "b", Path: b}, {Name: "gone", Path: filepath.Join(t.TempDir(), "gone")}}
+	results := PullParallel(repos, 2)
+
+	if len(results) != 3 {
+		t.Fatalf("PullParallel returned %d results, want 3", len(results))
+	}
+	outcomes := make(map[string]Outcome)
+	for _, r := range results {
+		outcomes[r.Repo.Name] = r.Outcome
+	}
+	if outcomes["a"] != OutcomeUpdated || outcomes["b"] != OutcomeUpToDate {
+		t.Errorf("outcomes = %v, want a updated and b up to date", outcomes)
+	}
+	if outcomes["gone"] == OutcomeUpdated || outcomes["gone"] == OutcomeUpToDate {
+		t.Errorf("missing repo outcome = %s, want skipped or failed", outcomes["gone"])
+	}
+}
@@ -1,11 +1,10 @@
 package remote
 
 import (
-	"bytes"
-	"os/exec"
 	"regexp"
 	"strings"
 
+	"github.com/sloanahrens/devbot-go/internal/git"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
@@ -42,7 +41,11 @@ func GetRemotes(repo workspace.RepoInfo) RemoteResult {
 	result := RemoteResult{Repo: repo}
 
 	// Get all remotes with URLs
-	output := gitCommand(repo.Path, "remote", "-v")
+	output, err := git.Output(repo.Path, "remote", "-v")
+	if err != nil {
+		result.Error = err
+		return result
+	}
 	if output == "" {
 		return result
 	}
@@ -107,6 +110,11 @@ func FindRepoByGitHub(repos []workspace.RepoInfo, identifier string) FindResult
 	return result
 }
 
+// GitHubID returns the org/repo identifier for a GitHub remote URL, or ""
+func GitHubID(url string) string {
+	return parseGitHub(url)
+}
+
 // parseGitHub extracts org/repo from a GitHub URL
 func parseGitHub(url string) string {
 	// Try SSH format
@@ -161,18 +169,3 @@ func (r *RemoteResult) GetOriginGitHub() string {
 	}
 	return ""
 }
-
-func gitCommand(dir string, args ...string) string {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = nil
-
-	if err := cmd.Run(); err != nil {
-		return ""
-	}
-
-	return strings.TrimSpace(out.String())
-}
@@ -0,0 +1,166 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/check"
+)
+
+// JUnit XML, in the common subset read by CI dashboards and test viewers
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Cases      []junitCase      `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Property []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Classname string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// encodeJUnit writes one test suite per check, named by sub-app and check
+// type. A test check devbot could parse has a test case per test; any other
+// check is a single test case.
+func encodeJUnit(result check.Result) ([]byte, error) {
+	doc := junitSuites{Name: result.Repo.Name, Time: junitTime(result.Duration)}
+
+	for _, c := range result.Checks {
+		suite := junitSuite{Name: suiteName(c), Time: junitTime(c.Duration)}
+		for _, p := range []junitProperty{
+			{"command", c.Command}, {"source", c.Source}, {"stack", c.Stack},
+		} {
+			if p.Value == "" {
+				continue
+			}
+			if suite.Properties == nil {
+				suite.Properties = &junitProperties{}
+			}
+			suite.Properties.Property = append(suite.Properties.Property, p)
+		}
+
+		if c.Tests != nil {
+			for _, tc := range c.Tests.Tests {
+				suite.add(testCase(suite.Name, tc))
+			}
+		}
+		// A check that failed without a failing test (or that has no per-test
+		// results) is reported as a test case of its own
+		if c.Tests == nil || (c.Status == "fail" && c.Tests.Failed == 0) {
+			suite.add(checkCase(suite.Name, c))
+		}
+
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Skipped += suite.Skipped
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+func (s *junitSuite) add(c junitCase) {
+	s.Tests++
+	if c.Failure != nil {
+		s.Failures++
+	}
+	if c.Skipped != nil {
+		s.Skipped++
+	}
+	s.Cases = append(s.Cases, c)
+}
+
+// testCase converts a parsed test. A suite-level failure (such as a Go
+// package that didn't build) is named after the suite.
+func testCase(suite string, tc check.TestCase) junitCase {
+	c := junitCase{
+		Classname: tc.Suite,
+		Name:      tc.Name,
+		File:      tc.File,
+		Line:      tc.Line,
+		Time:      junitTime(tc.Duration),
+	}
+	if c.Classname == "" {
+		c.Classname = suite
+	}
+	if c.Name == "" {
+		c.Name = c.Classname
+	}
+	switch tc.Status {
+	case "fail":
+		c.Failure = &junitFailure{Message: firstLine(tc.Message), Text: tc.Message}
+	case "skip":
+		c.Skipped = &junitSkipped{}
+	}
+	return c
+}
+
+// checkCase reports a whole check as one test case, with its output as the
+// failure text
+func checkCase(suite string, cr check.CheckResult) junitCase {
+	c := junitCase{Classname: suite, Name: string(cr.Type), Time: junitTime(cr.Duration)}
+	switch cr.Status {
+	case "fail":
+		msg := fmt.Sprintf("%s failed", cr.Type)
+		if cr.Error != nil {
+			msg = cr.Error.Error()
+		}
+		c.Failure = &junitFailure{Message: msg, Text: cr.Output}
+	case "skip":
+		c.Skipped = &junitSkipped{Message: cr.Output}
+	}
+	return c
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,78 @@
+// Package report encodes `devbot check` results for other tools: JUnit XML
+// for test dashboards and SARIF for editor problem panes.
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/check"
+)
+
+// Format is a report file format
+type Format string
+
+// Supported formats
+const (
+	JUnit Format = "junit"
+	SARIF Format = "sarif"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{JUnit, SARIF}
+
+// Spec is a requested report: a format and the file to write it to
+type Spec struct {
+	Format Format
+	Path   string
+}
+
+// ParseSpec parses "format=path", e.g. "junit=out/checks.xml"
+func ParseSpec(s string) (Spec, error) {
+	name, path, ok := strings.Cut(s, "=")
+	if !ok || path == "" {
+		return Spec{}, fmt.Errorf("report %q: want <format>=<path>, e.g. junit=checks.xml", s)
+	}
+	for _, f := range Formats {
+		if strings.EqualFold(name, string(f)) {
+			return Spec{Format: f, Path: path}, nil
+		}
+	}
+	return Spec{}, fmt.Errorf("report %q: unknown format %q (want junit or sarif)", s, name)
+}
+
+// Encode renders result in format f
+func Encode(f Format, result check.Result) ([]byte, error) {
+	switch f {
+	case JUnit:
+		return encodeJUnit(result)
+	case SARIF:
+		return encodeSARIF(result)
+	}
+	return nil, fmt.Errorf("unknown report format %q", f)
+}
+
+// Write encodes result as spec asks and writes it, creating parent dirs
+func Write(spec Spec, result check.Result) error {
+	data, err := Encode(spec.Format, result)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(spec.Path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(spec.Path, data, 0644)
+}
+
+// suiteName groups a check by sub-app and type, e.g. "api/test" or "lint"
+// at the repo root
+func suiteName(c check.CheckResult) string {
+	if c.SubDir == "" {
+		return string(c.Type)
+	}
+	return filepath.ToSlash(c.SubDir) + "/" + string(c.Type)
+}
@@ -0,0 +1,155 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/check"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func sampleResult() check.Result {
+	return check.Result{
+		Repo:     workspace.RepoInfo{Name: "shop", Path: "/work/shop"},
+		Duration: 3 * time.Second,
+		Checks: []check.CheckResult{
+			{
+				Type: check.CheckLint, Command: "golangci-lint run", Source: "builtin:go",
+				Status: "fail", Error: errors.New("exit status 1"), Output: "main.go:3:1: unused (unused)",
+				Findings: []check.Finding{
+					{Tool: "golangci-lint", Rule: "unused", Level: "error", File: "main.go", Line: 3, Column: 1, Message: "func `old` is unused"},
+				},
+			},
+			{
+				Type: check.CheckTest, Status: "fail", Duration: time.Second,
+				Tests: &check.TestReport{Format: "go", Passed: 1, Failed: 1, Tests: []check.TestCase{
+					{Suite: "example.com/shop/cart", Name: "TestAdd", Status: "pass", Duration: 10 * time.Millisecond},
+					{Suite: "example.com/shop/cart", Name: "TestRemove", Status: "fail", File: "cart/cart_test.go", Line: 21, Message: "got 2\nwant 1"},
+				}},
+			},
+			{
+				Type: check.CheckLint, SubDir: "web", Status: "fail",
+				Findings: []check.Finding{
+					{Tool: "eslint", Rule: "no-console", Level: "warning", File: "src/a.js", Line: 3, Column: 1, Message: "Unexpected console statement"},
+				},
+			},
+			{Type: check.CheckTest, SubDir: "web", Status: "skip"},
+		},
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec("JUnit=out/checks.xml")
+	if err != nil || spec.Format != JUnit || spec.Path != "out/checks.xml" {
+		t.Errorf("ParseSpec = %+v, %v", spec, err)
+	}
+	for _, bad := range []string{"junit", "junit=", "html=out.html"} {
+		if _, err := ParseSpec(bad); err == nil {
+			t.Errorf("ParseSpec(%q) should fail", bad)
+		}
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	data, err := Encode(JUnit, sampleResult())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, data)
+	}
+	if doc.Name != "shop" || doc.Tests != 5 || doc.Failures != 3 || doc.Skipped != 1 {
+		t.Errorf("totals = %d tests, %d failures, %d skipped", doc.Tests, doc.Failures, doc.Skipped)
+	}
+
+	var names []string
+	for _, s := range doc.Suites {
+		names = append(names, s.Name)
+	}
+	if strings.Join(names, ",") != "lint,test,web/lint,web/test" {
+		t.Errorf("suites = %v, want grouped by sub-app and check type", names)
+	}
+
+	test := doc.Suites[1]
+	if len(test.Cases) != 2 {
+		t.Fatalf("test cases = %+v, want one per test", test.Cases)
+	}
+	failed := test.Cases[1]
+	if failed.Classname != "example.com/shop/cart" || failed.Name != "TestRemove" ||
+		failed.File != "cart/cart_test.go" || failed.Line != 21 ||
+		failed.Failure == nil || failed.Failure.Message != "got 2" {
+		t.Errorf("failed case = %+v", failed)
+	}
+
+	lint := doc.Suites[0]
+	if len(lint.Cases) != 1 || lint.Cases[0].Failure == nil || lint.Cases[0].Failure.Message != "exit status 1" {
+		t.Errorf("lint case = %+v, want the check as one failed case", lint.Cases)
+	}
+	if lint.Properties == nil || len(lint.Properties.Property) != 2 || lint.Properties.Property[0].Value != "golangci-lint run" {
+		t.Errorf("lint properties = %+v", lint.Properties)
+	}
+	if doc.Suites[3].Properties != nil {
+		t.Errorf("properties = %+v, want none without a command", doc.Suites[3].Properties)
+	}
+	if doc.Suites[3].Cases[0].Skipped == nil {
+		t.Error("skipped check should be a skipped case")
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	data, err := Encode(SARIF, sampleResult())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc sarifLog
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Version != "2.1.0" || len(doc.Runs) != 2 {
+		t.Fatalf("doc = %+v, want a run per linter", doc)
+	}
+
+	eslint, golangci := doc.Runs[0], doc.Runs[1]
+	if eslint.Tool.Driver.Name != "eslint" || golangci.Tool.Driver.Name != "golangci-lint" {
+		t.Errorf("runs = %s, %s, want sorted by tool", eslint.Tool.Driver.Name, golangci.Tool.Driver.Name)
+	}
+	if golangci.OriginalURIBaseIDs["SRCROOT"].URI != "file:///work/shop/" {
+		t.Errorf("SRCROOT = %+v", golangci.OriginalURIBaseIDs)
+	}
+
+	r := eslint.Results[0]
+	loc := r.Locations[0].PhysicalLocation
+	if r.RuleID != "no-console" || r.Level != "warning" || loc.ArtifactLocation.URI != "web/src/a.js" ||
+		loc.ArtifactLocation.URIBaseID != "SRCROOT" || loc.Region.StartLine != 3 {
+		t.Errorf("eslint result = %+v", r)
+	}
+	if len(eslint.Tool.Driver.Rules) != 1 || eslint.Tool.Driver.Rules[0].ID != "no-console" {
+		t.Errorf("rules = %+v", eslint.Tool.Driver.Rules)
+	}
+
+	// Without findings the log still has a run, so viewers clear old results
+	empty, _ := Encode(SARIF, check.Result{Repo: workspace.RepoInfo{Path: "/work/shop"}})
+	if err := json.Unmarshal(empty, &doc); err != nil || len(doc.Runs) != 1 || len(doc.Runs[0].Results) != 0 {
+		t.Errorf("empty SARIF = %s", empty)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "checks.xml")
+	if err := Write(Spec{Format: JUnit, Path: path}, sampleResult()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(data), "<?xml") {
+		t.Errorf("report file = %q, %v", data, err)
+	}
+}
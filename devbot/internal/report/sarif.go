@@ -0,0 +1,161 @@
+package report
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/sloanahrens/devbot-go/internal/check"
+)
+
+// SARIF 2.1.0 (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLoc `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLoc `json:"physicalLocation"`
+}
+
+type sarifPhysicalLoc struct {
+	ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+	Region           *sarifRegion     `json:"region,omitempty"`
+}
+
+type sarifArtifactLoc struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// toolURIs are the drivers' informationUri values
+var toolURIs = map[string]string{
+	"golangci-lint": "https://golangci-lint.run",
+	"ruff":          "https://docs.astral.sh/ruff/",
+	"eslint":        "https://eslint.org",
+}
+
+// encodeSARIF writes a run per linter with the lint findings of every
+// sub-app. Paths are relative to the repo root (%SRCROOT%). Without any
+// findings there is a single empty devbot run, so viewers clear old results.
+func encodeSARIF(result check.Result) ([]byte, error) {
+	root := map[string]sarifArtifactLoc{
+		"SRCROOT": {URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(result.Repo.Path) + "/"}).String()},
+	}
+
+	runs := make(map[string]*sarifRun)
+	rules := make(map[string]map[string]bool)
+	for _, c := range result.Checks {
+		for _, f := range c.Findings {
+			run := runs[f.Tool]
+			if run == nil {
+				run = &sarifRun{
+					Tool:               sarifTool{Driver: sarifDriver{Name: f.Tool, InformationURI: toolURIs[f.Tool], Rules: []sarifRule{}}},
+					OriginalURIBaseIDs: root,
+					Results:            []sarifResult{},
+				}
+				runs[f.Tool] = run
+				rules[f.Tool] = make(map[string]bool)
+			}
+			if f.Rule != "" && !rules[f.Tool][f.Rule] {
+				rules[f.Tool][f.Rule] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: f.Rule})
+			}
+			run.Results = append(run.Results, sarifFinding(c.SubDir, f))
+		}
+	}
+
+	doc := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{},
+	}
+	var tools []string
+	for tool := range runs {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		run := runs[tool]
+		sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+			return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+		})
+		doc.Runs = append(doc.Runs, *run)
+	}
+	if len(doc.Runs) == 0 {
+		doc.Runs = append(doc.Runs, sarifRun{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "devbot", Rules: []sarifRule{}}},
+			Results: []sarifResult{},
+		})
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// sarifFinding converts a finding from the sub-app at subDir
+func sarifFinding(subDir string, f check.Finding) sarifResult {
+	loc := sarifArtifactLoc{URI: path.Join(filepath.ToSlash(subDir), filepath.ToSlash(f.File)), URIBaseID: "SRCROOT"}
+	if filepath.IsAbs(f.File) {
+		loc = sarifArtifactLoc{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(f.File)}).String()}
+	}
+
+	var region *sarifRegion
+	if f.Line > 0 {
+		region = &sarifRegion{StartLine: f.Line, StartColumn: f.Column}
+	}
+
+	level := f.Level
+	if level != "error" && level != "warning" {
+		level = "warning"
+	}
+	return sarifResult{
+		RuleID:    f.Rule,
+		Level:     level,
+		Message:   sarifMessage{Text: f.Message},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLoc{ArtifactLocation: loc, Region: region}}},
+	}
+}
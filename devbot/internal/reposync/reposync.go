@@ -0,0 +1,252 @@
+package reposync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sloanahrens/devbot-go/internal/git"
+	"github.com/sloanahrens/devbot-go/internal/remote"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// Action describes what sync did (or found) for a repo
+type Action string
+
+const (
+	ActionOK         Action = "ok"          // On disk, origin matches
+	ActionCloned     Action = "cloned"      // Was missing, now cloned
+	ActionWouldClone Action = "would_clone" // Dry run: would be cloned
+	ActionNoRemote   Action = "no_remote"   // Missing, but no URL to clone from
+	ActionMismatch   Action = "mismatch"    // Origin differs from the configured URL
+	ActionUntracked  Action = "untracked"   // On disk but not in config.yaml
+	ActionAdded      Action = "added"       // Untracked, now added to config.yaml
+	ActionFailed     Action = "failed"
+)
+
+// Result is the outcome of syncing a single repo
+type Result struct {
+	Name   string
+	Path   string
+	URL    string // Configured URL (or origin URL for untracked repos)
+	Origin string // Actual origin URL on disk
+	Action Action
+	Error  error
+}
+
+// Options controls a sync run
+type Options struct {
+	Jobs   int  // Max concurrent clones (<= 0 means 4)
+	Add    bool // Append untracked repos to config.yaml
+	DryRun bool // Report what would happen without cloning or writing
+}
+
+const defaultJobs = 4
+
+// Sync compares config.yaml with the discovered repos: configured repos that
+// are missing get cloned, on-disk repos not in config are reported (and
+// optionally added), and origins that don't match the config are flagged.
+func Sync(workspacePath string, cfg *workspace.WorkspaceConfig, repos []workspace.RepoInfo, opts Options) []Result {
+	byPath := make(map[string]workspace.RepoInfo, len(repos))
+	byName := make(map[string]workspace.RepoInfo, len(repos))
+	for _, r := range repos {
+		byPath[r.Path] = r
+		byName[r.Name] = r
+	}
+
+	var results []Result
+	var toClone []Result
+	configured := make(map[string]bool)
+
+	if cfg != nil {
+		for _, rc := range cfg.Repos {
+			if rc.Name == "" {
+				continue
+			}
+
+			dest := rc.RepoPath(workspacePath)
+			onDisk, ok := byPath[dest]
+			if !ok && rc.Path == "" {
+				onDisk, ok = byName[rc.Name]
+			}
+			if !ok && isRepo(dest) {
+				// Present but outside the discovery settings
+				onDisk, ok = workspace.RepoInfo{Name: rc.Name, Path: dest}, true
+			}
+
+			r := Result{Name: rc.Name, Path: dest, URL: rc.CloneURL()}
+			if ok {
+				configured[onDisk.Path] = true
+				r.Path = onDisk.Path
+				r.Origin = originURL(onDisk)
+				r.Action = ActionOK
+				if r.URL != "" && !SameRemote(r.URL, r.Origin) {
+					r.Action = ActionMismatch
+				}
+				results = append(results, r)
+				continue
+			}
+
+			switch {
+			case r.URL == "":
+				r.Action = ActionNoRemote
+				results = append(results, r)
+			case opts.DryRun:
+				r.Action = ActionWouldClone
+				results = append(results, r)
+			default:
+				toClone = append(toClone, r)
+			}
+		}
+	}
+
+	results = append(results, cloneAll(toClone, opts.Jobs)...)
+
+	// On-disk repos that config.yaml doesn't know about
+	var untracked []Result
+	for _, repo := range repos {
+		if configured[repo.Path] {
+			continue
+		}
+		origin := originURL(repo)
+		untracked = append(untracked, Result{
+			Name:   repo.Name,
+			Path:   repo.Path,
+			URL:    origin,
+			Origin: origin,
+			Action: ActionUntracked,
+		})
+	}
+
+	if opts.Add && !opts.DryRun && len(untracked) > 0 {
+		var entries []workspace.RepoConfig
+		for _, u := range untracked {
+			entry := workspace.RepoConfig{Name: u.Name, Remote: u.Origin}
+			if rel, err := filepath.Rel(workspacePath, u.Path); err == nil && filepath.ToSlash(rel) != u.Name {
+				entry.Path = filepath.ToSlash(rel)
+				if strings.HasPrefix(rel, "..") {
+					entry.Path = u.Path
+				}
+			}
+			entries = append(entries, entry)
+		}
+
+		err := workspace.AddRepos(entries)
+		for i := range untracked {
+			if err != nil {
+				untracked[i].Action = ActionFailed
+				untracked[i].Error = fmt.Errorf("adding to config.yaml: %w", err)
+			} else {
+				untracked[i].Action = ActionAdded
+			}
+		}
+	}
+
+	return append(results, untracked...)
+}
+
+// Failed reports whether any result is a failure
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Action == ActionFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneAll clones repos with at most jobs clones running at once
+func cloneAll(items []Result, jobs int) []Result {
+	if jobs <= 0 {
+		jobs = defaultJobs
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan Result, len(items))
+	sem := make(chan struct{}, jobs)
+
+	for _, item := range items {
+		wg.Add(1)
+		go func(r Result) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := clone(r.URL, r.Path); err != nil {
+				r.Action = ActionFailed
+				r.Error = err
+			} else {
+				r.Action = ActionCloned
+				r.Origin = r.URL
+			}
+			results <- r
+		}(item)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var out []Result
+	for r := range results {
+		out = append(out, r)
+	}
+	return out
+}
+
+func clone(url, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	// Clones can be large; no timeout beyond the user's patience
+	_, err := git.Run(context.Background(), "", "clone", "--quiet", url, dest)
+	return err
+}
+
+func isRepo(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// originURL returns the repo's origin URL (or its first remote)
+func originURL(repo workspace.RepoInfo) string {
+	remotes := remote.GetRemotes(repo).Remotes
+	for _, r := range remotes {
+		if r.Name == "origin" {
+			return r.URL
+		}
+	}
+	if len(remotes) > 0 {
+		return remotes[0].URL
+	}
+	return ""
+}
+
+// SameRemote reports whether two remote URLs point at the same repo,
+// treating SSH and HTTPS GitHub URLs (with or without .git) as equal
+func SameRemote(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	if ga, gb := remote.GitHubID(a), remote.GitHubID(b); ga != "" || gb != "" {
+		return strings.EqualFold(ga, gb)
+	}
+	return normalizeURL(a) == normalizeURL(b)
+}
+
+func normalizeURL(url string) string {
+	url = strings.TrimSuffix(strings.TrimSpace(url), "/")
+	url = strings.TrimSuffix(url, ".git")
+	url = strings.TrimPrefix(url, "file://")
+	if !strings.Contains(url, "://") && !strings.Contains(url, "@") {
+		if abs, err := filepath.Abs(url); err == nil {
+			url = abs
+		}
+	}
+	return url
+}
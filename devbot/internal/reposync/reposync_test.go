@@ -0,0 +1,185 @@
+package reposync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// makeBareRemote creates a bare repo with one commit and returns its path
+func makeBareRemote(t *testing.T, root, name string) string {
+	t.Helper()
+	src := filepath.Join(root, "src-"+name)
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	runGit(t, src, "init")
+	runGit(t, src, "config", "user.email", "test@test.com")
+	runGit(t, src, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(src, "README.md"), []byte("# "+name), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	runGit(t, src, "add", ".")
+	runGit(t, src, "commit", "-m", "init")
+
+	bare := filepath.Join(root, "remotes", name+".git")
+	runGit(t, root, "clone", "--bare", "--quiet", src, bare)
+	return bare
+}
+
+func byName(results []Result) map[string]Result {
+	m := make(map[string]Result)
+	for _, r := range results {
+		m[r.Name] = r
+	}
+	return m
+}
+
+func TestSync(t *testing.T) {
+	root := t.TempDir()
+	ws := filepath.Join(root, "code")
+
+	apiRemote := makeBareRemote(t, root, "api")
+	webRemote := makeBareRemote(t, root, "web")
+	otherRemote := makeBareRemote(t, root, "other")
+
+	// web is already cloned, but from the wrong remote
+	runGit(t, root, "clone", "--quiet", otherRemote, filepath.Join(ws, "web"))
+	// scratch is on disk but not in config
+	runGit(t, root, "clone", "--quiet", otherRemote, filepath.Join(ws, "scratch"))
+
+	cfg := &workspace.WorkspaceConfig{Repos: []workspace.RepoConfig{
+		{Name: "api", Remote: apiRemote, Path: "clients/acme/api"},
+		{Name: "web", URL: webRemote},
+		{Name: "notes"},
+	}}
+
+	repos, err := workspace.DiscoverWithOptions(ws, workspace.DiscoverOptions{})
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		results := byName(Sync(ws, cfg, repos, Options{DryRun: true}))
+		if results["api"].Action != ActionWouldClone {
+			t.Errorf("api = %s, want %s", results["api"].Action, ActionWouldClone)
+		}
+		if _, err := os.Stat(filepath.Join(ws, "clients", "acme", "api")); err == nil {
+			t.Error("dry run should not clone")
+		}
+	})
+
+	results := byName(Sync(ws, cfg, repos, Options{Jobs: 1}))
+
+	api := results["api"]
+	if api.Action != ActionCloned || api.Error != nil {
+		t.Errorf("api = %+v, want cloned", api)
+	}
+	if _, err := os.Stat(filepath.Join(ws, "clients", "acme", "api", "README.md")); err != nil {
+		t.Errorf("api should be cloned to its configured path: %v", err)
+	}
+
+	web := results["web"]
+	if web.Action != ActionMismatch || web.Origin != otherRemote {
+		t.Errorf("web = %+v, want mismatch with origin %s", web, otherRemote)
+	}
+
+	if results["notes"].Action != ActionNoRemote {
+		t.Errorf("notes = %s, want %s", results["notes"].Action, ActionNoRemote)
+	}
+
+	scratch := results["scratch"]
+	if scratch.Action != ActionUntracked || scratch.Origin != otherRemote {
+		t.Errorf("scratch = %+v, want untracked with origin", scratch)
+	}
+
+	if Failed(Sync(ws, cfg, repos, Options{})) {
+		t.Error("second sync should not fail")
+	}
+}
+
+func TestSyncCloneFailure(t *testing.T) {
+	ws := t.TempDir()
+	cfg := &workspace.WorkspaceConfig{Repos: []workspace.RepoConfig{
+		{Name: "ghost", Remote: filepath.Join(ws, "no-such-remote.git")},
+	}}
+
+	results := Sync(ws, cfg, nil, Options{})
+	if len(results) != 1 || results[0].Action != ActionFailed || results[0].Error == nil {
+		t.Fatalf("results = %+v, want one failed clone", results)
+	}
+	if !Failed(results) {
+		t.Error("Failed() should report the clone failure")
+	}
+}
+
+func TestSyncAddUntracked(t *testing.T) {
+	root := t.TempDir()
+	ws := filepath.Join(root, "code")
+	remote := makeBareRemote(t, root, "tool")
+	runGit(t, root, "clone", "--quiet", remote, filepath.Join(ws, "tool"))
+
+	configPath := filepath.Join(root, "config.yaml")
+	content := "# workspace config\nworkspace: " + ws + "\nrepos:\n  - name: existing # keep me\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv("DEVBOT_CONFIG", configPath)
+	workspace.ResetConfigCache()
+	defer workspace.ResetConfigCache()
+
+	repos, _ := workspace.DiscoverWithOptions(ws, workspace.DiscoverOptions{})
+	cfg, _ := workspace.LoadConfig()
+
+	results := byName(Sync(ws, cfg, repos, Options{Add: true}))
+	if results["tool"].Action != ActionAdded {
+		t.Fatalf("tool = %+v, want added", results["tool"])
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "# keep me") {
+		t.Errorf("config comments should be preserved:\n%s", data)
+	}
+
+	cfg, err := workspace.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(cfg.Repos) != 2 || cfg.Repos[1].Name != "tool" || cfg.Repos[1].Remote != remote {
+		t.Errorf("repos = %+v, want tool appended with its remote", cfg.Repos)
+	}
+}
+
+func TestSameRemote(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"git@github.com:org/repo.git", "https://github.com/org/repo", true},
+		{"https://github.com/Org/Repo.git", "https://github.com/org/repo", true},
+		{"git@github.com:org/repo.git", "git@github.com:org/other.git", false},
+		{"/srv/git/repo.git", "file:///srv/git/repo", true},
+		{"/srv/git/repo.git", "/srv/git/other.git", false},
+		{"https://gitlab.com/org/repo.git", "https://gitlab.com/org/repo/", true},
+		{"", "https://github.com/org/repo", false},
+	}
+
+	for _, tt := range tests {
+		if got := SameRemote(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameRemote(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -2,29 +2,97 @@ package runner
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os/exec"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
+// Status is the outcome of running a command in a repo
+type Status string
+
+const (
+	StatusOK        Status = "ok"
+	StatusFailed    Status = "failed"
+	StatusTimedOut  Status = "timed_out"
+	StatusCancelled Status = "cancelled"
+)
+
+// waitDelay bounds how long we wait for output pipes after the process
+// group has been killed (grandchildren may still hold them open)
+const waitDelay = 2 * time.Second
+
 // Result holds the output of running a command in a repo
 type Result struct {
+	Repo     workspace.RepoInfo
+	Output   string
+	Error    error
+	Status   Status
+	ExitCode int // -1 if the command never started or was killed by a signal
+	Duration time.Duration
+}
+
+// Line is a single line of command output, delivered as it's written
+type Line struct {
 	Repo   workspace.RepoInfo
-	Output string
-	Error  error
+	Stderr bool
+	Text   string
+}
+
+// Options controls concurrency, time limits and streaming for RunContext
+type Options struct {
+	Jobs    int           // Max concurrent repos (<= 0 means unlimited)
+	Timeout time.Duration // Per-repo time limit (0 means none)
+
+	// OnLine, if set, is called for each complete line of output while
+	// commands run. It's called concurrently from multiple repos.
+	OnLine func(Line)
+}
+
+// Failed reports whether the command did not complete successfully
+func (r Result) Failed() bool {
+	return r.Status != StatusOK
 }
 
 // RunParallel executes a command in all repos simultaneously
 func RunParallel(repos []workspace.RepoInfo, command string, args []string) []Result {
+	return RunContext(context.Background(), repos, command, args, Options{})
+}
+
+// RunContext executes a command in all repos with at most opts.Jobs running
+// at once. Cancelling ctx kills every running process group; repos that
+// haven't started yet are reported as cancelled without being run.
+func RunContext(ctx context.Context, repos []workspace.RepoInfo, command string, args []string, opts Options) []Result {
+	jobs := opts.Jobs
+	if jobs <= 0 || jobs > len(repos) {
+		jobs = len(repos)
+	}
+
 	var wg sync.WaitGroup
 	results := make(chan Result, len(repos))
+	sem := make(chan struct{}, jobs)
 
 	for _, repo := range repos {
 		wg.Add(1)
 		go func(r workspace.RepoInfo) {
 			defer wg.Done()
-			results <- runInRepo(r, command, args)
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results <- Result{Repo: r, Error: ctx.Err(), Status: StatusCancelled, ExitCode: -1}
+				return
+			}
+
+			results <- runInRepo(ctx, r, command, args, opts)
 		}(repo)
 	}
 
@@ -41,16 +109,61 @@ func RunParallel(repos []workspace.RepoInfo, command string, args []string) []Re
 	return out
 }
 
-func runInRepo(repo workspace.RepoInfo, command string, args []string) Result {
-	cmd := exec.Command(command, args...)
+func runInRepo(ctx context.Context, repo workspace.RepoInfo, command string, args []string, opts Options) Result {
+	start := time.Now()
+	timeout := opts.Timeout
+
+	// A cancel that raced with acquiring a slot shouldn't start the command
+	if ctx.Err() != nil {
+		return Result{Repo: repo, Error: ctx.Err(), Status: StatusCancelled, ExitCode: -1}
+	}
+
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, command, args...)
 	cmd.Dir = repo.Path
 
+	// Run in a new process group so we can kill the whole tree (npm, make
+	// and friends spawn children that would otherwise be orphaned)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = waitDelay
+
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	var outLines, errLines *lineWriter
+	if opts.OnLine != nil {
+		outLines = &lineWriter{repo: repo, onLine: opts.OnLine}
+		errLines = &lineWriter{repo: repo, stderr: true, onLine: opts.OnLine}
+		cmd.Stdout = io.MultiWriter(&stdout, outLines)
+		cmd.Stderr = io.MultiWriter(&stderr, errLines)
+	}
+
 	err := cmd.Run()
 
+	if opts.OnLine != nil {
+		outLines.flush()
+		errLines.flush()
+	}
+
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+
 	output := stdout.String()
 	if stderr.Len() > 0 {
 		if output != "" {
@@ -59,9 +172,59 @@ func runInRepo(repo workspace.RepoInfo, command string, args []string) Result {
 		output += stderr.String()
 	}
 
+	status := StatusOK
+	switch {
+	case ctx.Err() != nil:
+		status = StatusCancelled
+		err = ctx.Err()
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		status = StatusTimedOut
+		err = fmt.Errorf("timed out after %s", timeout)
+	case err != nil:
+		status = StatusFailed
+	}
+
 	return Result{
-		Repo:   repo,
-		Output: output,
-		Error:  err,
+		Repo:     repo,
+		Output:   output,
+		Error:    err,
+		Status:   status,
+		ExitCode: exitCode,
+		Duration: time.Since(start),
 	}
 }
+
+// lineWriter splits written bytes into lines and hands each to onLine.
+// exec copies each pipe from a single goroutine, so no locking is needed.
+type lineWriter struct {
+	repo    workspace.RepoInfo
+	stderr  bool
+	onLine  func(Line)
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush emits any trailing output that didn't end in a newline
+func (w *lineWriter) flush() {
+	if len(w.partial) > 0 {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+}
+
+func (w *lineWriter) emit(line []byte) {
+	text := strings.TrimSuffix(string(line), "\r")
+	w.onLine(Line{Repo: w.repo, Stderr: w.stderr, Text: text})
+}
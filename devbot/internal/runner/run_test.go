@@ -1,10 +1,13 @@
 package runner
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
@@ -132,3 +135,157 @@ func TestResult(t *testing.T) {
 		t.Errorf("Error = %v, want nil", result.Error)
 	}
 }
+
+func makeRepos(t *testing.T, names ...string) []workspace.RepoInfo {
+	t.Helper()
+	tmpDir := t.TempDir()
+	var repos []workspace.RepoInfo
+	for _, name := range names {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("Failed to create repo dir: %v", err)
+		}
+		repos = append(repos, workspace.RepoInfo{Name: name, Path: path})
+	}
+	return repos
+}
+
+func TestRunContextJobsLimit(t *testing.T) {
+	repos := makeRepos(t, "repo-a", "repo-b", "repo-c", "repo-d")
+
+	// mkdir fails if another repo holds the lock, so any overlap is an error
+	t.Setenv("LOCK", filepath.Join(t.TempDir(), "lock"))
+	script := `mkdir "$LOCK" && sleep 0.05 && rmdir "$LOCK"`
+
+	results := RunContext(context.Background(), repos, "sh", []string{"-c", script}, Options{Jobs: 1})
+
+	if len(results) != 4 {
+		t.Fatalf("RunContext returned %d results, want 4", len(results))
+	}
+	for _, r := range results {
+		if r.Status != StatusOK {
+			t.Errorf("%s: status = %s (%v), want ok with --jobs 1", r.Repo.Name, r.Status, r.Error)
+		}
+	}
+}
+
+func TestRunContextTimeout(t *testing.T) {
+	repos := makeRepos(t, "slow")
+
+	// The backgrounded sleep holds stdout open; if only sh were killed,
+	// Run would block until waitDelay
+	start := time.Now()
+	results := RunContext(context.Background(), repos, "sh", []string{"-c", "sleep 30 & wait"}, Options{Timeout: 100 * time.Millisecond})
+	elapsed := time.Since(start)
+
+	if len(results) != 1 {
+		t.Fatalf("RunContext returned %d results, want 1", len(results))
+	}
+	if results[0].Status != StatusTimedOut {
+		t.Errorf("Status = %s, want %s", results[0].Status, StatusTimedOut)
+	}
+	if !results[0].Failed() {
+		t.Error("timed out result should count as failed")
+	}
+	if elapsed > waitDelay {
+		t.Errorf("took %v, process group was not killed", elapsed)
+	}
+}
+
+func TestRunContextCancel(t *testing.T) {
+	repos := makeRepos(t, "running", "queued")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	results := RunContext(ctx, repos, "sleep", []string{"30"}, Options{Jobs: 1})
+
+	if len(results) != 2 {
+		t.Fatalf("RunContext returned %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Status != StatusCancelled {
+			t.Errorf("%s: status = %s, want %s", r.Repo.Name, r.Status, StatusCancelled)
+		}
+	}
+}
+
+func TestRunParallelStatus(t *testing.T) {
+	repos := makeRepos(t, "repo-a")
+
+	ok := RunParallel(repos, "true", nil)
+	if ok[0].Status != StatusOK || ok[0].Failed() {
+		t.Errorf("true: status = %s, want ok", ok[0].Status)
+	}
+
+	failed := RunParallel(repos, "false", nil)
+	if failed[0].Status != StatusFailed {
+		t.Errorf("false: status = %s, want failed", failed[0].Status)
+	}
+}
+
+func TestRunContextStream(t *testing.T) {
+	repos := makeRepos(t, "repo-a")
+
+	var mu sync.Mutex
+	var lines []Line
+	onLine := func(l Line) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, l)
+	}
+
+	script := "echo one; echo oops >&2; printf 'two\\r\\nno-newline'"
+	results := RunContext(context.Background(), repos, "sh", []string{"-c", script}, Options{OnLine: onLine})
+
+	var stdout, stderr []string
+	for _, l := range lines {
+		if l.Repo.Name != "repo-a" {
+			t.Errorf("line from %q, want repo-a", l.Repo.Name)
+		}
+		if l.Stderr {
+			stderr = append(stderr, l.Text)
+		} else {
+			stdout = append(stdout, l.Text)
+		}
+	}
+
+	if strings.Join(stdout, "|") != "one|two|no-newline" {
+		t.Errorf("stdout lines = %q, want [one two no-newline]", stdout)
+	}
+	if strings.Join(stderr, "|") != "oops" {
+		t.Errorf("stderr lines = %q, want [oops]", stderr)
+	}
+
+	// Buffered output is still captured for the summary/JSON
+	if !strings.Contains(results[0].Output, "one") || !strings.Contains(results[0].Output, "oops") {
+		t.Errorf("Output = %q, want both streams", results[0].Output)
+	}
+}
+
+func TestRunContextExitCode(t *testing.T) {
+	repos := makeRepos(t, "repo-a")
+
+	tests := []struct {
+		name string
+		args []string
+		opts Options
+		want int
+	}{
+		{"success", []string{"-c", "true"}, Options{}, 0},
+		{"exit 3", []string{"-c", "exit 3"}, Options{}, 3},
+		{"killed", []string{"-c", "sleep 30"}, Options{Timeout: 50 * time.Millisecond}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := RunContext(context.Background(), repos, "sh", tt.args, tt.opts)
+			if results[0].ExitCode != tt.want {
+				t.Errorf("ExitCode = %d, want %d", results[0].ExitCode, tt.want)
+			}
+			if results[0].Duration <= 0 {
+				t.Error("Duration should be set")
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package sbom
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CycloneDX 1.5 JSON (https://cyclonedx.org/docs/1.5/json/)
+
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type               string        `json:"type"`
+	BOMRef             string        `json:"bom-ref,omitempty"`
+	Name               string        `json:"name"`
+	Version            string        `json:"version,omitempty"`
+	Scope              string        `json:"scope,omitempty"`
+	PURL               string        `json:"purl,omitempty"`
+	ExternalReferences []cdxExtRef   `json:"externalReferences,omitempty"`
+	Properties         []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxExtRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// CycloneDX encodes doc as CycloneDX 1.5 JSON. The repo is the metadata
+// component, versioned by its commit; development-only packages have the
+// "excluded" scope. Go modules replaced by a local directory have no
+// version or purl, and name the directory in a property.
+func (doc *Document) CycloneDX() ([]byte, error) {
+	src := doc.Source
+	root := cdxComponent{
+		Type:    "application",
+		BOMRef:  "repo:" + src.Name,
+		Name:    src.Name,
+		Version: src.Commit,
+	}
+	if src.Remote != "" {
+		root.ExternalReferences = []cdxExtRef{{Type: "vcs", URL: src.Remote}}
+	}
+	if src.Commit != "" {
+		root.Properties = append(root.Properties, cdxProperty{Name: "devbot:git:commit", Value: src.Commit})
+		if src.Dirty {
+			root.Properties = append(root.Properties, cdxProperty{Name: "devbot:git:dirty", Value: "true"})
+		}
+	}
+
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + doc.uuid(CycloneDX),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: src.Time.UTC().Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxComponent{{Type: "application", Name: "devbot"}}},
+			Component: root,
+		},
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{{Ref: root.BOMRef, DependsOn: nonNil(doc.Direct)}},
+	}
+	for _, c := range doc.Components {
+		scope := "required"
+		if c.Dev {
+			scope = "excluded"
+		}
+		comp := cdxComponent{
+			Type:    "library",
+			BOMRef:  c.Ref,
+			Name:    c.Name,
+			Version: c.Version,
+			Scope:   scope,
+			PURL:    c.PURL,
+		}
+		if c.Local != "" {
+			comp.Properties = []cdxProperty{{Name: "devbot:go:replace-dir", Value: c.Local}}
+		}
+		bom.Components = append(bom.Components, comp)
+		if len(c.Requires) > 0 {
+			bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: c.Ref, DependsOn: c.Requires})
+		}
+	}
+	return marshal(bom)
+}
+
+func marshal(v any) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
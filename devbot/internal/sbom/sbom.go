@@ -0,0 +1,270 @@
+// Package sbom builds software bills of materials for repos from their
+// analyzed dependencies, encoded as CycloneDX or SPDX JSON.
+//
+// Documents are deterministic: components and relationships are sorted,
+// identifiers are derived from the content, and the timestamp is the commit
+// time, so two SBOMs of the same commit are byte-identical and SBOMs of
+// different commits diff cleanly.
+package sbom
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/deps"
+	"github.com/sloanahrens/devbot-go/internal/git"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// Format is an SBOM standard
+type Format string
+
+// Supported formats
+const (
+	CycloneDX Format = "cyclonedx"
+	SPDX      Format = "spdx"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{CycloneDX, SPDX}
+
+// ParseFormat parses a format name
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown SBOM format %q (want cyclonedx or spdx)", s)
+}
+
+// Extension returns the conventional file suffix for f
+func (f Format) Extension() string {
+	if f == SPDX {
+		return ".spdx.json"
+	}
+	return ".cdx.json"
+}
+
+// Source identifies what a document describes: a repo at a commit
+type Source struct {
+	Name   string
+	Commit string    // Full commit hash; empty outside git
+	Remote string    // origin URL without credentials
+	Time   time.Time // Commit time
+	Dirty  bool      // Tracked files differ from the commit
+}
+
+// GitSource reads the commit a repo is at. Outside git (or before the first
+// commit) the time is the current time, as there is nothing to diff against.
+func GitSource(repo workspace.RepoInfo) Source {
+	src := Source{Name: repo.Name, Time: time.Now().UTC()}
+	commit, err := git.Output(repo.Path, "rev-parse", "--verify", "-q", "HEAD")
+	if err != nil || commit == "" {
+		return src
+	}
+	src.Commit = commit
+
+	if out, err := git.Output(repo.Path, "show", "-s", "--format=%cI", "HEAD"); err == nil {
+		if t, err := time.Parse(time.RFC3339, out); err == nil {
+			src.Time = t.UTC()
+		}
+	}
+	if remote, err := git.Output(repo.Path, "remote", "get-url", "origin"); err == nil {
+		src.Remote = redactURL(remote)
+	}
+	if dirty, _ := git.Output(repo.Path, "status", "--porcelain", "--untracked-files=no"); dirty != "" {
+		src.Dirty = true
+	}
+	return src
+}
+
+// redactURL drops credentials from https remotes ("https://token@host/...")
+func redactURL(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil || u.Scheme == "" || u.Scheme == "ssh" {
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
+
+// Component is one package in a document
+type Component struct {
+	Ref       string // Unique within the document: the purl, or "local:<ecosystem>:<name>"
+	Name      string
+	Version   string // Exact version; empty when only a range is declared
+	Ecosystem string
+	PURL      string   // Empty for local components
+	Local     string   // Directory a Go replace points at; not a published version
+	Dev       bool     // Only used in development
+	Requires  []string // Refs of the components it depends on, sorted
+}
+
+// Document is a format-neutral SBOM for one repo
+type Document struct {
+	Source     Source
+	Components []Component // Sorted by Ref
+	Direct     []string    // Refs the repo depends on directly, sorted
+}
+
+// Build assembles the document for one repo's dependencies. Direct
+// dependencies hang off the repo; transitive ones hang off whichever
+// packages require them, when the lockfile records that.
+func Build(src Source, r deps.RepoDeps) *Document {
+	byRef := make(map[string]*Component)
+	prod := make(map[string]bool) // Refs used outside development anywhere
+	var direct []string
+
+	for _, d := range r.Dependencies {
+		name, version := d.Name, d.Exact()
+		var local string
+		switch fields := strings.Fields(d.Replace); len(fields) {
+		case 2:
+			name = fields[0] // What gets built is the replacement module
+		case 1:
+			// A local directory: nothing in the registry matches what's built
+			version, local = "", fields[0]
+		}
+		ref := PURL(d.Ecosystem, name, version)
+		purl := ref
+		if local != "" {
+			ref, purl = "local:"+d.Ecosystem+":"+name, ""
+		}
+		c := byRef[ref]
+		if c == nil {
+			c = &Component{Ref: ref, Name: name, Version: version, Ecosystem: d.Ecosystem, PURL: purl, Local: local}
+			byRef[ref] = c
+		}
+		if !d.Dev {
+			prod[ref] = true
+		}
+		for _, req := range d.Requires {
+			i := strings.LastIndex(req, "@")
+			if i <= 0 {
+				continue
+			}
+			c.Requires = appendUnique(c.Requires, PURL(d.Ecosystem, req[:i], req[i+1:]))
+		}
+		if d.Direct() {
+			direct = appendUnique(direct, ref)
+		}
+	}
+
+	doc := &Document{Source: src, Direct: direct}
+	for ref, c := range byRef {
+		c.Dev = !prod[ref]
+		// Drop edges to packages the analysis didn't report (workspace links)
+		requires := c.Requires[:0]
+		for _, req := range c.Requires {
+			if byRef[req] != nil {
+				requires = append(requires, req)
+			}
+		}
+		c.Requires = requires
+		sort.Strings(c.Requires)
+		doc.Components = append(doc.Components, *c)
+	}
+	sort.Slice(doc.Components, func(i, j int) bool {
+		return doc.Components[i].Ref < doc.Components[j].Ref
+	})
+	sort.Strings(doc.Direct)
+	return doc
+}
+
+// Encode renders doc in format f
+func (doc *Document) Encode(f Format) ([]byte, error) {
+	if f == SPDX {
+		return doc.SPDX()
+	}
+	return doc.CycloneDX()
+}
+
+// digest hashes everything the document says, for content-derived IDs
+func (doc *Document) digest() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%t\n%s\n", doc.Source.Name, doc.Source.Commit, doc.Source.Remote, doc.Source.Dirty, doc.Source.Time.Format(time.RFC3339))
+	for _, ref := range doc.Direct {
+		fmt.Fprintf(h, "direct %s\n", ref)
+	}
+	for _, c := range doc.Components {
+		fmt.Fprintf(h, "%s %t %s\n", c.Ref, c.Dev, strings.Join(c.Requires, " "))
+		if c.Local != "" {
+			fmt.Fprintf(h, "local %s\n", c.Local)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// uuid returns a name-based (version 5) UUID for doc and format f
+func (doc *Document) uuid(f Format) string {
+	// RFC 4122 URL namespace
+	namespace := []byte{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	h := sha1.New()
+	h.Write(namespace)
+	h.Write([]byte("devbot:" + string(f) + ":" + doc.digest()))
+	sum := h.Sum(nil)
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// purlTypes maps ecosystems to package URL types
+var purlTypes = map[string]string{
+	deps.EcosystemNPM:    "npm",
+	deps.EcosystemGo:     "golang",
+	deps.EcosystemPyPI:   "pypi",
+	deps.EcosystemCrates: "cargo",
+}
+
+// PURL returns the package URL for a package, e.g. "pkg:npm/%40types/node@20.1.0".
+// The version is left off when empty.
+func PURL(ecosystem, name, version string) string {
+	typ := purlTypes[ecosystem]
+	if typ == "" {
+		typ = "generic"
+	}
+	if typ == "pypi" {
+		// The purl spec lowercases PyPI names and uses dashes
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	}
+
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		segments[i] = purlEscape(s)
+	}
+	purl := "pkg:" + typ + "/" + strings.Join(segments, "/")
+	if version != "" {
+		purl += "@" + purlEscape(version)
+	}
+	return purl
+}
+
+// purlEscape percent-encodes everything but unreserved characters
+func purlEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,169 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// SPDX 2.3 JSON (https://spdx.github.io/spdx-spec/v2.3/)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string       `json:"SPDXID"`
+	Name             string       `json:"name"`
+	VersionInfo      string       `json:"versionInfo,omitempty"`
+	DownloadLocation string       `json:"downloadLocation"`
+	FilesAnalyzed    bool         `json:"filesAnalyzed"`
+	SourceInfo       string       `json:"sourceInfo,omitempty"`
+	LicenseConcluded string       `json:"licenseConcluded"`
+	LicenseDeclared  string       `json:"licenseDeclared"`
+	CopyrightText    string       `json:"copyrightText"`
+	ExternalRefs     []spdxExtRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExtRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+const noAssertion = "NOASSERTION"
+
+// SPDX encodes doc as SPDX 2.3 JSON. The repo is the described package,
+// versioned by its commit; packages it uses only in development are related
+// with DEV_DEPENDENCY_OF instead of DEPENDS_ON.
+func (doc *Document) SPDX() ([]byte, error) {
+	src := doc.Source
+	id := doc.uuid(SPDX)
+	root := spdxPackage{
+		SPDXID:           "SPDXRef-Repo-" + spdxSanitize(src.Name),
+		Name:             src.Name,
+		VersionInfo:      src.Commit,
+		DownloadLocation: noAssertion,
+		LicenseConcluded: noAssertion,
+		LicenseDeclared:  noAssertion,
+		CopyrightText:    noAssertion,
+	}
+	if src.Remote != "" && src.Commit != "" {
+		root.DownloadLocation = "git+" + strings.TrimPrefix(src.Remote, "git+") + "@" + src.Commit
+	}
+	if src.Commit != "" {
+		root.SourceInfo = "git commit " + src.Commit
+		if src.Dirty {
+			root.SourceInfo += " with uncommitted changes"
+		}
+	}
+
+	out := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              src.Name,
+		DocumentNamespace: "https://spdx.org/spdxdocs/devbot/" + src.Name + "-" + id,
+		CreationInfo: spdxCreationInfo{
+			Created:  src.Time.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: devbot"},
+		},
+		Packages: []spdxPackage{root},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: root.SPDXID},
+		},
+	}
+
+	ids := spdxIDs(doc.Components)
+	dev := make(map[string]bool)
+	for _, c := range doc.Components {
+		dev[c.Ref] = c.Dev
+		pkg := spdxPackage{
+			SPDXID:           ids[c.Ref],
+			Name:             c.Name,
+			VersionInfo:      c.Version,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  noAssertion,
+			CopyrightText:    noAssertion,
+		}
+		if c.PURL != "" {
+			pkg.ExternalRefs = []spdxExtRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: c.PURL},
+			}
+		}
+		if c.Local != "" {
+			pkg.SourceInfo = "local directory " + c.Local + " (go.mod replace)"
+		}
+		out.Packages = append(out.Packages, pkg)
+	}
+
+	for _, ref := range doc.Direct {
+		if dev[ref] {
+			out.Relationships = append(out.Relationships, spdxRelationship{SPDXElementID: ids[ref], RelationshipType: "DEV_DEPENDENCY_OF", RelatedSPDXElement: root.SPDXID})
+		} else {
+			out.Relationships = append(out.Relationships, spdxRelationship{SPDXElementID: root.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: ids[ref]})
+		}
+	}
+	for _, c := range doc.Components {
+		for _, req := range c.Requires {
+			out.Relationships = append(out.Relationships, spdxRelationship{SPDXElementID: ids[c.Ref], RelationshipType: "DEPENDS_ON", RelatedSPDXElement: ids[req]})
+		}
+	}
+	return marshal(out)
+}
+
+// spdxIDs assigns each component an SPDX identifier readable from its
+// ecosystem, name and version. Names that sanitize to the same identifier
+// get a hash of the purl appended so IDs stay stable as packages come and go.
+func spdxIDs(components []Component) map[string]string {
+	count := make(map[string]int)
+	base := make(map[string]string)
+	for _, c := range components {
+		id := "SPDXRef-Package-" + spdxSanitize(c.Ecosystem+"-"+c.Name)
+		if c.Version != "" {
+			id += "-" + spdxSanitize(c.Version)
+		}
+		base[c.Ref] = id
+		count[id]++
+	}
+
+	ids := make(map[string]string, len(components))
+	for ref, id := range base {
+		if count[id] > 1 {
+			sum := sha256.Sum256([]byte(ref))
+			id += "-" + hex.EncodeToString(sum[:4])
+		}
+		ids[ref] = id
+	}
+	return ids
+}
+
+// spdxSanitize replaces characters SPDX identifiers can't contain
+func spdxSanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, s)
+}
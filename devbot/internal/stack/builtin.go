@@ -0,0 +1,124 @@
+package stack
+
+// builtinDirs are the subdirectories searched for sub-apps
+var builtinDirs = []string{
+	"go-api", "api", "backend", "server", "cmd",
+	"nextapp", "web", "frontend", "app", "client",
+	"packages/*", "apps/*",
+}
+
+// builtinDefs are the built-in stacks, in detection order
+var builtinDefs = []Definition{
+	{
+		Name:    "go",
+		Markers: []string{"go.mod"},
+		Tools:   []string{"go"},
+		Checks: map[string][]string{
+			"lint":  {"golangci-lint", "run"},
+			"build": {"go", "build", "./..."},
+			"test":  {"go", "test", "./..."},
+		},
+		Fix:    []string{"--fix"},
+		Config: []string{"go.mod", "go.sum"},
+	},
+	{
+		Name:    "rust",
+		Markers: []string{"Cargo.toml"},
+		Tools:   []string{"cargo"},
+		Checks: map[string][]string{
+			"lint":  {"cargo", "clippy"},
+			"build": {"cargo", "build"},
+			"test":  {"cargo", "test"},
+		},
+		Fix:    []string{"--fix"},
+		Config: []string{"Cargo.toml", "Cargo.lock"},
+	},
+	{
+		Name:    "python",
+		Markers: []string{"pyproject.toml", "requirements.txt"},
+		Tools:   []string{"python3", "pip3"},
+		Checks: map[string][]string{
+			"lint":      {"uv", "run", "ruff", "check", "."},
+			"typecheck": {"uv", "run", "mypy", "."},
+			"test":      {"uv", "run", "pytest"},
+		},
+		Fix:    []string{"--fix"},
+		Config: []string{"pyproject.toml", "requirements.txt", "setup.py", "setup.cfg", "Pipfile"},
+	},
+	{
+		// A pnpm workspace root; check runs each script across the packages
+		Name:    "monorepo",
+		Markers: []string{"pnpm-workspace.yaml"},
+		Tools:   []string{"node", "npm"},
+		Checks: map[string][]string{
+			"lint":      {"npm", "run", "lint"},
+			"typecheck": {"npm", "run", "typecheck"},
+			"build":     {"npm", "run", "build"},
+			"test":      {"npm", "test"},
+		},
+		Fix:        []string{"--", "--fix"},
+		JS:         true,
+		ConfigType: "node",
+		Config:     []string{"pnpm-workspace.yaml"},
+	},
+	{
+		Name:     "ts",
+		Markers:  []string{"package.json"},
+		Requires: []string{"tsconfig.json"},
+		Tools:    []string{"node", "npm"},
+		Checks: map[string][]string{
+			"lint":      {"npm", "run", "lint"},
+			"typecheck": {"npx", "tsc", "--noEmit"},
+			"build":     {"npm", "run", "build"},
+			"test":      {"npm", "test"},
+		},
+		Fix:        []string{"--", "--fix"},
+		JS:         true,
+		ConfigType: "node",
+		Config:     []string{"package.json", "tsconfig.json"},
+	},
+	{
+		Name:     "js",
+		Markers:  []string{"package.json"},
+		Excludes: []string{"tsconfig.json"},
+		Tools:    []string{"node", "npm"},
+		Checks: map[string][]string{
+			"lint":  {"npm", "run", "lint"},
+			"build": {"npm", "run", "build"},
+			"test":  {"npm", "test"},
+		},
+		Fix:        []string{"--", "--fix"},
+		JS:         true,
+		ConfigType: "node",
+		Config:     []string{"package.json", "pnpm-lock.yaml", "yarn.lock", "package-lock.json", "bun.lockb", "bun.lock", "turbo.json"},
+	},
+	{
+		Name:     "nextjs",
+		Markers:  []string{"next.config.js", "next.config.mjs", "next.config.ts"},
+		Requires: []string{"package.json"},
+		Tools:    []string{"node", "npm"},
+		Checks: map[string][]string{
+			"lint":      {"npm", "run", "lint"},
+			"typecheck": {"npm", "run", "typecheck"},
+			"build":     {"npm", "run", "build"},
+			"test":      {"npm", "test", "--", "--passWithNoTests"},
+		},
+		Fix:        []string{"--", "--fix"},
+		JS:         true,
+		ConfigType: "node",
+	},
+}
+
+var builtin = func() *Registry {
+	defs := make([]Definition, len(builtinDefs))
+	for i, d := range builtinDefs {
+		d.Source = "builtin"
+		defs[i] = d
+	}
+	return &Registry{defs: defs, dirs: builtinDirs}
+}()
+
+// Builtin returns the registry of built-in stacks
+func Builtin() *Registry {
+	return builtin
+}
@@ -0,0 +1,187 @@
+package stack
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a stack definition from the stacks: list in config.yaml. A name
+// matching a built-in stack adjusts it: fields that are set replace the
+// built-in's, checks are merged by type and an empty command removes one.
+// Any other name adds a stack, detected ahead of the built-ins so its
+// commands win where both match (e.g. bun over ts).
+//
+// Example:
+//
+//	stacks:
+//	  - name: gradle
+//	    markers: [build.gradle, build.gradle.kts]
+//	    tools: [java]
+//	    checks:
+//	      build: ./gradlew build
+//	      test: ./gradlew test
+//	    config: [settings.gradle, gradle.properties]
+//	  - name: python
+//	    checks:
+//	      typecheck: ""
+//	stack_dirs: [services/*]
+type Config struct {
+	Name       string             `yaml:"name"`
+	Markers    []string           `yaml:"markers"`     // Files or globs, any of which marks the stack
+	Requires   []string           `yaml:"requires"`    // Files that must also exist
+	Excludes   []string           `yaml:"excludes"`    // Files that must not exist
+	Tools      []string           `yaml:"tools"`       // Binaries checked by prereq
+	Checks     map[string]Command `yaml:"checks"`      // lint, typecheck, build or test -> command
+	Fix        Command            `yaml:"fix"`         // Arguments appended to lint with --fix
+	JS         bool               `yaml:"js"`          // npm commands follow the project's package manager
+	ConfigType string             `yaml:"config_type"` // `devbot config` category (default: name)
+	Config     []string           `yaml:"config"`      // Files listed by `devbot config`
+}
+
+// Command is a command line, written in YAML as either a string or a list.
+// Strings are split on whitespace; use the list form for arguments with spaces.
+type Command []string
+
+// UnmarshalYAML accepts both `command: make test` and `command: [make, test]`
+func (c *Command) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*c = strings.Fields(node.Value)
+		return nil
+	case yaml.SequenceNode:
+		var args []string
+		if err := node.Decode(&args); err != nil {
+			return err
+		}
+		*c = args
+		return nil
+	}
+	return fmt.Errorf("line %d: command must be a string or a list", node.Line)
+}
+
+// New returns the built-in registry extended with stacks and search dirs
+// from config.yaml
+func New(configs []Config, dirs []string) (*Registry, error) {
+	defs := append([]Definition{}, builtin.defs...)
+	var added []Definition
+	seen := make(map[string]bool)
+
+	for i, c := range configs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("stacks[%d]: name is required", i)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("stacks.%s: defined more than once", c.Name)
+		}
+		seen[c.Name] = true
+		if err := c.validate(); err != nil {
+			return nil, err
+		}
+
+		if j := indexOf(defs, c.Name); j >= 0 {
+			defs[j] = c.apply(defs[j])
+			continue
+		}
+		if len(c.Markers) == 0 {
+			return nil, fmt.Errorf("stacks.%s: markers are required for a new stack", c.Name)
+		}
+		added = append(added, c.apply(Definition{Name: c.Name}))
+	}
+
+	r := &Registry{
+		defs: append(added, defs...),
+		dirs: append([]string{}, builtinDirs...),
+	}
+	for _, d := range dirs {
+		if _, err := filepath.Match(d, ""); err != nil {
+			return nil, fmt.Errorf("stack_dirs: %q: %w", d, err)
+		}
+		r.dirs = appendUnique(r.dirs, filepath.Clean(d))
+	}
+	return r, nil
+}
+
+func (c Config) validate() error {
+	for _, list := range []struct {
+		key      string
+		patterns []string
+	}{{"markers", c.Markers}, {"requires", c.Requires}, {"excludes", c.Excludes}} {
+		for _, p := range list.patterns {
+			if _, err := filepath.Match(p, ""); err != nil {
+				return fmt.Errorf("stacks.%s.%s: %q: %w", c.Name, list.key, p, err)
+			}
+		}
+	}
+	for ct := range c.Checks {
+		if !contains(CheckTypes, ct) {
+			return fmt.Errorf("stacks.%s.checks.%s: unknown check type (want %s)",
+				c.Name, ct, strings.Join(CheckTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// apply overlays the fields set in c onto d
+func (c Config) apply(d Definition) Definition {
+	d.Source = "config"
+	if len(c.Markers) > 0 {
+		d.Markers = c.Markers
+	}
+	if len(c.Requires) > 0 {
+		d.Requires = c.Requires
+	}
+	if len(c.Excludes) > 0 {
+		d.Excludes = c.Excludes
+	}
+	if len(c.Tools) > 0 {
+		d.Tools = c.Tools
+	}
+	if len(c.Fix) > 0 {
+		d.Fix = c.Fix
+	}
+	if c.JS {
+		d.JS = true
+	}
+	if c.ConfigType != "" {
+		d.ConfigType = c.ConfigType
+	}
+	if len(c.Config) > 0 {
+		d.Config = c.Config
+	}
+	if len(c.Checks) > 0 {
+		checks := make(map[string][]string)
+		for ct, args := range d.Checks {
+			checks[ct] = args
+		}
+		for ct, args := range c.Checks {
+			if len(args) == 0 {
+				delete(checks, ct)
+			} else {
+				checks[ct] = args
+			}
+		}
+		d.Checks = checks
+	}
+	return d
+}
+
+func indexOf(defs []Definition, name string) int {
+	for i, d := range defs {
+		if d.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+// Package stack is the registry of project stacks shared by detect, check,
+// prereq and config. A Definition says how to recognize a stack from marker
+// files, which tools it needs, which commands `devbot check` runs and which
+// files `devbot config` lists. The built-ins cover Go, Rust, Python and
+// Node; config.yaml can add stacks or adjust the built-ins (see Config).
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CheckTypes are the standard checks, in the order check runs them
+var CheckTypes = []string{"lint", "typecheck", "build", "test"}
+
+// Definition describes one stack
+type Definition struct {
+	Name       string
+	Markers    []string            // Any one of these (file names or globs) marks the stack
+	Requires   []string            // ...provided all of these exist too
+	Excludes   []string            // ...and none of these do
+	Tools      []string            // Binaries prereq looks for on PATH
+	Checks     map[string][]string // Check type -> command line
+	Fix        []string            // Arguments appended to the lint command for --fix
+	JS         bool                // npm commands follow the project's package manager
+	ConfigType string              // `devbot config` category (default: Name)
+	Config     []string            // Config files `devbot config` lists
+	Source     string              // "builtin" or "config"
+}
+
+// Registry is an ordered set of stack definitions plus the subdirectories
+// searched for sub-apps. Detection reports stacks in registry order.
+type Registry struct {
+	defs []Definition
+	dirs []string
+}
+
+// Definitions returns the stacks in detection order
+func (r *Registry) Definitions() []Definition {
+	return r.defs
+}
+
+// Lookup returns the definition for a stack name
+func (r *Registry) Lookup(name string) (Definition, bool) {
+	for _, d := range r.defs {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// SearchDirs returns the subdirectory patterns (relative, possibly globs
+// such as "packages/*") where sub-apps live
+func (r *Registry) SearchDirs() []string {
+	return r.dirs
+}
+
+// DetectAt returns the stacks whose markers are present in dir itself
+func (r *Registry) DetectAt(dir string) []string {
+	var stacks []string
+	for _, d := range r.defs {
+		if d.matches(dir) {
+			stacks = append(stacks, d.Name)
+		}
+	}
+	return stacks
+}
+
+// Command returns the command for a check, taken from the first stack in
+// stacks that defines one, along with that stack's name
+func (r *Registry) Command(stacks []string, checkType string) ([]string, string) {
+	for _, s := range stacks {
+		if d, ok := r.Lookup(s); ok {
+			if args, ok := d.Checks[checkType]; ok {
+				return args, s
+			}
+		}
+	}
+	return nil, ""
+}
+
+// FixCommand returns args with the --fix arguments of the first stack in
+// stacks that has any; args is returned unchanged if none does
+func (r *Registry) FixCommand(args []string, stacks []string) []string {
+	for _, s := range stacks {
+		if d, ok := r.Lookup(s); ok && len(d.Fix) > 0 {
+			return append(append([]string{}, args...), d.Fix...)
+		}
+	}
+	return args
+}
+
+// ConfigPatterns returns the config files of every stack, grouped by
+// ConfigType
+func (r *Registry) ConfigPatterns() map[string][]string {
+	patterns := make(map[string][]string)
+	for _, d := range r.defs {
+		t := d.ConfigType
+		if t == "" {
+			t = d.Name
+		}
+		for _, f := range d.Config {
+			patterns[t] = appendUnique(patterns[t], f)
+		}
+	}
+	return patterns
+}
+
+func (d Definition) matches(dir string) bool {
+	return d.examine(dir).Matched
+}
+
+// Evidence records how one stack definition fared in a directory
+type Evidence struct {
+	Stack    string
+	Matched  bool
+	Found    []string // Markers and required files present (globs show the file matched)
+	Missing  []string // Required files absent, or every marker if none was present
+	Excluded []string // Excluded files that are present
+}
+
+// Reason summarizes the evidence: what matched, or why nothing did
+func (e Evidence) Reason() string {
+	switch {
+	case e.Matched:
+		return strings.Join(e.Found, ", ")
+	case len(e.Found) == 0:
+		return "no " + strings.Join(e.Missing, ", ")
+	case len(e.Excluded) > 0:
+		return strings.Join(e.Found, ", ") + " but " + strings.Join(e.Excluded, ", ") + " present"
+	}
+	return strings.Join(e.Found, ", ") + " but no " + strings.Join(e.Missing, ", ")
+}
+
+// Explain reports, for every stack in detection order, which of its files
+// are present in dir
+func (r *Registry) Explain(dir string) []Evidence {
+	evidence := make([]Evidence, len(r.defs))
+	for i, d := range r.defs {
+		evidence[i] = d.examine(dir)
+	}
+	return evidence
+}
+
+func (d Definition) examine(dir string) Evidence {
+	e := Evidence{Stack: d.Name}
+	for _, m := range d.Markers {
+		if name := find(dir, m); name != "" {
+			e.Found = append(e.Found, name)
+		}
+	}
+	if len(e.Found) == 0 {
+		e.Missing = d.Markers
+		return e
+	}
+	for _, f := range d.Requires {
+		if name := find(dir, f); name != "" {
+			e.Found = append(e.Found, name)
+		} else {
+			e.Missing = append(e.Missing, f)
+		}
+	}
+	for _, f := range d.Excludes {
+		if name := find(dir, f); name != "" {
+			e.Excluded = append(e.Excluded, name)
+		}
+	}
+	e.Matched = len(e.Missing) == 0 && len(e.Excluded) == 0
+	return e
+}
+
+// Expand returns the existing directories under repoPath matching a search
+// dir pattern, relative to repoPath
+func Expand(repoPath, pattern string) []string {
+	matches, _ := filepath.Glob(filepath.Join(repoPath, pattern))
+	var dirs []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && info.IsDir() {
+			rel, _ := filepath.Rel(repoPath, m)
+			dirs = append(dirs, rel)
+		}
+	}
+	return dirs
+}
+
+// IsGlob reports whether a marker or search dir is a pattern
+func IsGlob(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// find returns name if it exists in dir, or for a glob the first file
+// matching it; "" if there is none
+func find(dir, name string) string {
+	if IsGlob(name) {
+		matches, _ := filepath.Glob(filepath.Join(dir, name))
+		if len(matches) == 0 {
+			return ""
+		}
+		return filepath.Base(matches[0])
+	}
+	if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+		return ""
+	}
+	return name
+}
+
+func appendUnique(slice []string, item string) []string {
+	for _, s := range slice {
+		if s == item {
+			return slice
+		}
+	}
+	return append(slice, item)
+}
+
+var (
+	defaultMu  sync.RWMutex
+	defaultReg *Registry
+)
+
+// Default returns the registry set with SetDefault, or the built-ins
+func Default() *Registry {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultReg == nil {
+		return Builtin()
+	}
+	return defaultReg
+}
+
+// SetDefault replaces the registry returned by Default (nil restores the
+// built-ins)
+func SetDefault(r *Registry) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultReg = r
+}
@@ -0,0 +1,240 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBuiltinDetectAt(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  []string
+	}{
+		{[]string{"go.mod"}, []string{"go"}},
+		{[]string{"Cargo.toml"}, []string{"rust"}},
+		{[]string{"requirements.txt"}, []string{"python"}},
+		{[]string{"pyproject.toml", "requirements.txt"}, []string{"python"}},
+		{[]string{"pnpm-workspace.yaml", "package.json"}, []string{"monorepo", "js"}},
+		{[]string{"package.json", "tsconfig.json"}, []string{"ts"}},
+		{[]string{"package.json", "tsconfig.json", "next.config.mjs"}, []string{"ts", "nextjs"}},
+		{[]string{"package.json", "next.config.js"}, []string{"js", "nextjs"}},
+		{[]string{"next.config.js"}, nil},
+		{[]string{"go.mod", "package.json"}, []string{"go", "js"}},
+		{nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.files, ","), func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files...)
+			if got := Builtin().DetectAt(dir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	reg := Builtin()
+
+	args, s := reg.Command([]string{"ts", "nextjs"}, "typecheck")
+	if s != "ts" || strings.Join(args, " ") != "npx tsc --noEmit" {
+		t.Errorf("Command(ts, nextjs) = %v from %q, want npx tsc --noEmit from ts", args, s)
+	}
+	args, s = reg.Command([]string{"python", "go"}, "build")
+	if s != "go" || strings.Join(args, " ") != "go build ./..." {
+		t.Errorf("Command(python, go) = %v from %q, want go build from go", args, s)
+	}
+	if args, _ := reg.Command([]string{"go"}, "typecheck"); args != nil {
+		t.Errorf("Command(go, typecheck) = %v, want none", args)
+	}
+
+	lint, _ := reg.Command([]string{"js"}, "lint")
+	fixed := reg.FixCommand(lint, []string{"monorepo", "js"})
+	if strings.Join(fixed, " ") != "npm run lint -- --fix" {
+		t.Errorf("FixCommand = %v", fixed)
+	}
+	if strings.Join(lint, " ") != "npm run lint" {
+		t.Errorf("FixCommand modified the registry's command: %v", lint)
+	}
+}
+
+func TestNew(t *testing.T) {
+	var cfg struct {
+		Stacks    []Config `yaml:"stacks"`
+		StackDirs []string `yaml:"stack_dirs"`
+	}
+	src := `
+stacks:
+  - name: terraform
+    markers: ["*.tf"]
+    tools: [terraform]
+    checks:
+      lint: terraform fmt -check
+      build: [terraform, validate]
+    config_type: iac
+    config: [.terraform.lock.hcl]
+  - name: python
+    checks:
+      typecheck: ""
+      test: uv run pytest -x
+stack_dirs: [services/*, infra]
+`
+	if err := yaml.Unmarshal([]byte(src), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	reg, err := New(cfg.Stacks, cfg.StackDirs)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// New stacks are detected ahead of the built-ins
+	if names := reg.Definitions(); names[0].Name != "terraform" || names[0].Source != "config" {
+		t.Errorf("first definition = %+v, want terraform from config", names[0])
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, "main.tf", "requirements.txt")
+	if got := reg.DetectAt(dir); !reflect.DeepEqual(got, []string{"terraform", "python"}) {
+		t.Errorf("DetectAt = %v, want [terraform python]", got)
+	}
+
+	tf, _ := reg.Lookup("terraform")
+	if strings.Join(tf.Checks["lint"], " ") != "terraform fmt -check" || len(tf.Checks["build"]) != 2 {
+		t.Errorf("terraform checks = %v", tf.Checks)
+	}
+
+	// Overriding a built-in merges checks; an empty command removes one
+	py, _ := reg.Lookup("python")
+	if _, ok := py.Checks["typecheck"]; ok {
+		t.Errorf("python typecheck should be removed: %v", py.Checks)
+	}
+	if strings.Join(py.Checks["test"], " ") != "uv run pytest -x" {
+		t.Errorf("python test = %v", py.Checks["test"])
+	}
+	if strings.Join(py.Checks["lint"], " ") != "uv run ruff check ." {
+		t.Errorf("python lint = %v, want built-in", py.Checks["lint"])
+	}
+	if !reflect.DeepEqual(py.Tools, []string{"python3", "pip3"}) || py.Source != "config" {
+		t.Errorf("python = %+v, want built-in tools from config", py)
+	}
+
+	// The built-in registry is untouched
+	if builtinPy, _ := Builtin().Lookup("python"); builtinPy.Checks["typecheck"] == nil {
+		t.Error("New modified the built-in python stack")
+	}
+
+	dirs := reg.SearchDirs()
+	if dirs[len(dirs)-2] != "services/*" || dirs[len(dirs)-1] != "infra" {
+		t.Errorf("SearchDirs = %v, want configured dirs last", dirs)
+	}
+
+	if got := reg.ConfigPatterns()["iac"]; !reflect.DeepEqual(got, []string{".terraform.lock.hcl"}) {
+		t.Errorf("ConfigPatterns[iac] = %v", got)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []Config
+		dirs    []string
+		want    string
+	}{
+		{"no name", []Config{{Markers: []string{"x"}}}, nil, "stacks[0]: name is required"},
+		{"no markers", []Config{{Name: "ruby"}}, nil, "stacks.ruby: markers are required"},
+		{"duplicate", []Config{{Name: "go"}, {Name: "go"}}, nil, "stacks.go: defined more than once"},
+		{"unknown check", []Config{{Name: "go", Checks: map[string]Command{"e2e": {"make"}}}}, nil, "stacks.go.checks.e2e: unknown check type"},
+		{"bad glob", []Config{{Name: "x", Markers: []string{"[a"}}}, nil, "stacks.x.markers"},
+		{"bad dir", nil, []string{"[a"}, "stack_dirs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.configs, tt.dirs)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("New error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "packages/a/package.json", "packages/b/package.json", "packages/README.md", "api/go.mod")
+
+	if got := Expand(dir, "packages/*"); !reflect.DeepEqual(got, []string{"packages/a", "packages/b"}) {
+		t.Errorf("Expand(packages/*) = %v", got)
+	}
+	if got := Expand(dir, "api"); !reflect.DeepEqual(got, []string{"api"}) {
+		t.Errorf("Expand(api) = %v", got)
+	}
+	if got := Expand(dir, "web"); got != nil {
+		t.Errorf("Expand(web) = %v, want nil", got)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	reg, err := New([]Config{{Name: "deno", Markers: []string{"deno.json"}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetDefault(reg)
+	defer SetDefault(nil)
+
+	if _, ok := Default().Lookup("deno"); !ok {
+		t.Error("Default should return the registry from SetDefault")
+	}
+	SetDefault(nil)
+	if Default() != Builtin() {
+		t.Error("SetDefault(nil) should restore the built-ins")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	reg, err := New([]Config{{Name: "terraform", Markers: []string{"*.tf"}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, "package.json", "main.tf")
+
+	reasons := map[string]string{}
+	for _, e := range reg.Explain(dir) {
+		reasons[e.Stack] = e.Reason()
+		if e.Matched != (e.Stack == "terraform" || e.Stack == "js") {
+			t.Errorf("%s matched = %v", e.Stack, e.Matched)
+		}
+	}
+
+	want := map[string]string{
+		"terraform": "main.tf",
+		"js":        "package.json",
+		"ts":        "package.json but no tsconfig.json",
+		"rust":      "no Cargo.toml",
+		"nextjs":    "no next.config.js, next.config.mjs, next.config.ts",
+	}
+	for s, reason := range want {
+		if reasons[s] != reason {
+			t.Errorf("%s reason = %q, want %q", s, reasons[s], reason)
+		}
+	}
+}
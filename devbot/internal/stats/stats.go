@@ -8,6 +8,8 @@ import (
 	"sort"
 	"strings"
 	"sync"
+
+	"github.com/sloanahrens/devbot-go/internal/ignore"
 )
 
 // FileStats holds statistics for a single file
@@ -202,18 +204,12 @@ func AnalyzeDir(path string, langFilter string) (DirStats, error) {
 	stats := DirStats{Path: path}
 
 	var files []string
-	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+	// Count what git would, plus vendored code that isn't the repo's own
+	err := ignore.New(path, "vendor/").Walk(func(p string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors
 		}
 		if info.IsDir() {
-			name := info.Name()
-			// Skip common non-source directories
-			if name == "node_modules" || name == ".git" || name == "dist" ||
-				name == "build" || name == "__pycache__" || name == "vendor" ||
-				name == ".next" || name == "coverage" {
-				return filepath.SkipDir
-			}
 			return nil
 		}
 
@@ -295,6 +295,41 @@ func TestAnalyzeDir_SkipsNodeModules(t *testing.T) {
 	}
 }
 
+func TestAnalyzeDir_RespectsGitignore(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		".gitignore":           "/gen\n*_mock.go\n",
+		"main.go":              "package main\nfunc main() {}\n",
+		"api_mock.go":          "package main\n",
+		"gen/types.go":         "package gen\n",
+		"internal/gen/real.go": "package gen\n", // /gen is anchored to the root
+		"vendor/lib/lib.go":    "package lib\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	stats, err := AnalyzeDir(tmpDir, "")
+	if err != nil {
+		t.Fatalf("AnalyzeDir failed: %v", err)
+	}
+
+	if stats.TotalFiles != 2 {
+		var paths []string
+		for _, f := range stats.Files {
+			paths = append(paths, f.Path)
+		}
+		t.Errorf("TotalFiles = %d, want 2 (main.go, internal/gen/real.go): %v", stats.TotalFiles, paths)
+	}
+}
+
 func TestAnalyzeDir_LargeFiles(t *testing.T) {
 	tmpDir := t.TempDir()
 
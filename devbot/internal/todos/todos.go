@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/sloanahrens/devbot-go/internal/ignore"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
@@ -41,21 +42,6 @@ var (
 		".yml":  true,
 	}
 
-	// Directories to always skip
-	skipDirs = map[string]bool{
-		"node_modules":  true,
-		".git":          true,
-		"dist":          true,
-		"build":         true,
-		".next":         true,
-		".turbo":        true,
-		"vendor":        true,
-		"__pycache__":   true,
-		".pytest_cache": true,
-		"coverage":      true,
-		".nyc_output":   true,
-	}
-
 	// Regex to match TODO-style comments
 	todoPattern = regexp.MustCompile(`\b(TODO|FIXME|HACK|XXX|BUG)\b[:\s]*(.*)`)
 )
@@ -89,16 +75,13 @@ func ScanParallel(repos []workspace.RepoInfo, typeFilter string) []RepoTodos {
 func scanRepo(repo workspace.RepoInfo, typeFilter string) RepoTodos {
 	result := RepoTodos{Repo: repo}
 
-	err := filepath.Walk(repo.Path, func(path string, info os.FileInfo, err error) error {
+	// Walk what git would, plus vendored code that isn't the repo's own
+	err := ignore.New(repo.Path, "vendor/").Walk(func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // skip errors
 		}
 
-		// Skip directories we don't want
 		if info.IsDir() {
-			if skipDirs[info.Name()] {
-				return filepath.SkipDir
-			}
 			// Skip hidden directories
 			if strings.HasPrefix(info.Name(), ".") && info.Name() != "." {
 				return filepath.SkipDir
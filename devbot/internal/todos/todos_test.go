@@ -3,6 +3,8 @@ package todos
 import (
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/sloanahrens/devbot-go/internal/workspace"
@@ -110,6 +112,43 @@ func TestScanSkipsNodeModules(t *testing.T) {
 	}
 }
 
+func TestScanRespectsGitignore(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := filepath.Join(tmpDir, "test-repo")
+
+	files := map[string]string{
+		".gitignore":           "generated/\n*.gen.go\n",
+		"main.go":              "// TODO: root",
+		"api.gen.go":           "// TODO: generated file",
+		"generated/types.go":   "// TODO: generated dir",
+		"pkg/.gitignore":       "!keep.gen.go\n",
+		"pkg/keep.gen.go":      "// TODO: re-included",
+		"vendor/lib/vendor.go": "// TODO: vendored",
+	}
+	for name, content := range files {
+		path := filepath.Join(repoPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	repos := []workspace.RepoInfo{{Name: "test-repo", Path: repoPath}}
+	results := ScanParallel(repos, "")
+
+	var found []string
+	for _, item := range results[0].Items {
+		found = append(found, item.RelPath)
+	}
+	sort.Strings(found)
+	want := []string{"main.go", filepath.Join("pkg", "keep.gen.go")}
+	if strings.Join(found, " ") != strings.Join(want, " ") {
+		t.Errorf("Found TODOs in %v, want %v", found, want)
+	}
+}
+
 func TestScanSkipsHiddenDirs(t *testing.T) {
 	tmpDir := t.TempDir()
 	repoPath := filepath.Join(tmpDir, "test-repo")
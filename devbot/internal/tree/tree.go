@@ -1,11 +1,12 @@
 package tree
 
 import (
-	"bufio"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/ignore"
 )
 
 // Options for tree display
@@ -28,12 +29,11 @@ func Build(root string, opts Options) (Entry, error) {
 		return Entry{}, err
 	}
 
-	ignorePatterns := loadGitignore(root)
-
-	return buildEntry(root, info.Name(), 0, opts, ignorePatterns)
+	return buildEntry(root, ".", info.Name(), 0, opts, ignore.New(root))
 }
 
-func buildEntry(path, name string, depth int, opts Options, ignorePatterns []string) (Entry, error) {
+// buildEntry reads path (rel to the root of the tree) and its children
+func buildEntry(path, rel, name string, depth int, opts Options, matcher *ignore.Matcher) (Entry, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return Entry{}, err
@@ -75,13 +75,14 @@ func buildEntry(path, name string, depth int, opts Options, ignorePatterns []str
 			continue
 		}
 
-		// Skip if matches gitignore
-		if shouldIgnore(childName, e.IsDir(), ignorePatterns) {
+		// Skip what git ignores
+		childRel := filepath.Join(rel, childName)
+		if matcher.Match(childRel, e.IsDir()) {
 			continue
 		}
 
 		childPath := filepath.Join(path, childName)
-		child, err := buildEntry(childPath, childName, depth+1, opts, ignorePatterns)
+		child, err := buildEntry(childPath, childRel, childName, depth+1, opts, matcher)
 		if err != nil {
 			continue
 		}
@@ -91,53 +92,6 @@ func buildEntry(path, name string, depth int, opts Options, ignorePatterns []str
 	return entry, nil
 }
 
-func loadGitignore(root string) []string {
-	var patterns []string
-
-	// Always ignore these
-	patterns = append(patterns, "node_modules", ".git", "__pycache__", ".pytest_cache",
-		"dist", "build", ".next", ".turbo", "coverage", ".nyc_output",
-		"*.pyc", "*.pyo", ".DS_Store", "Thumbs.db")
-
-	// Load .gitignore if exists
-	gitignorePath := filepath.Join(root, ".gitignore")
-	file, err := os.Open(gitignorePath)
-	if err != nil {
-		return patterns
-	}
-	defer func() { _ = file.Close() }()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" && !strings.HasPrefix(line, "#") {
-			patterns = append(patterns, line)
-		}
-	}
-
-	return patterns
-}
-
-func shouldIgnore(name string, isDir bool, patterns []string) bool {
-	for _, pattern := range patterns {
-		// Simple matching - just check if name matches pattern
-		pattern = strings.TrimSuffix(pattern, "/")
-
-		if pattern == name {
-			return true
-		}
-
-		// Handle glob patterns
-		if strings.Contains(pattern, "*") {
-			matched, _ := filepath.Match(pattern, name)
-			if matched {
-				return true
-			}
-		}
-	}
-	return false
-}
-
 // Render formats the tree as a string
 func Render(entry Entry, prefix string, isLast bool, isRoot bool) string {
 	var sb strings.Builder
@@ -250,90 +250,89 @@ func TestRenderEmptyDir(t *testing.T) {
 	}
 }
 
-func TestShouldIgnore(t *testing.T) {
-	patterns := []string{"node_modules", "*.pyc", "build"}
-
-	tests := []struct {
-		name     string
-		isDir    bool
-		expected bool
-	}{
-		{"node_modules", true, true},
-		{"src", true, false},
-		{"test.pyc", false, true},
-		{"test.py", false, false},
-		{"build", true, true},
-		{"rebuild", true, false},
-	}
-
-	for _, tt := range tests {
-		result := shouldIgnore(tt.name, tt.isDir, patterns)
-		if result != tt.expected {
-			t.Errorf("shouldIgnore(%q, %v) = %v, want %v", tt.name, tt.isDir, result, tt.expected)
-		}
+// names lists an entry's children
+func names(entry Entry) []string {
+	var out []string
+	for _, child := range entry.Children {
+		out = append(out, child.Name)
 	}
+	return out
 }
 
-func TestLoadGitignore(t *testing.T) {
+func TestBuildGitignore(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	// Create .gitignore
-	gitignore := `# Comment
-*.log
-dist/
-temp
-`
-	if err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte(gitignore), 0644); err != nil {
-		t.Fatalf("Failed to write .gitignore: %v", err)
+	files := map[string]string{
+		".gitignore":         "# Comment\n*.log\ndist/\ntemp\n/out\n!keep.log\n",
+		"app.log":            "",
+		"keep.log":           "",
+		"temp":               "",
+		"main.go":            "",
+		"out/bin":            "",
+		"src/out/bin":        "",
+		"src/temp/x.go":      "",
+		"src/.gitignore":     "*.gen.go\n",
+		"src/api.gen.go":     "",
+		"src/api.go":         "",
+		"src/debug.log":      "",
+		"src/sub/a.gen.go":   "",
+		"src/sub/b.go":       "",
+		"dist/bundle.js":     "",
+		"docs/dist/index.md": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
 	}
 
-	patterns := loadGitignore(tmpDir)
-
-	// Should have default patterns + custom ones
-	if len(patterns) < 4 {
-		t.Errorf("Expected at least 4 patterns, got %d", len(patterns))
+	entry, err := Build(tmpDir, Options{MaxDepth: 5})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
 	}
 
-	// Check custom patterns are included
-	hasLog := false
-	hasDist := false
-	hasTemp := false
-	for _, p := range patterns {
-		if p == "*.log" {
-			hasLog = true
-		}
-		if p == "dist/" {
-			hasDist = true
-		}
-		if p == "temp" {
-			hasTemp = true
-		}
+	// Directories first, then files; docs/ is empty once dist/ is skipped
+	if got := strings.Join(names(entry), " "); got != "docs src keep.log main.go" {
+		t.Errorf("root children = %s", got)
 	}
-
-	if !hasLog || !hasDist || !hasTemp {
-		t.Errorf("Missing custom patterns. hasLog=%v, hasDist=%v, hasTemp=%v", hasLog, hasDist, hasTemp)
+	src := entry.Children[1]
+	if got := strings.Join(names(src), " "); got != "out sub api.go" {
+		t.Errorf("src children = %s (nested .gitignore and unanchored patterns)", got)
+	}
+	if got := strings.Join(names(src.Children[1]), " "); got != "b.go" {
+		t.Errorf("src/sub children = %s", got)
 	}
 }
 
-func TestLoadGitignoreNoFile(t *testing.T) {
+func TestBuildDefaultIgnores(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	patterns := loadGitignore(tmpDir)
+	for _, dir := range []string{"__pycache__", "coverage", "build", "src"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "mod.pyc"), []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to write mod.pyc: %v", err)
+	}
 
-	// Should still have default patterns
-	if len(patterns) == 0 {
-		t.Error("Should have default patterns even without .gitignore")
+	// Default patterns apply without a .gitignore
+	entry, _ := Build(tmpDir, Options{MaxDepth: 3})
+	if got := strings.Join(names(entry), " "); got != "src" {
+		t.Errorf("children = %s, want only src", got)
 	}
 
-	// Verify some defaults
-	hasNodeModules := false
-	for _, p := range patterns {
-		if p == "node_modules" {
-			hasNodeModules = true
-		}
+	// ...and a .gitignore can re-include them
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte("!build/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .gitignore: %v", err)
 	}
-	if !hasNodeModules {
-		t.Error("Default patterns should include node_modules")
+	entry, _ = Build(tmpDir, Options{MaxDepth: 3})
+	if got := strings.Join(names(entry), " "); got != "build src" {
+		t.Errorf("children = %s, want build re-included", got)
 	}
 }
 
@@ -0,0 +1,402 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sloanahrens/devbot-go/internal/output"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// View is what the dashboard is showing
+type View int
+
+const (
+	ViewList View = iota
+	ViewDiff
+	ViewBranch
+	ViewLog
+)
+
+var viewTitles = map[View]string{ViewDiff: "diff", ViewBranch: "branch", ViewLog: "log"}
+
+// Action is what the caller should do after a key press
+type Action int
+
+const (
+	ActionNone    Action = iota
+	ActionQuit           // Exit the dashboard
+	ActionRefresh        // Re-read every repo's status
+	ActionDetail         // Load detail lines for Selected() into the current view
+)
+
+// Dashboard holds the watch screen's state. It does no I/O: the caller
+// feeds it statuses and key presses and draws what Render returns.
+type Dashboard struct {
+	Workspace string
+	Mode      string // Change detection in use, shown in the header
+	ShowAll   bool   // Show every repo, not just those needing attention
+
+	statuses []workspace.RepoStatus
+	selected string // Name of the selected repo, stable across updates
+	updated  time.Time
+
+	view   View
+	detail []string
+	scroll int
+}
+
+// NewDashboard returns a dashboard listing every repo
+func NewDashboard(workspacePath, mode string) *Dashboard {
+	return &Dashboard{Workspace: workspacePath, Mode: mode, ShowAll: true}
+}
+
+// SetStatuses replaces all statuses
+func (d *Dashboard) SetStatuses(statuses []workspace.RepoStatus, now time.Time) {
+	d.statuses = append([]workspace.RepoStatus(nil), statuses...)
+	sort.Slice(d.statuses, func(i, j int) bool { return d.statuses[i].Name < d.statuses[j].Name })
+	d.updated = now
+	d.keepSelection()
+}
+
+// UpdateStatuses replaces the statuses of the given repos only
+func (d *Dashboard) UpdateStatuses(statuses []workspace.RepoStatus, now time.Time) {
+	byName := make(map[string]workspace.RepoStatus, len(statuses))
+	for _, s := range statuses {
+		byName[s.Name] = s
+	}
+	for i, s := range d.statuses {
+		if updated, ok := byName[s.Name]; ok {
+			d.statuses[i] = updated
+		}
+	}
+	d.updated = now
+	d.keepSelection()
+}
+
+// Selected returns the selected repo, if any repo is visible
+func (d *Dashboard) Selected() (workspace.RepoInfo, bool) {
+	for _, s := range d.visible() {
+		if s.Name == d.selected {
+			return s.RepoInfo, true
+		}
+	}
+	return workspace.RepoInfo{}, false
+}
+
+// View returns the current view
+func (d *Dashboard) View() View {
+	return d.view
+}
+
+// SetDetail shows lines for the current drill-down view
+func (d *Dashboard) SetDetail(lines []string) {
+	d.detail = lines
+	if d.scroll >= len(lines) {
+		d.scroll = 0
+	}
+}
+
+// Key handles one key press (see ParseKeys for names)
+func (d *Dashboard) Key(key string) Action {
+	if d.view != ViewList {
+		switch key {
+		case "q", "esc", "backspace", "left", "h":
+			d.view, d.detail, d.scroll = ViewList, nil, 0
+		case "up", "k":
+			d.scrollBy(-1)
+		case "down", "j":
+			d.scrollBy(1)
+		case "pgup":
+			d.scrollBy(-10)
+		case "pgdn", " ":
+			d.scrollBy(10)
+		case "d":
+			return d.open(ViewDiff)
+		case "b":
+			return d.open(ViewBranch)
+		case "l":
+			return d.open(ViewLog)
+		case "r":
+			return ActionDetail
+		}
+		return ActionNone
+	}
+
+	switch key {
+	case "q", "ctrl-c":
+		return ActionQuit
+	case "up", "k":
+		d.move(-1)
+	case "down", "j":
+		d.move(1)
+	case "home", "g":
+		d.move(-len(d.statuses))
+	case "end", "G":
+		d.move(len(d.statuses))
+	case "a":
+		d.ShowAll = !d.ShowAll
+		d.keepSelection()
+	case "r":
+		return ActionRefresh
+	case "enter", "right", "d":
+		return d.open(ViewDiff)
+	case "b":
+		return d.open(ViewBranch)
+	case "l":
+		return d.open(ViewLog)
+	}
+	return ActionNone
+}
+
+func (d *Dashboard) open(v View) Action {
+	if _, ok := d.Selected(); !ok {
+		return ActionNone
+	}
+	d.view, d.detail, d.scroll = v, nil, 0
+	return ActionDetail
+}
+
+func (d *Dashboard) move(delta int) {
+	visible := d.visible()
+	if len(visible) == 0 {
+		return
+	}
+	i := d.selectedIndex(visible) + delta
+	i = max(0, min(i, len(visible)-1))
+	d.selected = visible[i].Name
+}
+
+func (d *Dashboard) scrollBy(delta int) {
+	d.scroll = max(0, min(d.scroll+delta, len(d.detail)-1))
+}
+
+// keepSelection makes sure a visible repo is selected
+func (d *Dashboard) keepSelection() {
+	visible := d.visible()
+	if len(visible) == 0 {
+		return
+	}
+	if d.selectedIndex(visible) < 0 {
+		d.selected = visible[0].Name
+	}
+}
+
+func (d *Dashboard) selectedIndex(visible []workspace.RepoStatus) int {
+	for i, s := range visible {
+		if s.Name == d.selected {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *Dashboard) visible() []workspace.RepoStatus {
+	if d.ShowAll {
+		return d.statuses
+	}
+	var out []workspace.RepoStatus
+	for _, s := range d.statuses {
+		if len(output.AttentionReasons(s)) > 0 {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Render returns the screen as lines, at most height lines of at most
+// width columns each. The selected row is shown in reverse video.
+func (d *Dashboard) Render(width, height int) []string {
+	displayPath := d.Workspace
+	if home, err := os.UserHomeDir(); err == nil {
+		displayPath = strings.Replace(d.Workspace, home, "~", 1)
+	}
+
+	title := fmt.Sprintf(" devbot watch  %s", displayPath)
+	if d.view != ViewList {
+		repo, _ := d.Selected()
+		title = fmt.Sprintf(" devbot watch  %s › %s", repo.Name, viewTitles[d.view])
+	}
+	header := []string{
+		fmt.Sprintf("%s  (%s, updated %s)", title, d.Mode, d.updated.Format("15:04:05")),
+		strings.Repeat("─", max(0, width)),
+	}
+
+	var footer string
+	var body []string
+	rows := max(0, height-len(header)-2)
+
+	if d.view == ViewList {
+		footer = " ↑↓ select  enter/d diff  b branch  l log  a all/attention  r refresh  q quit"
+		body = d.renderList(rows)
+	} else {
+		footer = " ↑↓ scroll  d diff  b branch  l log  r reload  esc back  q back"
+		body = d.renderDetail(rows)
+	}
+
+	lines := append(header, body...)
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, footer)
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	if len(lines) > height {
+		lines = lines[:max(0, height)]
+	}
+
+	// Highlight after truncating so escape codes aren't cut
+	if d.view == ViewList {
+		if i := d.selectedIndex(d.visibleWindow(rows)); i >= 0 && len(header)+i < len(lines) {
+			row := len(header) + i
+			lines[row] = "\x1b[7m" + pad(lines[row], width) + "\x1b[0m"
+		}
+	}
+	return lines
+}
+
+func (d *Dashboard) renderList(rows int) []string {
+	visible := d.visible()
+	if len(visible) == 0 {
+		if len(d.statuses) == 0 {
+			return []string{"  No repositories"}
+		}
+		return []string{fmt.Sprintf("  All repositories up-to-date (%d hidden; a to show)", len(d.statuses))}
+	}
+
+	var lines []string
+	for _, s := range d.visibleWindow(rows) {
+		marker := "  "
+		if len(output.AttentionReasons(s)) > 0 {
+			marker = " !"
+		}
+		lines = append(lines, marker+" "+output.FormatRepoLine(s))
+	}
+	return lines
+}
+
+// visibleWindow is the slice of visible repos that fits in rows, scrolled
+// so the selection stays on screen
+func (d *Dashboard) visibleWindow(rows int) []workspace.RepoStatus {
+	visible := d.visible()
+	if rows <= 0 || len(visible) <= rows {
+		return visible
+	}
+	start := max(0, d.selectedIndex(visible)-rows+1)
+	return visible[start : start+rows]
+}
+
+func (d *Dashboard) renderDetail(rows int) []string {
+	if d.detail == nil {
+		return []string{"  Loading..."}
+	}
+	if len(d.detail) == 0 {
+		return []string{"  (nothing to show)"}
+	}
+	end := min(len(d.detail), d.scroll+rows)
+	return d.detail[d.scroll:end]
+}
+
+// truncate cuts s to width runes
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// ParseKeys splits raw terminal input into key names: single characters,
+// "enter", "esc", "backspace", "tab", "ctrl-c", the arrows ("up", "down",
+// "left", "right"), "home", "end", "pgup" and "pgdn"
+func ParseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		if b[0] == 0x1b && len(b) >= 3 && (b[1] == '[' || b[1] == 'O') {
+			key, n := parseEscape(b)
+			if key != "" {
+				keys = append(keys, key)
+			}
+			b = b[n:]
+			continue
+		}
+
+		switch b[0] {
+		case 0x1b:
+			keys = append(keys, "esc")
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case '\t':
+			keys = append(keys, "tab")
+		case 0x03:
+			keys = append(keys, "ctrl-c")
+		default:
+			r, n := utf8.DecodeRune(b)
+			keys = append(keys, string(r))
+			b = b[n:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+// parseEscape decodes one CSI/SS3 sequence, returning its key name (empty if
+// unknown) and length
+func parseEscape(b []byte) (string, int) {
+	switch b[2] {
+	case 'A':
+		return "up", 3
+	case 'B':
+		return "down", 3
+	case 'C':
+		return "right", 3
+	case 'D':
+		return "left", 3
+	case 'H':
+		return "home", 3
+	case 'F':
+		return "end", 3
+	}
+
+	// Numbered sequences like ESC [ 5 ~
+	for i := 2; i < len(b); i++ {
+		if b[i] == '~' {
+			switch string(b[2:i]) {
+			case "1", "7":
+				return "home", i + 1
+			case "4", "8":
+				return "end", i + 1
+			case "5":
+				return "pgup", i + 1
+			case "6":
+				return "pgdn", i + 1
+			}
+			return "", i + 1
+		}
+		if b[i] < '0' || b[i] > '9' {
+			if b[i] != ';' {
+				return "", i + 1
+			}
+		}
+	}
+	return "", len(b)
+}
@@ -0,0 +1,170 @@
+package watch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func statuses() []workspace.RepoStatus {
+	return []workspace.RepoStatus{
+		{RepoInfo: workspace.RepoInfo{Name: "charlie"}, Branch: "main"},
+		{RepoInfo: workspace.RepoInfo{Name: "alpha"}, Branch: "main", DirtyFiles: 2, Modified: 2},
+		{RepoInfo: workspace.RepoInfo{Name: "bravo"}, Branch: "main", Stashes: 1},
+	}
+}
+
+func TestDashboardNavigation(t *testing.T) {
+	d := NewDashboard("/tmp/code", "notify")
+	d.SetStatuses(statuses(), time.Now())
+
+	selected := func() string {
+		r, _ := d.Selected()
+		return r.Name
+	}
+
+	if selected() != "alpha" {
+		t.Fatalf("initial selection = %q, want alpha (sorted first)", selected())
+	}
+
+	steps := []struct {
+		key    string
+		want   string
+		action Action
+	}{
+		{"down", "bravo", ActionNone},
+		{"j", "charlie", ActionNone},
+		{"down", "charlie", ActionNone}, // Stays at the bottom
+		{"up", "bravo", ActionNone},
+		{"home", "alpha", ActionNone},
+		{"r", "alpha", ActionRefresh},
+	}
+	for _, s := range steps {
+		if got := d.Key(s.key); got != s.action {
+			t.Errorf("Key(%q) = %v, want %v", s.key, got, s.action)
+		}
+		if selected() != s.want {
+			t.Errorf("after %q selection = %q, want %q", s.key, selected(), s.want)
+		}
+	}
+
+	// Hiding up-to-date repos keeps a visible repo selected
+	d.Key("end")
+	d.Key("a")
+	if selected() != "alpha" {
+		t.Errorf("after hiding clean repos selection = %q, want alpha", selected())
+	}
+
+	if d.Key("q") != ActionQuit {
+		t.Error("q in the list should quit")
+	}
+}
+
+func TestDashboardDrillDown(t *testing.T) {
+	d := NewDashboard("/tmp/code", "notify")
+	d.SetStatuses(statuses(), time.Now())
+	d.Key("down")
+
+	if d.Key("enter") != ActionDetail || d.View() != ViewDiff {
+		t.Fatalf("enter should open the diff view, got %v", d.View())
+	}
+	lines := strings.Join(d.Render(80, 10), "\n")
+	if !strings.Contains(lines, "bravo › diff") || !strings.Contains(lines, "Loading...") {
+		t.Errorf("diff view before load:\n%s", lines)
+	}
+
+	var detail []string
+	for i := 0; i < 30; i++ {
+		detail = append(detail, "line "+string(rune('A'+i)))
+	}
+	d.SetDetail(detail)
+	d.Key("pgdn")
+	if lines := d.Render(80, 10); !strings.Contains(lines[2], "line K") {
+		t.Errorf("after pgdn first body line = %q, want line K", lines[2])
+	}
+
+	if d.Key("l") != ActionDetail || d.View() != ViewLog {
+		t.Errorf("l should switch to the log view, got %v", d.View())
+	}
+	if d.Key("q") != ActionNone || d.View() != ViewList {
+		t.Errorf("q in a detail view should go back to the list, got %v", d.View())
+	}
+}
+
+func TestDashboardUpdate(t *testing.T) {
+	d := NewDashboard("/tmp/code", "polling")
+	d.SetStatuses(statuses(), time.Now())
+	d.Key("down")
+
+	d.UpdateStatuses([]workspace.RepoStatus{
+		{RepoInfo: workspace.RepoInfo{Name: "bravo"}, Error: errors.New("git status: not a git repository")},
+	}, time.Now())
+
+	out := strings.Join(d.Render(120, 12), "\n")
+	if !strings.Contains(out, "✗ error: git status") {
+		t.Errorf("updated status not rendered:\n%s", out)
+	}
+	if r, _ := d.Selected(); r.Name != "bravo" {
+		t.Errorf("selection = %q, want bravo kept across updates", r.Name)
+	}
+}
+
+func TestDashboardRender(t *testing.T) {
+	d := NewDashboard("/tmp/code", "notify")
+	d.SetStatuses(statuses(), time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC))
+
+	lines := d.Render(60, 8)
+	if len(lines) != 8 {
+		t.Fatalf("Render returned %d lines, want 8", len(lines))
+	}
+	if !strings.Contains(lines[0], "/tmp/code") || !strings.Contains(lines[0], "notify, updated 15:04:05") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[2], "\x1b[7m !") || !strings.Contains(lines[2], "alpha") {
+		t.Errorf("selected row = %q, want highlighted alpha with attention marker", lines[2])
+	}
+	if strings.HasPrefix(lines[4], " !") {
+		t.Errorf("clean repo row = %q, want no attention marker", lines[4])
+	}
+	if !strings.HasPrefix(lines[7], " ↑↓ select") {
+		t.Errorf("footer = %q", lines[7])
+	}
+	for i, line := range lines {
+		plain := strings.NewReplacer("\x1b[7m", "", "\x1b[0m", "").Replace(line)
+		if n := len([]rune(plain)); n > 60 {
+			t.Errorf("line %d is %d columns, want <= 60", i, n)
+		}
+	}
+
+	// The selection scrolls into view when the list is taller than the screen
+	d.Key("end")
+	lines = d.Render(60, 5) // Room for one row
+	if !strings.Contains(lines[2], "charlie") {
+		t.Errorf("scrolled row = %q, want charlie", lines[2])
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"q", []string{"q"}},
+		{"\x1b[A\x1b[B", []string{"up", "down"}},
+		{"\x1bOC", []string{"right"}},
+		{"\x1b[5~\x1b[6~", []string{"pgup", "pgdn"}},
+		{"\x1b", []string{"esc"}},
+		{"\r\x7f", []string{"enter", "backspace"}},
+		{"jk\x1b[1;5A", []string{"j", "k"}}, // Unknown modified arrow is dropped
+	}
+
+	for _, tt := range tests {
+		got := ParseKeys([]byte(tt.input))
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("ParseKeys(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package watch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/branch"
+	"github.com/sloanahrens/devbot-go/internal/diff"
+	"github.com/sloanahrens/devbot-go/internal/git"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// logLimit is how many commits the log view shows
+const logLimit = 100
+
+// LoadDetail returns the lines for a drill-down view of repo
+func LoadDetail(v View, repo workspace.RepoInfo) []string {
+	switch v {
+	case ViewDiff:
+		return diffLines(diff.GetDiff(repo))
+	case ViewBranch:
+		return branchLines(branch.GetBranch(repo))
+	case ViewLog:
+		return logLines(repo)
+	}
+	return nil
+}
+
+func diffLines(r diff.DiffResult) []string {
+	if r.Error != nil {
+		return []string{fmt.Sprintf("  ✗ %v", r.Error)}
+	}
+
+	lines := []string{fmt.Sprintf("  Branch: %s", r.Branch)}
+	if len(r.Staged) == 0 && len(r.Unstaged) == 0 {
+		return append(lines, "", "  (clean)")
+	}
+
+	section := func(title string, changes []diff.FileChange) {
+		if len(changes) == 0 {
+			return
+		}
+		adds, dels := 0, 0
+		for _, c := range changes {
+			adds += c.Additions
+			dels += c.Deletions
+		}
+		lines = append(lines, "", fmt.Sprintf("  %s: %d files (+%d, -%d)", title, len(changes), adds, dels))
+		for _, c := range changes {
+			lines = append(lines, fmt.Sprintf("    %s  %-50s +%d -%d", c.Status, c.Path, c.Additions, c.Deletions))
+		}
+	}
+	section("Staged", r.Staged)
+	section("Unstaged", r.Unstaged)
+	return lines
+}
+
+func branchLines(r branch.BranchResult) []string {
+	if r.Error != nil {
+		return []string{fmt.Sprintf("  ✗ %v", r.Error)}
+	}
+
+	tracking := r.Tracking
+	if tracking == "" {
+		tracking = "(none)"
+	}
+	lines := []string{
+		fmt.Sprintf("  Branch:   %s", r.Branch),
+		fmt.Sprintf("  Tracking: %s", tracking),
+		fmt.Sprintf("  Ahead:    %d", r.Ahead),
+		fmt.Sprintf("  Behind:   %d", r.Behind),
+	}
+	if len(r.Commits) > 0 {
+		lines = append(lines, "", "  To push:")
+		for _, c := range r.Commits {
+			lines = append(lines, fmt.Sprintf("    %s %s", c.Hash, c.Subject))
+		}
+	}
+	return lines
+}
+
+func logLines(repo workspace.RepoInfo) []string {
+	out, err := git.Lines(repo.Path, "log", "--oneline", "--decorate", fmt.Sprintf("-%d", logLimit))
+	if err != nil {
+		return []string{fmt.Sprintf("  ✗ %v", err)}
+	}
+	for i, line := range out {
+		out[i] = "  " + strings.TrimRight(line, " ")
+	}
+	return out
+}
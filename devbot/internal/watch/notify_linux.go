@@ -0,0 +1,137 @@
+//go:build linux
+
+package watch
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM
+
+type watched struct {
+	repo      string
+	path      string
+	recursive bool // Subdirectories created later are watched too (refs/)
+}
+
+// inotify watches git dirs with Linux inotify
+type inotify struct {
+	fd   int
+	file *os.File
+	done chan struct{}
+	once sync.Once
+
+	mu  sync.Mutex
+	wds map[int32]watched
+	all []string // Every repo, for queue overflows
+}
+
+// newNotifier watches each target's git dir, common dir and refs tree
+func newNotifier(targets []target, out chan<- string) (io.Closer, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	// A non-blocking fd lets the runtime poller unblock Read on Close
+	n := &inotify{fd: fd, file: os.NewFile(uintptr(fd), "inotify"), done: make(chan struct{}), wds: make(map[int32]watched)}
+	for _, t := range targets {
+		n.all = append(n.all, t.repo)
+		dirs := []string{t.gitDir}
+		if t.commonDir != t.gitDir {
+			dirs = append(dirs, t.commonDir)
+		}
+		for _, dir := range dirs {
+			if err := n.add(watched{repo: t.repo, path: dir}); err != nil {
+				_ = n.file.Close()
+				return nil, err
+			}
+		}
+		for _, dir := range refDirs(t.commonDir) {
+			if err := n.add(watched{repo: t.repo, path: dir, recursive: true}); err != nil {
+				_ = n.file.Close()
+				return nil, err
+			}
+		}
+	}
+
+	go n.read(out)
+	return n, nil
+}
+
+func (n *inotify) add(w watched) error {
+	wd, err := syscall.InotifyAddWatch(n.fd, w.path, inotifyMask)
+	if err != nil {
+		return os.NewSyscallError("inotify_add_watch", err)
+	}
+	n.mu.Lock()
+	n.wds[int32(wd)] = w
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *inotify) Close() error {
+	n.once.Do(func() { close(n.done) })
+	return n.file.Close()
+}
+
+// send reports a changed repo, giving up once the notifier is closed
+func (n *inotify) send(out chan<- string, repo string) bool {
+	select {
+	case out <- repo:
+		return true
+	case <-n.done:
+		return false
+	}
+}
+
+func (n *inotify) read(out chan<- string) {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		size, err := n.file.Read(buf)
+		if err != nil {
+			return // Closed
+		}
+
+		for off := 0; off+syscall.SizeofInotifyEvent <= size; {
+			wd := int32(binary.NativeEndian.Uint32(buf[off:]))
+			mask := binary.NativeEndian.Uint32(buf[off+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[off+12:]))
+			start := off + syscall.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[start:start+nameLen]), "\x00")
+			off = start + nameLen
+
+			if mask&syscall.IN_Q_OVERFLOW != 0 {
+				for _, repo := range n.all {
+					if !n.send(out, repo) {
+						return
+					}
+				}
+				continue
+			}
+
+			n.mu.Lock()
+			w, ok := n.wds[wd]
+			n.mu.Unlock()
+			// Lock files come and go around every write; the rename that
+			// follows is what matters
+			if !ok || strings.HasSuffix(name, ".lock") {
+				continue
+			}
+
+			if w.recursive && mask&syscall.IN_CREATE != 0 && mask&syscall.IN_ISDIR != 0 {
+				_ = n.add(watched{repo: w.repo, path: filepath.Join(w.path, name), recursive: true})
+			}
+			if !n.send(out, w.repo) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package watch
+
+import (
+	"errors"
+	"io"
+)
+
+// newNotifier is only implemented on Linux (inotify). macOS, the BSDs and
+// Windows have no FSEvents/kqueue backend yet and poll .git instead.
+func newNotifier(targets []target, out chan<- string) (io.Closer, error) {
+	return nil, errors.New("filesystem notifications not supported on this platform")
+}
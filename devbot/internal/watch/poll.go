@@ -0,0 +1,91 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// metadataFiles are the per-worktree files whose changes matter
+var metadataFiles = []string{
+	"HEAD", "index", "MERGE_HEAD", "CHERRY_PICK_HEAD", "REVERT_HEAD",
+	"BISECT_LOG", "rebase-merge", "rebase-apply",
+}
+
+// sharedFiles live in the common dir, shared by all worktrees
+var sharedFiles = []string{"FETCH_HEAD", "packed-refs", "logs/refs/stash"}
+
+type poller struct {
+	done chan struct{}
+}
+
+// newPoller checks each target's git metadata every interval and sends the
+// repo name whenever its signature changes
+func newPoller(targets []target, interval time.Duration, out chan<- string) *poller {
+	p := &poller{done: make(chan struct{})}
+
+	prev := make(map[string]string, len(targets))
+	for _, t := range targets {
+		prev[t.repo] = signature(t)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				for _, t := range targets {
+					sig := signature(t)
+					if sig == prev[t.repo] {
+						continue
+					}
+					prev[t.repo] = sig
+					select {
+					case out <- t.repo:
+					case <-p.done:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return p
+}
+
+func (p *poller) Close() error {
+	close(p.done)
+	return nil
+}
+
+// signature summarizes the size and mtime of everything git touches when
+// HEAD, the index or refs move
+func signature(t target) string {
+	var b strings.Builder
+	stat := func(path string) {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+
+	for _, f := range metadataFiles {
+		stat(filepath.Join(t.gitDir, f))
+	}
+	for _, f := range sharedFiles {
+		stat(filepath.Join(t.commonDir, f))
+	}
+
+	_ = filepath.WalkDir(filepath.Join(t.commonDir, "refs"), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			stat(path)
+		}
+		return nil
+	})
+
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/git"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// Run shows the dashboard for repos until the user quits or ctx is done.
+// Changed repos are re-read as soon as the watcher reports them; everything
+// is re-read every opts.Refresh to catch working-tree edits, which don't
+// touch .git.
+func Run(ctx context.Context, workspacePath string, repos []workspace.RepoInfo, opts Options) error {
+	opts = opts.withDefaults()
+
+	// Our own status calls must not rewrite the index, or every refresh
+	// would trigger another change notification
+	defer git.SetEnv("GIT_OPTIONAL_LOCKS=0")()
+
+	term, err := openTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	defer term.restore()
+
+	watcher := NewWatcher(repos, opts)
+	defer watcher.Close()
+
+	dash := NewDashboard(workspacePath, watcher.Mode())
+	dash.SetStatuses(workspace.GetStatus(repos), time.Now())
+
+	byName := make(map[string]workspace.RepoInfo, len(repos))
+	for _, r := range repos {
+		byName[r.Name] = r
+	}
+
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	refresh := time.NewTicker(opts.Refresh)
+	defer refresh.Stop()
+
+	loadDetail := func() {
+		if repo, ok := dash.Selected(); ok && dash.View() != ViewList {
+			dash.SetDetail(LoadDetail(dash.View(), repo))
+		}
+	}
+
+	width, height := term.size()
+	for {
+		term.draw(dash.Render(width, height))
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case input, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, key := range ParseKeys(input) {
+				switch dash.Key(key) {
+				case ActionQuit:
+					return nil
+				case ActionRefresh:
+					dash.SetStatuses(workspace.GetStatus(repos), time.Now())
+				case ActionDetail:
+					term.draw(dash.Render(width, height)) // Show "Loading..."
+					loadDetail()
+				}
+			}
+
+		case names, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			var changed []workspace.RepoInfo
+			for _, name := range names {
+				if r, ok := byName[name]; ok {
+					changed = append(changed, r)
+				}
+			}
+			dash.UpdateStatuses(workspace.GetStatus(changed), time.Now())
+			if repo, ok := dash.Selected(); ok && contains(names, repo.Name) {
+				loadDetail()
+			}
+
+		case <-refresh.C:
+			dash.SetStatuses(workspace.GetStatus(repos), time.Now())
+			loadDetail()
+
+		case <-winch:
+			width, height = term.size()
+		}
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
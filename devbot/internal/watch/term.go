@@ -0,0 +1,80 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// terminal puts the controlling terminal into cbreak mode on the alternate
+// screen. It shells out to stty so no terminal library is needed.
+type terminal struct {
+	in    *os.File
+	out   io.Writer
+	saved string // `stty -g` state to restore
+}
+
+func openTerminal(in *os.File, out io.Writer) (*terminal, error) {
+	if info, err := in.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, errors.New("devbot watch needs an interactive terminal")
+	}
+
+	saved, err := stty(in, "-g")
+	if err != nil {
+		return nil, err
+	}
+	// No line buffering or echo; Ctrl-C still sends SIGINT
+	if _, err := stty(in, "-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+
+	t := &terminal{in: in, out: out, saved: saved}
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l") // Alternate screen, hide cursor
+	return t, nil
+}
+
+// restore returns the terminal to the state it was in before openTerminal
+func (t *terminal) restore() {
+	fmt.Fprint(t.out, "\x1b[?25h\x1b[?1049l")
+	_, _ = stty(t.in, t.saved)
+}
+
+// size returns the terminal's columns and rows, defaulting to 80x24
+func (t *terminal) size() (int, int) {
+	out, err := stty(t.in, "size")
+	if err == nil {
+		var rows, cols int
+		if _, err := fmt.Sscanf(out, "%d %d", &rows, &cols); err == nil && rows > 0 && cols > 0 {
+			return cols, rows
+		}
+	}
+	return 80, 24
+}
+
+// draw repaints the whole screen with lines
+func (t *terminal) draw(lines []string) {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	fmt.Fprint(t.out, b.String())
+}
+
+func stty(in *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = in
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
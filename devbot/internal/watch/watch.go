@@ -0,0 +1,159 @@
+// Package watch drives `devbot watch`: it notices changes to each repo's git
+// metadata (HEAD, index, refs) and redraws a full-screen status dashboard.
+package watch
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/git"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+// Options configures the watcher and dashboard
+type Options struct {
+	Poll         bool          // Force polling instead of filesystem notifications
+	PollInterval time.Duration // How often polling checks git metadata (default 1s)
+	Refresh      time.Duration // Full status refresh, catching working-tree edits (default 10s)
+	Debounce     time.Duration // Quiet period before a burst of changes is reported (default 150ms)
+}
+
+func (o Options) withDefaults() Options {
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.Refresh <= 0 {
+		o.Refresh = 10 * time.Second
+	}
+	if o.Debounce <= 0 {
+		o.Debounce = 150 * time.Millisecond
+	}
+	return o
+}
+
+// Watcher reports repos whose git metadata changed
+type Watcher struct {
+	// Events delivers batches of repo names, sorted, after each burst of changes
+	Events <-chan []string
+
+	mode    string
+	backend io.Closer
+	raw     chan string
+	done    chan struct{}
+	once    sync.Once
+}
+
+// target is one repo's git directories
+type target struct {
+	repo      string
+	gitDir    string // Per-worktree dir: HEAD, index, in-progress markers
+	commonDir string // Shared dir: refs, packed-refs, FETCH_HEAD
+}
+
+// NewWatcher watches repos using filesystem notifications where supported,
+// falling back to polling. Repos whose git dir can't be resolved are skipped.
+func NewWatcher(repos []workspace.RepoInfo, opts Options) *Watcher {
+	opts = opts.withDefaults()
+	targets := resolveTargets(repos)
+
+	events := make(chan []string)
+	w := &Watcher{
+		Events: events,
+		raw:    make(chan string, 256),
+		done:   make(chan struct{}),
+	}
+
+	if !opts.Poll {
+		if backend, err := newNotifier(targets, w.raw); err == nil {
+			w.backend, w.mode = backend, "notify"
+		}
+	}
+	if w.backend == nil {
+		w.backend, w.mode = newPoller(targets, opts.PollInterval, w.raw), "polling"
+	}
+
+	go w.debounce(events, opts.Debounce)
+	return w
+}
+
+// Mode reports the change detection in use: "notify" or "polling"
+func (w *Watcher) Mode() string {
+	return w.mode
+}
+
+// Close stops watching; Events is closed once pending batches are dropped
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		err = w.backend.Close()
+		close(w.done)
+	})
+	return err
+}
+
+// debounce coalesces raw change notifications into sorted batches
+func (w *Watcher) debounce(events chan<- []string, quiet time.Duration) {
+	defer close(events)
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(quiet)
+	timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case name := <-w.raw:
+			pending[name] = true
+			timer.Reset(quiet)
+		case <-timer.C:
+			batch := make([]string, 0, len(pending))
+			for name := range pending {
+				batch = append(batch, name)
+			}
+			sort.Strings(batch)
+			pending = make(map[string]bool)
+
+			select {
+			case events <- batch:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func resolveTargets(repos []workspace.RepoInfo) []target {
+	var targets []target
+	for _, r := range repos {
+		if r.Missing {
+			continue
+		}
+		dirs, err := git.Lines(r.Path, "rev-parse", "--absolute-git-dir", "--git-common-dir")
+		if err != nil || len(dirs) != 2 {
+			continue
+		}
+		common := dirs[1]
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(r.Path, common)
+		}
+		targets = append(targets, target{repo: r.Name, gitDir: dirs[0], commonDir: filepath.Clean(common)})
+	}
+	return targets
+}
+
+// refDirs returns refs/ and every directory below it
+func refDirs(commonDir string) []string {
+	var dirs []string
+	_ = filepath.WalkDir(filepath.Join(commonDir, "refs"), func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs
+}
@@ -0,0 +1,160 @@
+package watch
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/git"
+	"github.com/sloanahrens/devbot-go/internal/workspace"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func makeRepo(t *testing.T, name string) workspace.RepoInfo {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "init", "--quiet")
+	runGit(t, dir, "config", "user.email", "test@test.com")
+	runGit(t, dir, "config", "user.name", "Test User")
+	commitFile(t, dir, "README.md")
+	return workspace.RepoInfo{Name: name, Path: dir}
+}
+
+func commitFile(t *testing.T, dir, file string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	runGit(t, dir, "add", file)
+	runGit(t, dir, "commit", "--quiet", "-m", "add "+file)
+}
+
+// expectBatch waits for the next batch and checks it's exactly want
+func expectBatch(t *testing.T, w *Watcher, want ...string) {
+	t.Helper()
+	select {
+	case got := <-w.Events:
+		if len(got) != len(want) {
+			t.Fatalf("batch = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("batch = %v, want %v", got, want)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no change reported, want %v", want)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	for _, poll := range []bool{false, true} {
+		name := "notify"
+		if poll {
+			name = "polling"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			a := makeRepo(t, "a")
+			b := makeRepo(t, "b")
+
+			w := NewWatcher([]workspace.RepoInfo{a, b}, Options{Poll: poll, PollInterval: 20 * time.Millisecond, Debounce: 50 * time.Millisecond})
+			defer w.Close()
+			if (poll || runtime.GOOS == "linux") && w.Mode() != name {
+				t.Fatalf("Mode() = %q, want %s", w.Mode(), name)
+			}
+
+			commitFile(t, b.Path, "new.txt")
+			expectBatch(t, w, "b")
+
+			// A new branch creates refs/heads/feature/ on the fly
+			runGit(t, a.Path, "branch", "feature/x")
+			expectBatch(t, w, "a")
+			runGit(t, a.Path, "branch", "feature/y")
+			expectBatch(t, w, "a")
+
+			// Staging touches only the index
+			if err := os.WriteFile(filepath.Join(a.Path, "staged.txt"), []byte("x"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			runGit(t, a.Path, "add", "staged.txt")
+			expectBatch(t, w, "a")
+		})
+	}
+}
+
+func TestWatcherIgnoresOwnStatus(t *testing.T) {
+	repo := makeRepo(t, "a")
+	defer git.SetEnv("GIT_OPTIONAL_LOCKS=0")()
+
+	w := NewWatcher([]workspace.RepoInfo{repo}, Options{PollInterval: 20 * time.Millisecond, Debounce: 50 * time.Millisecond})
+	defer w.Close()
+
+	// Touch a file so status has stat info to refresh
+	if err := os.Chtimes(filepath.Join(repo.Path, "README.md"), time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	workspace.GetStatus([]workspace.RepoInfo{repo})
+
+	select {
+	case got := <-w.Events:
+		t.Errorf("status triggered a change: %v", got)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestNotifierCloseWithoutReceiver(t *testing.T) {
+	repo := makeRepo(t, "a")
+	before := runtime.NumGoroutine()
+
+	// Nobody receives, so the reader blocks on its first change
+	n, err := newNotifier(resolveTargets([]workspace.RepoInfo{repo}), make(chan string))
+	if err != nil {
+		t.Skipf("no notifier on %s: %v", runtime.GOOS, err)
+	}
+	commitFile(t, repo.Path, "new.txt")
+	time.Sleep(50 * time.Millisecond)
+	if err := n.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("reader still running after Close: %d goroutines, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatcherSkipsNonRepos(t *testing.T) {
+	repos := []workspace.RepoInfo{
+		{Name: "plain", Path: t.TempDir()},
+		{Name: "gone", Missing: true},
+	}
+	if targets := resolveTargets(repos); len(targets) != 0 {
+		t.Errorf("resolveTargets = %v, want none", targets)
+	}
+
+	w := NewWatcher(repos, Options{})
+	if err := w.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+	if _, ok := <-w.Events; ok {
+		t.Error("Events should be closed after Close")
+	}
+}
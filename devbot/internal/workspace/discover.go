@@ -1,24 +1,138 @@
 package workspace
 
 import (
+	"bufio"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// DiscoverOptions controls how Discover walks the workspace
+type DiscoverOptions struct {
+	MaxDepth   int      // Directory levels below the workspace to search (default 1)
+	Include    []string // Relative path globs a repo must match (empty = all)
+	Exclude    []string // Relative path globs to skip (not descended into)
+	Paths      []string // Extra repo paths, absolute or relative to the workspace
+	Submodules bool     // Also return initialized git submodules
+}
+
 // DefaultWorkspace returns the workspace path from config.yaml, or falls back to ~/code
 func DefaultWorkspace() string {
 	return GetWorkspacePath()
 }
 
-// Discover finds all git repositories in the workspace directory.
-// It only checks immediate subdirectories (not recursive).
+// Discover finds all git repositories in the workspace directory, using
+// the discovery settings from config.yaml. Without any settings it only
+// checks immediate subdirectories. A config.yaml that can't be read is an
+// error rather than a silent fallback, since the discovery settings in it
+// decide which repos exist.
 func Discover(workspacePath string) ([]RepoInfo, error) {
-	entries, err := os.ReadDir(workspacePath)
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("config.yaml: %w", err)
+	}
+	if cfg == nil {
+		return DiscoverWithOptions(workspacePath, DiscoverOptions{})
+	}
+
+	repos, err := DiscoverWithOptions(workspacePath, DiscoverOptions(cfg.Discovery))
 	if err != nil {
 		return nil, err
 	}
+	return withConfiguredPaths(repos, cfg, workspacePath), nil
+}
+
+// withConfiguredPaths adds repos that config.yaml places at an explicit
+// path, using their configured name
+func withConfiguredPaths(repos []RepoInfo, cfg *WorkspaceConfig, workspacePath string) []RepoInfo {
+	for _, rc := range cfg.Repos {
+		if rc.Name == "" || rc.Path == "" {
+			continue
+		}
+		p := rc.RepoPath(workspacePath)
+
+		found := false
+		for i := range repos {
+			if repos[i].Path == p {
+				repos[i].Name = rc.Name
+				found = true
+				break
+			}
+		}
+		if !found && isRepo(p) {
+			repos = append(repos, RepoInfo{Name: rc.Name, Path: p})
+		}
+	}
+	return repos
+}
+
+// DiscoverWithOptions finds git repositories under workspacePath. It stops
+// descending at any directory containing .git, so nested checkouts (other
+// than submodules) are never reported. Repo names are the directory name,
+// or the workspace-relative path when two repos share a directory name.
+func DiscoverWithOptions(workspacePath string, opts DiscoverOptions) ([]RepoInfo, error) {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 1
+	}
+
+	// The workspace itself must be readable
+	if _, err := os.ReadDir(workspacePath); err != nil {
+		return nil, err
+	}
+
+	var found []discovered
+	walkRepos(workspacePath, "", 1, opts, &found)
 
-	var repos []RepoInfo
+	seen := make(map[string]bool)
+	for _, d := range found {
+		seen[d.path] = true
+	}
+
+	for _, p := range opts.Paths {
+		p = expandHome(p)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(workspacePath, p)
+		}
+		p = filepath.Clean(p)
+		if seen[p] || !isRepo(p) {
+			continue
+		}
+		seen[p] = true
+		found = append(found, discovered{path: p, rel: relOrAbs(workspacePath, p)})
+	}
+
+	if opts.Submodules {
+		for i := 0; i < len(found); i++ {
+			for _, sub := range submodulePaths(found[i].path) {
+				if seen[sub] {
+					continue
+				}
+				seen[sub] = true
+				found = append(found, discovered{
+					path:   sub,
+					rel:    relOrAbs(workspacePath, sub),
+					parent: found[i].path,
+				})
+			}
+		}
+	}
+
+	return nameRepos(found), nil
+}
+
+// discovered is a repo found on disk before naming
+type discovered struct {
+	path   string
+	rel    string // Slash-separated path relative to the workspace
+	parent string // Superproject path for submodules
+}
+
+func walkRepos(dir, rel string, depth int, opts DiscoverOptions, found *[]discovered) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
 
 	for _, entry := range entries {
 		if !entry.IsDir() {
@@ -30,17 +144,179 @@ func Discover(workspacePath string) ([]RepoInfo, error) {
 			continue
 		}
 
-		repoPath := filepath.Join(workspacePath, entry.Name())
-		gitPath := filepath.Join(repoPath, ".git")
+		childRel := entry.Name()
+		if rel != "" {
+			childRel = rel + "/" + entry.Name()
+		}
+		if matchAny(opts.Exclude, childRel) {
+			continue
+		}
+
+		childPath := filepath.Join(dir, entry.Name())
 
 		// Check if .git exists (file or directory - could be worktree)
-		if _, err := os.Stat(gitPath); err == nil {
-			repos = append(repos, RepoInfo{
-				Name: entry.Name(),
-				Path: repoPath,
-			})
+		if isRepo(childPath) {
+			if len(opts.Include) == 0 || matchAny(opts.Include, childRel) {
+				*found = append(*found, discovered{path: childPath, rel: childRel})
+			}
+			continue
+		}
+
+		if depth < opts.MaxDepth {
+			walkRepos(childPath, childRel, depth+1, opts, found)
 		}
 	}
+}
 
-	return repos, nil
+// nameRepos assigns each repo its directory name, falling back to the
+// relative path for any names that collide
+func nameRepos(found []discovered) []RepoInfo {
+	names := make(map[string]string, len(found))
+	counts := make(map[string]int)
+
+	for _, d := range found {
+		name := filepath.Base(d.path)
+		if d.parent != "" {
+			// Submodules are always qualified by their superproject
+			for _, p := range found {
+				if p.path == d.parent {
+					name = names[p.path] + "/" + filepath.ToSlash(strings.TrimPrefix(d.path, d.parent+string(filepath.Separator)))
+					break
+				}
+			}
+		}
+		names[d.path] = name
+		counts[name]++
+	}
+
+	repos := make([]RepoInfo, 0, len(found))
+	for _, d := range found {
+		name := names[d.path]
+		if counts[name] > 1 {
+			name = d.rel
+		}
+		repos = append(repos, RepoInfo{Name: name, Path: d.path})
+	}
+	return repos
+}
+
+// MissingRepos returns the config.yaml repos and discovery paths that
+// weren't discovered, marked as Missing
+func MissingRepos(repos []RepoInfo) []RepoInfo {
+	cfg, err := LoadConfig()
+	if err != nil || cfg == nil {
+		return nil
+	}
+	return missingFrom(repos, cfg, GetWorkspacePath())
+}
+
+func missingFrom(repos []RepoInfo, cfg *WorkspaceConfig, workspacePath string) []RepoInfo {
+	names := make(map[string]bool, len(repos))
+	paths := make(map[string]bool, len(repos))
+	for _, r := range repos {
+		names[r.Name] = true
+		paths[r.Path] = true
+	}
+
+	var missing []RepoInfo
+	for _, rc := range cfg.Repos {
+		if rc.Name == "" || names[rc.Name] {
+			continue
+		}
+		names[rc.Name] = true
+		if rc.Path != "" {
+			p := rc.RepoPath(workspacePath)
+			if paths[p] || isRepo(p) {
+				continue
+			}
+			missing = append(missing, RepoInfo{Name: rc.Name, Path: p, Missing: true})
+			continue
+		}
+		missing = append(missing, RepoInfo{Name: rc.Name, Missing: true})
+	}
+
+	for _, p := range cfg.Discovery.Paths {
+		p = expandHome(p)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(workspacePath, p)
+		}
+		p = filepath.Clean(p)
+		if paths[p] || isRepo(p) || names[filepath.Base(p)] {
+			continue
+		}
+		missing = append(missing, RepoInfo{Name: filepath.Base(p), Path: p, Missing: true})
+	}
+
+	return missing
+}
+
+// submodulePaths returns the initialized submodules listed in a repo's
+// .gitmodules (uninitialized ones have no .git and are skipped)
+func submodulePaths(repoPath string) []string {
+	f, err := os.Open(filepath.Join(repoPath, ".gitmodules"))
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.TrimSpace(key) != "path" {
+			continue
+		}
+		sub := filepath.Join(repoPath, filepath.FromSlash(strings.TrimSpace(value)))
+		if isRepo(sub) {
+			paths = append(paths, sub)
+		}
+	}
+	return paths
+}
+
+func isRepo(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+func relOrAbs(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// matchAny reports whether rel matches any of the glob patterns. A
+// pattern also matches everything beneath a matching directory.
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if matchGlob(strings.Split(strings.Trim(p, "/"), "/"), strings.Split(rel, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches path segments against pattern segments, where "**"
+// matches any number of segments
+func matchGlob(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchGlob(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return true
 }
@@ -0,0 +1,187 @@
+package workspace
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/sloanahrens/devbot-go/internal/detect"
+)
+
+// Selector syntax (comma or space separated terms):
+//
+//	my-app          exact repo name
+//	api-*           glob on repo name
+//	group:apps      repos whose config.yaml group matches
+//	tag:infra       repos with a matching config.yaml tag
+//	lang:go         repos whose configured language or detected stack matches
+//	!tag:legacy     negation (also -tag:legacy); removes matches
+//
+// Positive terms are OR'd together. If there are only negative terms,
+// they're applied to all repos. Every value accepts glob patterns.
+
+// selectorKinds are the recognized "kind:" prefixes
+var selectorKinds = map[string]bool{
+	"name":  true,
+	"group": true,
+	"tag":   true,
+	"lang":  true,
+}
+
+// selectorTerm is one parsed term of a selector expression
+type selectorTerm struct {
+	kind    string // name, group, tag or lang
+	pattern string
+	negate  bool
+}
+
+// Selector is a parsed repo selector expression
+type Selector struct {
+	expr  string
+	terms []selectorTerm
+}
+
+// ParseSelector parses a selector expression. An empty expression
+// selects every repo.
+func ParseSelector(expr string) (Selector, error) {
+	sel := Selector{expr: expr}
+
+	fields := strings.FieldsFunc(expr, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	for _, field := range fields {
+		term := selectorTerm{kind: "name"}
+
+		if strings.HasPrefix(field, "!") || strings.HasPrefix(field, "-") {
+			term.negate = true
+			field = field[1:]
+		}
+
+		if kind, value, ok := strings.Cut(field, ":"); ok {
+			if !selectorKinds[kind] {
+				return Selector{}, fmt.Errorf("unknown selector %q (want name:, group:, tag: or lang:)", kind+":")
+			}
+			term.kind = kind
+			field = value
+		}
+
+		if field == "" {
+			return Selector{}, fmt.Errorf("empty selector term in %q", expr)
+		}
+		if _, err := path.Match(field, ""); err != nil {
+			return Selector{}, fmt.Errorf("invalid pattern %q: %w", field, err)
+		}
+
+		term.pattern = field
+		sel.terms = append(sel.terms, term)
+	}
+
+	return sel, nil
+}
+
+// Select returns the repos matching a selector expression, using
+// config.yaml for group, tag and language metadata
+func Select(repos []RepoInfo, expr string) ([]RepoInfo, error) {
+	sel, err := ParseSelector(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return sel.Filter(repos, cfg), nil
+}
+
+// Filter returns the repos matching the selector, preserving order
+func (s Selector) Filter(repos []RepoInfo, cfg *WorkspaceConfig) []RepoInfo {
+	byName := make(map[string]*RepoConfig)
+	if cfg != nil {
+		for i := range cfg.Repos {
+			byName[cfg.Repos[i].Name] = &cfg.Repos[i]
+		}
+	}
+
+	var selected []RepoInfo
+	for _, r := range repos {
+		if s.Match(r, byName[r.Name]) {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+// Match reports whether a repo (and its config.yaml entry, if any)
+// matches the selector
+func (s Selector) Match(repo RepoInfo, rc *RepoConfig) bool {
+	hasPositive := false
+	matched := false
+
+	for _, t := range s.terms {
+		if !t.negate {
+			hasPositive = true
+			if !matched && t.match(repo, rc) {
+				matched = true
+			}
+		}
+	}
+
+	if hasPositive && !matched {
+		return false
+	}
+
+	for _, t := range s.terms {
+		if t.negate && t.match(repo, rc) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the original expression
+func (s Selector) String() string {
+	return s.expr
+}
+
+func (t selectorTerm) match(repo RepoInfo, rc *RepoConfig) bool {
+	switch t.kind {
+	case "name":
+		return globMatch(t.pattern, repo.Name)
+	case "group":
+		return rc != nil && globMatch(t.pattern, rc.Group)
+	case "tag":
+		if rc == nil {
+			return false
+		}
+		for _, tag := range rc.Tags {
+			if globMatch(t.pattern, tag) {
+				return true
+			}
+		}
+		return false
+	case "lang":
+		if rc != nil && rc.Language != "" && globMatch(t.pattern, rc.Language) {
+			return true
+		}
+		stack := repo.Stack
+		if len(stack) == 0 && repo.Path != "" {
+			stack = detect.ProjectStack(repo.Path)
+		}
+		for _, s := range stack {
+			if globMatch(t.pattern, s) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// globMatch is a case-insensitive path.Match (patterns are pre-validated)
+func globMatch(pattern, value string) bool {
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return ok
+}
@@ -0,0 +1,124 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func selectNames(repos []RepoInfo) string {
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSelectorFilter(t *testing.T) {
+	repos := []RepoInfo{
+		{Name: "api-server", Stack: []string{"go"}},
+		{Name: "api-client", Stack: []string{"ts"}},
+		{Name: "web", Stack: []string{"nextjs", "ts"}},
+		{Name: "infra", Stack: []string{"go"}},
+		{Name: "scratch"},
+	}
+	cfg := &WorkspaceConfig{Repos: []RepoConfig{
+		{Name: "api-server", Group: "apps", Tags: []string{"backend"}},
+		{Name: "api-client", Group: "apps", Tags: []string{"frontend", "sdk"}},
+		{Name: "web", Group: "apps", Tags: []string{"frontend"}},
+		{Name: "infra", Group: "ops", Tags: []string{"infra", "legacy"}},
+		{Name: "scratch", Language: "python"},
+	}}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "api-server,api-client,web,infra,scratch"},
+		{"web", "web"},
+		{"api", ""},
+		{"api-*", "api-server,api-client"},
+		{"API-*", "api-server,api-client"},
+		{"group:apps", "api-server,api-client,web"},
+		{"group:ops", "infra"},
+		{"tag:frontend", "api-client,web"},
+		{"tag:s*", "api-client"},
+		{"lang:go", "api-server,infra"},
+		{"lang:python", "scratch"},
+		{"lang:ts", "api-client,web"},
+		{"group:apps,infra", "api-server,api-client,web,infra"},
+		{"group:apps !tag:frontend", "api-server"},
+		{"-tag:legacy", "api-server,api-client,web,scratch"},
+		{"lang:go,!name:infra", "api-server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			sel, err := ParseSelector(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSelector(%q) error: %v", tt.expr, err)
+			}
+			got := selectNames(sel.Filter(repos, cfg))
+			if got != tt.want {
+				t.Errorf("Filter(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	for _, expr := range []string{"owner:me", "group:", "!", "name:[a"} {
+		if _, err := ParseSelector(expr); err == nil {
+			t.Errorf("ParseSelector(%q) should fail", expr)
+		}
+	}
+}
+
+func TestSelectUsesConfig(t *testing.T) {
+	ResetConfigCache()
+	defer ResetConfigCache()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+repos:
+  - name: tools
+    group: internal
+    tags: [cli, go]
+  - name: site
+    group: apps
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("DEVBOT_CONFIG", configPath)
+
+	repos := []RepoInfo{{Name: "tools"}, {Name: "site"}}
+
+	got, err := Select(repos, "tag:cli")
+	if err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+	if selectNames(got) != "tools" {
+		t.Errorf("Select(tag:cli) = %q, want tools", selectNames(got))
+	}
+
+	if _, err := Select(repos, "bogus:x"); err == nil {
+		t.Error("Select should reject unknown selector kinds")
+	}
+}
+
+func TestSelectConfigError(t *testing.T) {
+	ResetConfigCache()
+	defer ResetConfigCache()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("repos: [\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("DEVBOT_CONFIG", configPath)
+
+	if _, err := Select([]RepoInfo{{Name: "tools"}}, "tag:cli"); err == nil {
+		t.Error("Select should return the config.yaml parse error")
+	}
+}
@@ -1,13 +1,14 @@
 package workspace
 
 import (
-	"bytes"
-	"os/exec"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/sloanahrens/devbot-go/internal/detect"
+	"github.com/sloanahrens/devbot-go/internal/git"
 )
 
 // GetStatus retrieves git status for all repos in parallel
@@ -40,25 +41,43 @@ func GetStatus(repos []RepoInfo) []RepoStatus {
 
 func getRepoStatus(repo RepoInfo) RepoStatus {
 	status := RepoStatus{RepoInfo: repo}
+	if repo.Missing {
+		return status
+	}
 
-	// Get current branch
-	status.Branch = gitCommand(repo.Path, "rev-parse", "--abbrev-ref", "HEAD")
-
-	// Count dirty files
-	porcelain := gitCommand(repo.Path, "status", "--porcelain")
-	if porcelain != "" {
-		status.DirtyFiles = len(strings.Split(strings.TrimSpace(porcelain), "\n"))
+	// Count dirty files; this also tells us whether the repo is usable at all.
+	// Raw output: trimming would eat the first line's leading status column.
+	porcelain, err := git.OutputRaw(repo.Path, "status", "--porcelain")
+	if err != nil {
+		status.Error = err
+		return status
 	}
+	countChanges(&status, porcelain)
+
+	// Get current branch (fails harmlessly before the first commit)
+	status.Branch, _ = git.Output(repo.Path, "rev-parse", "--abbrev-ref", "HEAD")
 
 	// Get ahead/behind counts (may fail if no upstream)
-	ahead := gitCommand(repo.Path, "rev-list", "--count", "@{u}..HEAD")
-	if n, err := strconv.Atoi(ahead); err == nil {
-		status.Ahead = n
+	if ahead, err := git.Output(repo.Path, "rev-list", "--count", "@{u}..HEAD"); err == nil {
+		status.Ahead, _ = strconv.Atoi(ahead)
+	}
+	if behind, err := git.Output(repo.Path, "rev-list", "--count", "HEAD..@{u}"); err == nil {
+		status.Behind, _ = strconv.Atoi(behind)
 	}
 
-	behind := gitCommand(repo.Path, "rev-list", "--count", "HEAD..@{u}")
-	if n, err := strconv.Atoi(behind); err == nil {
-		status.Behind = n
+	// Stashes, in-progress operations and fetch age
+	if stashes, err := git.Lines(repo.Path, "stash", "list"); err == nil {
+		status.Stashes = len(stashes)
+	}
+	if dirs, err := git.Lines(repo.Path, "rev-parse", "--absolute-git-dir", "--git-common-dir"); err == nil && len(dirs) == 2 {
+		gitDir, commonDir := dirs[0], dirs[1]
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(repo.Path, commonDir)
+		}
+		status.Operation = operation(gitDir)
+		if info, err := os.Stat(filepath.Join(commonDir, "FETCH_HEAD")); err == nil {
+			status.LastFetch = info.ModTime()
+		}
 	}
 
 	// Detect project stack
@@ -67,17 +86,51 @@ func getRepoStatus(repo RepoInfo) RepoStatus {
 	return status
 }
 
-func gitCommand(dir string, args ...string) string {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
+// countChanges fills the per-kind file counts from `git status --porcelain`
+func countChanges(status *RepoStatus, porcelain string) {
+	for _, line := range strings.Split(porcelain, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		status.DirtyFiles++
+
+		x, y := line[0], line[1]
+		switch {
+		case x == '?':
+			status.Untracked++
+		case x == 'U' || y == 'U' || (x == 'A' && y == 'A') || (x == 'D' && y == 'D'):
+			status.Conflicts++
+		default:
+			if x != ' ' {
+				status.Staged++
+			}
+			if y != ' ' {
+				status.Modified++
+			}
+		}
+	}
+}
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = nil // Suppress stderr
+// operationMarkers maps files git leaves in the git dir to the operation
+// they indicate, checked in order
+var operationMarkers = []struct {
+	file string
+	op   string
+}{
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+	{"BISECT_LOG", "bisect"},
+}
 
-	if err := cmd.Run(); err != nil {
-		return ""
+// operation returns the in-progress operation in gitDir, if any
+func operation(gitDir string) string {
+	for _, m := range operationMarkers {
+		if _, err := os.Stat(filepath.Join(gitDir, m.file)); err == nil {
+			return m.op
+		}
 	}
-
-	return strings.TrimSpace(out.String())
+	return ""
 }
@@ -1,18 +1,30 @@
 package workspace
 
+import "time"
+
 // RepoInfo contains basic information about a discovered repository
 type RepoInfo struct {
 	Name  string   // Directory name (e.g., "my-app")
 	Path  string   // Full path (e.g., "/home/user/code/my-app")
 	Stack []string // Detected stacks (e.g., ["go", "nextjs"])
+
+	// Missing is set for repos listed in config.yaml but not found on disk
+	Missing bool
 }
 
 // RepoStatus contains git status information for a repository
 type RepoStatus struct {
 	RepoInfo
-	Branch     string // Current branch name
-	DirtyFiles int    // Number of uncommitted changes
-	Ahead      int    // Commits ahead of upstream
-	Behind     int    // Commits behind upstream
-	Error      error  // Any error encountered
+	Branch     string    // Current branch name
+	DirtyFiles int       // Number of uncommitted changes
+	Staged     int       // Files with staged changes
+	Modified   int       // Files with unstaged changes to tracked content
+	Untracked  int       // Untracked files (or directories)
+	Conflicts  int       // Files with unresolved merge conflicts
+	Stashes    int       // Entries in the stash
+	Operation  string    // In-progress operation: rebase, merge, cherry-pick, revert, bisect
+	Ahead      int       // Commits ahead of upstream
+	Behind     int       // Commits behind upstream
+	LastFetch  time.Time // Modification time of FETCH_HEAD; zero if never fetched
+	Error      error     // Any error encountered
 }
@@ -1,11 +1,15 @@
 package workspace
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/sloanahrens/devbot-go/internal/git"
 )
 
 func TestDefaultWorkspace(t *testing.T) {
@@ -219,6 +223,104 @@ func TestGetRepoStatus(t *testing.T) {
 			t.Errorf("DirtyFiles = %d, want 1", status.DirtyFiles)
 		}
 	})
+
+	t.Run("not a git repo", func(t *testing.T) {
+		repo := RepoInfo{Name: "plain", Path: t.TempDir()}
+		status := getRepoStatus(repo)
+
+		if !errors.Is(status.Error, git.ErrNotRepo) {
+			t.Errorf("Error = %v, want git.ErrNotRepo", status.Error)
+		}
+	})
+}
+
+func TestGetRepoStatusDetails(t *testing.T) {
+	write := func(t *testing.T, dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	t.Run("staged, modified and untracked", func(t *testing.T) {
+		dir := t.TempDir()
+		setupGitRepo(t, dir)
+		write(t, dir, "README.md", "# Modified")
+		write(t, dir, "staged.txt", "staged")
+		runGit(t, dir, "add", "staged.txt")
+		write(t, dir, "new.txt", "new")
+
+		status := getRepoStatus(RepoInfo{Name: "r", Path: dir})
+		if status.Staged != 1 || status.Modified != 1 || status.Untracked != 1 || status.DirtyFiles != 3 {
+			t.Errorf("counts = %d staged, %d modified, %d untracked, %d dirty; want 1, 1, 1, 3",
+				status.Staged, status.Modified, status.Untracked, status.DirtyFiles)
+		}
+	})
+
+	t.Run("stash", func(t *testing.T) {
+		dir := t.TempDir()
+		setupGitRepo(t, dir)
+		write(t, dir, "README.md", "# Stashed")
+		runGit(t, dir, "stash")
+
+		status := getRepoStatus(RepoInfo{Name: "r", Path: dir})
+		if status.Stashes != 1 || status.DirtyFiles != 0 {
+			t.Errorf("Stashes = %d, DirtyFiles = %d; want 1, 0", status.Stashes, status.DirtyFiles)
+		}
+	})
+
+	t.Run("merge conflict", func(t *testing.T) {
+		dir := t.TempDir()
+		setupGitRepo(t, dir)
+		base := strings.TrimSpace(runGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD"))
+		runGit(t, dir, "checkout", "-q", "-b", "other")
+		write(t, dir, "README.md", "# Other")
+		runGit(t, dir, "commit", "-qam", "other")
+		runGit(t, dir, "checkout", "-q", base)
+		write(t, dir, "README.md", "# Base")
+		runGit(t, dir, "commit", "-qam", "base")
+
+		// Merge fails with a conflict, leaving MERGE_HEAD behind
+		cmd := exec.Command("git", "merge", "other")
+		cmd.Dir = dir
+		_ = cmd.Run()
+
+		status := getRepoStatus(RepoInfo{Name: "r", Path: dir})
+		if status.Operation != "merge" || status.Conflicts != 1 {
+			t.Errorf("Operation = %q, Conflicts = %d; want merge, 1", status.Operation, status.Conflicts)
+		}
+	})
+
+	t.Run("bisect", func(t *testing.T) {
+		dir := t.TempDir()
+		setupGitRepo(t, dir)
+		runGit(t, dir, "bisect", "start")
+
+		status := getRepoStatus(RepoInfo{Name: "r", Path: dir})
+		if status.Operation != "bisect" {
+			t.Errorf("Operation = %q, want bisect", status.Operation)
+		}
+	})
+
+	t.Run("fetch age", func(t *testing.T) {
+		dir := t.TempDir()
+		setupGitRepo(t, dir)
+
+		if status := getRepoStatus(RepoInfo{Name: "r", Path: dir}); !status.LastFetch.IsZero() {
+			t.Errorf("LastFetch = %v, want zero before any fetch", status.LastFetch)
+		}
+
+		write(t, dir, filepath.Join(".git", "FETCH_HEAD"), "")
+		old := time.Now().Add(-30 * 24 * time.Hour)
+		if err := os.Chtimes(filepath.Join(dir, ".git", "FETCH_HEAD"), old, old); err != nil {
+			t.Fatal(err)
+		}
+
+		status := getRepoStatus(RepoInfo{Name: "r", Path: dir})
+		if status.LastFetch.Sub(old).Abs() > time.Second {
+			t.Errorf("LastFetch = %v, want %v", status.LastFetch, old)
+		}
+	})
 }
 
 func TestGetStatus(t *testing.T) {
@@ -278,3 +380,180 @@ func TestGetStatus(t *testing.T) {
 		}
 	})
 }
+
+func mkRepoDirs(t *testing.T, root string, rels ...string) {
+	t.Helper()
+	for _, rel := range rels {
+		if err := os.MkdirAll(filepath.Join(root, rel, ".git"), 0755); err != nil {
+			t.Fatalf("Failed to create mock repo: %v", err)
+		}
+	}
+}
+
+func discoveredNames(repos []RepoInfo) map[string]string {
+	names := make(map[string]string)
+	for _, r := range repos {
+		names[r.Name] = r.Path
+	}
+	return names
+}
+
+func TestDiscoverWithOptions(t *testing.T) {
+	root := t.TempDir()
+	mkRepoDirs(t, root,
+		"top",
+		"clients/acme/api",
+		"clients/globex/api",
+		"clients/globex/web",
+		"oss/lib",
+		"archive/old",
+		"top/vendor/nested", // inside a repo, never reported
+	)
+	outside := t.TempDir()
+	mkRepoDirs(t, outside, "extra")
+
+	t.Run("default depth is immediate children", func(t *testing.T) {
+		repos, err := DiscoverWithOptions(root, DiscoverOptions{})
+		if err != nil {
+			t.Fatalf("DiscoverWithOptions failed: %v", err)
+		}
+		names := discoveredNames(repos)
+		if len(names) != 1 || names["top"] == "" {
+			t.Errorf("names = %v, want only top", names)
+		}
+	})
+
+	t.Run("recursive with exclude and extra paths", func(t *testing.T) {
+		repos, err := DiscoverWithOptions(root, DiscoverOptions{
+			MaxDepth: 3,
+			Exclude:  []string{"archive"},
+			Paths:    []string{filepath.Join(outside, "extra"), "oss/lib", "does/not/exist"},
+		})
+		if err != nil {
+			t.Fatalf("DiscoverWithOptions failed: %v", err)
+		}
+		names := discoveredNames(repos)
+
+		want := map[string]string{
+			"top":                filepath.Join(root, "top"),
+			"clients/acme/api":   filepath.Join(root, "clients/acme/api"),
+			"clients/globex/api": filepath.Join(root, "clients/globex/api"),
+			"web":                filepath.Join(root, "clients/globex/web"),
+			"lib":                filepath.Join(root, "oss/lib"),
+			"extra":              filepath.Join(outside, "extra"),
+		}
+		if len(names) != len(want) {
+			t.Errorf("names = %v, want %v", names, want)
+		}
+		for name, path := range want {
+			if names[name] != path {
+				t.Errorf("repo %q path = %q, want %q", name, names[name], path)
+			}
+		}
+	})
+
+	t.Run("include globs", func(t *testing.T) {
+		repos, err := DiscoverWithOptions(root, DiscoverOptions{
+			MaxDepth: 3,
+			Include:  []string{"clients/*/api", "**/lib"},
+		})
+		if err != nil {
+			t.Fatalf("DiscoverWithOptions failed: %v", err)
+		}
+		names := discoveredNames(repos)
+		if len(names) != 3 || names["lib"] == "" || names["clients/acme/api"] == "" {
+			t.Errorf("names = %v, want both api repos and lib", names)
+		}
+	})
+}
+
+func TestDiscoverSubmodules(t *testing.T) {
+	root := t.TempDir()
+	mkRepoDirs(t, root, "app", "app/libs/core")
+	// Listed but not initialized
+	gitmodules := `[submodule "core"]
+	path = libs/core
+	url = ../core.git
+[submodule "docs"]
+	path = docs
+	url = ../docs.git
+`
+	if err := os.WriteFile(filepath.Join(root, "app", ".gitmodules"), []byte(gitmodules), 0644); err != nil {
+		t.Fatalf("Failed to write .gitmodules: %v", err)
+	}
+
+	without, _ := DiscoverWithOptions(root, DiscoverOptions{})
+	if len(without) != 1 {
+		t.Errorf("without submodules found %d repos, want 1", len(without))
+	}
+
+	with, err := DiscoverWithOptions(root, DiscoverOptions{Submodules: true})
+	if err != nil {
+		t.Fatalf("DiscoverWithOptions failed: %v", err)
+	}
+	names := discoveredNames(with)
+	if len(names) != 2 || names["app/libs/core"] != filepath.Join(root, "app", "libs", "core") {
+		t.Errorf("names = %v, want app and app/libs/core", names)
+	}
+}
+
+func TestMissingFrom(t *testing.T) {
+	root := t.TempDir()
+	mkRepoDirs(t, root, "present")
+
+	cfg := &WorkspaceConfig{
+		Repos: []RepoConfig{{Name: "present"}, {Name: "gone"}},
+		Discovery: DiscoveryConfig{
+			Paths: []string{"present", "elsewhere/lost"},
+		},
+	}
+	repos := []RepoInfo{{Name: "present", Path: filepath.Join(root, "present")}}
+
+	missing := missingFrom(repos, cfg, root)
+	if len(missing) != 2 {
+		t.Fatalf("missing = %+v, want gone and lost", missing)
+	}
+	if missing[0].Name != "gone" || !missing[0].Missing {
+		t.Errorf("missing[0] = %+v, want gone marked Missing", missing[0])
+	}
+	if missing[1].Name != "lost" || missing[1].Path != filepath.Join(root, "elsewhere", "lost") {
+		t.Errorf("missing[1] = %+v, want lost with its configured path", missing[1])
+	}
+
+	status := getRepoStatus(missing[0])
+	if !status.Missing || status.Branch != "" {
+		t.Errorf("status of missing repo = %+v, want Missing without git info", status)
+	}
+}
+
+func TestWithConfiguredPaths(t *testing.T) {
+	root := t.TempDir()
+	mkRepoDirs(t, root, "top", "deep/down/svc")
+
+	cfg := &WorkspaceConfig{Repos: []RepoConfig{
+		{Name: "service", Path: "deep/down/svc"},
+		{Name: "renamed", Path: "top"},
+		{Name: "absent", Path: "not/here"},
+	}}
+	repos, _ := DiscoverWithOptions(root, DiscoverOptions{})
+
+	names := discoveredNames(withConfiguredPaths(repos, cfg, root))
+	if len(names) != 2 || names["service"] != filepath.Join(root, "deep/down/svc") || names["renamed"] != filepath.Join(root, "top") {
+		t.Errorf("names = %v, want service and renamed", names)
+	}
+}
+
+func TestDiscoverConfigError(t *testing.T) {
+	ResetConfigCache()
+	defer ResetConfigCache()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("discovery: [\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("DEVBOT_CONFIG", configPath)
+
+	if _, err := Discover(t.TempDir()); err == nil {
+		t.Error("Discover should return the config.yaml parse error")
+	}
+}
@@ -1,10 +1,13 @@
 package workspace
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/sloanahrens/devbot-go/internal/stack"
 	"gopkg.in/yaml.v3"
 )
 
@@ -13,17 +16,79 @@ type WorkspaceConfig struct {
 	// New unified path (preferred)
 	Workspace string `yaml:"workspace"`
 	// Legacy paths (for backwards compatibility)
-	BasePath string       `yaml:"base_path"`
-	CodePath string       `yaml:"code_path"`
-	Repos    []RepoConfig `yaml:"repos"`
+	BasePath  string          `yaml:"base_path"`
+	CodePath  string          `yaml:"code_path"`
+	Repos     []RepoConfig    `yaml:"repos"`
+	Discovery DiscoveryConfig `yaml:"discovery"`
+	Deps      DepsConfig      `yaml:"deps"`
+	Licenses  LicensesConfig  `yaml:"licenses"`
+	Stacks    []stack.Config  `yaml:"stacks"`     // Extra or adjusted stacks (see stack.Config)
+	StackDirs []string        `yaml:"stack_dirs"` // Extra sub-app dirs, e.g. "services/*"
+}
+
+// DepsConfig configures `devbot deps`
+type DepsConfig struct {
+	Drift DriftConfig `yaml:"drift"`
+}
+
+// DriftConfig is the policy for `devbot deps --drift`. Levels are
+// "major", "minor", "patch" or "never".
+type DriftConfig struct {
+	FailOn   string            `yaml:"fail_on"`  // Smallest drift that fails (default: major)
+	Packages map[string]string `yaml:"packages"` // Per-package fail_on
+	Ignore   []string          `yaml:"ignore"`   // Package name globs to skip, e.g. "@types/*"
+}
+
+// LicensesConfig is the policy for `devbot licenses`. Allow and deny
+// entries are SPDX identifiers or globs such as "GPL-*".
+type LicensesConfig struct {
+	Allow         []string          `yaml:"allow"`           // If set, every shipped license must match one
+	Deny          []string          `yaml:"deny"`            // Violations even when allowed
+	Packages      map[string]string `yaml:"packages"`        // Package -> license, where metadata is missing or wrong
+	Ignore        []string          `yaml:"ignore"`          // Package name globs to skip, e.g. "@org/*"
+	FailOnUnknown bool              `yaml:"fail_on_unknown"` // Packages without a recognized license are violations
+}
+
+// DiscoveryConfig controls repository discovery (see DiscoverOptions)
+type DiscoveryConfig struct {
+	MaxDepth   int      `yaml:"max_depth"`
+	Include    []string `yaml:"include"`
+	Exclude    []string `yaml:"exclude"`
+	Paths      []string `yaml:"paths"`
+	Submodules bool     `yaml:"submodules"`
 }
 
 // RepoConfig represents a repository entry in config.yaml
 type RepoConfig struct {
-	Name     string `yaml:"name"`
-	Group    string `yaml:"group"`
-	Language string `yaml:"language"`
-	WorkDir  string `yaml:"work_dir"`
+	Name     string   `yaml:"name"`
+	Group    string   `yaml:"group"`
+	Language string   `yaml:"language"`
+	WorkDir  string   `yaml:"work_dir"`
+	Tags     []string `yaml:"tags"`
+	Remote   string   `yaml:"remote"` // Clone URL (alias: url)
+	URL      string   `yaml:"url"`
+	Path     string   `yaml:"path"` // Location relative to the workspace (default: name)
+}
+
+// CloneURL returns the configured remote URL, if any
+func (r RepoConfig) CloneURL() string {
+	if r.Remote != "" {
+		return r.Remote
+	}
+	return r.URL
+}
+
+// RepoPath returns where the repo should live on disk
+func (r RepoConfig) RepoPath(workspacePath string) string {
+	p := r.Path
+	if p == "" {
+		p = r.Name
+	}
+	p = expandHome(p)
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Join(workspacePath, p)
 }
 
 var cachedConfig *WorkspaceConfig
@@ -132,6 +197,81 @@ func GetWorkspacePath() string {
 	return filepath.Join(home, "code")
 }
 
+// AddRepos appends entries to the repos list in config.yaml, preserving
+// the rest of the file's content and comments
+func AddRepos(entries []RepoConfig) error {
+	configPath := findConfigPath()
+	if configPath == "" {
+		return fmt.Errorf("no config.yaml found (set $DEVBOT_CONFIG or create ~/.claude/config.yaml)")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: top level is not a mapping", configPath)
+	}
+
+	var repos *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "repos" {
+			repos = root.Content[i+1]
+			break
+		}
+	}
+	switch {
+	case repos == nil:
+		repos = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "repos"}, repos)
+	case repos.Kind == yaml.ScalarNode && repos.Tag == "!!null":
+		// "repos:" with nothing after it; keep any comments attached to it
+		repos.Kind, repos.Tag, repos.Value = yaml.SequenceNode, "", ""
+	case repos.Kind != yaml.SequenceNode:
+		return fmt.Errorf("%s: repos is not a list", configPath)
+	}
+
+	for _, e := range entries {
+		var node yaml.Node
+		if err := node.Encode(e); err != nil {
+			return err
+		}
+		// Drop empty fields so new entries look hand-written
+		var kept []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v := node.Content[i+1]
+			if (v.Kind == yaml.ScalarNode && v.Value == "") || (v.Kind == yaml.SequenceNode && len(v.Content) == 0) {
+				continue
+			}
+			kept = append(kept, node.Content[i], v)
+		}
+		node.Content = kept
+		repos.Content = append(repos.Content, &node)
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := os.WriteFile(configPath, out.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	ResetConfigCache()
+	return nil
+}
+
 // FindRepoByName finds a repo by name or alias (with fuzzy matching)
 // Deprecated: Use FindRepoByNameExact instead
 func FindRepoByName(name string) *RepoConfig {
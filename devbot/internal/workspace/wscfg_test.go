@@ -3,6 +3,7 @@ package workspace
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -308,3 +309,54 @@ repos:
 		}
 	})
 }
+
+func TestAddRepos(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+		want    []string
+	}{
+		{"appends to existing list", "# mine\nrepos:\n  - name: a\n", false, []string{"a", "b"}},
+		{"adds missing key", "workspace: ~/code\n", false, []string{"b"}},
+		{"fills empty key", "repos:\n", false, []string{"b"}},
+		{"rejects a mapping", "repos:\n  a: {}\n", true, nil},
+		{"rejects a scalar", "repos: a\n", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetConfigCache()
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			_ = os.WriteFile(configPath, []byte(tt.content), 0644)
+			t.Setenv("DEVBOT_CONFIG", configPath)
+
+			err := AddRepos([]RepoConfig{{Name: "b", Remote: "git@example.com:b.git"}})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("AddRepos() should fail")
+				}
+				data, _ := os.ReadFile(configPath)
+				if string(data) != tt.content {
+					t.Errorf("config was rewritten:\n%s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddRepos() error: %v", err)
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() error: %v", err)
+			}
+			var names []string
+			for _, r := range cfg.Repos {
+				names = append(names, r.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("repos = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
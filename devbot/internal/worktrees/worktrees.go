@@ -1,13 +1,12 @@
 package worktrees
 
 import (
-	"bytes"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
 
+	"github.com/sloanahrens/devbot-go/internal/git"
 	"github.com/sloanahrens/devbot-go/internal/workspace"
 )
 
@@ -17,6 +16,7 @@ type Worktree struct {
 	Path       string // full path
 	Branch     string // current branch
 	DirtyFiles int    // number of uncommitted changes
+	Error      error  // set if git couldn't read the worktree
 }
 
 // RepoWorktrees holds worktrees for a repository
@@ -119,29 +119,18 @@ func getWorktreeInfo(name, path string) Worktree {
 		Path: path,
 	}
 
-	// Get current branch
-	wt.Branch = gitCommand(path, "rev-parse", "--abbrev-ref", "HEAD")
-
 	// Count dirty files
-	porcelain := gitCommand(path, "status", "--porcelain")
+	porcelain, err := git.Output(path, "status", "--porcelain")
+	if err != nil {
+		wt.Error = err
+		return wt
+	}
 	if porcelain != "" {
-		wt.DirtyFiles = len(strings.Split(strings.TrimSpace(porcelain), "\n"))
+		wt.DirtyFiles = len(strings.Split(porcelain, "\n"))
 	}
 
-	return wt
-}
-
-func gitCommand(dir string, args ...string) string {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = nil
-
-	if err := cmd.Run(); err != nil {
-		return ""
-	}
+	// Get current branch
+	wt.Branch, _ = git.Output(path, "rev-parse", "--abbrev-ref", "HEAD")
 
-	return strings.TrimSpace(out.String())
+	return wt
 }
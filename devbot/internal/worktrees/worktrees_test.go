@@ -179,13 +179,16 @@ func TestWorktreeDirsConst(t *testing.T) {
 	}
 }
 
-func TestGitCommand(t *testing.T) {
+func TestGetWorktreeInfoNotRepo(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	// Test with non-git directory - should return empty string
-	result := gitCommand(tmpDir, "status")
-	if result != "" {
-		t.Errorf("gitCommand in non-git dir returned %q, want empty", result)
+	// A non-git directory reports an error rather than a blank worktree
+	wt := getWorktreeInfo("plain", tmpDir)
+	if wt.Error == nil {
+		t.Error("getWorktreeInfo in non-git dir should set Error")
+	}
+	if wt.Branch != "" || wt.DirtyFiles != 0 {
+		t.Errorf("getWorktreeInfo in non-git dir = %+v, want empty", wt)
 	}
 }
 